@@ -1,21 +1,30 @@
 package main
 
 import (
+	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
+	"github.com/multiversx/mx-chain-core-go/core"
+	"github.com/multiversx/mx-chain-go/storage"
 	logger "github.com/multiversx/mx-chain-logger-go"
 	"github.com/multiversx/mx-chain-logger-go/file"
+	"github.com/multiversx/mx-chain-tools-go/dbmerger/exitcodes"
+	"github.com/multiversx/mx-chain-tools-go/dbmerger/logging"
 	"github.com/multiversx/mx-chain-tools-go/dbmerger/path"
 	"github.com/multiversx/mx-chain-tools-go/dbmerger/storer"
+	"github.com/multiversx/mx-chain-tools-go/dbmerger/version"
 	"github.com/urfave/cli"
 )
 
 const sourcePathsDelimiter = ","
 const defaultLogsPath = "logs"
 const logFilePrefix = "log"
+const manifestFilePerms = 0644
 
 var (
 	log = logger.GetOrCreate("main")
@@ -38,12 +47,99 @@ var (
 			" log level.",
 		Value: "*:" + logger.LogDebug.String(),
 	}
+	conflictPolicy = cli.StringFlag{
+		Name:  "conflict-policy",
+		Usage: "This flag specifies how the keys found in more than one source are handled. One of: " + storer.AllConflictPoliciesNames(),
+		Value: string(storer.LastWins),
+	}
+	workers = cli.IntFlag{
+		Name:  "workers",
+		Usage: "This flag specifies the number of source DBs read concurrently. The writes in the destination DB are still done in the order of the sources",
+		Value: 1,
+	}
+	destType = cli.StringFlag{
+		Name:  "dest-type",
+		Usage: "This flag specifies the persister type of the destination DB. One of: " + storer.AllPersisterTypesNames(),
+		Value: string(storer.LvlDB),
+	}
+	sourceTypes = cli.StringFlag{
+		Name: "source-types",
+		Usage: `This flag specifies the persister types of the sources, separated by "," and in the same order as the sources. ` +
+			`An empty type means the type is detected from the directory contents. Example "-source-types ` +
+			strings.Join([]string{string(storer.LvlDB), "", string(storer.LvlDBSerial)}, sourcePathsDelimiter) + "\". One of: " + storer.AllPersisterTypesNames(),
+		Value: "",
+	}
+	keyPrefix = cli.StringFlag{
+		Name:  "key-prefix",
+		Usage: "This flag specifies, as hex, the prefix of the keys to be merged. The keys not starting with it are not copied. An empty prefix means all the keys are copied",
+		Value: "",
+	}
+	atomic = cli.BoolTFlag{
+		Name: "atomic",
+		Usage: "Boolean option for merging into a temporary directory, next to the destination, that is moved in place of the " +
+			"destination only if the merge succeeds. On failure, the temporary directory is removed and the destination is left empty. " +
+			"Enabled by default, use -atomic=false to merge directly into the destination.",
+	}
+	appendFlag = cli.BoolFlag{
+		Name: "append",
+		Usage: "Boolean option for merging into an already populated destination. The existing destination contents are merged " +
+			"as an implicit source, with the priority given by append-dest-priority, and the conflict policy applies on them as well. " +
+			"Requires the atomic merge and can not be used along with verify.",
+	}
+	appendDestPriority = cli.StringFlag{
+		Name: "append-dest-priority",
+		Usage: "This flag specifies, when appending, which value is kept for a key found both in the destination and in a source: " +
+			"highest keeps the destination value, lowest keeps the source one. One of: " + storer.AllDestinationPrioritiesNames(),
+		Value: string(storer.HighestPriority),
+	}
+	dbOpenAttempts = cli.Uint64Flag{
+		Name:  "db-open-attempts",
+		Usage: "This flag specifies the number of attempts made to open each DB (e.g. while a node still holds its lock). The default, 1, means no retry",
+		Value: 1,
+	}
+	dbOpenRetryDelay = cli.DurationFlag{
+		Name:  "db-open-retry-delay",
+		Usage: "This flag specifies the delay between two attempts of opening a DB, when db-open-attempts is greater than 1",
+		Value: time.Second,
+	}
+	dbOpenTimeout = cli.DurationFlag{
+		Name:  "open-timeout",
+		Usage: "This flag specifies the max duration of opening each DB, retries included. If reached (e.g. while a running node holds the lock), the tool stops with a DB in use error. The default, 0, means no timeout",
+		Value: 0,
+	}
+	verify = cli.BoolFlag{
+		Name:  "verify",
+		Usage: "Boolean option for enabling a verification pass after the merge. If set, all the keys from the sources will be re-read and checked against the destination.",
+	}
+	compact = cli.BoolFlag{
+		Name: "compact",
+		Usage: "Boolean option for running a full compaction of the destination DB after the merge (and the verification, if enabled). " +
+			"The sizes of the destination directory before and after the compaction are logged. Skipped if the destination is not a level DB.",
+	}
+	manifestOut = cli.StringFlag{
+		Name: "manifest-out",
+		Usage: "This flag specifies, if not empty, the path of a JSON manifest written after the merge. It lists each source path, " +
+			"its number of keys and a hash of its contents, along with the number of keys and the hash of the destination",
+		Value: "",
+	}
+	estimate = cli.BoolFlag{
+		Name: "estimate",
+		Usage: "Boolean option for a dry run: the sources are read and the number of keys and the raw size (keys plus values) of each " +
+			"source and of the merged data are reported, accounting for the conflict policy and the key prefix. Nothing is written.",
+	}
 	logSaveFile = cli.BoolFlag{
 		Name:  "log-save",
 		Usage: "Boolean option for enabling log saving. If set, it will automatically save all the logs into a file.",
 	}
+	logJSON = cli.BoolFlag{
+		Name:  "log-json",
+		Usage: "Boolean option for writing the logs as JSON lines, both in the console and in the log file (if log-save is set).",
+	}
 
-	errEmptyPathProvided = errors.New("empty path provided")
+	errEmptyPathProvided       = errors.New("empty path provided")
+	errInvalidNumSourceTypes   = errors.New("invalid number of source types")
+	errMergeVerificationFailed = errors.New("merge verification failed")
+	errIncompatibleFlags       = errors.New("incompatible flags")
 )
 
 const helpTemplate = `NAME:
@@ -63,23 +159,54 @@ VERSION:
 `
 
 type parsedFlags struct {
-	destPath    string
-	sourcePaths []string
-	logLevel    string
-	logSave     bool
+	destPath       string
+	sourcePaths    []string
+	destType       storer.PersisterType
+	sourceTypes    []storer.PersisterType
+	conflictPolicy storer.ConflictPolicy
+	numWorkers     int
+	keyPrefix      []byte
+	atomic         bool
+	appendMode     storer.AppendMode
+	openAttempts   uint64
+	openDelay      time.Duration
+	openTimeout    time.Duration
+	verify         bool
+	compact        bool
+	manifestPath   string
+	estimate       bool
+	logLevel       string
+	logSave        bool
+	logJSON        bool
 }
 
 func main() {
 	app := cli.NewApp()
 	cli.AppHelpTemplate = helpTemplate
 	app.Name = "DB merger tool CLI App"
-	app.Version = "v1.0.0"
+	app.Version = version.GetVersion()
 	app.Usage = "This is the entry point for DB merge tool able to merge 2 or more level DB databases"
 	app.Flags = []cli.Flag{
 		dest,
 		sources,
+		conflictPolicy,
+		workers,
+		destType,
+		sourceTypes,
+		keyPrefix,
+		atomic,
+		appendFlag,
+		appendDestPriority,
+		dbOpenAttempts,
+		dbOpenRetryDelay,
+		dbOpenTimeout,
+		verify,
+		compact,
+		manifestOut,
+		estimate,
 		logLevel,
 		logSaveFile,
+		logJSON,
 	}
 	app.Authors = []cli.Author{
 		{
@@ -89,38 +216,51 @@ func main() {
 	}
 
 	app.Action = action
+	app.OnUsageError = exitcodes.OnUsageError
 
 	err := app.Run(os.Args)
 	if err != nil {
 		log.Error(err.Error())
-		os.Exit(1)
+		os.Exit(exitcodes.ExitCode(err))
 	}
 }
 
-func action(ctx *cli.Context) {
+func action(ctx *cli.Context) error {
 	flags, err := parseFlags(ctx)
 	if err != nil {
-		log.Error("cannot process input flags", "error", err)
-		return
+		return exitcodes.NewUsageError(fmt.Errorf("%w while processing the input flags", err))
 	}
 
 	err = doAction(flags)
 	if err != nil {
-		log.Error("cannot perform action", "error", err)
-		return
+		return fmt.Errorf("%w while performing the action", err)
 	}
 
 	log.Info("action performed")
+
+	return nil
 }
 
 func parseFlags(ctx *cli.Context) (parsedFlags, error) {
 	sourcePaths := ctx.GlobalString(sources.Name)
 
 	flags := parsedFlags{
-		destPath:    ctx.GlobalString(dest.Name),
-		sourcePaths: strings.Split(sourcePaths, sourcePathsDelimiter),
-		logLevel:    ctx.GlobalString(logLevel.Name),
-		logSave:     ctx.GlobalBool(logSaveFile.Name),
+		destPath:       ctx.GlobalString(dest.Name),
+		sourcePaths:    strings.Split(sourcePaths, sourcePathsDelimiter),
+		destType:       storer.PersisterType(ctx.GlobalString(destType.Name)),
+		conflictPolicy: storer.ConflictPolicy(ctx.GlobalString(conflictPolicy.Name)),
+		numWorkers:     ctx.GlobalInt(workers.Name),
+		atomic:         ctx.GlobalBoolT(atomic.Name),
+		openAttempts:   ctx.GlobalUint64(dbOpenAttempts.Name),
+		openDelay:      ctx.GlobalDuration(dbOpenRetryDelay.Name),
+		openTimeout:    ctx.GlobalDuration(dbOpenTimeout.Name),
+		verify:         ctx.GlobalBool(verify.Name),
+		compact:        ctx.GlobalBool(compact.Name),
+		manifestPath:   ctx.GlobalString(manifestOut.Name),
+		estimate:       ctx.GlobalBool(estimate.Name),
+		logLevel:       ctx.GlobalString(logLevel.Name),
+		logSave:        ctx.GlobalBool(logSaveFile.Name),
+		logJSON:        ctx.GlobalBool(logJSON.Name),
 	}
 
 	// TODO add separate check functions
@@ -133,6 +273,41 @@ func parseFlags(ctx *cli.Context) (parsedFlags, error) {
 		}
 	}
 
+	prefix, err := hex.DecodeString(ctx.GlobalString(keyPrefix.Name))
+	if err != nil {
+		return parsedFlags{}, fmt.Errorf("%w while decoding the `key-prefix` flag", err)
+	}
+	flags.keyPrefix = prefix
+
+	if ctx.GlobalBool(appendFlag.Name) {
+		if flags.verify {
+			return parsedFlags{}, fmt.Errorf("%w, `append` can not be used along with `verify`, since the previous destination contents are not kept", errIncompatibleFlags)
+		}
+		if !flags.atomic {
+			return parsedFlags{}, fmt.Errorf("%w, `append` requires `atomic`", errIncompatibleFlags)
+		}
+
+		priority := storer.DestinationPriority(ctx.GlobalString(appendDestPriority.Name))
+		flags.appendMode, err = storer.AppendModeForPriority(flags.conflictPolicy, priority)
+		if err != nil {
+			return parsedFlags{}, err
+		}
+	}
+
+	if flags.estimate && (flags.verify || flags.compact || len(flags.manifestPath) > 0) {
+		return parsedFlags{}, fmt.Errorf("%w, `estimate` does not merge, so it can not be used along with `verify`, `compact` or `manifest-out`", errIncompatibleFlags)
+	}
+
+	sourceTypesValue := ctx.GlobalString(sourceTypes.Name)
+	if len(sourceTypesValue) > 0 {
+		for _, sourceType := range strings.Split(sourceTypesValue, sourcePathsDelimiter) {
+			flags.sourceTypes = append(flags.sourceTypes, storer.PersisterType(strings.TrimSpace(sourceType)))
+		}
+		if len(flags.sourceTypes) != len(flags.sourcePaths) {
+			return parsedFlags{}, fmt.Errorf("%w, provided %d, expected %d (one for each source)", errInvalidNumSourceTypes, len(flags.sourceTypes), len(flags.sourcePaths))
+		}
+	}
+
 	return flags, nil
 }
 
@@ -142,28 +317,193 @@ func doAction(flags parsedFlags) error {
 		return err
 	}
 
-	persisterCreator := storer.NewPersisterCreator()
+	dataMerger, err := storer.NewDataMergerWithArgs(storer.ArgsDataMerger{
+		ConflictPolicy: flags.conflictPolicy,
+		NumWorkers:     flags.numWorkers,
+		KeyPrefix:      flags.keyPrefix,
+	})
+	if err != nil {
+		return exitcodes.NewUsageError(err)
+	}
+
+	persisterCreator, err := storer.NewPersisterCreatorWithArgs(storer.ArgsPersisterCreator{
+		DefaultType:    storer.LvlDB,
+		PathTypes:      createPathTypes(flags),
+		OpenAttempts:   flags.openAttempts,
+		OpenRetryDelay: flags.openDelay,
+		OpenTimeout:    flags.openTimeout,
+	})
+	if err != nil {
+		return exitcodes.NewUsageError(err)
+	}
+
 	args := storer.ArgsFullDBMerger{
-		DataMergerInstance:  storer.NewDataMerger(),
+		DataMergerInstance:  dataMerger,
 		PersisterCreator:    persisterCreator,
 		OsOperationsHandler: path.NewOsOperationsHandler(),
+		Atomic:              flags.atomic,
+		AppendMode:          flags.appendMode,
+		MergeEstimator:      dataMerger,
+		ProgressHandler: func(keysCopied uint64) {
+			log.Info("merging in progress", "num key-values copied", keysCopied)
+		},
 	}
 	fullDataMerger, err := storer.NewFullDBMerger(args)
 	if err != nil {
 		return err
 	}
 
+	if flags.estimate {
+		return estimateMerge(fullDataMerger, flags)
+	}
+
 	destDB, err := fullDataMerger.MergeDBs(flags.destPath, flags.sourcePaths...)
+	if err != nil {
+		return exitcodes.NewIOError(err)
+	}
+
+	if flags.verify {
+		err = verifyMerge(fullDataMerger, destDB, flags)
+		if err != nil {
+			_ = destDB.Close()
+			return err
+		}
+	}
+
+	err = destDB.Close()
+	if err != nil {
+		return err
+	}
+
+	if flags.compact {
+		err = compactDestination(flags.destPath)
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(flags.manifestPath) > 0 {
+		return writeManifest(fullDataMerger, flags)
+	}
+
+	return nil
+}
+
+func estimateMerge(fullDataMerger storer.FullDBMerger, flags parsedFlags) error {
+	log.Info("estimating the merge, nothing will be written...")
+
+	mergeEstimate, err := fullDataMerger.EstimateMerge(flags.destPath, flags.sourcePaths...)
+	if err != nil {
+		return exitcodes.NewIOError(err)
+	}
+
+	for _, sourceEstimate := range mergeEstimate.Sources {
+		log.Info("source estimate", "path", sourceEstimate.Path,
+			"num keys", sourceEstimate.NumKeys, "size", core.ConvertBytes(sourceEstimate.NumBytes))
+	}
+	log.Info("merge estimate", "num keys", mergeEstimate.NumKeys, "size", core.ConvertBytes(mergeEstimate.NumBytes),
+		"num bytes", mergeEstimate.NumBytes, "num duplicate keys", mergeEstimate.NumDuplicateKeys, "conflict policy", flags.conflictPolicy)
+
+	if flags.conflictPolicy == storer.ErrorOnConflict && mergeEstimate.NumDuplicateKeys > 0 {
+		log.Warn("the merge would fail, since keys are found in more than one source", "num duplicate keys", mergeEstimate.NumDuplicateKeys)
+	}
+
+	return nil
+}
+
+func writeManifest(fullDataMerger storer.FullDBMerger, flags parsedFlags) error {
+	log.Info("computing the manifest...")
+
+	manifest, err := fullDataMerger.ComputeManifest(flags.destPath, flags.sourcePaths...)
+	if err != nil {
+		return exitcodes.NewIOError(err)
+	}
+	manifest.Version = version.GetVersion()
+	manifest.ConflictPolicy = flags.conflictPolicy
+	manifest.KeyPrefix = hex.EncodeToString(flags.keyPrefix)
+	manifest.AppendMode = flags.appendMode
+
+	manifestJSON, err := json.MarshalIndent(manifest, "", "    ")
+	if err != nil {
+		return err
+	}
+
+	err = os.WriteFile(flags.manifestPath, manifestJSON, manifestFilePerms)
+	if err != nil {
+		return exitcodes.NewIOError(fmt.Errorf("%w while writing the manifest", err))
+	}
+
+	log.Info("manifest written", "path", flags.manifestPath,
+		"destination num keys", manifest.Destination.NumKeys, "destination hash", manifest.Destination.Hash)
+
+	return nil
+}
+
+// compactDestination compacts the closed destination DB. The persisters do not expose the compaction, so the
+// destination directory is re-opened as a raw level DB, which is only possible once the persister released its lock
+func compactDestination(destPath string) error {
+	log.Info("compacting the destination...")
+
+	start := time.Now()
+	result, err := storer.CompactLevelDBDirectory(destPath)
+	if errors.Is(err, storer.ErrCompactionNotSupported) {
+		log.Warn("skipped the compaction of the destination", "reason", err.Error())
+		return nil
+	}
+	if err != nil {
+		return exitcodes.NewIOError(err)
+	}
+
+	log.Info("destination compacted",
+		"size before", result.SizeBeforeBytes,
+		"size after", result.SizeAfterBytes,
+		"duration", time.Since(start))
+
+	return nil
+}
+
+func createPathTypes(flags parsedFlags) map[string]storer.PersisterType {
+	pathTypes := make(map[string]storer.PersisterType)
+	for idx, sourceType := range flags.sourceTypes {
+		if len(sourceType) > 0 {
+			pathTypes[flags.sourcePaths[idx]] = sourceType
+		}
+	}
+	pathTypes[flags.destPath] = flags.destType
+	pathTypes[storer.TemporaryDestinationPath(flags.destPath)] = flags.destType
+
+	return pathTypes
+}
+
+func verifyMerge(fullDataMerger storer.FullDBMerger, destDB storage.Persister, flags parsedFlags) error {
+	log.Info("verifying the merged data...")
+
+	numVerifiedKeys, mismatchedKeys, err := fullDataMerger.VerifyMerge(destDB, flags.sourcePaths...)
 	if err != nil {
 		return err
 	}
 
-	return destDB.Close()
+	for _, key := range mismatchedKeys {
+		log.Error("mismatched key in destination", "key", key)
+	}
+	if len(mismatchedKeys) > 0 {
+		return exitcodes.NewIntegrityError(fmt.Errorf("%w, num verified keys %d, num mismatched keys %d", errMergeVerificationFailed, numVerifiedKeys, len(mismatchedKeys)))
+	}
+
+	log.Info("merged data verified", "num verified keys", numVerifiedKeys)
+
+	return nil
 }
 
 func processFileLogger(log logger.Logger, flags parsedFlags) error {
 	var err error
-	if flags.logSave {
+	switch {
+	case flags.logSave && flags.logJSON:
+		_, err = logging.NewJSONFileLogging("", defaultLogsPath, logFilePrefix)
+		if err != nil {
+			return fmt.Errorf("%w creating a log file", err)
+		}
+	case flags.logSave:
 		_, err = file.NewFileLogging(file.ArgsFileLogging{
 			WorkingDir:      "",
 			DefaultLogsPath: defaultLogsPath,
@@ -176,10 +516,17 @@ func processFileLogger(log logger.Logger, flags parsedFlags) error {
 
 	err = logger.SetLogLevel(flags.logLevel)
 	if err != nil {
-		return err
+		return exitcodes.NewUsageError(err)
+	}
+
+	if flags.logJSON {
+		err = logging.SetConsoleJSONFormatter()
+		if err != nil {
+			return err
+		}
 	}
 
-	log.Trace("logger updated", "level", flags.logLevel)
+	log.Trace("logger updated", "level", flags.logLevel, "JSON", flags.logJSON)
 
 	return nil
 }
@@ -0,0 +1,85 @@
+package exitcodes
+
+import (
+	"errors"
+
+	"github.com/urfave/cli"
+)
+
+// Copy of trieTools/trieToolsCommon/exitCodes.go: the dbmerger module does not depend on trieTools
+
+const (
+	// ExitCodeGeneric is the exit code used for the errors without a category (e.g. internal failures)
+	ExitCodeGeneric = 1
+	// ExitCodeUsage is the exit code used for the invalid flags or configuration
+	ExitCodeUsage = 2
+	// ExitCodeIO is the exit code used when a file or a database can not be read or written
+	ExitCodeIO = 3
+	// ExitCodeIntegrity is the exit code used when the checked data is found inconsistent
+	ExitCodeIntegrity = 4
+)
+
+type exitCodeError struct {
+	err      error
+	exitCode int
+}
+
+// Error returns the message of the wrapped error
+func (e *exitCodeError) Error() string {
+	return e.err.Error()
+}
+
+// Unwrap returns the wrapped error
+func (e *exitCodeError) Unwrap() error {
+	return e.err
+}
+
+// NewUsageError marks the provided error as an usage or configuration error. A nil error is returned as nil
+func NewUsageError(err error) error {
+	return newExitCodeError(err, ExitCodeUsage)
+}
+
+// NewIOError marks the provided error as an IO or database error. A nil error is returned as nil
+func NewIOError(err error) error {
+	return newExitCodeError(err, ExitCodeIO)
+}
+
+// NewIntegrityError marks the provided error as an integrity error. A nil error is returned as nil
+func NewIntegrityError(err error) error {
+	return newExitCodeError(err, ExitCodeIntegrity)
+}
+
+func newExitCodeError(err error, exitCode int) error {
+	if err == nil {
+		return nil
+	}
+
+	return &exitCodeError{
+		err:      err,
+		exitCode: exitCode,
+	}
+}
+
+// ExitCode returns the exit code for the provided error: 0 for a nil error, the code of the innermost category the
+// error was marked with (the closest to where the error occurred), or ExitCodeGeneric if the error was not marked
+func ExitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+
+	exitCode := ExitCodeGeneric
+	for ; err != nil; err = errors.Unwrap(err) {
+		errWithCode, isExitCodeError := err.(*exitCodeError)
+		if isExitCodeError {
+			exitCode = errWithCode.exitCode
+		}
+	}
+
+	return exitCode
+}
+
+// OnUsageError marks the errors of the flags parsing as usage errors. It should be set as the OnUsageError field of
+// the cli app
+func OnUsageError(_ *cli.Context, err error, _ bool) error {
+	return NewUsageError(err)
+}
@@ -0,0 +1,34 @@
+package exitcodes
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExitCode(t *testing.T) {
+	t.Parallel()
+
+	expectedErr := errors.New("expected error")
+
+	assert.Equal(t, 0, ExitCode(nil))
+	assert.Equal(t, ExitCodeGeneric, ExitCode(expectedErr))
+	assert.Equal(t, ExitCodeUsage, ExitCode(NewUsageError(expectedErr)))
+	assert.Equal(t, ExitCodeIO, ExitCode(NewIOError(expectedErr)))
+	assert.Equal(t, ExitCodeIntegrity, ExitCode(NewIntegrityError(expectedErr)))
+	assert.Equal(t, ExitCodeUsage, ExitCode(OnUsageError(nil, expectedErr, false)))
+
+	wrappedErr := fmt.Errorf("%w while processing", NewIOError(expectedErr))
+	assert.Equal(t, ExitCodeIO, ExitCode(wrappedErr))
+	assert.True(t, errors.Is(wrappedErr, expectedErr))
+	assert.Equal(t, "expected error while processing", wrappedErr.Error())
+
+	assert.Equal(t, ExitCodeIO, ExitCode(NewIntegrityError(NewIOError(expectedErr))))
+	assert.Equal(t, ExitCodeUsage, ExitCode(NewIOError(fmt.Errorf("%w in file", NewUsageError(expectedErr)))))
+
+	assert.Nil(t, NewUsageError(nil))
+	assert.Nil(t, NewIOError(nil))
+	assert.Nil(t, NewIntegrityError(nil))
+}
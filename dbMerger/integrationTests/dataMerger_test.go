@@ -0,0 +1,65 @@
+package integrationTests
+
+import (
+	"testing"
+
+	"github.com/multiversx/mx-chain-storage-go/types"
+	"github.com/multiversx/mx-chain-tools-go/dbmerger/storer"
+	"github.com/stretchr/testify/assert"
+)
+
+func benchmarkDataMerger(b *testing.B, numWorkers int, numSources int, numKeysPerSource int) {
+	persisterCreator := storer.NewPersisterCreator()
+	writeChecker := NewDBDataWriteChecker()
+
+	sources := make([]types.Persister, 0, numSources)
+	for i := 0; i < numSources; i++ {
+		dbPath := createDBAndAddData(b, persisterCreator, writeChecker, numKeysPerSource)
+		source, err := persisterCreator.CreatePersister(dbPath)
+		assert.Nil(b, err)
+
+		sources = append(sources, source)
+	}
+
+	dm, err := storer.NewDataMergerWithArgs(storer.ArgsDataMerger{
+		ConflictPolicy: storer.LastWins,
+		NumWorkers:     numWorkers,
+	})
+	assert.Nil(b, err)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		dest, errCreate := persisterCreator.CreatePersister(b.TempDir())
+		assert.Nil(b, errCreate)
+		b.StartTimer()
+
+		err = dm.MergeDBs(dest, sources...)
+		assert.Nil(b, err)
+
+		b.StopTimer()
+		_ = dest.Close()
+		b.StartTimer()
+	}
+	b.StopTimer()
+
+	for _, source := range sources {
+		_ = source.Close()
+	}
+}
+
+func BenchmarkDataMerger_SmallInputSequential(b *testing.B) {
+	benchmarkDataMerger(b, 1, 4, 100)
+}
+
+func BenchmarkDataMerger_SmallInputParallel(b *testing.B) {
+	benchmarkDataMerger(b, 4, 4, 100)
+}
+
+func BenchmarkDataMerger_LargeInputSequential(b *testing.B) {
+	benchmarkDataMerger(b, 1, 4, 50000)
+}
+
+func BenchmarkDataMerger_LargeInputParallel(b *testing.B) {
+	benchmarkDataMerger(b, 4, 4, 50000)
+}
@@ -1,8 +1,14 @@
 package integrationTests
 
 import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
+	"github.com/multiversx/mx-chain-go/storage"
+
 	"github.com/multiversx/mx-chain-tools-go/dbmerger/path"
 	"github.com/multiversx/mx-chain-tools-go/dbmerger/storer"
 	"github.com/stretchr/testify/assert"
@@ -29,6 +35,338 @@ func TestFullDBMergerWith3Persisters(t *testing.T) {
 	assert.Nil(t, err)
 
 	writeChecker.CheckDB(t, dest)
+
+	numVerifiedKeys, mismatchedKeys, err := fullDataMerger.VerifyMerge(dest, dbPath1, dbPath2, dbPath3)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(60), numVerifiedKeys)
+	assert.Empty(t, mismatchedKeys)
+}
+
+func TestFullDBMergerAtomic(t *testing.T) {
+	persisterCreator := storer.NewPersisterCreator()
+	args := storer.ArgsFullDBMerger{
+		DataMergerInstance:  storer.NewDataMerger(),
+		PersisterCreator:    persisterCreator,
+		OsOperationsHandler: path.NewOsOperationsHandler(),
+		Atomic:              true,
+	}
+	fullDataMerger, err := storer.NewFullDBMerger(args)
+	assert.Nil(t, err)
+
+	t.Run("should work", func(t *testing.T) {
+		writeChecker := NewDBDataWriteChecker()
+		dbPath1 := createDBAndAddData(t, persisterCreator, writeChecker, 10)
+		dbPath2 := createDBAndAddData(t, persisterCreator, writeChecker, 20)
+		dbPathDest := filepath.Join(t.TempDir(), "dest")
+		err = os.Mkdir(dbPathDest, os.ModePerm)
+		assert.Nil(t, err)
+
+		dest, errMerge := fullDataMerger.MergeDBs(dbPathDest, dbPath1, dbPath2)
+		assert.Nil(t, errMerge)
+
+		writeChecker.CheckDB(t, dest)
+		_ = dest.Close()
+
+		_, err = os.Stat(storer.TemporaryDestinationPath(dbPathDest))
+		assert.True(t, os.IsNotExist(err))
+	})
+	t.Run("failed merge should leave the destination empty", func(t *testing.T) {
+		writeChecker := NewDBDataWriteChecker()
+		dbPath1 := createDBAndAddData(t, persisterCreator, writeChecker, 10)
+		dbPathInvalid := filepath.Join(t.TempDir(), "invalid")
+		dbPathDest := filepath.Join(t.TempDir(), "dest")
+		err = os.Mkdir(dbPathDest, os.ModePerm)
+		assert.Nil(t, err)
+
+		// the second source is a file, so it can not be opened as a persister
+		err = os.WriteFile(dbPathInvalid, []byte("not a DB"), os.ModePerm)
+		assert.Nil(t, err)
+
+		dest, errMerge := fullDataMerger.MergeDBs(dbPathDest, dbPath1, dbPathInvalid)
+		assert.NotNil(t, errMerge)
+		assert.Nil(t, dest)
+
+		entries, errRead := os.ReadDir(dbPathDest)
+		assert.Nil(t, errRead)
+		assert.Empty(t, entries)
+
+		_, err = os.Stat(storer.TemporaryDestinationPath(dbPathDest))
+		assert.True(t, os.IsNotExist(err))
+	})
+}
+
+func TestFullDBMergerWithMixedPersisterTypes(t *testing.T) {
+	writeChecker := NewDBDataWriteChecker()
+
+	dbPath1 := t.TempDir()
+	dbPath2 := t.TempDir()
+	dbPath3 := t.TempDir()
+	dbPathDest := t.TempDir()
+	persisterCreator, err := storer.NewPersisterCreatorWithArgs(storer.ArgsPersisterCreator{
+		DefaultType: storer.LvlDB,
+		PathTypes: map[string]storer.PersisterType{
+			dbPath1:    storer.LvlDBSerial,
+			dbPath2:    storer.LvlDB,
+			dbPathDest: storer.LvlDBSerial,
+		},
+	})
+	assert.Nil(t, err)
+
+	createDBAtPathAndAddData(t, persisterCreator, writeChecker, dbPath1, 10)
+	createDBAtPathAndAddData(t, persisterCreator, writeChecker, dbPath2, 20)
+	// the third source does not have a type hint, so its type is detected
+	createDBAtPathAndAddData(t, storer.NewPersisterCreator(), writeChecker, dbPath3, 30)
+
+	args := storer.ArgsFullDBMerger{
+		DataMergerInstance:  storer.NewDataMerger(),
+		PersisterCreator:    persisterCreator,
+		OsOperationsHandler: path.NewOsOperationsHandler(),
+	}
+	fullDataMerger, err := storer.NewFullDBMerger(args)
+	assert.Nil(t, err)
+
+	dest, err := fullDataMerger.MergeDBs(dbPathDest, dbPath1, dbPath2, dbPath3)
+	assert.Nil(t, err)
+	assert.Equal(t, "*leveldb.SerialDB", fmt.Sprintf("%T", dest))
+
+	writeChecker.CheckDB(t, dest)
+
+	numVerifiedKeys, mismatchedKeys, err := fullDataMerger.VerifyMerge(dest, dbPath1, dbPath2, dbPath3)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(60), numVerifiedKeys)
+	assert.Empty(t, mismatchedKeys)
+	_ = dest.Close()
+}
+
+func TestFullDBMergerWithKeyPrefix(t *testing.T) {
+	persisterCreator := storer.NewPersisterCreator()
+
+	dbPath1 := t.TempDir()
+	dbPath2 := t.TempDir()
+	dbPathDest := t.TempDir()
+
+	db1, err := persisterCreator.CreatePersister(dbPath1)
+	assert.Nil(t, err)
+	_ = db1.Put([]byte("static_key1"), []byte("value1"))
+	_ = db1.Put([]byte("other_key2"), []byte("value2"))
+	_ = db1.Close()
+
+	db2, err := persisterCreator.CreatePersister(dbPath2)
+	assert.Nil(t, err)
+	_ = db2.Put([]byte("static_key3"), []byte("value3"))
+	_ = db2.Put([]byte("key4"), []byte("value4"))
+	_ = db2.Close()
+
+	dataMerger, err := storer.NewDataMergerWithArgs(storer.ArgsDataMerger{
+		ConflictPolicy: storer.LastWins,
+		NumWorkers:     1,
+		KeyPrefix:      []byte("static_"),
+	})
+	assert.Nil(t, err)
+
+	args := storer.ArgsFullDBMerger{
+		DataMergerInstance:  dataMerger,
+		PersisterCreator:    persisterCreator,
+		OsOperationsHandler: path.NewOsOperationsHandler(),
+	}
+	fullDataMerger, err := storer.NewFullDBMerger(args)
+	assert.Nil(t, err)
+
+	dest, err := fullDataMerger.MergeDBs(dbPathDest, dbPath1, dbPath2)
+	assert.Nil(t, err)
+
+	val, err := dest.Get([]byte("static_key1"))
+	assert.Nil(t, err)
+	assert.Equal(t, []byte("value1"), val)
+	val, err = dest.Get([]byte("static_key3"))
+	assert.Nil(t, err)
+	assert.Equal(t, []byte("value3"), val)
+
+	// the unrelated keys, including the ones of the first source, are excluded
+	assert.NotNil(t, dest.Has([]byte("other_key2")))
+	assert.NotNil(t, dest.Has([]byte("key4")))
+
+	numVerifiedKeys, mismatchedKeys, err := fullDataMerger.VerifyMerge(dest, dbPath1, dbPath2)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(2), numVerifiedKeys)
+	assert.Empty(t, mismatchedKeys)
+	_ = dest.Close()
+}
+
+func TestFullDBMergerManifest(t *testing.T) {
+	persisterCreator := storer.NewPersisterCreator()
+	writeChecker := NewDBDataWriteChecker()
+
+	dbPath1 := createDBAndAddData(t, persisterCreator, writeChecker, 10)
+	dbPath2 := createDBAndAddData(t, persisterCreator, writeChecker, 20)
+	dbPathDest := t.TempDir()
+
+	args := storer.ArgsFullDBMerger{
+		DataMergerInstance:  storer.NewDataMerger(),
+		PersisterCreator:    persisterCreator,
+		OsOperationsHandler: path.NewOsOperationsHandler(),
+	}
+	fullDataMerger, err := storer.NewFullDBMerger(args)
+	assert.Nil(t, err)
+
+	dest, err := fullDataMerger.MergeDBs(dbPathDest, dbPath1, dbPath2)
+	assert.Nil(t, err)
+	_ = dest.Close()
+
+	manifest, err := fullDataMerger.ComputeManifest(dbPathDest, dbPath1, dbPath2)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(10), manifest.Sources[0].NumKeys)
+	assert.Equal(t, uint64(20), manifest.Sources[1].NumKeys)
+	assert.Equal(t, uint64(30), manifest.Destination.NumKeys)
+
+	// the same contents, written in a different order, should have the same hash
+	dbPathCopy := t.TempDir()
+	db, err := persisterCreator.CreatePersister(dbPathCopy)
+	assert.Nil(t, err)
+	for i := 30; i > 0; i-- {
+		_ = db.Put([]byte(fmt.Sprintf(keyFormat, i)), []byte(fmt.Sprintf(valueFormat, i)))
+	}
+	_ = db.Close()
+
+	manifestCopy, err := fullDataMerger.ComputeManifest(dbPathCopy)
+	assert.Nil(t, err)
+	assert.Equal(t, manifest.Destination.Hash, manifestCopy.Destination.Hash)
+	assert.NotEqual(t, manifest.Destination.Hash, manifest.Sources[0].Hash)
+}
+
+func TestFullDBMergerEstimate(t *testing.T) {
+	persisterCreator := storer.NewPersisterCreator()
+
+	dbPathDest := filepath.Join(t.TempDir(), "dest")
+	dbPathSource := t.TempDir()
+	createDBWithKeyValues(t, persisterCreator, dbPathDest, map[string]string{
+		"dest_only": "dest value",
+		"both":      "dest value",
+	})
+	createDBWithKeyValues(t, persisterCreator, dbPathSource, map[string]string{
+		"source_only": "source value",
+		"both":        "source value!",
+	})
+
+	dataMerger := storer.NewDataMerger()
+	fullDataMerger, err := storer.NewFullDBMerger(storer.ArgsFullDBMerger{
+		DataMergerInstance:  dataMerger,
+		PersisterCreator:    persisterCreator,
+		OsOperationsHandler: path.NewOsOperationsHandler(),
+		Atomic:              true,
+		AppendMode:          storer.AppendDestinationFirst,
+		MergeEstimator:      dataMerger,
+	})
+	assert.Nil(t, err)
+
+	estimate, err := fullDataMerger.EstimateMerge(dbPathDest, dbPathSource)
+	assert.Nil(t, err)
+	assert.Equal(t, []storer.PersisterEstimate{
+		{Path: dbPathDest, NumKeys: 2, NumBytes: 33},
+		{Path: dbPathSource, NumKeys: 2, NumBytes: 40},
+	}, estimate.Sources)
+	// the source value of the overlapping key wins
+	assert.Equal(t, uint64(3), estimate.NumKeys)
+	assert.Equal(t, uint64(59), estimate.NumBytes)
+	assert.Equal(t, uint64(1), estimate.NumDuplicateKeys)
+
+	// nothing should have been written
+	_, errStat := os.Stat(storer.TemporaryDestinationPath(dbPathDest))
+	assert.True(t, os.IsNotExist(errStat))
+	dest, err := persisterCreator.CreatePersister(dbPathDest)
+	assert.Nil(t, err)
+	assert.NotNil(t, dest.Has([]byte("source_only")))
+	_ = dest.Close()
+}
+
+func createDBWithKeyValues(t *testing.T, persisterCreator storer.PersisterCreator, dbPath string, keyValues map[string]string) {
+	db, err := persisterCreator.CreatePersister(dbPath)
+	assert.Nil(t, err)
+	for key, value := range keyValues {
+		assert.Nil(t, db.Put([]byte(key), []byte(value)))
+	}
+	_ = db.Close()
+}
+
+func TestFullDBMergerAppend(t *testing.T) {
+	persisterCreator := storer.NewPersisterCreator()
+
+	appendInto := func(t *testing.T, policy storer.ConflictPolicy, priority storer.DestinationPriority) (storage.Persister, error) {
+		dbPathDest := filepath.Join(t.TempDir(), "dest")
+		dbPathSource := t.TempDir()
+		createDBWithKeyValues(t, persisterCreator, dbPathDest, map[string]string{
+			"dest_only": "dest value",
+			"both":      "dest value",
+		})
+		createDBWithKeyValues(t, persisterCreator, dbPathSource, map[string]string{
+			"source_only": "source value",
+			"both":        "source value",
+		})
+
+		dataMerger, err := storer.NewDataMergerWithArgs(storer.ArgsDataMerger{
+			ConflictPolicy: policy,
+			NumWorkers:     1,
+		})
+		assert.Nil(t, err)
+		appendMode, err := storer.AppendModeForPriority(policy, priority)
+		assert.Nil(t, err)
+
+		fullDataMerger, err := storer.NewFullDBMerger(storer.ArgsFullDBMerger{
+			DataMergerInstance:  dataMerger,
+			PersisterCreator:    persisterCreator,
+			OsOperationsHandler: path.NewOsOperationsHandler(),
+			Atomic:              true,
+			AppendMode:          appendMode,
+		})
+		assert.Nil(t, err)
+
+		dest, err := fullDataMerger.MergeDBs(dbPathDest, dbPathSource)
+
+		_, errStat := os.Stat(storer.TemporaryDestinationPath(dbPathDest))
+		assert.True(t, os.IsNotExist(errStat))
+
+		return dest, err
+	}
+	checkValue := func(t *testing.T, dest storage.Persister, key string, expectedValue string) {
+		val, err := dest.Get([]byte(key))
+		assert.Nil(t, err)
+		assert.Equal(t, expectedValue, string(val))
+	}
+
+	for _, policy := range []storer.ConflictPolicy{storer.FirstWins, storer.LastWins} {
+		policy := policy
+		t.Run(string(policy)+" with the destination as highest priority should keep the destination values", func(t *testing.T) {
+			dest, err := appendInto(t, policy, storer.HighestPriority)
+			assert.Nil(t, err)
+
+			checkValue(t, dest, "dest_only", "dest value")
+			checkValue(t, dest, "source_only", "source value")
+			checkValue(t, dest, "both", "dest value")
+			_ = dest.Close()
+		})
+		t.Run(string(policy)+" with the destination as lowest priority should keep the source values", func(t *testing.T) {
+			dest, err := appendInto(t, policy, storer.LowestPriority)
+			assert.Nil(t, err)
+
+			checkValue(t, dest, "dest_only", "dest value")
+			checkValue(t, dest, "source_only", "source value")
+			checkValue(t, dest, "both", "source value")
+			_ = dest.Close()
+		})
+	}
+	t.Run("error on conflict should error for a key found in the destination and in a source", func(t *testing.T) {
+		dest, err := appendInto(t, storer.ErrorOnConflict, storer.HighestPriority)
+		assert.NotNil(t, err)
+		assert.True(t, strings.Contains(err.Error(), "key conflict"))
+		assert.Nil(t, dest)
+	})
+}
+
+func createDBAtPathAndAddData(tb testing.TB, persisterCreator storer.PersisterCreator, writeChecker *dbDataWriteChecker, dbPath string, numData int) {
+	db, err := persisterCreator.CreatePersister(dbPath)
+	assert.Nil(tb, err)
+	writeChecker.AddDataToDB(db, numData)
+	_ = db.Close()
 }
 
 func createDBAndAddData(tb testing.TB, persisterCreator storer.PersisterCreator, writeChecker *dbDataWriteChecker, numData int) string {
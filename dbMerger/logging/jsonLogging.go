@@ -0,0 +1,162 @@
+package logging
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/multiversx/mx-chain-core-go/core"
+	logger "github.com/multiversx/mx-chain-logger-go"
+	"github.com/multiversx/mx-chain-logger-go/redirects"
+)
+
+// Copy of trieTools/trieToolsCommon/jsonLogging.go: the dbmerger module does not depend on trieTools
+
+const jsonLogTimeLayout = "2006-01-02T15:04:05.000Z07:00"
+
+var log = logger.GetOrCreate("logging")
+
+type jsonLogCorrelation struct {
+	Shard    string `json:"shard"`
+	Epoch    uint32 `json:"epoch"`
+	Round    int64  `json:"round"`
+	SubRound string `json:"subRound"`
+}
+
+type jsonLogLine struct {
+	Timestamp   string              `json:"timestamp"`
+	Level       string              `json:"level"`
+	Logger      string              `json:"logger,omitempty"`
+	Correlation *jsonLogCorrelation `json:"correlation,omitempty"`
+	Message     string              `json:"message"`
+	Fields      map[string]string   `json:"fields,omitempty"`
+}
+
+// JSONFormatter implements the formatter interface and outputs each log line as a JSON object, followed by a new line.
+// The arguments of the log line are written as the fields object, with the same names and values as in the plain output
+type JSONFormatter struct {
+}
+
+// Output converts the provided LogLineHandler into a JSON line
+func (jf *JSONFormatter) Output(line logger.LogLineHandler) []byte {
+	if line == nil {
+		return nil
+	}
+
+	jsonLine := jsonLogLine{
+		Timestamp: time.Unix(0, line.GetTimestamp()).Format(jsonLogTimeLayout),
+		Level:     strings.TrimSpace(logger.LogLevel(line.GetLogLevel()).String()),
+		Message:   line.GetMessage(),
+		Fields:    argsToFields(line.GetArgs()),
+	}
+	if logger.IsEnabledLoggerName() {
+		jsonLine.Logger = line.GetLoggerName()
+	}
+	if logger.IsEnabledCorrelation() {
+		correlation := line.GetCorrelation()
+		jsonLine.Correlation = &jsonLogCorrelation{
+			Shard:    correlation.GetShard(),
+			Epoch:    correlation.GetEpoch(),
+			Round:    correlation.GetRound(),
+			SubRound: correlation.GetSubRound(),
+		}
+	}
+
+	buff, err := json.Marshal(jsonLine)
+	if err != nil {
+		return nil
+	}
+
+	return append(buff, '\n')
+}
+
+// argsToFields pairs the provided arguments as name, value. As for the plain formatter, an odd argument is ignored
+func argsToFields(args []string) map[string]string {
+	if len(args) < 2 {
+		return nil
+	}
+
+	fields := make(map[string]string, len(args)/2)
+	for index := 1; index < len(args); index += 2 {
+		fields[args[index-1]] = args[index]
+	}
+
+	return fields
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (jf *JSONFormatter) IsInterfaceNil() bool {
+	return jf == nil
+}
+
+// SetConsoleJSONFormatter replaces the formatter of the console logs with the JSON formatter
+func SetConsoleJSONFormatter() error {
+	err := logger.RemoveLogObserver(os.Stdout)
+	if err != nil {
+		return err
+	}
+
+	return logger.AddLogObserver(os.Stdout, &JSONFormatter{})
+}
+
+// jsonFileLogging writes the logs, as JSON lines, in a single file. Unlike the plain file logging, the file is not rotated
+type jsonFileLogging struct {
+	mutFile sync.Mutex
+	file    *os.File
+}
+
+// NewJSONFileLogging creates the log file in the same location and with the same name format as the plain file logging
+func NewJSONFileLogging(workingDir string, logsPath string, logFilePrefix string) (*jsonFileLogging, error) {
+	logFile, err := core.CreateFile(core.ArgCreateFileArgument{
+		Prefix:        logFilePrefix,
+		Directory:     filepath.Join(workingDir, logsPath),
+		FileExtension: "log",
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = logger.AddLogObserver(logFile, &JSONFormatter{})
+	if err != nil {
+		_ = logFile.Close()
+		return nil, err
+	}
+
+	errNotCritical := redirects.RedirectStderr(logFile)
+	log.LogIfError(errNotCritical, "step", "redirecting std error")
+
+	return &jsonFileLogging{
+		file: logFile,
+	}, nil
+}
+
+// ChangeFileLifeSpan does nothing, as the JSON log file is not rotated
+func (jfl *jsonFileLogging) ChangeFileLifeSpan(_ time.Duration, _ uint64) error {
+	return nil
+}
+
+// Close removes the log observer and closes the log file
+func (jfl *jsonFileLogging) Close() error {
+	jfl.mutFile.Lock()
+	defer jfl.mutFile.Unlock()
+
+	if jfl.file == nil {
+		return nil
+	}
+
+	errNotCritical := logger.RemoveLogObserver(jfl.file)
+	log.LogIfError(errNotCritical, "step", "removing log observer")
+
+	err := jfl.file.Close()
+	jfl.file = nil
+
+	return err
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (jfl *jsonFileLogging) IsInterfaceNil() bool {
+	return jfl == nil
+}
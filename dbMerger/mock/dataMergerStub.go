@@ -4,7 +4,10 @@ import "github.com/multiversx/mx-chain-storage-go/types"
 
 // DataMergerStub -
 type DataMergerStub struct {
-	MergeDBsCalled func(dest types.Persister, sources ...types.Persister) error
+	MergeDBsCalled             func(dest types.Persister, sources ...types.Persister) error
+	MergeDBsWithProgressCalled func(dest types.Persister, onProgress func(keysCopied uint64), sources ...types.Persister) error
+	VerifyMergeCalled          func(dest types.Persister, sources ...types.Persister) (uint64, []string, error)
+	HasKeyFilterCalled         func() bool
 }
 
 // MergeDBs -
@@ -16,6 +19,33 @@ func (stub *DataMergerStub) MergeDBs(dest types.Persister, sources ...types.Pers
 	return nil
 }
 
+// MergeDBsWithProgress -
+func (stub *DataMergerStub) MergeDBsWithProgress(dest types.Persister, onProgress func(keysCopied uint64), sources ...types.Persister) error {
+	if stub.MergeDBsWithProgressCalled != nil {
+		return stub.MergeDBsWithProgressCalled(dest, onProgress, sources...)
+	}
+
+	return nil
+}
+
+// VerifyMerge -
+func (stub *DataMergerStub) VerifyMerge(dest types.Persister, sources ...types.Persister) (uint64, []string, error) {
+	if stub.VerifyMergeCalled != nil {
+		return stub.VerifyMergeCalled(dest, sources...)
+	}
+
+	return 0, nil, nil
+}
+
+// HasKeyFilter -
+func (stub *DataMergerStub) HasKeyFilter() bool {
+	if stub.HasKeyFilterCalled != nil {
+		return stub.HasKeyFilterCalled()
+	}
+
+	return false
+}
+
 // IsInterfaceNil -
 func (stub *DataMergerStub) IsInterfaceNil() bool {
 	return stub == nil
@@ -4,6 +4,9 @@ package mock
 type OsOperationsHandlerStub struct {
 	CheckIfDirectoryIsEmptyCalled func(directory string) error
 	CopyDirectoryCalled           func(destination string, source string) error
+	CreateDirectoryCalled         func(directory string) error
+	RemoveDirectoryCalled         func(directory string) error
+	MoveDirectoryCalled           func(destination string, source string) error
 }
 
 // CopyDirectory -
@@ -24,6 +27,33 @@ func (stub *OsOperationsHandlerStub) CheckIfDirectoryIsEmpty(directory string) e
 	return nil
 }
 
+// CreateDirectory -
+func (stub *OsOperationsHandlerStub) CreateDirectory(directory string) error {
+	if stub.CreateDirectoryCalled != nil {
+		return stub.CreateDirectoryCalled(directory)
+	}
+
+	return nil
+}
+
+// RemoveDirectory -
+func (stub *OsOperationsHandlerStub) RemoveDirectory(directory string) error {
+	if stub.RemoveDirectoryCalled != nil {
+		return stub.RemoveDirectoryCalled(directory)
+	}
+
+	return nil
+}
+
+// MoveDirectory -
+func (stub *OsOperationsHandlerStub) MoveDirectory(destination string, source string) error {
+	if stub.MoveDirectoryCalled != nil {
+		return stub.MoveDirectoryCalled(destination, source)
+	}
+
+	return nil
+}
+
 // IsInterfaceNil -
 func (stub *OsOperationsHandlerStub) IsInterfaceNil() bool {
 	return stub == nil
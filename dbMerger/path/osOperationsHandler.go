@@ -162,6 +162,44 @@ func (handler *osOperationsHandler) CheckIfDirectoryIsEmpty(directory string) er
 	return nil
 }
 
+// CreateDirectory creates the provided directory. It errors if the directory already exists
+func (handler *osOperationsHandler) CreateDirectory(directory string) error {
+	err := os.Mkdir(directory, dirPermMode)
+	if err != nil {
+		return fmt.Errorf("%w while creating the directory %s", err, directory)
+	}
+
+	return nil
+}
+
+// RemoveDirectory removes the provided directory along with all its contents
+func (handler *osOperationsHandler) RemoveDirectory(directory string) error {
+	log.Debug("removing directory", "directory", directory)
+	err := os.RemoveAll(directory)
+	if err != nil {
+		return fmt.Errorf("%w while removing the directory %s", err, directory)
+	}
+
+	return nil
+}
+
+// MoveDirectory moves the source directory in place of the destination directory. The destination directory,
+// if it exists, should be empty
+func (handler *osOperationsHandler) MoveDirectory(destination string, source string) error {
+	log.Debug("moving directory", "source", source, "destination", destination)
+	err := os.Remove(destination)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("%w while removing the directory %s", err, destination)
+	}
+
+	err = os.Rename(source, destination)
+	if err != nil {
+		return fmt.Errorf("%w while moving the directory %s to %s", err, source, destination)
+	}
+
+	return nil
+}
+
 // IsInterfaceNil returns true if there is no value under the interface
 func (handler *osOperationsHandler) IsInterfaceNil() bool {
 	return handler == nil
@@ -92,6 +92,65 @@ func TestOsOperationsHandler_CheckIfDirectoryIsEmpty(t *testing.T) {
 	})
 }
 
+func TestOsOperationsHandler_CreateAndRemoveDirectory(t *testing.T) {
+	t.Parallel()
+
+	workingDir := path.Join(testDataDirectory, "createDirectoryTest")
+	cleanupDirectory(t, workingDir)
+	defer cleanupDirectory(t, workingDir)
+
+	handler := NewOsOperationsHandler()
+
+	err := handler.CreateDirectory(workingDir)
+	assert.Nil(t, err)
+	assert.Nil(t, handler.CheckIfDirectoryIsEmpty(workingDir))
+
+	err = handler.CreateDirectory(workingDir)
+	assert.NotNil(t, err)
+	assert.True(t, errors.Is(err, os.ErrExist))
+
+	err = ioutil.WriteFile(path.Join(workingDir, "test.log"), []byte("data"), os.ModePerm)
+	assert.Nil(t, err)
+
+	err = handler.RemoveDirectory(workingDir)
+	assert.Nil(t, err)
+	assert.False(t, exists(workingDir))
+}
+
+func TestOsOperationsHandler_MoveDirectory(t *testing.T) {
+	t.Parallel()
+
+	workingDir := path.Join(testDataDirectory, "moveDirectoryTest")
+	cleanupDirectory(t, workingDir)
+	defer cleanupDirectory(t, workingDir)
+
+	handler := NewOsOperationsHandler()
+	source := path.Join(workingDir, "source")
+	destination := path.Join(workingDir, "destination")
+	err := os.MkdirAll(source, os.ModePerm)
+	require.Nil(t, err)
+	err = ioutil.WriteFile(path.Join(source, "test.log"), []byte("data"), os.ModePerm)
+	require.Nil(t, err)
+
+	t.Run("destination not empty should error", func(t *testing.T) {
+		err = os.MkdirAll(path.Join(destination, "test"), os.ModePerm)
+		require.Nil(t, err)
+
+		err = handler.MoveDirectory(destination, source)
+		assert.NotNil(t, err)
+		assert.True(t, exists(source))
+	})
+	t.Run("empty destination should work", func(t *testing.T) {
+		err = os.RemoveAll(path.Join(destination, "test"))
+		require.Nil(t, err)
+
+		err = handler.MoveDirectory(destination, source)
+		assert.Nil(t, err)
+		assert.False(t, exists(source))
+		assert.Equal(t, "data", readFileContent(t, path.Join(destination, "test.log")))
+	})
+}
+
 func readFileContent(tb testing.TB, path string) string {
 	in, err := os.Open(path)
 	require.Nil(tb, err)
@@ -0,0 +1,66 @@
+package storer
+
+import (
+	"fmt"
+	"strings"
+)
+
+// AppendMode defines whether the contents already present in the destination persister are merged, and in which
+// position among the sources
+type AppendMode string
+
+const (
+	// NoAppend requires an empty destination
+	NoAppend AppendMode = ""
+	// AppendDestinationFirst merges the existing destination contents as if they were the first source
+	AppendDestinationFirst AppendMode = "destination-first"
+	// AppendDestinationLast merges the existing destination contents as if they were the last source
+	AppendDestinationLast AppendMode = "destination-last"
+)
+
+// DestinationPriority defines which value is kept when a key is found both in the existing destination contents and
+// in a source, while appending
+type DestinationPriority string
+
+const (
+	// HighestPriority keeps the existing destination values
+	HighestPriority DestinationPriority = "highest"
+	// LowestPriority overwrites the existing destination values with the source ones
+	LowestPriority DestinationPriority = "lowest"
+)
+
+var allDestinationPriorities = []DestinationPriority{HighestPriority, LowestPriority}
+
+// AllDestinationPrioritiesNames returns the names of all supported destination priorities, comma separated
+func AllDestinationPrioritiesNames() string {
+	names := make([]string, 0, len(allDestinationPriorities))
+	for _, priority := range allDestinationPriorities {
+		names = append(names, string(priority))
+	}
+
+	return strings.Join(names, ", ")
+}
+
+// AppendModeForPriority returns the position of the existing destination contents among the sources, so the provided
+// conflict policy gives them the provided priority. With the error-on-conflict policy, any key found both in the
+// destination and in a source aborts the merge, so the destination is placed first (it is then copied at the OS level)
+func AppendModeForPriority(policy ConflictPolicy, priority DestinationPriority) (AppendMode, error) {
+	if !isValidConflictPolicy(policy) {
+		return NoAppend, fmt.Errorf("%w: %s, valid policies are: %s", errInvalidConflictPolicy, policy, AllConflictPoliciesNames())
+	}
+
+	switch priority {
+	case HighestPriority:
+		if policy == LastWins {
+			return AppendDestinationLast, nil
+		}
+	case LowestPriority:
+		if policy == FirstWins {
+			return AppendDestinationLast, nil
+		}
+	default:
+		return NoAppend, fmt.Errorf("%w: %s, valid priorities are: %s", errInvalidDestinationPriority, priority, AllDestinationPrioritiesNames())
+	}
+
+	return AppendDestinationFirst, nil
+}
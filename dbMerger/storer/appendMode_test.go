@@ -0,0 +1,52 @@
+package storer
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAppendModeForPriority(t *testing.T) {
+	t.Parallel()
+
+	t.Run("invalid conflict policy should error", func(t *testing.T) {
+		t.Parallel()
+
+		mode, err := AppendModeForPriority("invalid", HighestPriority)
+		assert.True(t, errors.Is(err, errInvalidConflictPolicy))
+		assert.Equal(t, NoAppend, mode)
+	})
+	t.Run("invalid priority should error", func(t *testing.T) {
+		t.Parallel()
+
+		mode, err := AppendModeForPriority(LastWins, "invalid")
+		assert.True(t, errors.Is(err, errInvalidDestinationPriority))
+		assert.Equal(t, NoAppend, mode)
+	})
+	t.Run("should place the destination so the policy gives it the priority", func(t *testing.T) {
+		t.Parallel()
+
+		expectedModes := map[ConflictPolicy]map[DestinationPriority]AppendMode{
+			FirstWins: {
+				HighestPriority: AppendDestinationFirst,
+				LowestPriority:  AppendDestinationLast,
+			},
+			LastWins: {
+				HighestPriority: AppendDestinationLast,
+				LowestPriority:  AppendDestinationFirst,
+			},
+			ErrorOnConflict: {
+				HighestPriority: AppendDestinationFirst,
+				LowestPriority:  AppendDestinationFirst,
+			},
+		}
+		for policy, modes := range expectedModes {
+			for priority, expectedMode := range modes {
+				mode, err := AppendModeForPriority(policy, priority)
+				assert.Nil(t, err)
+				assert.Equal(t, expectedMode, mode, "%s, %s", policy, priority)
+			}
+		}
+	})
+}
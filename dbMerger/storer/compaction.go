@@ -0,0 +1,83 @@
+package storer
+
+import (
+	"fmt"
+	"io/fs"
+	"path/filepath"
+
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/opt"
+	"github.com/syndtr/goleveldb/leveldb/util"
+)
+
+// CompactionResult holds the size of a DB directory before and after its compaction
+type CompactionResult struct {
+	SizeBeforeBytes int64
+	SizeAfterBytes  int64
+}
+
+// CompactLevelDBDirectory runs a full compaction of the level DB found in the provided directory, reclaiming the space
+// held by overwritten or deleted values. The directory must not be opened by a persister, since a level DB can only be
+// opened once. If the directory does not hold a level DB, ErrCompactionNotSupported is returned and nothing is changed
+func CompactLevelDBDirectory(path string) (CompactionResult, error) {
+	if !isLevelDBDirectory(path) {
+		return CompactionResult{}, fmt.Errorf("%w for directory %s", ErrCompactionNotSupported, path)
+	}
+
+	sizeBefore, err := directorySize(path)
+	if err != nil {
+		return CompactionResult{}, err
+	}
+
+	db, err := leveldb.OpenFile(path, &opt.Options{
+		ErrorIfMissing:         true,
+		OpenFilesCacheCapacity: maxOpenFiles,
+	})
+	if err != nil {
+		return CompactionResult{}, fmt.Errorf("%w while opening the directory %s for compaction", err, path)
+	}
+
+	err = db.CompactRange(util.Range{})
+	errClose := db.Close()
+	if err != nil {
+		return CompactionResult{}, fmt.Errorf("%w while compacting the directory %s", err, path)
+	}
+	if errClose != nil {
+		return CompactionResult{}, fmt.Errorf("%w while closing the compacted directory %s", errClose, path)
+	}
+
+	sizeAfter, err := directorySize(path)
+	if err != nil {
+		return CompactionResult{}, err
+	}
+
+	return CompactionResult{
+		SizeBeforeBytes: sizeBefore,
+		SizeAfterBytes:  sizeAfter,
+	}, nil
+}
+
+func directorySize(path string) (int64, error) {
+	size := int64(0)
+	err := filepath.WalkDir(path, func(_ string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			return nil
+		}
+
+		info, errInfo := entry.Info()
+		if errInfo != nil {
+			return errInfo
+		}
+		size += info.Size()
+
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("%w while computing the size of the directory %s", err, path)
+	}
+
+	return size, nil
+}
@@ -0,0 +1,60 @@
+package storer
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompactLevelDBDirectory(t *testing.T) {
+	t.Parallel()
+
+	t.Run("not a level DB directory should not compact", func(t *testing.T) {
+		t.Parallel()
+
+		dir := t.TempDir()
+		err := os.WriteFile(filepath.Join(dir, "file"), []byte("not a DB"), os.ModePerm)
+		assert.Nil(t, err)
+
+		_, err = CompactLevelDBDirectory(dir)
+		assert.True(t, errors.Is(err, ErrCompactionNotSupported))
+
+		entries, _ := os.ReadDir(dir)
+		assert.Equal(t, 1, len(entries))
+	})
+	t.Run("should compact and keep the data", func(t *testing.T) {
+		t.Parallel()
+
+		dir := t.TempDir()
+		persister, err := NewPersisterCreator().CreatePersister(dir)
+		assert.Nil(t, err)
+
+		value := make([]byte, 1024)
+		for round := 0; round < 5; round++ {
+			for i := 0; i < 1000; i++ {
+				err = persister.Put([]byte(fmt.Sprintf("key_%d", i)), append(value, byte(round)))
+				assert.Nil(t, err)
+			}
+		}
+		err = persister.Close()
+		assert.Nil(t, err)
+
+		result, err := CompactLevelDBDirectory(dir)
+		assert.Nil(t, err)
+		assert.True(t, result.SizeBeforeBytes > 0)
+		assert.True(t, result.SizeAfterBytes < result.SizeBeforeBytes)
+
+		persister, err = NewPersisterCreator().CreatePersister(dir)
+		assert.Nil(t, err)
+		for i := 0; i < 1000; i++ {
+			val, errGet := persister.Get([]byte(fmt.Sprintf("key_%d", i)))
+			assert.Nil(t, errGet)
+			assert.Equal(t, append(value, byte(4)), val)
+		}
+		_ = persister.Close()
+	})
+}
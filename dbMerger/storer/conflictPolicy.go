@@ -0,0 +1,37 @@
+package storer
+
+import "strings"
+
+// ConflictPolicy defines how a key already present in the destination persister is handled while merging
+type ConflictPolicy string
+
+const (
+	// FirstWins keeps the value that was written first in the destination persister
+	FirstWins ConflictPolicy = "first-wins"
+	// LastWins overwrites the existing value with the one found in the later source persister
+	LastWins ConflictPolicy = "last-wins"
+	// ErrorOnConflict aborts the merge when a key is found in more than one source persister
+	ErrorOnConflict ConflictPolicy = "error-on-conflict"
+)
+
+var allConflictPolicies = []ConflictPolicy{FirstWins, LastWins, ErrorOnConflict}
+
+// AllConflictPoliciesNames returns the names of all supported conflict policies, comma separated
+func AllConflictPoliciesNames() string {
+	names := make([]string, 0, len(allConflictPolicies))
+	for _, policy := range allConflictPolicies {
+		names = append(names, string(policy))
+	}
+
+	return strings.Join(names, ", ")
+}
+
+func isValidConflictPolicy(policy ConflictPolicy) bool {
+	for _, knownPolicy := range allConflictPolicies {
+		if policy == knownPolicy {
+			return true
+		}
+	}
+
+	return false
+}
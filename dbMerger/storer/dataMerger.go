@@ -1,40 +1,105 @@
 package storer
 
 import (
+	"bytes"
+	"encoding/hex"
 	"fmt"
 
 	"github.com/multiversx/mx-chain-core-go/core/check"
 	logger "github.com/multiversx/mx-chain-logger-go"
 	"github.com/multiversx/mx-chain-storage-go/types"
+	"github.com/multiversx/mx-chain-tools-go/dbmerger/exitcodes"
 )
 
 var log = logger.GetOrCreate("storer")
 
+const (
+	defaultProgressInterval = 100000
+	readBatchSize           = 256
+	numBufferedBatches      = 10
+)
+
+// ArgsDataMerger is the DTO used in the NewDataMergerWithArgs constructor function
+type ArgsDataMerger struct {
+	ConflictPolicy ConflictPolicy
+	NumWorkers     int
+	// KeyPrefix, if not empty, restricts the merge to the keys starting with it
+	KeyPrefix []byte
+}
+
 // dataMerger is able to copy key by key all values from the provided sources persisters into the destination persister
 type dataMerger struct {
+	progressInterval uint64
+	conflictPolicy   ConflictPolicy
+	numWorkers       int
+	keyPrefix        []byte
+}
+
+type keyValue struct {
+	key []byte
+	val []byte
 }
 
-// NewDataMerger returns a new instance of a data merger
+// NewDataMerger returns a new instance of a data merger that sequentially reads the sources and overwrites the keys
+// found in more than one source
 func NewDataMerger() *dataMerger {
-	return &dataMerger{}
+	return &dataMerger{
+		progressInterval: defaultProgressInterval,
+		conflictPolicy:   LastWins,
+		numWorkers:       1,
+	}
+}
+
+// NewDataMergerWithArgs returns a new instance of a data merger that handles the keys found in more than one source
+// as specified by the provided conflict policy. If more than one worker is provided, the sources will be read
+// concurrently, while the writes in the destination persister are still done in the order of the sources
+func NewDataMergerWithArgs(args ArgsDataMerger) (*dataMerger, error) {
+	if !isValidConflictPolicy(args.ConflictPolicy) {
+		return nil, fmt.Errorf("%w: %s, valid policies are: %s", errInvalidConflictPolicy, args.ConflictPolicy, AllConflictPoliciesNames())
+	}
+	if args.NumWorkers < 1 {
+		return nil, fmt.Errorf("%w: %d, minimum 1", errInvalidNumberOfWorkers, args.NumWorkers)
+	}
+
+	dm := NewDataMerger()
+	dm.conflictPolicy = args.ConflictPolicy
+	dm.numWorkers = args.NumWorkers
+	dm.keyPrefix = append([]byte{}, args.KeyPrefix...)
+
+	return dm, nil
 }
 
 // MergeDBs will iterate over all provided sources and take all key-value pairs and write them in the destination persister
 func (dm *dataMerger) MergeDBs(dest types.Persister, sources ...types.Persister) error {
+	return dm.MergeDBsWithProgress(dest, func(_ uint64) {}, sources...)
+}
+
+// MergeDBsWithProgress does the same thing as MergeDBs but also calls the onProgress handler each time another
+// batch of key-value pairs was copied. The handler receives the total number of key-value pairs copied so far
+func (dm *dataMerger) MergeDBsWithProgress(dest types.Persister, onProgress func(keysCopied uint64), sources ...types.Persister) error {
 	err := checkArgs(dest, sources...)
 	if err != nil {
 		return err
 	}
+	if onProgress == nil {
+		return errNilProgressHandler
+	}
 
-	numKeys := 0
-
-	for _, source := range sources {
-		copiedKeys, errMerge := mergeDB(dest, source)
-		if errMerge != nil {
-			return errMerge
+	numKeys := uint64(0)
+	handlePut := func() {
+		numKeys++
+		if numKeys%dm.progressInterval == 0 {
+			onProgress(numKeys)
 		}
+	}
 
-		numKeys += copiedKeys
+	if dm.numWorkers > 1 {
+		err = dm.mergeDBsInParallel(dest, handlePut, sources...)
+	} else {
+		err = dm.mergeDBsSequentially(dest, handlePut, sources...)
+	}
+	if err != nil {
+		return err
 	}
 
 	log.Debug("finished copying data",
@@ -56,20 +121,187 @@ func checkArgs(dest types.Persister, sources ...types.Persister) error {
 	return nil
 }
 
-func mergeDB(dest types.Persister, source types.Persister) (int, error) {
-	var foundErr error
-	numKeysCopied := 0
-	source.RangeKeys(func(key []byte, val []byte) bool {
-		numKeysCopied++
-		foundErr = dest.Put(key, val)
+func (dm *dataMerger) mergeDBsSequentially(dest types.Persister, handlePut func(), sources ...types.Persister) error {
+	for _, source := range sources {
+		var foundErr error
+		source.RangeKeys(func(key []byte, val []byte) bool {
+			foundErr = dm.writeKeyValue(dest, key, val, handlePut)
+			return foundErr == nil
+		})
 		if foundErr != nil {
+			return foundErr
+		}
+	}
+
+	return nil
+}
+
+// mergeDBsInParallel reads the sources using at most numWorkers goroutines. Each source has its own bounded channel
+// and the channels are consumed in the sources order, so the writes are done exactly as in the sequential case.
+// The workers are started in the sources order as well, so the source currently written always holds a worker slot
+func (dm *dataMerger) mergeDBsInParallel(dest types.Persister, handlePut func(), sources ...types.Persister) error {
+	done := make(chan struct{})
+	defer close(done)
+
+	channels := make([]chan []keyValue, len(sources))
+	for idx := range channels {
+		channels[idx] = make(chan []keyValue, numBufferedBatches)
+	}
+
+	go func() {
+		workersSlots := make(chan struct{}, dm.numWorkers)
+		for idx := range sources {
+			select {
+			case workersSlots <- struct{}{}:
+			case <-done:
+				return
+			}
+
+			go func(source types.Persister, ch chan []keyValue) {
+				readSource(source, ch, done)
+				<-workersSlots
+			}(sources[idx], channels[idx])
+		}
+	}()
+
+	for _, ch := range channels {
+		for batch := range ch {
+			for _, kv := range batch {
+				err := dm.writeKeyValue(dest, kv.key, kv.val, handlePut)
+				if err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+func readSource(source types.Persister, ch chan []keyValue, done chan struct{}) {
+	defer close(ch)
+
+	batch := make([]keyValue, 0, readBatchSize)
+	sendBatch := func() bool {
+		select {
+		case ch <- batch:
+			batch = make([]keyValue, 0, readBatchSize)
+			return true
+		case <-done:
 			return false
 		}
+	}
+
+	isDone := false
+	source.RangeKeys(func(key []byte, val []byte) bool {
+		// the iterator might reuse the provided buffers
+		batch = append(batch, keyValue{
+			key: append([]byte{}, key...),
+			val: append([]byte{}, val...),
+		})
+		if len(batch) < readBatchSize {
+			return true
+		}
 
-		return true
+		isDone = !sendBatch()
+		return !isDone
 	})
 
-	return numKeysCopied, foundErr
+	if !isDone && len(batch) > 0 {
+		sendBatch()
+	}
+}
+
+// HasKeyFilter returns true if only a part of the keys (the ones starting with the configured prefix) are merged
+func (dm *dataMerger) HasKeyFilter() bool {
+	return len(dm.keyPrefix) > 0
+}
+
+func (dm *dataMerger) isKeyIncluded(key []byte) bool {
+	return bytes.HasPrefix(key, dm.keyPrefix)
+}
+
+func (dm *dataMerger) writeKeyValue(dest types.Persister, key []byte, val []byte, handlePut func()) error {
+	if !dm.isKeyIncluded(key) {
+		return nil
+	}
+	if dm.conflictPolicy != LastWins && dest.Has(key) == nil {
+		if dm.conflictPolicy == ErrorOnConflict {
+			return exitcodes.NewIntegrityError(fmt.Errorf("%w for key %s", errKeyConflict, hex.EncodeToString(key)))
+		}
+
+		return nil
+	}
+
+	err := dest.Put(key, val)
+	if err != nil {
+		return err
+	}
+
+	handlePut()
+
+	return nil
+}
+
+// VerifyMerge re-reads every key found in the provided sources and checks that the destination persister holds the
+// value of the source chosen by the configured conflict policy. The sources should be provided in the same order used
+// when merging, including the ones copied at the OS level. It returns the number of verified keys and the hex-encoded
+// keys that are missing or hold a different value in the destination persister
+func (dm *dataMerger) VerifyMerge(dest types.Persister, sources ...types.Persister) (uint64, []string, error) {
+	err := checkArgs(dest, sources...)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	numVerifiedKeys := uint64(0)
+	mismatchedKeys := make([]string, 0)
+	for idx := range sources {
+		sources[idx].RangeKeys(func(key []byte, val []byte) bool {
+			if !dm.isKeyIncluded(key) {
+				return true
+			}
+			if !dm.isWinningSource(key, sources, idx) {
+				return true
+			}
+
+			destVal, errGet := dest.Get(key)
+			if errGet != nil || !bytes.Equal(destVal, val) {
+				mismatchedKeys = append(mismatchedKeys, hex.EncodeToString(key))
+				return true
+			}
+
+			numVerifiedKeys++
+
+			return true
+		})
+	}
+
+	log.Debug("finished verifying data",
+		"num source persisters", len(sources), "num key-values verified", numVerifiedKeys,
+		"num mismatches", len(mismatchedKeys))
+
+	return numVerifiedKeys, mismatchedKeys, nil
+}
+
+// isWinningSource returns true if the value of the key found in the source with the provided index is the one
+// that should have been written in the destination persister
+func (dm *dataMerger) isWinningSource(key []byte, sources []types.Persister, sourceIndex int) bool {
+	switch dm.conflictPolicy {
+	case FirstWins:
+		for i := 0; i < sourceIndex; i++ {
+			if sources[i].Has(key) == nil {
+				return false
+			}
+		}
+	case LastWins:
+		for i := sourceIndex + 1; i < len(sources); i++ {
+			if sources[i].Has(key) == nil {
+				return false
+			}
+		}
+	}
+
+	return true
 }
 
 // IsInterfaceNil returns true if there is no value under the interface
@@ -0,0 +1,95 @@
+package storer
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/multiversx/mx-chain-storage-go/types"
+	"github.com/multiversx/mx-chain-tools-go/dbmerger/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+func createOverlappingSources(numSources int, numKeysPerSource int) []types.Persister {
+	sources := make([]types.Persister, 0, numSources)
+	for i := 0; i < numSources; i++ {
+		data := make(map[string]string)
+		// each source shares half of its keys with the next one
+		for j := 0; j < numKeysPerSource; j++ {
+			key := fmt.Sprintf("key%d", i*numKeysPerSource/2+j)
+			data[key] = fmt.Sprintf("val%d-source%d", j, i)
+		}
+
+		sources = append(sources, createPersisterMock(data))
+	}
+
+	return sources
+}
+
+func getAllData(persister types.Persister) map[string]string {
+	data := make(map[string]string)
+	persister.RangeKeys(func(key []byte, val []byte) bool {
+		data[string(key)] = string(val)
+		return true
+	})
+
+	return data
+}
+
+func TestMergeDBsInParallel(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should write the same data as the sequential merge", func(t *testing.T) {
+		t.Parallel()
+
+		for _, policy := range []ConflictPolicy{FirstWins, LastWins} {
+			sources := createOverlappingSources(5, 3*readBatchSize+7)
+
+			sequentialDest := mock.NewPersisterMock()
+			dm, _ := NewDataMergerWithArgs(ArgsDataMerger{ConflictPolicy: policy, NumWorkers: 1})
+			err := dm.MergeDBs(sequentialDest, sources...)
+			assert.Nil(t, err)
+
+			parallelDest := mock.NewPersisterMock()
+			dm, _ = NewDataMergerWithArgs(ArgsDataMerger{ConflictPolicy: policy, NumWorkers: 3})
+			err = dm.MergeDBs(parallelDest, sources...)
+			assert.Nil(t, err)
+
+			assert.Equal(t, getAllData(sequentialDest), getAllData(parallelDest), string(policy))
+		}
+	})
+	t.Run("more workers than sources should work", func(t *testing.T) {
+		t.Parallel()
+
+		sources := createOverlappingSources(2, 10)
+
+		dest := mock.NewPersisterMock()
+		dm, _ := NewDataMergerWithArgs(ArgsDataMerger{ConflictPolicy: LastWins, NumWorkers: 10})
+		err := dm.MergeDBs(dest, sources...)
+		assert.Nil(t, err)
+		assert.Equal(t, 15, len(getAllData(dest)))
+	})
+	t.Run("conflict should error", func(t *testing.T) {
+		t.Parallel()
+
+		sources := createOverlappingSources(5, 3*readBatchSize)
+
+		dm, _ := NewDataMergerWithArgs(ArgsDataMerger{ConflictPolicy: ErrorOnConflict, NumWorkers: 2})
+		err := dm.MergeDBs(mock.NewPersisterMock(), sources...)
+		assert.True(t, errors.Is(err, errKeyConflict))
+	})
+	t.Run("put errors, should error", func(t *testing.T) {
+		t.Parallel()
+
+		expectedErr := errors.New("expected error")
+		sources := createOverlappingSources(5, 3*readBatchSize)
+
+		dm, _ := NewDataMergerWithArgs(ArgsDataMerger{ConflictPolicy: LastWins, NumWorkers: 2})
+		err := dm.MergeDBs(&mock.PersisterStub{
+			PutCalled: func(key, val []byte) error {
+				return expectedErr
+			},
+		}, sources...)
+		assert.Equal(t, expectedErr, err)
+	})
+}
@@ -1,11 +1,14 @@
 package storer
 
 import (
+	"encoding/hex"
 	"errors"
+	"fmt"
 	"strings"
 	"testing"
 
 	"github.com/multiversx/mx-chain-core-go/core/check"
+	"github.com/multiversx/mx-chain-storage-go/types"
 	"github.com/multiversx/mx-chain-tools-go/dbmerger/mock"
 	"github.com/stretchr/testify/assert"
 )
@@ -112,11 +115,270 @@ func TestMergeDBs(t *testing.T) {
 	})
 }
 
+func TestMergeDBsWithProgress(t *testing.T) {
+	t.Parallel()
+
+	t.Run("nil progress handler should error", func(t *testing.T) {
+		t.Parallel()
+
+		dm := NewDataMerger()
+		err := dm.MergeDBsWithProgress(&mock.PersisterStub{}, nil, &mock.PersisterStub{})
+		assert.Equal(t, errNilProgressHandler, err)
+	})
+	t.Run("should call the progress handler every interval", func(t *testing.T) {
+		t.Parallel()
+
+		src1 := make(map[string]string)
+		for i := 0; i < 13; i++ {
+			src1[fmt.Sprintf("key%d", i)] = "val"
+		}
+		src2 := make(map[string]string)
+		for i := 13; i < 25; i++ {
+			src2[fmt.Sprintf("key%d", i)] = "val"
+		}
+
+		reportedKeys := make([]uint64, 0)
+		dm := NewDataMerger()
+		dm.progressInterval = 10
+		err := dm.MergeDBsWithProgress(&mock.PersisterStub{},
+			func(keysCopied uint64) {
+				reportedKeys = append(reportedKeys, keysCopied)
+			},
+			createPersisterStub(src1),
+			createPersisterStub(src2),
+		)
+
+		assert.Nil(t, err)
+		assert.Equal(t, []uint64{10, 20}, reportedKeys)
+	})
+	t.Run("put errors should not report the failed key", func(t *testing.T) {
+		t.Parallel()
+
+		expectedErr := errors.New("expected error")
+		dm := NewDataMerger()
+		dm.progressInterval = 1
+		err := dm.MergeDBsWithProgress(&mock.PersisterStub{
+			PutCalled: func(key, val []byte) error {
+				return expectedErr
+			},
+		},
+			func(keysCopied uint64) {
+				assert.Fail(t, "should have not called the progress handler")
+			},
+			createPersisterStub(map[string]string{"key1": "val1"}),
+		)
+
+		assert.Equal(t, expectedErr, err)
+	})
+}
+
+func TestNewDataMergerWithArgs(t *testing.T) {
+	t.Parallel()
+
+	t.Run("invalid policy should error", func(t *testing.T) {
+		t.Parallel()
+
+		dm, err := NewDataMergerWithArgs(ArgsDataMerger{ConflictPolicy: "invalid", NumWorkers: 1})
+		assert.True(t, check.IfNil(dm))
+		assert.True(t, errors.Is(err, errInvalidConflictPolicy))
+		assert.True(t, strings.Contains(err.Error(), AllConflictPoliciesNames()))
+	})
+	t.Run("invalid number of workers should error", func(t *testing.T) {
+		t.Parallel()
+
+		dm, err := NewDataMergerWithArgs(ArgsDataMerger{ConflictPolicy: LastWins, NumWorkers: 0})
+		assert.True(t, check.IfNil(dm))
+		assert.True(t, errors.Is(err, errInvalidNumberOfWorkers))
+	})
+	t.Run("should work", func(t *testing.T) {
+		t.Parallel()
+
+		for _, policy := range allConflictPolicies {
+			dm, err := NewDataMergerWithArgs(ArgsDataMerger{ConflictPolicy: policy, NumWorkers: 1})
+			assert.Nil(t, err)
+			assert.False(t, check.IfNil(dm))
+		}
+	})
+}
+
+func TestMergeDBsWithOverlappingKeys(t *testing.T) {
+	t.Parallel()
+
+	src1 := map[string]string{
+		"key1": "val1",
+		"key2": "val2",
+	}
+	src2 := map[string]string{
+		"key2": "val2-overwritten",
+		"key3": "val3",
+	}
+
+	t.Run("first wins should keep the first value", func(t *testing.T) {
+		t.Parallel()
+
+		dest := mock.NewPersisterMock()
+		dm, _ := NewDataMergerWithArgs(ArgsDataMerger{ConflictPolicy: FirstWins, NumWorkers: 1})
+		err := dm.MergeDBs(dest, createPersisterStub(src1), createPersisterStub(src2))
+		assert.Nil(t, err)
+
+		checkPersisterContains(t, dest, map[string]string{
+			"key1": "val1",
+			"key2": "val2",
+			"key3": "val3",
+		})
+	})
+	t.Run("last wins should keep the last value", func(t *testing.T) {
+		t.Parallel()
+
+		dest := mock.NewPersisterMock()
+		dm, _ := NewDataMergerWithArgs(ArgsDataMerger{ConflictPolicy: LastWins, NumWorkers: 1})
+		err := dm.MergeDBs(dest, createPersisterStub(src1), createPersisterStub(src2))
+		assert.Nil(t, err)
+
+		checkPersisterContains(t, dest, map[string]string{
+			"key1": "val1",
+			"key2": "val2-overwritten",
+			"key3": "val3",
+		})
+	})
+	t.Run("error on conflict should error", func(t *testing.T) {
+		t.Parallel()
+
+		dest := mock.NewPersisterMock()
+		dm, _ := NewDataMergerWithArgs(ArgsDataMerger{ConflictPolicy: ErrorOnConflict, NumWorkers: 1})
+		err := dm.MergeDBs(dest, createPersisterStub(src1), createPersisterStub(src2))
+		assert.True(t, errors.Is(err, errKeyConflict))
+		assert.True(t, strings.Contains(err.Error(), hex.EncodeToString([]byte("key2"))))
+	})
+	t.Run("error on conflict without overlapping keys should work", func(t *testing.T) {
+		t.Parallel()
+
+		dest := mock.NewPersisterMock()
+		dm, _ := NewDataMergerWithArgs(ArgsDataMerger{ConflictPolicy: ErrorOnConflict, NumWorkers: 1})
+		err := dm.MergeDBs(dest, createPersisterStub(src1), createPersisterStub(map[string]string{"key3": "val3"}))
+		assert.Nil(t, err)
+
+		checkPersisterContains(t, dest, map[string]string{
+			"key1": "val1",
+			"key2": "val2",
+			"key3": "val3",
+		})
+	})
+}
+
+func TestMergeDBsWithKeyPrefix(t *testing.T) {
+	t.Parallel()
+
+	src1 := map[string]string{
+		"prefix_key1": "val1",
+		"other_key2":  "val2",
+	}
+	src2 := map[string]string{
+		"prefix_key3": "val3",
+		"key4":        "val4",
+	}
+
+	for _, numWorkers := range []int{1, 2} {
+		dest := mock.NewPersisterMock()
+		dm, _ := NewDataMergerWithArgs(ArgsDataMerger{ConflictPolicy: LastWins, NumWorkers: numWorkers, KeyPrefix: []byte("prefix_")})
+		assert.True(t, dm.HasKeyFilter())
+		err := dm.MergeDBs(dest, createPersisterMock(src1), createPersisterMock(src2))
+		assert.Nil(t, err)
+
+		checkPersisterContains(t, dest, map[string]string{
+			"prefix_key1": "val1",
+			"prefix_key3": "val3",
+		})
+		assert.NotNil(t, dest.Has([]byte("other_key2")))
+		assert.NotNil(t, dest.Has([]byte("key4")))
+
+		numVerifiedKeys, mismatchedKeys, err := dm.VerifyMerge(dest, createPersisterMock(src1), createPersisterMock(src2))
+		assert.Nil(t, err)
+		assert.Equal(t, uint64(2), numVerifiedKeys)
+		assert.Empty(t, mismatchedKeys)
+	}
+
+	dm, _ := NewDataMergerWithArgs(ArgsDataMerger{ConflictPolicy: LastWins, NumWorkers: 1})
+	assert.False(t, dm.HasKeyFilter())
+}
+
+func TestVerifyMerge(t *testing.T) {
+	t.Parallel()
+
+	src1 := map[string]string{
+		"key1": "val1",
+		"key2": "val2",
+	}
+	src2 := map[string]string{
+		"key2": "val2-overwritten",
+		"key3": "val3",
+	}
+
+	t.Run("nil destination should error", func(t *testing.T) {
+		t.Parallel()
+
+		dm := NewDataMerger()
+		_, _, err := dm.VerifyMerge(nil)
+		assert.True(t, errors.Is(err, errNilPersister))
+	})
+	t.Run("merged data should verify for all policies", func(t *testing.T) {
+		t.Parallel()
+
+		for _, policy := range []ConflictPolicy{FirstWins, LastWins} {
+			dest := mock.NewPersisterMock()
+			dm, _ := NewDataMergerWithArgs(ArgsDataMerger{ConflictPolicy: policy, NumWorkers: 1})
+			err := dm.MergeDBs(dest, createPersisterMock(src1), createPersisterMock(src2))
+			assert.Nil(t, err)
+
+			numVerifiedKeys, mismatchedKeys, err := dm.VerifyMerge(dest, createPersisterMock(src1), createPersisterMock(src2))
+			assert.Nil(t, err)
+			assert.Equal(t, uint64(3), numVerifiedKeys, string(policy))
+			assert.Empty(t, mismatchedKeys, string(policy))
+		}
+	})
+	t.Run("corrupted or missing data should be reported", func(t *testing.T) {
+		t.Parallel()
+
+		dest := mock.NewPersisterMock()
+		dm := NewDataMerger()
+		err := dm.MergeDBs(dest, createPersisterMock(src1), createPersisterMock(src2))
+		assert.Nil(t, err)
+
+		_ = dest.Put([]byte("key1"), []byte("corrupted"))
+		_ = dest.Remove([]byte("key3"))
+
+		numVerifiedKeys, mismatchedKeys, err := dm.VerifyMerge(dest, createPersisterMock(src1), createPersisterMock(src2))
+		assert.Nil(t, err)
+		assert.Equal(t, uint64(1), numVerifiedKeys)
+		assert.ElementsMatch(t, []string{hex.EncodeToString([]byte("key1")), hex.EncodeToString([]byte("key3"))}, mismatchedKeys)
+	})
+}
+
+func createPersisterMock(data map[string]string) types.Persister {
+	persister := mock.NewPersisterMock()
+	for key, val := range data {
+		_ = persister.Put([]byte(key), []byte(val))
+	}
+
+	return persister
+}
+
+func checkPersisterContains(tb testing.TB, persister types.Persister, expected map[string]string) {
+	for key, val := range expected {
+		recovered, err := persister.Get([]byte(key))
+		assert.Nil(tb, err)
+		assert.Equal(tb, val, string(recovered))
+	}
+}
+
 func createPersisterStub(rangeMap map[string]string) *mock.PersisterStub {
 	return &mock.PersisterStub{
 		RangeKeysCalled: func(handler func(key []byte, val []byte) bool) {
 			for key, val := range rangeMap {
-				handler([]byte(key), []byte(val))
+				shouldContinue := handler([]byte(key), []byte(val))
+				if !shouldContinue {
+					return
+				}
 			}
 		},
 	}
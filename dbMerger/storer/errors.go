@@ -5,3 +5,19 @@ import "errors"
 var errNilPersister = errors.New("nil persister")
 var errInvalidNumberOfPersisters = errors.New("invalid number of persisters")
 var errNilComponent = errors.New("nil component")
+var errNilProgressHandler = errors.New("nil progress handler")
+var errInvalidConflictPolicy = errors.New("invalid conflict policy")
+var errKeyConflict = errors.New("key conflict")
+var errInvalidNumberOfWorkers = errors.New("invalid number of workers")
+var errInvalidPersisterType = errors.New("invalid persister type")
+var errUnknownPersisterType = errors.New("can not determine the persister type")
+var errInvalidOpenRetryDelay = errors.New("invalid open retry delay")
+var errInvalidOpenTimeout = errors.New("invalid open timeout")
+var errDatabaseInUse = errors.New("database appears to be in use")
+var errInvalidDestinationPriority = errors.New("invalid destination priority")
+var errInvalidAppendMode = errors.New("invalid append mode")
+var errAppendRequiresAtomic = errors.New("appending requires the atomic merge")
+var errAppendLastWithKeyFilter = errors.New("the destination can not be appended as the last source when merging only a part of the keys")
+
+// ErrCompactionNotSupported signals that the DB directory can not be compacted
+var ErrCompactionNotSupported = errors.New("compaction not supported")
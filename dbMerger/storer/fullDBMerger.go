@@ -2,6 +2,7 @@ package storer
 
 import (
 	"fmt"
+	"path/filepath"
 
 	"github.com/multiversx/mx-chain-core-go/core/check"
 	"github.com/multiversx/mx-chain-go/storage"
@@ -9,18 +10,31 @@ import (
 )
 
 const minNumOfPersisters = 2
+const temporaryDestinationSuffix = ".merging"
+const previousDestinationSuffix = ".previous"
 
 // ArgsFullDBMerger is the DTO used in the NewFullDBMerger constructor function
 type ArgsFullDBMerger struct {
 	DataMergerInstance  DataMerger
 	PersisterCreator    PersisterCreator
 	OsOperationsHandler OsOperationsHandler
+	ProgressHandler     func(keysCopied uint64)
+	// Atomic makes the merge write into a temporary destination, moved in place of the destination only on success
+	Atomic bool
+	// AppendMode, if set, merges the existing destination contents as well, as an implicit source. Requires Atomic
+	AppendMode AppendMode
+	// MergeEstimator is optional, it is only required by EstimateMerge
+	MergeEstimator MergeEstimator
 }
 
 type fullDBMerger struct {
 	dataMergerInstance  DataMerger
 	persisterCreator    PersisterCreator
 	osOperationsHandler OsOperationsHandler
+	progressHandler     func(keysCopied uint64)
+	atomic              bool
+	appendMode          AppendMode
+	mergeEstimator      MergeEstimator
 }
 
 // NewFullDBMerger creates a new instance of type fullDBMerger
@@ -35,15 +49,37 @@ func NewFullDBMerger(args ArgsFullDBMerger) (*fullDBMerger, error) {
 		return nil, fmt.Errorf("%w, OsOperationsHandler", errNilComponent)
 	}
 
+	switch args.AppendMode {
+	case NoAppend, AppendDestinationFirst, AppendDestinationLast:
+	default:
+		return nil, fmt.Errorf("%w: %s", errInvalidAppendMode, args.AppendMode)
+	}
+	if args.AppendMode != NoAppend && !args.Atomic {
+		return nil, errAppendRequiresAtomic
+	}
+
+	progressHandler := args.ProgressHandler
+	if progressHandler == nil {
+		progressHandler = func(_ uint64) {}
+	}
+
 	return &fullDBMerger{
 		dataMergerInstance:  args.DataMergerInstance,
 		persisterCreator:    args.PersisterCreator,
 		osOperationsHandler: args.OsOperationsHandler,
+		progressHandler:     progressHandler,
+		atomic:              args.Atomic,
+		appendMode:          args.AppendMode,
+		mergeEstimator:      args.MergeEstimator,
 	}, nil
 }
 
 // MergeDBs will merge all data from the source persister paths into a new storage persister
 func (fdm *fullDBMerger) MergeDBs(destinationPath string, sourcePaths ...string) (storage.Persister, error) {
+	if fdm.appendMode != NoAppend {
+		return fdm.mergeDBsIntoExisting(destinationPath, sourcePaths...)
+	}
+
 	if len(sourcePaths) < minNumOfPersisters {
 		return nil, fmt.Errorf("%w, provided %d, minimum %d", errInvalidNumberOfPersisters, len(sourcePaths), minNumOfPersisters)
 	}
@@ -53,37 +89,188 @@ func (fdm *fullDBMerger) MergeDBs(destinationPath string, sourcePaths ...string)
 		return nil, err
 	}
 
-	err = fdm.osOperationsHandler.CopyDirectory(destinationPath, sourcePaths[0])
+	if !fdm.atomic {
+		return fdm.mergeDBsInto(destinationPath, sourcePaths...)
+	}
+
+	return fdm.mergeDBsAtomically(destinationPath, sourcePaths...)
+}
+
+// mergeDBsIntoExisting merges the existing destination contents, as the first or the last source, along with the
+// provided sources. The result is written in the temporary destination, which replaces the destination on success
+func (fdm *fullDBMerger) mergeDBsIntoExisting(destinationPath string, sourcePaths ...string) (storage.Persister, error) {
+	if len(sourcePaths)+1 < minNumOfPersisters {
+		return nil, fmt.Errorf("%w, provided %d plus the destination, minimum %d", errInvalidNumberOfPersisters, len(sourcePaths), minNumOfPersisters)
+	}
+	if fdm.appendMode == AppendDestinationLast && fdm.dataMergerInstance.HasKeyFilter() {
+		return nil, errAppendLastWithKeyFilter
+	}
+
+	log.Debug("appending into the existing destination", "destination", destinationPath, "mode", fdm.appendMode)
+
+	return fdm.mergeDBsAtomically(destinationPath, fdm.withDestinationAsSource(destinationPath, sourcePaths...)...)
+}
+
+// withDestinationAsSource returns the source paths along with the destination path, placed as required by the
+// append mode
+func (fdm *fullDBMerger) withDestinationAsSource(destinationPath string, sourcePaths ...string) []string {
+	allSourcePaths := make([]string, 0, len(sourcePaths)+1)
+	if fdm.appendMode == AppendDestinationFirst {
+		allSourcePaths = append(allSourcePaths, destinationPath)
+		return append(allSourcePaths, sourcePaths...)
+	}
+
+	allSourcePaths = append(allSourcePaths, sourcePaths...)
+	return append(allSourcePaths, destinationPath)
+}
+
+// mergeDBsAtomically merges the sources into a temporary destination that is moved in place of the destination
+// only after the merge succeeded. On failure, the temporary destination is removed and the destination is left untouched
+func (fdm *fullDBMerger) mergeDBsAtomically(destinationPath string, sourcePaths ...string) (storage.Persister, error) {
+	tempPath := TemporaryDestinationPath(destinationPath)
+	err := fdm.osOperationsHandler.CreateDirectory(tempPath)
+	if err != nil {
+		return nil, fmt.Errorf("%w, a previous merge might have left it behind", err)
+	}
+
+	tempPersister, err := fdm.mergeDBsInto(tempPath, sourcePaths...)
 	if err != nil {
+		fdm.removeTemporaryDestination(tempPath)
 		return nil, err
 	}
 
+	err = tempPersister.Close()
+	if err != nil {
+		fdm.removeTemporaryDestination(tempPath)
+		return nil, fmt.Errorf("%w while closing the temporary destination persister", err)
+	}
+
+	if fdm.appendMode != NoAppend {
+		err = fdm.replaceExistingDestination(destinationPath, tempPath)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		err = fdm.osOperationsHandler.MoveDirectory(destinationPath, tempPath)
+		if err != nil {
+			fdm.removeTemporaryDestination(tempPath)
+			return nil, err
+		}
+	}
+
+	destPersister, err := fdm.persisterCreator.CreatePersister(destinationPath)
+	if err != nil {
+		return nil, fmt.Errorf("%w for destination persister", err)
+	}
+
+	return destPersister, nil
+}
+
+// replaceExistingDestination moves the existing destination aside before moving the temporary destination in its
+// place, so at any time at least one of them holds all the data
+func (fdm *fullDBMerger) replaceExistingDestination(destinationPath string, tempPath string) error {
+	previousPath := filepath.Clean(destinationPath) + previousDestinationSuffix
+	err := fdm.osOperationsHandler.MoveDirectory(previousPath, destinationPath)
+	if err != nil {
+		fdm.removeTemporaryDestination(tempPath)
+		return fmt.Errorf("%w while moving the existing destination aside", err)
+	}
+
+	err = fdm.osOperationsHandler.MoveDirectory(destinationPath, tempPath)
+	if err != nil {
+		log.Error("can not move the merged data in place of the destination, the previous destination contents are kept aside",
+			"merged data", tempPath, "previous destination", previousPath, "error", err)
+		return err
+	}
+
+	err = fdm.osOperationsHandler.RemoveDirectory(previousPath)
+	if err != nil {
+		log.Error("can not remove the previous destination contents, remove them manually", "path", previousPath, "error", err)
+	}
+
+	return nil
+}
+
+func (fdm *fullDBMerger) removeTemporaryDestination(tempPath string) {
+	err := fdm.osOperationsHandler.RemoveDirectory(tempPath)
+	if err != nil {
+		log.Error("can not remove the temporary destination, remove it manually", "path", tempPath, "error", err)
+	}
+}
+
+func (fdm *fullDBMerger) mergeDBsInto(destinationPath string, sourcePaths ...string) (storage.Persister, error) {
+	// when only a part of the keys are merged, the first source can not be copied at the OS level, unless it is the
+	// existing destination, whose contents are all kept
+	startIndex := 0
+	if !fdm.dataMergerInstance.HasKeyFilter() || fdm.appendMode == AppendDestinationFirst {
+		err := fdm.osOperationsHandler.CopyDirectory(destinationPath, sourcePaths[0])
+		if err != nil {
+			return nil, err
+		}
+
+		startIndex = 1
+	}
+
 	destPersister, err := fdm.persisterCreator.CreatePersister(destinationPath)
 	if err != nil {
 		return nil, fmt.Errorf("%w for destination persister", err)
 	}
 
-	sourcePersisters, err := fdm.createSourcePersisters(sourcePaths...)
+	sourcePersisters, err := fdm.createSourcePersisters(startIndex, sourcePaths...)
 	if err != nil {
+		_ = destPersister.Close()
 		return nil, err
 	}
 
-	err = fdm.dataMergerInstance.MergeDBs(destPersister, sourcePersisters...)
+	err = fdm.dataMergerInstance.MergeDBsWithProgress(destPersister, fdm.progressHandler, sourcePersisters...)
 	if err != nil {
+		_ = fdm.closeSourcePersisters(sourcePersisters)
+		_ = destPersister.Close()
 		return nil, err
 	}
 
 	err = fdm.closeSourcePersisters(sourcePersisters)
 	if err != nil {
+		_ = destPersister.Close()
 		return nil, err
 	}
 
 	return destPersister, nil
 }
 
-func (fdm *fullDBMerger) createSourcePersisters(sourcePaths ...string) ([]types.Persister, error) {
-	sourcePersisters := make([]types.Persister, 0, len(sourcePaths)-1)
-	for i := 1; i < len(sourcePaths); i++ {
+// TemporaryDestinationPath returns the path used as temporary destination by the atomic merge
+func TemporaryDestinationPath(destinationPath string) string {
+	return filepath.Clean(destinationPath) + temporaryDestinationSuffix
+}
+
+// VerifyMerge will re-open all the source persister paths, including the one copied at the OS level, and will check
+// that the destination persister holds the expected values. It returns the number of verified keys and the hex-encoded
+// mismatched keys
+func (fdm *fullDBMerger) VerifyMerge(destPersister storage.Persister, sourcePaths ...string) (uint64, []string, error) {
+	if check.IfNil(destPersister) {
+		return 0, nil, fmt.Errorf("%w for the destination persister", errNilPersister)
+	}
+
+	sourcePersisters, err := fdm.createSourcePersisters(0, sourcePaths...)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	numVerifiedKeys, mismatchedKeys, err := fdm.dataMergerInstance.VerifyMerge(destPersister, sourcePersisters...)
+	errClose := fdm.closeSourcePersisters(sourcePersisters)
+	if err != nil {
+		return 0, nil, err
+	}
+	if errClose != nil {
+		return 0, nil, errClose
+	}
+
+	return numVerifiedKeys, mismatchedKeys, nil
+}
+
+func (fdm *fullDBMerger) createSourcePersisters(startIndex int, sourcePaths ...string) ([]types.Persister, error) {
+	sourcePersisters := make([]types.Persister, 0, len(sourcePaths)-startIndex)
+	for i := startIndex; i < len(sourcePaths); i++ {
 		srcPersister, errPersister := fdm.persisterCreator.CreatePersister(sourcePaths[i])
 		if errPersister != nil {
 			return nil, fmt.Errorf("%w for source persister with index %d", errPersister, i)
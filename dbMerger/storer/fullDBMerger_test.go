@@ -55,6 +55,27 @@ func TestNewFullDBMerger(t *testing.T) {
 		assert.True(t, errors.Is(err, errNilComponent))
 		assert.True(t, strings.Contains(err.Error(), "OsOperationsHandler"))
 	})
+	t.Run("invalid append mode", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgsFullDBMerger()
+		args.Atomic = true
+		args.AppendMode = "invalid"
+		merger, err := NewFullDBMerger(args)
+
+		assert.True(t, check.IfNil(merger))
+		assert.True(t, errors.Is(err, errInvalidAppendMode))
+	})
+	t.Run("append without atomic", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgsFullDBMerger()
+		args.AppendMode = AppendDestinationFirst
+		merger, err := NewFullDBMerger(args)
+
+		assert.True(t, check.IfNil(merger))
+		assert.Equal(t, errAppendRequiresAtomic, err)
+	})
 	t.Run("should work", func(t *testing.T) {
 		t.Parallel()
 
@@ -170,7 +191,7 @@ func TestDataMerger_MergeDBs(t *testing.T) {
 			},
 		}
 		args.DataMergerInstance = &mock.DataMergerStub{
-			MergeDBsCalled: func(dest types.Persister, sources ...types.Persister) error {
+			MergeDBsWithProgressCalled: func(dest types.Persister, onProgress func(keysCopied uint64), sources ...types.Persister) error {
 				return expectedErr
 			},
 		}
@@ -208,9 +229,10 @@ func TestDataMerger_MergeDBs(t *testing.T) {
 			},
 		}
 		args.DataMergerInstance = &mock.DataMergerStub{
-			MergeDBsCalled: func(dest types.Persister, sources ...types.Persister) error {
+			MergeDBsWithProgressCalled: func(dest types.Persister, onProgress func(keysCopied uint64), sources ...types.Persister) error {
 				assert.Equal(t, 2, len(sources))
 				assert.False(t, check.IfNil(dest))
+				assert.NotNil(t, onProgress)
 				mergeDBCalled = true
 
 				return nil
@@ -226,4 +248,404 @@ func TestDataMerger_MergeDBs(t *testing.T) {
 		assert.True(t, mergeDBCalled)
 		assert.Equal(t, 2, numClosedPersisters) // 3 sources, 1 copied, 2 opened to copy key by key
 	})
+	t.Run("with key filter should not copy the first source at the OS level", func(t *testing.T) {
+		t.Parallel()
+
+		numPersistersCreated := 0
+		args := createMockArgsFullDBMerger()
+		args.OsOperationsHandler = &mock.OsOperationsHandlerStub{
+			CopyDirectoryCalled: func(destination string, source string) error {
+				assert.Fail(t, "should have not called CopyDirectory")
+
+				return nil
+			},
+		}
+		args.PersisterCreator = &mock.PersisterCreatorStub{
+			CreatePersisterCalled: func(path string) (types.Persister, error) {
+				numPersistersCreated++
+				return mock.NewPersisterMock(), nil
+			},
+		}
+		args.DataMergerInstance = &mock.DataMergerStub{
+			HasKeyFilterCalled: func() bool {
+				return true
+			},
+			MergeDBsWithProgressCalled: func(dest types.Persister, onProgress func(keysCopied uint64), sources ...types.Persister) error {
+				assert.Equal(t, 3, len(sources))
+
+				return nil
+			},
+		}
+		merger, _ := NewFullDBMerger(args)
+
+		destPersister, err := merger.MergeDBs("dest", "src1", "src2", "src3")
+		assert.False(t, check.IfNil(destPersister))
+		assert.Nil(t, err)
+		assert.Equal(t, 4, numPersistersCreated)
+	})
+	t.Run("should forward the progress handler", func(t *testing.T) {
+		t.Parallel()
+
+		reportedKeys := make([]uint64, 0)
+		args := createMockArgsFullDBMerger()
+		args.PersisterCreator = &mock.PersisterCreatorStub{
+			CreatePersisterCalled: func(path string) (types.Persister, error) {
+				return mock.NewPersisterMock(), nil
+			},
+		}
+		args.DataMergerInstance = &mock.DataMergerStub{
+			MergeDBsWithProgressCalled: func(dest types.Persister, onProgress func(keysCopied uint64), sources ...types.Persister) error {
+				onProgress(10)
+				onProgress(20)
+
+				return nil
+			},
+		}
+		args.ProgressHandler = func(keysCopied uint64) {
+			reportedKeys = append(reportedKeys, keysCopied)
+		}
+		merger, _ := NewFullDBMerger(args)
+
+		_, err := merger.MergeDBs("dest", "src1", "src2")
+		assert.Nil(t, err)
+		assert.Equal(t, []uint64{10, 20}, reportedKeys)
+	})
+}
+
+func TestFullDBMerger_MergeDBsAtomic(t *testing.T) {
+	t.Parallel()
+
+	t.Run("temporary directory creation errors", func(t *testing.T) {
+		t.Parallel()
+
+		expectedErr := errors.New("expected error")
+		args := createMockArgsFullDBMerger()
+		args.Atomic = true
+		args.OsOperationsHandler = &mock.OsOperationsHandlerStub{
+			CreateDirectoryCalled: func(directory string) error {
+				return expectedErr
+			},
+			CopyDirectoryCalled: func(destination string, source string) error {
+				assert.Fail(t, "should have not called CopyDirectory")
+
+				return nil
+			},
+			RemoveDirectoryCalled: func(directory string) error {
+				assert.Fail(t, "should have not called RemoveDirectory")
+
+				return nil
+			},
+		}
+		merger, _ := NewFullDBMerger(args)
+
+		destPersister, err := merger.MergeDBs("dest", "src1", "src2")
+		assert.True(t, check.IfNil(destPersister))
+		assert.True(t, errors.Is(err, expectedErr))
+	})
+	t.Run("merge errors should remove the temporary directory", func(t *testing.T) {
+		t.Parallel()
+
+		expectedErr := errors.New("expected error")
+		numClosedPersisters := 0
+		removedDirectory := ""
+		args := createMockArgsFullDBMerger()
+		args.Atomic = true
+		args.OsOperationsHandler = &mock.OsOperationsHandlerStub{
+			RemoveDirectoryCalled: func(directory string) error {
+				removedDirectory = directory
+
+				return nil
+			},
+			MoveDirectoryCalled: func(destination string, source string) error {
+				assert.Fail(t, "should have not called MoveDirectory")
+
+				return nil
+			},
+		}
+		args.PersisterCreator = &mock.PersisterCreatorStub{
+			CreatePersisterCalled: func(path string) (types.Persister, error) {
+				persisterMock := mock.NewPersisterMock()
+				persisterMock.CloseCalled = func() error {
+					numClosedPersisters++
+
+					return nil
+				}
+				return persisterMock, nil
+			},
+		}
+		args.DataMergerInstance = &mock.DataMergerStub{
+			MergeDBsWithProgressCalled: func(dest types.Persister, onProgress func(keysCopied uint64), sources ...types.Persister) error {
+				return expectedErr
+			},
+		}
+		merger, _ := NewFullDBMerger(args)
+
+		destPersister, err := merger.MergeDBs("dest", "src1", "src2")
+		assert.True(t, check.IfNil(destPersister))
+		assert.True(t, errors.Is(err, expectedErr))
+		assert.Equal(t, TemporaryDestinationPath("dest"), removedDirectory)
+		assert.Equal(t, 2, numClosedPersisters) // the temporary destination and the second source
+	})
+	t.Run("move errors should remove the temporary directory", func(t *testing.T) {
+		t.Parallel()
+
+		expectedErr := errors.New("expected error")
+		removedDirectory := ""
+		args := createMockArgsFullDBMerger()
+		args.Atomic = true
+		args.OsOperationsHandler = &mock.OsOperationsHandlerStub{
+			RemoveDirectoryCalled: func(directory string) error {
+				removedDirectory = directory
+
+				return nil
+			},
+			MoveDirectoryCalled: func(destination string, source string) error {
+				return expectedErr
+			},
+		}
+		args.PersisterCreator = &mock.PersisterCreatorStub{
+			CreatePersisterCalled: func(path string) (types.Persister, error) {
+				return mock.NewPersisterMock(), nil
+			},
+		}
+		merger, _ := NewFullDBMerger(args)
+
+		destPersister, err := merger.MergeDBs("dest", "src1", "src2")
+		assert.True(t, check.IfNil(destPersister))
+		assert.Equal(t, expectedErr, err)
+		assert.Equal(t, TemporaryDestinationPath("dest"), removedDirectory)
+	})
+	t.Run("should work", func(t *testing.T) {
+		t.Parallel()
+
+		tempPath := TemporaryDestinationPath("dest")
+		createdDirectory := ""
+		copiedDestination := ""
+		openedPaths := make([]string, 0)
+		args := createMockArgsFullDBMerger()
+		args.Atomic = true
+		args.OsOperationsHandler = &mock.OsOperationsHandlerStub{
+			CreateDirectoryCalled: func(directory string) error {
+				createdDirectory = directory
+
+				return nil
+			},
+			CopyDirectoryCalled: func(destination string, source string) error {
+				copiedDestination = destination
+
+				return nil
+			},
+			RemoveDirectoryCalled: func(directory string) error {
+				assert.Fail(t, "should have not called RemoveDirectory")
+
+				return nil
+			},
+			MoveDirectoryCalled: func(destination string, source string) error {
+				assert.Equal(t, "dest", destination)
+				assert.Equal(t, tempPath, source)
+
+				return nil
+			},
+		}
+		args.PersisterCreator = &mock.PersisterCreatorStub{
+			CreatePersisterCalled: func(path string) (types.Persister, error) {
+				openedPaths = append(openedPaths, path)
+				return mock.NewPersisterMock(), nil
+			},
+		}
+		merger, _ := NewFullDBMerger(args)
+
+		destPersister, err := merger.MergeDBs("dest", "src1", "src2")
+		assert.False(t, check.IfNil(destPersister))
+		assert.Nil(t, err)
+		assert.Equal(t, tempPath, createdDirectory)
+		assert.Equal(t, tempPath, copiedDestination)
+		assert.Equal(t, []string{tempPath, "src2", "dest"}, openedPaths)
+	})
+}
+
+func TestFullDBMerger_VerifyMerge(t *testing.T) {
+	t.Parallel()
+
+	t.Run("nil destination should error", func(t *testing.T) {
+		t.Parallel()
+
+		merger, _ := NewFullDBMerger(createMockArgsFullDBMerger())
+
+		_, _, err := merger.VerifyMerge(nil, "src1", "src2")
+		assert.True(t, errors.Is(err, errNilPersister))
+	})
+	t.Run("create source persister errors", func(t *testing.T) {
+		t.Parallel()
+
+		expectedErr := errors.New("expected error")
+		args := createMockArgsFullDBMerger()
+		args.PersisterCreator = &mock.PersisterCreatorStub{
+			CreatePersisterCalled: func(path string) (types.Persister, error) {
+				return nil, expectedErr
+			},
+		}
+		merger, _ := NewFullDBMerger(args)
+
+		_, _, err := merger.VerifyMerge(mock.NewPersisterMock(), "src1", "src2")
+		assert.True(t, errors.Is(err, expectedErr))
+		assert.True(t, strings.Contains(err.Error(), "for source persister with index 0"))
+	})
+	t.Run("should open and close all sources", func(t *testing.T) {
+		t.Parallel()
+
+		numClosedPersisters := 0
+		numPersistersCreated := 0
+		args := createMockArgsFullDBMerger()
+		args.PersisterCreator = &mock.PersisterCreatorStub{
+			CreatePersisterCalled: func(path string) (types.Persister, error) {
+				numPersistersCreated++
+				persisterMock := mock.NewPersisterMock()
+				persisterMock.CloseCalled = func() error {
+					numClosedPersisters++
+
+					return nil
+				}
+				return persisterMock, nil
+			},
+		}
+		args.DataMergerInstance = &mock.DataMergerStub{
+			VerifyMergeCalled: func(dest types.Persister, sources ...types.Persister) (uint64, []string, error) {
+				assert.Equal(t, 3, len(sources))
+
+				return 7, []string{"aa"}, nil
+			},
+		}
+		merger, _ := NewFullDBMerger(args)
+
+		numVerifiedKeys, mismatchedKeys, err := merger.VerifyMerge(mock.NewPersisterMock(), "src1", "src2", "src3")
+		assert.Nil(t, err)
+		assert.Equal(t, uint64(7), numVerifiedKeys)
+		assert.Equal(t, []string{"aa"}, mismatchedKeys)
+		assert.Equal(t, 3, numPersistersCreated)
+		assert.Equal(t, 3, numClosedPersisters)
+	})
+}
+
+type appendCalls struct {
+	copiedSources []string
+	openedPaths   []string
+	moves         []string
+	removedPaths  []string
+}
+
+func createMockArgsFullDBMergerForAppend(t *testing.T, appendMode AppendMode, hasKeyFilter bool, calls *appendCalls) ArgsFullDBMerger {
+	args := createMockArgsFullDBMerger()
+	args.Atomic = true
+	args.AppendMode = appendMode
+	args.DataMergerInstance = &mock.DataMergerStub{
+		HasKeyFilterCalled: func() bool {
+			return hasKeyFilter
+		},
+	}
+	args.OsOperationsHandler = &mock.OsOperationsHandlerStub{
+		CheckIfDirectoryIsEmptyCalled: func(directory string) error {
+			assert.Fail(t, "should have not called CheckIfDirectoryIsEmpty")
+
+			return nil
+		},
+		CopyDirectoryCalled: func(destination string, source string) error {
+			calls.copiedSources = append(calls.copiedSources, source)
+
+			return nil
+		},
+		MoveDirectoryCalled: func(destination string, source string) error {
+			calls.moves = append(calls.moves, source+" -> "+destination)
+
+			return nil
+		},
+		RemoveDirectoryCalled: func(directory string) error {
+			calls.removedPaths = append(calls.removedPaths, directory)
+
+			return nil
+		},
+	}
+	args.PersisterCreator = &mock.PersisterCreatorStub{
+		CreatePersisterCalled: func(path string) (types.Persister, error) {
+			calls.openedPaths = append(calls.openedPaths, path)
+			return mock.NewPersisterMock(), nil
+		},
+	}
+
+	return args
+}
+
+func TestFullDBMerger_MergeDBsAppend(t *testing.T) {
+	t.Parallel()
+
+	tempPath := TemporaryDestinationPath("dest")
+
+	t.Run("no source should error", func(t *testing.T) {
+		t.Parallel()
+
+		calls := &appendCalls{}
+		merger, _ := NewFullDBMerger(createMockArgsFullDBMergerForAppend(t, AppendDestinationFirst, false, calls))
+
+		destPersister, err := merger.MergeDBs("dest")
+		assert.True(t, check.IfNil(destPersister))
+		assert.True(t, errors.Is(err, errInvalidNumberOfPersisters))
+	})
+	t.Run("destination first should copy the destination at the OS level", func(t *testing.T) {
+		t.Parallel()
+
+		calls := &appendCalls{}
+		merger, _ := NewFullDBMerger(createMockArgsFullDBMergerForAppend(t, AppendDestinationFirst, false, calls))
+
+		destPersister, err := merger.MergeDBs("dest", "src1")
+		assert.False(t, check.IfNil(destPersister))
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"dest"}, calls.copiedSources)
+		assert.Equal(t, []string{tempPath, "src1", "dest"}, calls.openedPaths)
+	})
+	t.Run("should move the existing destination aside before replacing it", func(t *testing.T) {
+		t.Parallel()
+
+		calls := &appendCalls{}
+		merger, _ := NewFullDBMerger(createMockArgsFullDBMergerForAppend(t, AppendDestinationFirst, false, calls))
+
+		_, err := merger.MergeDBs("dest", "src1")
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"dest -> dest.previous", tempPath + " -> dest"}, calls.moves)
+		assert.Equal(t, []string{"dest.previous"}, calls.removedPaths)
+	})
+	t.Run("destination first with key filter should still copy the destination at the OS level", func(t *testing.T) {
+		t.Parallel()
+
+		calls := &appendCalls{}
+		merger, _ := NewFullDBMerger(createMockArgsFullDBMergerForAppend(t, AppendDestinationFirst, true, calls))
+
+		destPersister, err := merger.MergeDBs("dest", "src1", "src2")
+		assert.False(t, check.IfNil(destPersister))
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"dest"}, calls.copiedSources)
+		assert.Equal(t, []string{tempPath, "src1", "src2", "dest"}, calls.openedPaths)
+	})
+	t.Run("destination last should merge the destination after the sources", func(t *testing.T) {
+		t.Parallel()
+
+		calls := &appendCalls{}
+		merger, _ := NewFullDBMerger(createMockArgsFullDBMergerForAppend(t, AppendDestinationLast, false, calls))
+
+		destPersister, err := merger.MergeDBs("dest", "src1", "src2")
+		assert.False(t, check.IfNil(destPersister))
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"src1"}, calls.copiedSources)
+		assert.Equal(t, []string{tempPath, "src2", "dest", "dest"}, calls.openedPaths)
+	})
+	t.Run("destination last with key filter should error", func(t *testing.T) {
+		t.Parallel()
+
+		calls := &appendCalls{}
+		merger, _ := NewFullDBMerger(createMockArgsFullDBMergerForAppend(t, AppendDestinationLast, true, calls))
+
+		destPersister, err := merger.MergeDBs("dest", "src1")
+		assert.True(t, check.IfNil(destPersister))
+		assert.Equal(t, errAppendLastWithKeyFilter, err)
+		assert.Empty(t, calls.openedPaths)
+	})
 }
@@ -1,12 +1,22 @@
 package storer
 
 import (
+	"github.com/multiversx/mx-chain-go/storage"
 	"github.com/multiversx/mx-chain-storage-go/types"
 )
 
 // DataMerger specify the operations supported by a component able to merge data between persisters
 type DataMerger interface {
 	MergeDBs(dest types.Persister, sources ...types.Persister) error
+	MergeDBsWithProgress(dest types.Persister, onProgress func(keysCopied uint64), sources ...types.Persister) error
+	VerifyMerge(dest types.Persister, sources ...types.Persister) (uint64, []string, error)
+	HasKeyFilter() bool
+	IsInterfaceNil() bool
+}
+
+// MergeEstimator is able to estimate the size of the data merged from the provided persisters, without writing it
+type MergeEstimator interface {
+	EstimateMerge(sources ...types.Persister) (*MergeEstimate, error)
 	IsInterfaceNil() bool
 }
 
@@ -20,5 +30,17 @@ type PersisterCreator interface {
 type OsOperationsHandler interface {
 	CheckIfDirectoryIsEmpty(directory string) error
 	CopyDirectory(destination string, source string) error
+	CreateDirectory(directory string) error
+	RemoveDirectory(directory string) error
+	MoveDirectory(destination string, source string) error
+	IsInterfaceNil() bool
+}
+
+// FullDBMerger is able to merge the persisters found on the provided paths into a new persister
+type FullDBMerger interface {
+	MergeDBs(destinationPath string, sourcePaths ...string) (storage.Persister, error)
+	VerifyMerge(destPersister storage.Persister, sourcePaths ...string) (uint64, []string, error)
+	ComputeManifest(destinationPath string, sourcePaths ...string) (*Manifest, error)
+	EstimateMerge(destinationPath string, sourcePaths ...string) (*MergeEstimate, error)
 	IsInterfaceNil() bool
 }
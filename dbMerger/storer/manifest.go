@@ -0,0 +1,92 @@
+package storer
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/multiversx/mx-chain-storage-go/types"
+)
+
+// PersisterDigest identifies the contents of a persister: its number of keys and a hash over all its key-value pairs
+type PersisterDigest struct {
+	Path    string `json:"path"`
+	NumKeys uint64 `json:"numKeys"`
+	Hash    string `json:"hash"`
+}
+
+// Manifest lists the digests of the source persisters and of the resulting destination persister, so it can later
+// be checked that a destination was produced from exactly those sources
+type Manifest struct {
+	Version        string            `json:"version"`
+	ConflictPolicy ConflictPolicy    `json:"conflictPolicy"`
+	KeyPrefix      string            `json:"keyPrefix"`
+	AppendMode     AppendMode        `json:"appendMode"`
+	Sources        []PersisterDigest `json:"sources"`
+	Destination    PersisterDigest   `json:"destination"`
+}
+
+// computePersisterDigest iterates over all the key-value pairs of the provided persister, in the key order, and
+// hashes them using sha256. Each key and each value is prefixed by its length (8 bytes, big endian), so the hash
+// does not depend on how the pairs are split
+func computePersisterDigest(path string, persister types.Persister) PersisterDigest {
+	hasher := sha256.New()
+	lengthBuff := make([]byte, 8)
+	writeWithLength := func(data []byte) {
+		binary.BigEndian.PutUint64(lengthBuff, uint64(len(data)))
+		_, _ = hasher.Write(lengthBuff)
+		_, _ = hasher.Write(data)
+	}
+
+	numKeys := uint64(0)
+	persister.RangeKeys(func(key []byte, val []byte) bool {
+		writeWithLength(key)
+		writeWithLength(val)
+		numKeys++
+
+		return true
+	})
+
+	return PersisterDigest{
+		Path:    path,
+		NumKeys: numKeys,
+		Hash:    hex.EncodeToString(hasher.Sum(nil)),
+	}
+}
+
+// ComputeManifest re-opens, one at a time, the destination and the source persister paths and computes their
+// digests. The destination should be closed beforehand, so all its buffered writes are flushed. The sources are hashed
+// entirely, even when only a part of their keys were merged
+func (fdm *fullDBMerger) ComputeManifest(destinationPath string, sourcePaths ...string) (*Manifest, error) {
+	destinationDigest, err := fdm.computeDigest(destinationPath)
+	if err != nil {
+		return nil, fmt.Errorf("%w for destination persister", err)
+	}
+
+	manifest := &Manifest{
+		Sources:     make([]PersisterDigest, 0, len(sourcePaths)),
+		Destination: destinationDigest,
+	}
+	for idx, sourcePath := range sourcePaths {
+		sourceDigest, errDigest := fdm.computeDigest(sourcePath)
+		if errDigest != nil {
+			return nil, fmt.Errorf("%w for source persister with index %d", errDigest, idx)
+		}
+
+		manifest.Sources = append(manifest.Sources, sourceDigest)
+	}
+
+	return manifest, nil
+}
+
+func (fdm *fullDBMerger) computeDigest(path string) (PersisterDigest, error) {
+	persister, err := fdm.persisterCreator.CreatePersister(path)
+	if err != nil {
+		return PersisterDigest{}, err
+	}
+
+	digest := computePersisterDigest(path, persister)
+
+	return digest, persister.Close()
+}
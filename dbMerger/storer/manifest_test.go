@@ -0,0 +1,109 @@
+package storer
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/multiversx/mx-chain-storage-go/types"
+	"github.com/multiversx/mx-chain-tools-go/dbmerger/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+func createPersisterMockWithKeyValue(key string, val string) types.Persister {
+	persister := mock.NewPersisterMock()
+	_ = persister.Put([]byte(key), []byte(val))
+
+	return persister
+}
+
+func TestComputePersisterDigest(t *testing.T) {
+	t.Parallel()
+
+	t.Run("empty persister", func(t *testing.T) {
+		t.Parallel()
+
+		digest := computePersisterDigest("path", mock.NewPersisterMock())
+		assert.Equal(t, "path", digest.Path)
+		assert.Equal(t, uint64(0), digest.NumKeys)
+		// sha256 of no data
+		assert.Equal(t, "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855", digest.Hash)
+	})
+	t.Run("the keys and the values should not be mixed up", func(t *testing.T) {
+		t.Parallel()
+
+		first := computePersisterDigest("", createPersisterMockWithKeyValue("ab", "c"))
+		second := computePersisterDigest("", createPersisterMockWithKeyValue("a", "bc"))
+		assert.Equal(t, uint64(1), first.NumKeys)
+		assert.NotEqual(t, first.Hash, second.Hash)
+
+		same := computePersisterDigest("", createPersisterMockWithKeyValue("ab", "c"))
+		assert.Equal(t, first.Hash, same.Hash)
+	})
+}
+
+func TestFullDBMerger_ComputeManifest(t *testing.T) {
+	t.Parallel()
+
+	t.Run("destination persister errors", func(t *testing.T) {
+		t.Parallel()
+
+		expectedErr := errors.New("expected error")
+		args := createMockArgsFullDBMerger()
+		args.PersisterCreator = &mock.PersisterCreatorStub{
+			CreatePersisterCalled: func(path string) (types.Persister, error) {
+				return nil, expectedErr
+			},
+		}
+		merger, _ := NewFullDBMerger(args)
+
+		manifest, err := merger.ComputeManifest("dest", "src1")
+		assert.Nil(t, manifest)
+		assert.True(t, errors.Is(err, expectedErr))
+		assert.True(t, strings.Contains(err.Error(), "destination"))
+	})
+	t.Run("source persister errors", func(t *testing.T) {
+		t.Parallel()
+
+		expectedErr := errors.New("expected error")
+		args := createMockArgsFullDBMerger()
+		args.PersisterCreator = &mock.PersisterCreatorStub{
+			CreatePersisterCalled: func(path string) (types.Persister, error) {
+				if path == "src2" {
+					return nil, expectedErr
+				}
+
+				return mock.NewPersisterMock(), nil
+			},
+		}
+		merger, _ := NewFullDBMerger(args)
+
+		manifest, err := merger.ComputeManifest("dest", "src1", "src2")
+		assert.Nil(t, manifest)
+		assert.True(t, errors.Is(err, expectedErr))
+		assert.True(t, strings.Contains(err.Error(), "index 1"))
+	})
+	t.Run("should work", func(t *testing.T) {
+		t.Parallel()
+
+		openedPaths := make([]string, 0)
+		args := createMockArgsFullDBMerger()
+		args.PersisterCreator = &mock.PersisterCreatorStub{
+			CreatePersisterCalled: func(path string) (types.Persister, error) {
+				openedPaths = append(openedPaths, path)
+				return createPersisterMockWithKeyValue("key of "+path, "value"), nil
+			},
+		}
+		merger, _ := NewFullDBMerger(args)
+
+		manifest, err := merger.ComputeManifest("dest", "src1", "src2")
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"dest", "src1", "src2"}, openedPaths)
+		assert.Equal(t, "dest", manifest.Destination.Path)
+		assert.Equal(t, uint64(1), manifest.Destination.NumKeys)
+		assert.Equal(t, 2, len(manifest.Sources))
+		assert.Equal(t, "src1", manifest.Sources[0].Path)
+		assert.Equal(t, "src2", manifest.Sources[1].Path)
+		assert.NotEqual(t, manifest.Sources[0].Hash, manifest.Sources[1].Hash)
+	})
+}
@@ -0,0 +1,120 @@
+package storer
+
+import (
+	"fmt"
+
+	"github.com/multiversx/mx-chain-core-go/core/check"
+	"github.com/multiversx/mx-chain-storage-go/types"
+)
+
+// PersisterEstimate holds the number of keys and the raw size (keys plus values) of the data a persister contributes
+// to the merge
+type PersisterEstimate struct {
+	Path     string `json:"path"`
+	NumKeys  uint64 `json:"numKeys"`
+	NumBytes uint64 `json:"numBytes"`
+}
+
+// MergeEstimate holds the estimated size of the merged data. The combined figures count each key once, with the value
+// of the source chosen by the conflict policy. These are raw sizes: the level DB compresses the values, but it also
+// needs extra space while compacting, so the actual disk usage differs
+type MergeEstimate struct {
+	Sources []PersisterEstimate `json:"sources"`
+	// NumKeys and NumBytes are the combined figures, that is, the expected destination contents
+	NumKeys  uint64 `json:"numKeys"`
+	NumBytes uint64 `json:"numBytes"`
+	// NumDuplicateKeys is the number of times a key was found again in a later source. With the error-on-conflict
+	// policy, any duplicate key makes the merge fail
+	NumDuplicateKeys uint64 `json:"numDuplicateKeys"`
+}
+
+// EstimateMerge iterates over the keys of the provided sources, without writing anything, and returns the number of
+// keys and the raw size of each source and of the merged data. Only the keys starting with the configured prefix are
+// accounted
+func (dm *dataMerger) EstimateMerge(sources ...types.Persister) (*MergeEstimate, error) {
+	for idx, source := range sources {
+		if check.IfNil(source) {
+			return nil, fmt.Errorf("%w for the source persister, index %d", errNilPersister, idx)
+		}
+	}
+
+	estimate := &MergeEstimate{
+		Sources: make([]PersisterEstimate, len(sources)),
+	}
+	for idx := range sources {
+		sources[idx].RangeKeys(func(key []byte, val []byte) bool {
+			if !dm.isKeyIncluded(key) {
+				return true
+			}
+
+			size := uint64(len(key) + len(val))
+			estimate.Sources[idx].NumKeys++
+			estimate.Sources[idx].NumBytes += size
+
+			isDuplicate := isKeyInSources(key, sources[:idx])
+			if isDuplicate {
+				estimate.NumDuplicateKeys++
+			}
+
+			isWritten := !isDuplicate
+			if dm.conflictPolicy != ErrorOnConflict {
+				isWritten = dm.isWinningSource(key, sources, idx)
+			}
+			if isWritten {
+				estimate.NumKeys++
+				estimate.NumBytes += size
+			}
+
+			return true
+		})
+	}
+
+	log.Debug("finished estimating the merge",
+		"num source persisters", len(sources), "num keys", estimate.NumKeys, "num bytes", estimate.NumBytes,
+		"num duplicate keys", estimate.NumDuplicateKeys)
+
+	return estimate, nil
+}
+
+func isKeyInSources(key []byte, sources []types.Persister) bool {
+	for _, source := range sources {
+		if source.Has(key) == nil {
+			return true
+		}
+	}
+
+	return false
+}
+
+// EstimateMerge opens the source persister paths and estimates the size of the merged data, without writing
+// anything. When appending, the existing destination contents are accounted as well, as an implicit source
+func (fdm *fullDBMerger) EstimateMerge(destinationPath string, sourcePaths ...string) (*MergeEstimate, error) {
+	if check.IfNil(fdm.mergeEstimator) {
+		return nil, fmt.Errorf("%w, MergeEstimator", errNilComponent)
+	}
+
+	allSourcePaths := sourcePaths
+	if fdm.appendMode != NoAppend {
+		allSourcePaths = fdm.withDestinationAsSource(destinationPath, sourcePaths...)
+	}
+
+	sourcePersisters, err := fdm.createSourcePersisters(0, allSourcePaths...)
+	if err != nil {
+		return nil, err
+	}
+
+	estimate, err := fdm.mergeEstimator.EstimateMerge(sourcePersisters...)
+	errClose := fdm.closeSourcePersisters(sourcePersisters)
+	if err != nil {
+		return nil, err
+	}
+	if errClose != nil {
+		return nil, errClose
+	}
+
+	for idx := range estimate.Sources {
+		estimate.Sources[idx].Path = allSourcePaths[idx]
+	}
+
+	return estimate, nil
+}
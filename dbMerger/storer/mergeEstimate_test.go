@@ -0,0 +1,149 @@
+package storer
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/multiversx/mx-chain-storage-go/types"
+	"github.com/multiversx/mx-chain-tools-go/dbmerger/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+func createPersisterMockWithKeyValues(keyValues map[string]string) types.Persister {
+	persister := mock.NewPersisterMock()
+	for key, val := range keyValues {
+		_ = persister.Put([]byte(key), []byte(val))
+	}
+
+	return persister
+}
+
+func createEstimateSources() []types.Persister {
+	return []types.Persister{
+		createPersisterMockWithKeyValues(map[string]string{
+			"a": "11",
+			"b": "1",
+		}),
+		createPersisterMockWithKeyValues(map[string]string{
+			"b": "222",
+			"c": "2",
+		}),
+	}
+}
+
+func TestDataMerger_EstimateMerge(t *testing.T) {
+	t.Parallel()
+
+	t.Run("sources contains a nil persister should error", func(t *testing.T) {
+		t.Parallel()
+
+		dm := NewDataMerger()
+		estimate, err := dm.EstimateMerge(mock.NewPersisterMock(), nil)
+		assert.Nil(t, estimate)
+		assert.True(t, errors.Is(err, errNilPersister))
+		assert.True(t, strings.Contains(err.Error(), "for the source persister, index 1"))
+	})
+	t.Run("should count the overlapping keys once, as chosen by the conflict policy", func(t *testing.T) {
+		t.Parallel()
+
+		expectedCombinedNumBytes := map[ConflictPolicy]uint64{
+			// a:11 + b:222 + c:2
+			LastWins: 9,
+			// a:11 + b:1 + c:2
+			FirstWins:       7,
+			ErrorOnConflict: 7,
+		}
+		for policy, expectedNumBytes := range expectedCombinedNumBytes {
+			dm, _ := NewDataMergerWithArgs(ArgsDataMerger{
+				ConflictPolicy: policy,
+				NumWorkers:     1,
+			})
+
+			estimate, err := dm.EstimateMerge(createEstimateSources()...)
+			assert.Nil(t, err)
+			assert.Equal(t, []PersisterEstimate{{NumKeys: 2, NumBytes: 5}, {NumKeys: 2, NumBytes: 6}}, estimate.Sources)
+			assert.Equal(t, uint64(3), estimate.NumKeys, policy)
+			assert.Equal(t, expectedNumBytes, estimate.NumBytes, policy)
+			assert.Equal(t, uint64(1), estimate.NumDuplicateKeys, policy)
+		}
+	})
+	t.Run("should only account the keys with the configured prefix", func(t *testing.T) {
+		t.Parallel()
+
+		dm, _ := NewDataMergerWithArgs(ArgsDataMerger{
+			ConflictPolicy: LastWins,
+			NumWorkers:     1,
+			KeyPrefix:      []byte("b"),
+		})
+
+		estimate, err := dm.EstimateMerge(createEstimateSources()...)
+		assert.Nil(t, err)
+		assert.Equal(t, []PersisterEstimate{{NumKeys: 1, NumBytes: 2}, {NumKeys: 1, NumBytes: 4}}, estimate.Sources)
+		assert.Equal(t, uint64(1), estimate.NumKeys)
+		assert.Equal(t, uint64(4), estimate.NumBytes)
+		assert.Equal(t, uint64(1), estimate.NumDuplicateKeys)
+	})
+}
+
+func TestFullDBMerger_EstimateMerge(t *testing.T) {
+	t.Parallel()
+
+	t.Run("nil merge estimator should error", func(t *testing.T) {
+		t.Parallel()
+
+		merger, _ := NewFullDBMerger(createMockArgsFullDBMerger())
+
+		estimate, err := merger.EstimateMerge("dest", "src1")
+		assert.Nil(t, estimate)
+		assert.True(t, errors.Is(err, errNilComponent))
+		assert.True(t, strings.Contains(err.Error(), "MergeEstimator"))
+	})
+	t.Run("source persister errors", func(t *testing.T) {
+		t.Parallel()
+
+		expectedErr := errors.New("expected error")
+		args := createMockArgsFullDBMerger()
+		args.MergeEstimator = NewDataMerger()
+		args.PersisterCreator = &mock.PersisterCreatorStub{
+			CreatePersisterCalled: func(path string) (types.Persister, error) {
+				return nil, expectedErr
+			},
+		}
+		merger, _ := NewFullDBMerger(args)
+
+		estimate, err := merger.EstimateMerge("dest", "src1")
+		assert.Nil(t, estimate)
+		assert.True(t, errors.Is(err, expectedErr))
+	})
+	t.Run("should not open the destination and should set the paths", func(t *testing.T) {
+		t.Parallel()
+
+		openedPaths := make([]string, 0)
+		numClosed := 0
+		args := createMockArgsFullDBMerger()
+		args.MergeEstimator = NewDataMerger()
+		args.PersisterCreator = &mock.PersisterCreatorStub{
+			CreatePersisterCalled: func(path string) (types.Persister, error) {
+				openedPaths = append(openedPaths, path)
+				persister := mock.NewPersisterMock()
+				_ = persister.Put([]byte("key of "+path), []byte("value"))
+				persister.CloseCalled = func() error {
+					numClosed++
+					return nil
+				}
+
+				return persister, nil
+			},
+		}
+		merger, _ := NewFullDBMerger(args)
+
+		estimate, err := merger.EstimateMerge("dest", "src1", "src2")
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"src1", "src2"}, openedPaths)
+		assert.Equal(t, 2, numClosed)
+		assert.Equal(t, "src1", estimate.Sources[0].Path)
+		assert.Equal(t, "src2", estimate.Sources[1].Path)
+		assert.Equal(t, uint64(2), estimate.NumKeys)
+	})
+}
@@ -1,8 +1,13 @@
 package storer
 
 import (
+	"errors"
+	"fmt"
+	"time"
+
 	"github.com/multiversx/mx-chain-storage-go/leveldb"
 	"github.com/multiversx/mx-chain-storage-go/types"
+	"github.com/multiversx/mx-chain-tools-go/dbmerger/exitcodes"
 )
 
 const (
@@ -11,17 +16,160 @@ const (
 	maxOpenFiles      = 10
 )
 
+// ArgsPersisterCreator is the DTO used in the NewPersisterCreatorWithArgs constructor function
+type ArgsPersisterCreator struct {
+	// DefaultType is used for the paths without a type hint
+	DefaultType PersisterType
+	// PathTypes holds the type hints, by path
+	PathTypes map[string]PersisterType
+	// OpenAttempts is the number of attempts made to open a persister. 0 and 1 both mean a single attempt
+	OpenAttempts uint64
+	// OpenRetryDelay is the delay between two attempts of opening a persister
+	OpenRetryDelay time.Duration
+	// OpenTimeout bounds the opening of a persister, retries included. 0 means no timeout
+	OpenTimeout time.Duration
+}
+
 type persisterCreator struct {
+	defaultType    PersisterType
+	pathTypes      map[string]PersisterType
+	openAttempts   uint64
+	openRetryDelay time.Duration
+	openTimeout    time.Duration
 }
 
-// NewPersisterCreator will create a new persister creator instance
+// NewPersisterCreator will create a new persister creator instance that opens all the paths as LvlDB persisters
 func NewPersisterCreator() *persisterCreator {
-	return &persisterCreator{}
+	return &persisterCreator{
+		defaultType:  LvlDB,
+		pathTypes:    make(map[string]PersisterType),
+		openAttempts: 1,
+	}
 }
 
-// CreatePersister will try to create a new persister instance provided the directory path
+// NewPersisterCreatorWithArgs will create a new persister creator instance that opens each path using its type hint,
+// or the default type if the path does not have a type hint
+func NewPersisterCreatorWithArgs(args ArgsPersisterCreator) (*persisterCreator, error) {
+	if !isValidPersisterType(args.DefaultType) {
+		return nil, fmt.Errorf("%w: %s, valid types are: %s", errInvalidPersisterType, args.DefaultType, AllPersisterTypesNames())
+	}
+
+	if args.OpenRetryDelay < 0 {
+		return nil, fmt.Errorf("%w: %v", errInvalidOpenRetryDelay, args.OpenRetryDelay)
+	}
+	if args.OpenTimeout < 0 {
+		return nil, fmt.Errorf("%w: %v", errInvalidOpenTimeout, args.OpenTimeout)
+	}
+
+	pathTypes := make(map[string]PersisterType, len(args.PathTypes))
+	for path, persisterType := range args.PathTypes {
+		if !isValidPersisterType(persisterType) {
+			return nil, fmt.Errorf("%w: %s for path %s, valid types are: %s", errInvalidPersisterType, persisterType, path, AllPersisterTypesNames())
+		}
+
+		pathTypes[path] = persisterType
+	}
+
+	openAttempts := args.OpenAttempts
+	if openAttempts == 0 {
+		openAttempts = 1
+	}
+
+	return &persisterCreator{
+		defaultType:    args.DefaultType,
+		pathTypes:      pathTypes,
+		openAttempts:   openAttempts,
+		openRetryDelay: args.OpenRetryDelay,
+		openTimeout:    args.OpenTimeout,
+	}, nil
+}
+
+// CreatePersister will try to create a new persister instance provided the directory path. If the open timeout is
+// reached, either while opening or before the next attempt, a database in use error is returned
 func (creator *persisterCreator) CreatePersister(path string) (types.Persister, error) {
-	return leveldb.NewDB(path, batchDelaySeconds, maxBatchSize, maxOpenFiles)
+	persisterType, err := creator.getPersisterType(path)
+	if err != nil {
+		return nil, err
+	}
+
+	deadline := time.Now().Add(creator.openTimeout)
+	for attempt := uint64(1); ; attempt++ {
+		persister, errOpen := creator.openPersisterWithTimeout(path, persisterType, deadline)
+		if errOpen == nil {
+			return persister, nil
+		}
+		if errors.Is(errOpen, errDatabaseInUse) {
+			return nil, exitcodes.NewIOError(fmt.Errorf("%w: could not open %s within the %v open timeout, the DB might be locked by a running node",
+				errOpen, path, creator.openTimeout))
+		}
+		if attempt >= creator.openAttempts {
+			if creator.openAttempts > 1 {
+				return nil, exitcodes.NewIOError(fmt.Errorf("%w after %d attempts, path %s", errOpen, creator.openAttempts, path))
+			}
+
+			return nil, exitcodes.NewIOError(errOpen)
+		}
+
+		if creator.openTimeout > 0 && time.Now().Add(creator.openRetryDelay).After(deadline) {
+			return nil, exitcodes.NewIOError(fmt.Errorf("%w: could not open %s within the %v open timeout, the DB might be locked by a running node, last error: %s",
+				errDatabaseInUse, path, creator.openTimeout, errOpen.Error()))
+		}
+
+		log.Warn("could not open the persister, retrying", "path", path, "attempt", attempt,
+			"max attempts", creator.openAttempts, "retry delay", creator.openRetryDelay, "error", errOpen)
+		time.Sleep(creator.openRetryDelay)
+	}
+}
+
+// openPersisterWithTimeout stops waiting for the persister at the provided deadline, if an open timeout is set. A
+// blocked open is left running, as it can not be interrupted
+func (creator *persisterCreator) openPersisterWithTimeout(path string, persisterType PersisterType, deadline time.Time) (types.Persister, error) {
+	if creator.openTimeout == 0 {
+		return openPersister(path, persisterType)
+	}
+
+	type openResult struct {
+		persister types.Persister
+		err       error
+	}
+
+	chResult := make(chan openResult, 1)
+	go func() {
+		persister, err := openPersister(path, persisterType)
+		chResult <- openResult{persister: persister, err: err}
+	}()
+
+	timer := time.NewTimer(time.Until(deadline))
+	defer timer.Stop()
+
+	select {
+	case result := <-chResult:
+		return result.persister, result.err
+	case <-timer.C:
+		return nil, errDatabaseInUse
+	}
+}
+
+func openPersister(path string, persisterType PersisterType) (types.Persister, error) {
+	switch persisterType {
+	case LvlDBSerial:
+		return leveldb.NewSerialDB(path, batchDelaySeconds, maxBatchSize, maxOpenFiles)
+	default:
+		return leveldb.NewDB(path, batchDelaySeconds, maxBatchSize, maxOpenFiles)
+	}
+}
+
+func (creator *persisterCreator) getPersisterType(path string) (PersisterType, error) {
+	persisterType, found := creator.pathTypes[path]
+	if found {
+		return persisterType, nil
+	}
+
+	if isLevelDBDirectory(path) || isNewDirectory(path) {
+		return creator.defaultType, nil
+	}
+
+	return "", exitcodes.NewUsageError(fmt.Errorf("%w for path %s, provide a type hint, valid types are: %s", errUnknownPersisterType, path, AllPersisterTypesNames()))
 }
 
 // IsInterfaceNil returns true if there is no value under the interface
@@ -1,8 +1,13 @@
 package storer
 
 import (
+	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/multiversx/mx-chain-core-go/core/check"
 	"github.com/stretchr/testify/assert"
@@ -27,3 +32,207 @@ func TestPersisterCreator_CreatePersister(t *testing.T) {
 
 	_ = persister.Destroy()
 }
+
+func TestNewPersisterCreatorWithArgs(t *testing.T) {
+	t.Parallel()
+
+	t.Run("invalid default type should error", func(t *testing.T) {
+		t.Parallel()
+
+		creator, err := NewPersisterCreatorWithArgs(ArgsPersisterCreator{
+			DefaultType: "unknown",
+		})
+		assert.True(t, check.IfNil(creator))
+		assert.True(t, errors.Is(err, errInvalidPersisterType))
+	})
+	t.Run("invalid path type should error", func(t *testing.T) {
+		t.Parallel()
+
+		creator, err := NewPersisterCreatorWithArgs(ArgsPersisterCreator{
+			DefaultType: LvlDB,
+			PathTypes: map[string]PersisterType{
+				"path": "unknown",
+			},
+		})
+		assert.True(t, check.IfNil(creator))
+		assert.True(t, errors.Is(err, errInvalidPersisterType))
+		assert.True(t, strings.Contains(err.Error(), "for path path"))
+	})
+	t.Run("negative open retry delay should error", func(t *testing.T) {
+		t.Parallel()
+
+		creator, err := NewPersisterCreatorWithArgs(ArgsPersisterCreator{
+			DefaultType:    LvlDB,
+			OpenRetryDelay: -time.Second,
+		})
+		assert.True(t, check.IfNil(creator))
+		assert.True(t, errors.Is(err, errInvalidOpenRetryDelay))
+	})
+	t.Run("negative open timeout should error", func(t *testing.T) {
+		t.Parallel()
+
+		creator, err := NewPersisterCreatorWithArgs(ArgsPersisterCreator{
+			DefaultType: LvlDB,
+			OpenTimeout: -time.Second,
+		})
+		assert.True(t, check.IfNil(creator))
+		assert.True(t, errors.Is(err, errInvalidOpenTimeout))
+	})
+	t.Run("should work", func(t *testing.T) {
+		t.Parallel()
+
+		creator, err := NewPersisterCreatorWithArgs(ArgsPersisterCreator{
+			DefaultType: LvlDBSerial,
+		})
+		assert.False(t, check.IfNil(creator))
+		assert.Nil(t, err)
+	})
+}
+
+func TestPersisterCreator_CreatePersisterWithTypes(t *testing.T) {
+	t.Parallel()
+
+	t.Run("path type hint should be used", func(t *testing.T) {
+		t.Parallel()
+
+		serialPath := t.TempDir()
+		creator, _ := NewPersisterCreatorWithArgs(ArgsPersisterCreator{
+			DefaultType: LvlDB,
+			PathTypes: map[string]PersisterType{
+				serialPath: LvlDBSerial,
+			},
+		})
+
+		persister, err := creator.CreatePersister(serialPath)
+		assert.Nil(t, err)
+		assert.Equal(t, "*leveldb.SerialDB", fmt.Sprintf("%T", persister))
+		_ = persister.Close()
+
+		persister, err = creator.CreatePersister(t.TempDir())
+		assert.Nil(t, err)
+		assert.Equal(t, "*leveldb.DB", fmt.Sprintf("%T", persister))
+		_ = persister.Close()
+	})
+	t.Run("existing level DB should be detected", func(t *testing.T) {
+		t.Parallel()
+
+		dbPath := t.TempDir()
+		persister, err := NewPersisterCreator().CreatePersister(dbPath)
+		assert.Nil(t, err)
+		_ = persister.Close()
+
+		creator, _ := NewPersisterCreatorWithArgs(ArgsPersisterCreator{
+			DefaultType: LvlDBSerial,
+		})
+		persister, err = creator.CreatePersister(dbPath)
+		assert.Nil(t, err)
+		assert.Equal(t, "*leveldb.SerialDB", fmt.Sprintf("%T", persister))
+		_ = persister.Close()
+	})
+	t.Run("unknown directory contents should error", func(t *testing.T) {
+		t.Parallel()
+
+		dbPath := t.TempDir()
+		err := os.WriteFile(filepath.Join(dbPath, "file.txt"), []byte("data"), 0644)
+		assert.Nil(t, err)
+
+		persister, err := NewPersisterCreator().CreatePersister(dbPath)
+		assert.True(t, check.IfNil(persister))
+		assert.True(t, errors.Is(err, errUnknownPersisterType))
+		assert.True(t, strings.Contains(err.Error(), dbPath))
+	})
+}
+
+func TestPersisterCreator_CreatePersisterWithRetries(t *testing.T) {
+	t.Parallel()
+
+	// a file in place of the DB directory makes the open fail, until the file is removed
+	createBlockedPath := func(t *testing.T) string {
+		dbPath := filepath.Join(t.TempDir(), "db")
+		err := os.WriteFile(dbPath, []byte("data"), 0644)
+		assert.Nil(t, err)
+
+		return dbPath
+	}
+
+	t.Run("single attempt should error", func(t *testing.T) {
+		t.Parallel()
+
+		dbPath := createBlockedPath(t)
+		creator, _ := NewPersisterCreatorWithArgs(ArgsPersisterCreator{
+			DefaultType:    LvlDB,
+			PathTypes:      map[string]PersisterType{dbPath: LvlDB},
+			OpenRetryDelay: time.Hour,
+		})
+		persister, err := creator.CreatePersister(dbPath)
+		assert.True(t, check.IfNil(persister))
+		assert.NotNil(t, err)
+		assert.False(t, strings.Contains(err.Error(), "attempts"))
+	})
+	t.Run("should open once the path is available", func(t *testing.T) {
+		t.Parallel()
+
+		dbPath := createBlockedPath(t)
+		go func() {
+			time.Sleep(50 * time.Millisecond)
+			_ = os.Remove(dbPath)
+		}()
+
+		creator, _ := NewPersisterCreatorWithArgs(ArgsPersisterCreator{
+			DefaultType:    LvlDB,
+			PathTypes:      map[string]PersisterType{dbPath: LvlDB},
+			OpenAttempts:   50,
+			OpenRetryDelay: 10 * time.Millisecond,
+		})
+		persister, err := creator.CreatePersister(dbPath)
+		assert.Nil(t, err)
+		assert.False(t, check.IfNil(persister))
+		_ = persister.Close()
+	})
+	t.Run("should error after all the attempts", func(t *testing.T) {
+		t.Parallel()
+
+		dbPath := createBlockedPath(t)
+		creator, _ := NewPersisterCreatorWithArgs(ArgsPersisterCreator{
+			DefaultType:    LvlDB,
+			PathTypes:      map[string]PersisterType{dbPath: LvlDB},
+			OpenAttempts:   3,
+			OpenRetryDelay: time.Millisecond,
+		})
+		persister, err := creator.CreatePersister(dbPath)
+		assert.True(t, check.IfNil(persister))
+		assert.NotNil(t, err)
+		assert.True(t, strings.Contains(err.Error(), "after 3 attempts"))
+	})
+	t.Run("should error with database in use after the open timeout", func(t *testing.T) {
+		t.Parallel()
+
+		dbPath := createBlockedPath(t)
+		creator, _ := NewPersisterCreatorWithArgs(ArgsPersisterCreator{
+			DefaultType:    LvlDB,
+			PathTypes:      map[string]PersisterType{dbPath: LvlDB},
+			OpenAttempts:   1000,
+			OpenRetryDelay: 10 * time.Millisecond,
+			OpenTimeout:    50 * time.Millisecond,
+		})
+		start := time.Now()
+		persister, err := creator.CreatePersister(dbPath)
+		assert.True(t, check.IfNil(persister))
+		assert.True(t, errors.Is(err, errDatabaseInUse))
+		assert.True(t, strings.Contains(err.Error(), "within the 50ms open timeout"))
+		assert.Less(t, time.Since(start), 5*time.Second)
+	})
+	t.Run("should open within the open timeout", func(t *testing.T) {
+		t.Parallel()
+
+		dbPath := filepath.Join(t.TempDir(), "db")
+		creator, _ := NewPersisterCreatorWithArgs(ArgsPersisterCreator{
+			DefaultType: LvlDB,
+			OpenTimeout: time.Minute,
+		})
+		persister, err := creator.CreatePersister(dbPath)
+		assert.Nil(t, err)
+		assert.False(t, check.IfNil(persister))
+		_ = persister.Close()
+	})
+}
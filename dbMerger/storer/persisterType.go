@@ -0,0 +1,66 @@
+package storer
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// PersisterType defines the kind of persister used to open a DB directory
+type PersisterType string
+
+const (
+	// LvlDB opens the DB directory as a level DB with a batch flushed asynchronously
+	LvlDB PersisterType = "LvlDB"
+	// LvlDBSerial opens the DB directory as a level DB with all the operations serialized
+	LvlDBSerial PersisterType = "LvlDBSerial"
+)
+
+// levelDBCurrentFile is the file every level DB directory holds, pointing to the current manifest
+const levelDBCurrentFile = "CURRENT"
+
+var allPersisterTypes = []PersisterType{LvlDB, LvlDBSerial}
+
+// AllPersisterTypesNames returns the names of all supported persister types, comma separated
+func AllPersisterTypesNames() string {
+	names := make([]string, 0, len(allPersisterTypes))
+	for _, persisterType := range allPersisterTypes {
+		names = append(names, string(persisterType))
+	}
+
+	return strings.Join(names, ", ")
+}
+
+func isValidPersisterType(persisterType PersisterType) bool {
+	for _, knownType := range allPersisterTypes {
+		if persisterType == knownType {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isLevelDBDirectory returns true if the provided directory holds a level DB. Both LvlDB and LvlDBSerial persisters
+// share the same on-disk format, so they can not be told apart from the directory contents
+func isLevelDBDirectory(path string) bool {
+	info, err := os.Stat(filepath.Join(path, levelDBCurrentFile))
+	if err != nil {
+		return false
+	}
+
+	return !info.IsDir()
+}
+
+// isNewDirectory returns true if the provided directory does not exist or is empty, that is, a new DB will be created
+func isNewDirectory(path string) bool {
+	entries, err := os.ReadDir(path)
+	if os.IsNotExist(err) {
+		return true
+	}
+	if err != nil {
+		return false
+	}
+
+	return len(entries) == 0
+}
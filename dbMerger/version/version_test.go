@@ -0,0 +1,19 @@
+package version
+
+import (
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetVersion(t *testing.T) {
+	t.Parallel()
+
+	version := GetVersion()
+	assert.True(t, strings.HasPrefix(version, AppVersion+" "))
+	assert.True(t, strings.Contains(version, "commit "+Commit))
+	assert.True(t, strings.Contains(version, "built "+BuildDate))
+	assert.True(t, strings.Contains(version, runtime.Version()))
+}
@@ -1,12 +1,18 @@
 package main
 
 import (
+	"fmt"
 	"io/ioutil"
 	"os"
+	"time"
 
 	logger "github.com/multiversx/mx-chain-logger-go"
 	"github.com/multiversx/mx-chain-tools-go/elasticreindexer/config"
+	"github.com/multiversx/mx-chain-tools-go/elasticreindexer/elastic"
+	"github.com/multiversx/mx-chain-tools-go/elasticreindexer/exitcodes"
+	"github.com/multiversx/mx-chain-tools-go/elasticreindexer/logging"
 	"github.com/multiversx/mx-chain-tools-go/elasticreindexer/process"
+	"github.com/multiversx/mx-chain-tools-go/elasticreindexer/version"
 	"github.com/pelletier/go-toml"
 	"github.com/urfave/cli"
 )
@@ -26,6 +32,137 @@ var (
 		Name:  "skip-mappings",
 		Usage: "If set, the reindexing tool will skip the copying of the mappings",
 	}
+	// maxRetriesFlag defines the maximum number of retries for a request that failed with a retryable status code
+	maxRetriesFlag = cli.IntFlag{
+		Name:  "max-retries",
+		Usage: "The maximum number of retries for the requests that failed with a retryable status code (429, 500, 502, 503, 504). Zero disables the retries",
+		Value: elastic.DefaultMaxRetries,
+	}
+	// retryBaseDelayFlag defines the base delay of the exponential backoff between retries
+	retryBaseDelayFlag = cli.DurationFlag{
+		Name:  "retry-base-delay",
+		Usage: "The base delay of the exponential backoff between retries. The n-th retry will wait 2^n * base delay",
+		Value: time.Second,
+	}
+	// scrollKeepAliveFlag defines how long the search context of a source scroll is kept alive between two requests
+	scrollKeepAliveFlag = cli.DurationFlag{
+		Name: "scroll-keep-alive",
+		Usage: "How long the source cluster keeps the search context of a scroll alive between two scroll requests. " +
+			"Zero keeps the defaults: 10m after the first request and 2m after the next ones. An expired scroll of an index " +
+			"with timestamp is restarted from the last timestamp read. The indices without timestamp having a sort field " +
+			"configured are read using search_after, without a scroll, while an expired scroll of the other ones fails the reindexing",
+		Value: 0,
+	}
+	// progressIntervalFlag defines how often the reindexing progress of an index is logged
+	progressIntervalFlag = cli.DurationFlag{
+		Name: "progress-interval",
+		Usage: "How often the reindexing progress of an index is logged: the processed documents, the percentage out of the " +
+			"source count taken at the start, the current throughput and the estimated remaining time. Zero disables the progress logs",
+		Value: 30 * time.Second,
+	}
+	// reindexWorkersFlag defines the number of goroutines that bulk index the scroll pages of an index
+	reindexWorkersFlag = cli.IntFlag{
+		Name: "reindex-workers",
+		Usage: "The number of goroutines that bulk index the pages read from the source, for each scroll. Note that the indices " +
+			"with timestamp are already scrolled on num-parallel-writes intervals at once, so the total number of bulk " +
+			"requests in flight is num-parallel-writes * reindex-workers",
+		Value: 1,
+	}
+	// checkpointFileFlag defines the file where the reindexing progress is periodically saved
+	checkpointFileFlag = cli.StringFlag{
+		Name:  "checkpoint-file",
+		Usage: "The file where the reindexing progress is periodically saved, so an interrupted reindexing can be resumed",
+		Value: "./reindex-checkpoint.json",
+	}
+	// validateCountFlag defines a bool flag for checking the destination counts after the reindexing
+	validateCountFlag = cli.BoolTFlag{
+		Name: "validate-count",
+		Usage: "If set, after the reindexing the destination indices are refreshed and their counts are compared with the " +
+			"source counts. Enabled by default, use --validate-count=false in order to disable it",
+	}
+	// countToleranceFlag defines the accepted difference between the source and the destination counts
+	countToleranceFlag = cli.Uint64Flag{
+		Name: "count-tolerance",
+		Usage: "The accepted difference between the source and the destination counts of an index. Useful when a document " +
+			"transformer skips documents",
+		Value: 0,
+	}
+	// disableRefreshFlag defines a bool flag for disabling the refresh of the destination indices while bulk loading
+	disableRefreshFlag = cli.BoolFlag{
+		Name: "disable-refresh",
+		Usage: "If set, the refresh of each destination index is disabled (refresh_interval = -1) while the index is bulk " +
+			"loaded, and restored afterwards to the configured refresh-interval (or the cluster default)",
+	}
+	// bulkRateFlag defines the maximum number of documents indexed per second in the destination
+	bulkRateFlag = cli.Uint64Flag{
+		Name: "bulk-rate",
+		Usage: "The maximum number of documents indexed per second in the destination, for all the bulk requests of the tool. " +
+			"The bulk requests are delayed when the limit is reached. 0 means unlimited",
+		Value: 0,
+	}
+	// bulkMaxDocsFlag defines the maximum number of documents of a bulk request
+	bulkMaxDocsFlag = cli.IntFlag{
+		Name:  "bulk-max-docs",
+		Usage: "The maximum number of documents of a bulk request. 0 means no limit, so only the bulk-max-bytes threshold is used",
+		Value: 0,
+	}
+	// bulkMaxBytesFlag defines the maximum size of a bulk request
+	bulkMaxBytesFlag = cli.IntFlag{
+		Name: "bulk-max-bytes",
+		Usage: "The maximum size, in bytes, of a bulk request. A new bulk request is started whenever one of bulk-max-docs or " +
+			"bulk-max-bytes is reached. A document larger than the threshold is sent alone",
+		Value: process.DefaultBulkMaxBytes,
+	}
+	// queryFileFlag defines the file holding the query that filters the reindexed documents
+	queryFileFlag = cli.StringFlag{
+		Name: "query-file",
+		Usage: "The JSON file holding a search body with a query (e.g. {\"query\": {\"term\": {\"status\": \"success\"}}}). " +
+			"If set, only the documents matching the query are reindexed, for all the indices",
+		Value: "",
+	}
+	// preCleanQueryFlag defines the file holding the query of the destination documents deleted before reindexing
+	preCleanQueryFlag = cli.StringFlag{
+		Name: "pre-clean-query",
+		Usage: "The JSON file holding a search body with a query, in the same format as query-file. If set, the documents " +
+			"matching the query are deleted from the existing destination indices before reindexing. Requires --confirm",
+		Value: "",
+	}
+	// confirmFlag defines a bool flag for confirming the destructive operations
+	confirmFlag = cli.BoolFlag{
+		Name:  "confirm",
+		Usage: "Confirms that the documents matching pre-clean-query can be deleted from the destination",
+	}
+	// deadLetterFileFlag defines the file where the documents that failed to be indexed are written
+	deadLetterFileFlag = cli.StringFlag{
+		Name: "dead-letter-file",
+		Usage: "The JSON lines file where the documents that failed to be indexed (e.g. mapping errors) are appended, along with " +
+			"the error reported by the destination. If not set, the first failed document aborts the reindexing",
+		Value: "",
+	}
+	// maxFailuresFlag defines the number of failed documents accepted before the reindexing is aborted
+	maxFailuresFlag = cli.Uint64Flag{
+		Name:  "max-failures",
+		Usage: "The number of failed documents written in the dead-letter-file before the reindexing is aborted. Requires dead-letter-file",
+		Value: 0,
+	}
+	// createOnlyFlag defines a bool flag for skipping the documents that already exist in the destination
+	createOnlyFlag = cli.BoolFlag{
+		Name: "create-only",
+		Usage: "If set, the documents are written using the create bulk action instead of index, so the documents that already " +
+			"exist in the destination are skipped (and counted separately) instead of being overwritten",
+	}
+	// strictMappingFlag defines a bool flag for aborting the reindexing on incompatible mappings
+	strictMappingFlag = cli.BoolFlag{
+		Name: "strict-mapping",
+		Usage: "If set, the reindexing is aborted if the mapping of an index that already exists in the destination has " +
+			"fields with different types than the source mapping. Otherwise, the incompatible fields are only reported",
+	}
+	// resumeFlag defines a bool flag for resuming an interrupted reindexing from the checkpoint file
+	resumeFlag = cli.BoolFlag{
+		Name: "resume",
+		Usage: "If set, the reindexing tool will resume from the checkpoint file, skipping the already reindexed documents. " +
+			"The checkpoint must have been created for the same source and destination clusters. Implies --overwrite",
+	}
 )
 
 const helpTemplate = `NAME:
@@ -48,11 +185,32 @@ func main() {
 	app := cli.NewApp()
 	cli.AppHelpTemplate = helpTemplate
 	app.Name = "Elasticsearch reindexing CLI App"
-	app.Version = "v1.0.0"
+	app.Version = version.GetVersion()
 	app.Usage = "This is the entry point for Elasticsearch reindexing tool"
 	app.Flags = []cli.Flag{
 		overwriteFlag,
 		skipMappingsFlag,
+		maxRetriesFlag,
+		retryBaseDelayFlag,
+		scrollKeepAliveFlag,
+		reindexWorkersFlag,
+		progressIntervalFlag,
+		checkpointFileFlag,
+		resumeFlag,
+		validateCountFlag,
+		countToleranceFlag,
+		disableRefreshFlag,
+		bulkRateFlag,
+		bulkMaxDocsFlag,
+		bulkMaxBytesFlag,
+		queryFileFlag,
+		preCleanQueryFlag,
+		confirmFlag,
+		deadLetterFileFlag,
+		maxFailuresFlag,
+		createOnlyFlag,
+		strictMappingFlag,
+		logging.LogJSON,
 	}
 	app.Authors = []cli.Author{
 		{
@@ -62,45 +220,186 @@ func main() {
 	}
 
 	app.Action = startReindexing
+	app.OnUsageError = exitcodes.OnUsageError
 
 	err := app.Run(os.Args)
 	if err != nil {
 		log.Error(err.Error())
-		os.Exit(1)
+		os.Exit(exitcodes.ExitCode(err))
 	}
 }
 
-func startReindexing(ctx *cli.Context) {
+func startReindexing(ctx *cli.Context) error {
+	if ctx.Bool(logging.LogJSON.Name) {
+		err := logging.SetConsoleJSONFormatter()
+		if err != nil {
+			return err
+		}
+	}
+
+	if ctx.Uint64(maxFailuresFlag.Name) > 0 && len(ctx.String(deadLetterFileFlag.Name)) == 0 {
+		return exitcodes.NewUsageError(fmt.Errorf("--%s requires --%s, otherwise the failed documents are lost", maxFailuresFlag.Name, deadLetterFileFlag.Name))
+	}
+
 	cfg, err := loadConfig()
 	if err != nil {
-		log.Error("cannot load configuration", "error", err)
-		return
+		return exitcodes.NewUsageError(fmt.Errorf("%w while loading the configuration", err))
+	}
+
+	applyRetryFlags(ctx, &cfg.Indexers.Input)
+	applyRetryFlags(ctx, &cfg.Indexers.Output)
+	cfg.Indexers.Input.ScrollKeepAlive = ctx.Duration(scrollKeepAliveFlag.Name)
+	cfg.Indexers.NumReindexWorkers = ctx.Int(reindexWorkersFlag.Name)
+	cfg.Indexers.DisableRefresh = ctx.Bool(disableRefreshFlag.Name)
+	cfg.Indexers.BulkRate = ctx.Uint64(bulkRateFlag.Name)
+	cfg.Indexers.BulkMaxDocuments = ctx.Int(bulkMaxDocsFlag.Name)
+	cfg.Indexers.BulkMaxBytes = ctx.Int(bulkMaxBytesFlag.Name)
+	cfg.Indexers.ProgressInterval = ctx.Duration(progressIntervalFlag.Name)
+	cfg.Indexers.CreateOnly = ctx.Bool(createOnlyFlag.Name)
+
+	queryFile := ctx.String(queryFileFlag.Name)
+	if len(queryFile) > 0 {
+		cfg.Indexers.QueryFilter, err = loadQueryFilter(queryFile)
+		if err != nil {
+			return exitcodes.NewUsageError(fmt.Errorf("%w while loading the query file %s", err, queryFile))
+		}
+	}
+
+	preCleanQueryFile := ctx.String(preCleanQueryFlag.Name)
+	if len(preCleanQueryFile) > 0 {
+		err = checkPreCleanFlags(ctx)
+		if err != nil {
+			return exitcodes.NewUsageError(err)
+		}
+
+		cfg.Indexers.PreCleanQuery, err = loadQueryFilter(preCleanQueryFile)
+		if err != nil {
+			return exitcodes.NewUsageError(fmt.Errorf("%w while loading the pre-clean query file %s", err, preCleanQueryFile))
+		}
 	}
 
 	reindexer, err := process.CreateReindexer(cfg)
 	if err != nil {
-		log.Error("cannot create reindexer", "error", err)
-		return
+		return exitcodes.NewUsageError(fmt.Errorf("%w while creating the reindexer", err))
 	}
 
-	multiWriteReindexer, err := process.NewReindexerMultiWrite(reindexer, cfg.Indexers.IndicesConfig)
+	resume := ctx.Bool(resumeFlag.Name)
+	checkpoints, err := process.NewCheckpointStore(process.ArgsCheckpointStore{
+		FilePath:       ctx.String(checkpointFileFlag.Name),
+		SourceURL:      cfg.Indexers.Input.URL,
+		DestinationURL: cfg.Indexers.Output.URL,
+		Indices:        getRequestedIndices(cfg.Indexers.IndicesConfig),
+		Resume:         resume,
+	})
 	if err != nil {
-		log.Error("cannot create multi-write reindexer", "error", err)
-		return
+		return exitcodes.NewIOError(fmt.Errorf("%w while creating the checkpoint store", err))
 	}
 
+	err = reindexer.SetCheckpointHandler(checkpoints)
+	if err != nil {
+		return fmt.Errorf("%w while setting the checkpoint handler", err)
+	}
+
+	deadLetterFile := ctx.String(deadLetterFileFlag.Name)
+	if len(deadLetterFile) > 0 {
+		deadLetter, errDeadLetter := process.NewDeadLetterWriter(process.ArgsDeadLetterWriter{
+			FilePath:    deadLetterFile,
+			MaxFailures: ctx.Uint64(maxFailuresFlag.Name),
+		})
+		if errDeadLetter != nil {
+			return exitcodes.NewIOError(fmt.Errorf("%w while creating the dead-letter writer", errDeadLetter))
+		}
+		defer closeDeadLetter(deadLetter, deadLetterFile)
+
+		err = reindexer.SetFailedDocumentsHandler(deadLetter)
+		if err != nil {
+			return fmt.Errorf("%w while setting the failed documents handler", err)
+		}
+	}
+
+	multiWriteReindexer, err := process.NewReindexerMultiWrite(reindexer, cfg.Indexers.IndicesConfig, checkpoints)
+	if err != nil {
+		return exitcodes.NewUsageError(fmt.Errorf("%w while creating the multi-write reindexer", err))
+	}
+
+	err = multiWriteReindexer.CheckMappings(ctx.Bool(strictMappingFlag.Name))
+	if err != nil {
+		return exitcodes.NewIOError(err)
+	}
+
+	if cfg.Indexers.PreCleanQuery != nil {
+		err = multiWriteReindexer.PreClean()
+		if err != nil {
+			return exitcodes.NewIOError(err)
+		}
+	}
+
+	// the destination indices were already created by the interrupted reindexing
+	overwrite := ctx.Bool(overwriteFlag.Name) || resume
 	skipMappings := ctx.Bool(skipMappingsFlag.Name)
-	err = multiWriteReindexer.ProcessNoTimestamp(ctx.Bool(overwriteFlag.Name), skipMappings)
+	err = multiWriteReindexer.ProcessNoTimestamp(overwrite, skipMappings)
 	if err != nil {
-		log.Error(err.Error())
-		return
+		return exitcodes.NewIOError(err)
 	}
 
-	err = multiWriteReindexer.ProcessWithTimestamp(ctx.Bool(overwriteFlag.Name), skipMappings)
+	err = multiWriteReindexer.ProcessWithTimestamp(overwrite, skipMappings)
 	if err != nil {
-		log.Error(err.Error())
-		return
+		return exitcodes.NewIOError(err)
+	}
+
+	if !ctx.BoolT(validateCountFlag.Name) {
+		return nil
 	}
+
+	return exitcodes.NewIOError(multiWriteReindexer.ValidateCounts(ctx.Uint64(countToleranceFlag.Name)))
+}
+
+// checkPreCleanFlags checks the flags used along with --pre-clean-query. The pre-clean is refused when resuming, since
+// it would also delete the documents already copied by the interrupted reindexing
+func checkPreCleanFlags(ctx *cli.Context) error {
+	if !ctx.Bool(confirmFlag.Name) {
+		return fmt.Errorf("--%s deletes documents from the destination, set --%s in order to proceed", preCleanQueryFlag.Name, confirmFlag.Name)
+	}
+	if ctx.Bool(resumeFlag.Name) {
+		return fmt.Errorf("--%s can not be used along with --%s", preCleanQueryFlag.Name, resumeFlag.Name)
+	}
+	if !ctx.Bool(overwriteFlag.Name) && !ctx.Bool(skipMappingsFlag.Name) {
+		return fmt.Errorf("--%s requires --%s or --%s, as the destination indices already exist", preCleanQueryFlag.Name, overwriteFlag.Name, skipMappingsFlag.Name)
+	}
+
+	return nil
+}
+
+type deadLetterCloser interface {
+	NumFailedDocuments() uint64
+	Close() error
+}
+
+func closeDeadLetter(deadLetter deadLetterCloser, filePath string) {
+	numFailed := deadLetter.NumFailedDocuments()
+	if numFailed > 0 {
+		log.Warn("some documents failed to be indexed", "num failed documents", numFailed, "dead-letter file", filePath)
+	}
+
+	err := deadLetter.Close()
+	if err != nil {
+		log.Warn("cannot close the dead-letter file", "error", err)
+	}
+}
+
+func applyRetryFlags(ctx *cli.Context, cfg *config.ElasticInstanceConfig) {
+	cfg.MaxRetries = ctx.Int(maxRetriesFlag.Name)
+	cfg.RetryBaseDelay = ctx.Duration(retryBaseDelayFlag.Name)
+}
+
+// getRequestedIndices returns the indices reindexed in the current run, as configured
+func getRequestedIndices(cfg config.IndicesConfig) []string {
+	indices := append([]string{}, cfg.Indices...)
+	if cfg.WithTimestamp.Enabled {
+		indices = append(indices, cfg.WithTimestamp.IndicesWithTimestamp...)
+	}
+
+	return indices
 }
 
 func loadConfig() (*config.GeneralConfig, error) {
@@ -118,6 +417,15 @@ func loadConfig() (*config.GeneralConfig, error) {
 	return &tc, nil
 }
 
+func loadQueryFilter(file string) (map[string]interface{}, error) {
+	queryBytes, err := loadBytesFromFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	return process.ParseQueryFilter(queryBytes)
+}
+
 func loadBytesFromFile(file string) ([]byte, error) {
 	return ioutil.ReadFile(file)
 }
@@ -10,7 +10,10 @@ import (
 	logger "github.com/multiversx/mx-chain-logger-go"
 	"github.com/multiversx/mx-chain-tools-go/elasticreindexer/config"
 	"github.com/multiversx/mx-chain-tools-go/elasticreindexer/elastic"
+	"github.com/multiversx/mx-chain-tools-go/elasticreindexer/exitcodes"
+	"github.com/multiversx/mx-chain-tools-go/elasticreindexer/logging"
 	"github.com/multiversx/mx-chain-tools-go/elasticreindexer/reader"
+	"github.com/multiversx/mx-chain-tools-go/elasticreindexer/version"
 	"github.com/pelletier/go-toml"
 	"github.com/urfave/cli"
 )
@@ -58,10 +61,11 @@ func main() {
 	app := cli.NewApp()
 	cli.AppHelpTemplate = helpTemplate
 	app.Name = "Index cr"
-	app.Version = "v1.0.0"
+	app.Version = version.GetVersion()
 	app.Usage = "Elasticsearch indices creator tool"
 	app.Flags = []cli.Flag{
 		configPath,
+		logging.LogJSON,
 	}
 	app.Authors = []cli.Author{
 		{
@@ -73,21 +77,28 @@ func main() {
 	_ = logger.SetLogLevel("*:DEBUG")
 
 	app.Action = createIndexesAndMappings
+	app.OnUsageError = exitcodes.OnUsageError
 
 	err := app.Run(os.Args)
 	if err != nil {
 		log.Error(err.Error())
-		os.Exit(1)
+		os.Exit(exitcodes.ExitCode(err))
 	}
 
 }
 
-func createIndexesAndMappings(ctx *cli.Context) {
+func createIndexesAndMappings(ctx *cli.Context) error {
+	if ctx.Bool(logging.LogJSON.Name) {
+		err := logging.SetConsoleJSONFormatter()
+		if err != nil {
+			return err
+		}
+	}
+
 	cfgPath := ctx.String(configPath.Name)
 	cfg, err := loadConfigFile(cfgPath)
 	if err != nil {
-		log.Error("cannot load config file", "error", err.Error())
-		return
+		return exitcodes.NewUsageError(fmt.Errorf("%w while loading the config file", err))
 	}
 
 	pathToMappings := path.Join(cfgPath, "noKibana")
@@ -97,24 +108,25 @@ func createIndexesAndMappings(ctx *cli.Context) {
 
 	indexesMappings, _, err := reader.GetElasticTemplatesAndPolicies(pathToMappings, cfg.ClusterConfig.EnabledIndices)
 	if err != nil {
-		log.Error("cannot load templates", "error", err.Error())
-		return
+		return exitcodes.NewIOError(fmt.Errorf("%w while loading the templates", err))
 	}
 
 	err = createIndies(cfg, indexesMappings)
 	if err != nil {
-		log.Error("cannot create templates", "error", err.Error())
-		return
+		return exitcodes.NewIOError(fmt.Errorf("%w while creating the templates", err))
 	}
 
 	log.Info("all indices were created")
+
+	return nil
 }
 
 func createIndies(cfg *Cfg, indexesMappings map[string]*bytes.Buffer) error {
 	databaseClient, err := elastic.NewElasticClient(config.ElasticInstanceConfig{
-		URL:      cfg.ClusterConfig.URL,
-		Username: cfg.ClusterConfig.Username,
-		Password: cfg.ClusterConfig.Password,
+		URL:        cfg.ClusterConfig.URL,
+		Username:   cfg.ClusterConfig.Username,
+		Password:   cfg.ClusterConfig.Password,
+		MaxRetries: elastic.DefaultMaxRetries,
 	})
 	if err != nil {
 		return err
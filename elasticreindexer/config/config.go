@@ -1,5 +1,7 @@
 package config
 
+import "time"
+
 // GeneralConfig holds the entire configuration
 type GeneralConfig struct {
 	Indexers IndexersConfig `toml:"config"`
@@ -10,6 +12,45 @@ type IndexersConfig struct {
 	Input         ElasticInstanceConfig `toml:"input"`
 	Output        ElasticInstanceConfig `toml:"output"`
 	IndicesConfig IndicesConfig         `toml:"indices"`
+	IndexSettings IndexSettings         `toml:"index-settings"`
+
+	// NumReindexWorkers is set from the CLI flags and represents the number of goroutines that bulk index the scroll pages
+	NumReindexWorkers int `toml:"-"`
+	// DisableRefresh is set from the CLI flags. If set, the refresh of the destination indices is disabled while they
+	// are bulk loaded
+	DisableRefresh bool `toml:"-"`
+	// BulkRate is set from the CLI flags and represents the maximum number of documents indexed per second in the
+	// destination. Zero means unlimited
+	BulkRate uint64 `toml:"-"`
+	// BulkMaxDocuments and BulkMaxBytes are set from the CLI flags and represent the thresholds of a bulk request,
+	// the first one reached starting a new bulk request. Zero values mean unlimited documents and the default size
+	BulkMaxDocuments int `toml:"-"`
+	BulkMaxBytes     int `toml:"-"`
+	// QueryFilter is loaded from the query file provided in the CLI flags. If set, only the documents matching it are
+	// reindexed
+	QueryFilter map[string]interface{} `toml:"-"`
+	// ProgressInterval is set from the CLI flags and represents how often the reindexing progress of an index is
+	// logged. Zero disables the progress logs
+	ProgressInterval time.Duration `toml:"-"`
+	// PreCleanQuery is loaded from the pre-clean query file provided in the CLI flags. If set, the destination documents
+	// matching it are deleted before reindexing
+	PreCleanQuery map[string]interface{} `toml:"-"`
+	// CreateOnly is set from the CLI flags. If set, the documents are written using the create bulk action, so the
+	// documents that already exist in the destination are skipped instead of being overwritten
+	CreateOnly bool `toml:"-"`
+}
+
+// IndexSettings holds the settings applied on the destination indices when they are created. The unset values are
+// not applied, so the cluster defaults are used
+type IndexSettings struct {
+	NumberOfShards   int    `toml:"number-of-shards"`
+	NumberOfReplicas *int   `toml:"number-of-replicas"`
+	RefreshInterval  string `toml:"refresh-interval"`
+}
+
+// IsEmpty returns true if none of the settings is set
+func (settings IndexSettings) IsEmpty() bool {
+	return settings.NumberOfShards == 0 && settings.NumberOfReplicas == nil && len(settings.RefreshInterval) == 0
 }
 
 // ElasticInstanceConfig holds the configuration needed for connecting to an Elasticsearch instance
@@ -17,11 +58,23 @@ type ElasticInstanceConfig struct {
 	URL      string `toml:"url"`
 	Username string `toml:"username"`
 	Password string `toml:"password"`
+
+	// MaxRetries and RetryBaseDelay are set from the CLI flags. Zero max retries means no retries, while zero retry
+	// base delay means the default of the elastic client
+	MaxRetries     int           `toml:"-"`
+	RetryBaseDelay time.Duration `toml:"-"`
+	// ScrollKeepAlive is set from the CLI flags and represents how long the search context of a scroll is kept alive
+	// between two scroll requests. Zero means the defaults of the elastic client
+	ScrollKeepAlive time.Duration `toml:"-"`
 }
 
 // IndicesConfig holds the configuration for the indices
 type IndicesConfig struct {
-	Indices       []string `toml:"indices-no-timestamp"`
+	Indices []string `toml:"indices-no-timestamp"`
+	// SortFields holds, for the indices without timestamp, the sortable field (a keyword or numeric field with doc values)
+	// holding a unique value per document. Such an index is read in the order of the field, so an interrupted
+	// reindexing is resumed after the last saved document, while the other indices start over
+	SortFields    map[string]string `toml:"sort-fields"`
 	WithTimestamp struct {
 		Enabled              bool     `toml:"enabled"`
 		BlockchainStartTime  int64    `toml:"blockchain-start-time"`
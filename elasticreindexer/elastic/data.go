@@ -1,41 +1,93 @@
 package elastic
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
 	"net/http"
 )
 
 const numOfErrorsToExtractBulkResponse = 5
 
-// bulkRequestResponse defines the structure of a bulk request response
+// bulkRequestResponse defines the structure of a bulk request response. Each item is keyed by its action (e.g. index)
 type bulkRequestResponse struct {
-	Errors bool `json:"errors"`
-	Items  []struct {
-		Index struct {
-			Status int `json:"status"`
-			Error  struct {
-				Type   string `json:"type"`
-				Reason string `json:"reason"`
-			} `json:"error"`
-		} `json:"index"`
-	} `json:"items"`
+	Errors bool                          `json:"errors"`
+	Items  []map[string]bulkResponseItem `json:"items"`
 }
 
-func extractErrorFromBulkResponse(response *bulkRequestResponse) error {
-	count := 0
+type bulkResponseItem struct {
+	ID     string `json:"_id"`
+	Status int    `json:"status"`
+	Error  struct {
+		Type   string `json:"type"`
+		Reason string `json:"reason"`
+	} `json:"error"`
+}
+
+// BulkItemFailure holds a document that could not be written by a bulk request, along with the error reported for it
+type BulkItemFailure struct {
+	ID        string          `json:"id"`
+	Status    int             `json:"status"`
+	ErrorType string          `json:"errorType"`
+	Reason    string          `json:"reason"`
+	Document  json.RawMessage `json:"document,omitempty"`
+}
+
+// BulkItemsError is returned when some of the items of a bulk request failed. It holds all the failed items, while
+// its message only holds the first ones
+type BulkItemsError struct {
+	NumItems int
+	Failures []BulkItemFailure
+}
+
+// Error returns the number of failed items and the errors of the first ones
+func (err *BulkItemsError) Error() string {
 	errorsString := ""
-	for _, item := range response.Items {
-		if item.Index.Status < http.StatusBadRequest {
-			continue
+	for idx, failure := range err.Failures {
+		if idx >= numOfErrorsToExtractBulkResponse {
+			break
 		}
 
-		count++
-		errorsString += fmt.Sprintf("{ status code: %d, error type: %s, reason: %s }\n", item.Index.Status, item.Index.Error.Type, item.Index.Error.Reason)
+		errorsString += fmt.Sprintf("{ status code: %d, error type: %s, reason: %s }\n", failure.Status, failure.ErrorType, failure.Reason)
+	}
+
+	return fmt.Sprintf("%s, %d out of %d items failed, first errors:\n%s", errBulkItemsFailed.Error(), len(err.Failures), err.NumItems, errorsString)
+}
 
-		if count == numOfErrorsToExtractBulkResponse {
-			break
+// Unwrap returns errBulkItemsFailed, so the error can be checked using errors.Is
+func (err *BulkItemsError) Unwrap() error {
+	return errBulkItemsFailed
+}
+
+// extractErrorFromBulkResponse returns the failed items of the bulk response. Since the items of the response are in
+// the order of the actions of the request, the document of each failed item is taken from the request body, where
+// each action line is followed by its document line
+func extractErrorFromBulkResponse(response *bulkRequestResponse, requestBody []byte) *BulkItemsError {
+	lines := bytes.Split(requestBody, []byte("\n"))
+	bulkErr := &BulkItemsError{
+		NumItems: len(response.Items),
+		Failures: make([]BulkItemFailure, 0),
+	}
+	for idx, actionItem := range response.Items {
+		for _, item := range actionItem {
+			if item.Status < http.StatusBadRequest {
+				continue
+			}
+
+			failure := BulkItemFailure{
+				ID:        item.ID,
+				Status:    item.Status,
+				ErrorType: item.Error.Type,
+				Reason:    item.Error.Reason,
+			}
+			documentLineIndex := 2*idx + 1
+			if documentLineIndex < len(lines) && json.Valid(lines[documentLineIndex]) {
+				failure.Document = lines[documentLineIndex]
+			}
+
+			bulkErr.Failures = append(bulkErr.Failures, failure)
 		}
 	}
 
-	return fmt.Errorf("%s", errorsString)
+	return bulkErr
 }
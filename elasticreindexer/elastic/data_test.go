@@ -0,0 +1,71 @@
+package elastic
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtractErrorFromBulkResponse(t *testing.T) {
+	t.Parallel()
+
+	responseBytes := []byte(`{"errors":true,"items":[` +
+		`{"index":{"_id":"id1","status":201}},` +
+		`{"index":{"_id":"id2","status":400,"error":{"type":"mapper_parsing_exception","reason":"failed to parse field"}}},` +
+		`{"create":{"_id":"id3","status":409,"error":{"type":"version_conflict_engine_exception","reason":"document already exists"}}}]}`)
+	requestBody := []byte(`{ "index" : { "_id" : "id1" } }` + "\n" + `{"field":"a"}` + "\n" +
+		`{ "index" : { "_id" : "id2" } }` + "\n" + `{"field":"b"}` + "\n" +
+		`{ "create" : { "_id" : "id3" } }` + "\n" + `{"field":"c"}` + "\n")
+
+	response := &bulkRequestResponse{}
+	require.Nil(t, json.Unmarshal(responseBytes, response))
+
+	bulkErr := extractErrorFromBulkResponse(response, requestBody)
+	require.Equal(t, 3, bulkErr.NumItems)
+	require.Equal(t, []BulkItemFailure{
+		{
+			ID:        "id2",
+			Status:    400,
+			ErrorType: "mapper_parsing_exception",
+			Reason:    "failed to parse field",
+			Document:  json.RawMessage(`{"field":"b"}`),
+		},
+		{
+			ID:        "id3",
+			Status:    409,
+			ErrorType: "version_conflict_engine_exception",
+			Reason:    "document already exists",
+			Document:  json.RawMessage(`{"field":"c"}`),
+		},
+	}, bulkErr.Failures)
+
+	require.True(t, errors.Is(bulkErr, errBulkItemsFailed))
+	require.True(t, strings.Contains(bulkErr.Error(), "2 out of 3 items failed"))
+	require.True(t, strings.Contains(bulkErr.Error(), "mapper_parsing_exception"))
+}
+
+func TestExtractErrorFromBulkResponse_MissingDocumentLines(t *testing.T) {
+	t.Parallel()
+
+	response := &bulkRequestResponse{}
+	require.Nil(t, json.Unmarshal([]byte(`{"errors":true,"items":[{"index":{"_id":"id1","status":400}}]}`), response))
+
+	bulkErr := extractErrorFromBulkResponse(response, []byte("{}\n"))
+	require.Len(t, bulkErr.Failures, 1)
+	require.Equal(t, "id1", bulkErr.Failures[0].ID)
+	require.Nil(t, bulkErr.Failures[0].Document)
+}
+
+func TestBulkItemsError_ShouldOnlyPrintTheFirstErrors(t *testing.T) {
+	t.Parallel()
+
+	bulkErr := &BulkItemsError{NumItems: 10}
+	for i := 0; i < 10; i++ {
+		bulkErr.Failures = append(bulkErr.Failures, BulkItemFailure{Status: 400, ErrorType: "mapper_parsing_exception"})
+	}
+
+	require.Equal(t, numOfErrorsToExtractBulkResponse, strings.Count(bulkErr.Error(), "mapper_parsing_exception"))
+}
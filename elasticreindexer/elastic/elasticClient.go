@@ -22,13 +22,25 @@ var (
 	httpStatusesForRetry = []int{http.StatusTooManyRequests, http.StatusBadGateway, http.StatusInternalServerError, http.StatusServiceUnavailable, http.StatusGatewayTimeout}
 )
 
+// DefaultMaxRetries is the default maximum number of retries for a request that failed with a retryable status code
+const DefaultMaxRetries = 10
+
 const (
 	stepDelayBetweenRequests = 500 * time.Millisecond
-	numRetriesBackOff        = 10
+	retryBaseDelay           = time.Second
+	initialScrollKeepAlive   = 10 * time.Minute
+	scrollKeepAlive          = 2 * time.Minute
+	pageSize                 = 9000
 )
 
 type esClient struct {
-	client *elasticsearch.Client
+	client     *elasticsearch.Client
+	maxRetries int
+
+	// initialScrollKeepAlive and scrollKeepAlive are the durations the search context of a scroll is kept alive after
+	// the first request and after each of the next ones, respectively
+	initialScrollKeepAlive time.Duration
+	scrollKeepAlive        time.Duration
 
 	// countScroll is used to be incremented after each scroll so the scroll duration is different each time,
 	// bypassing any possible caching based on the same request
@@ -37,29 +49,56 @@ type esClient struct {
 
 // NewElasticClient will create a new instance of an esClient
 func NewElasticClient(cfg config.ElasticInstanceConfig) (*esClient, error) {
+	if cfg.MaxRetries < 0 {
+		return nil, fmt.Errorf("%w, provided %d", errInvalidMaxRetries, cfg.MaxRetries)
+	}
+	if cfg.RetryBaseDelay < 0 {
+		return nil, fmt.Errorf("%w, provided %v", errInvalidRetryBaseDelay, cfg.RetryBaseDelay)
+	}
+	if cfg.ScrollKeepAlive < 0 {
+		return nil, fmt.Errorf("%w, provided %v", errInvalidScrollKeepAlive, cfg.ScrollKeepAlive)
+	}
+
+	baseDelay := cfg.RetryBaseDelay
+	if baseDelay == 0 {
+		baseDelay = retryBaseDelay
+	}
+	initialKeepAlive, keepAlive := initialScrollKeepAlive, scrollKeepAlive
+	if cfg.ScrollKeepAlive > 0 {
+		initialKeepAlive, keepAlive = cfg.ScrollKeepAlive, cfg.ScrollKeepAlive
+	}
+
 	elasticClient, err := elasticsearch.NewClient(elasticsearch.Config{
 		Addresses:     []string{cfg.URL},
 		Username:      cfg.Username,
 		Password:      cfg.Password,
 		RetryOnStatus: httpStatusesForRetry,
 		RetryBackoff: func(i int) time.Duration {
-			// A simple exponential delay
-			d := time.Duration(math.Exp2(float64(i))) * time.Second
+			d := computeRetryBackoff(baseDelay, i)
 			log.Info("elastic: retry backoff", "attempt", i, "sleep duration", d)
 			return d
 		},
-		MaxRetries: numRetriesBackOff,
+		// the transport counts the first request as an attempt as well, so zero max retries means a single attempt
+		MaxRetries: cfg.MaxRetries + 1,
 	})
 	if err != nil {
 		return nil, err
 	}
 
 	return &esClient{
-		client:      elasticClient,
-		countScroll: 0,
+		client:                 elasticClient,
+		maxRetries:             cfg.MaxRetries,
+		initialScrollKeepAlive: initialKeepAlive,
+		scrollKeepAlive:        keepAlive,
+		countScroll:            0,
 	}, nil
 }
 
+// computeRetryBackoff returns a simple exponential delay, starting from the provided base delay
+func computeRetryBackoff(baseDelay time.Duration, attempt int) time.Duration {
+	return time.Duration(math.Exp2(float64(attempt))) * baseDelay
+}
+
 // GetMultiple queries a multi search and returns the responses
 func (esc *esClient) GetMultiple(index string, requests []string) ([]byte, error) {
 	var query string
@@ -125,9 +164,22 @@ func (esc *esClient) GetMapping(index string) (*bytes.Buffer, error) {
 		return nil, err
 	}
 
-	propertiesRes := gjson.Get(string(respBytes), fmt.Sprintf("%s-000001", index))
+	return extractSingleMapping(index, respBytes)
+}
 
-	return bytes.NewBufferString(propertiesRes.Raw), nil
+// extractSingleMapping returns the mapping of the only index found in the _mapping response, whatever its name is,
+// since the provided index can be an alias pointing to an index with any suffix
+func extractSingleMapping(index string, respBytes []byte) (*bytes.Buffer, error) {
+	indicesMappings := gjson.ParseBytes(respBytes).Map()
+	if len(indicesMappings) != 1 {
+		return nil, fmt.Errorf("%w: found %d mappings for index %s", errUnexpectedMappingsCount, len(indicesMappings), index)
+	}
+
+	for _, mapping := range indicesMappings {
+		return bytes.NewBufferString(mapping.Raw), nil
+	}
+
+	return nil, nil
 }
 
 // CreateIndexWithMapping will create an index with the provided
@@ -154,6 +206,93 @@ func (esc *esClient) CreateIndexWithMapping(targetIndex string, body *bytes.Buff
 	return nil
 }
 
+// CreateIndexWithSettings will create an index with the provided mapping, applying the provided settings at creation
+func (esc *esClient) CreateIndexWithSettings(targetIndex string, mapping *bytes.Buffer, settings config.IndexSettings) error {
+	body, err := createIndexBody(mapping, settings)
+	if err != nil {
+		return err
+	}
+
+	return esc.CreateIndexWithMapping(targetIndex, body)
+}
+
+func createIndexBody(mapping *bytes.Buffer, settings config.IndexSettings) (*bytes.Buffer, error) {
+	if settings.NumberOfShards < 0 {
+		return nil, fmt.Errorf("%w, number of shards %d", errInvalidIndexSettings, settings.NumberOfShards)
+	}
+	if settings.NumberOfReplicas != nil && *settings.NumberOfReplicas < 0 {
+		return nil, fmt.Errorf("%w, number of replicas %d", errInvalidIndexSettings, *settings.NumberOfReplicas)
+	}
+
+	body := make(map[string]interface{})
+	if mapping != nil && mapping.Len() > 0 {
+		mappingBody := make(map[string]json.RawMessage)
+		err := json.Unmarshal(mapping.Bytes(), &mappingBody)
+		if err != nil {
+			return nil, fmt.Errorf("%w while decoding the mapping", err)
+		}
+
+		for key, value := range mappingBody {
+			body[key] = value
+		}
+	}
+
+	indexSettings := make(map[string]interface{})
+	if settings.NumberOfShards > 0 {
+		indexSettings["number_of_shards"] = settings.NumberOfShards
+	}
+	if settings.NumberOfReplicas != nil {
+		indexSettings["number_of_replicas"] = *settings.NumberOfReplicas
+	}
+	if len(settings.RefreshInterval) > 0 {
+		indexSettings["refresh_interval"] = settings.RefreshInterval
+	}
+	body["settings"] = map[string]interface{}{
+		"index": indexSettings,
+	}
+
+	bodyBytes, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+
+	return bytes.NewBuffer(bodyBytes), nil
+}
+
+// SetRefreshInterval will update the refresh interval of the provided index. An empty refresh interval resets the
+// setting to the cluster default
+func (esc *esClient) SetRefreshInterval(index string, refreshInterval string) error {
+	var value interface{}
+	if len(refreshInterval) > 0 {
+		value = refreshInterval
+	}
+
+	bodyBytes, err := json.Marshal(map[string]interface{}{
+		"index": map[string]interface{}{
+			"refresh_interval": value,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	res, err := esc.client.Indices.PutSettings(
+		bytes.NewBuffer(bodyBytes),
+		esc.client.Indices.PutSettings.WithIndex(index),
+	)
+	if err != nil {
+		return err
+	}
+
+	defer closeBody(res)
+
+	if res.IsError() {
+		return fmt.Errorf("%s", res.String())
+	}
+
+	return nil
+}
+
 // PutIndexTemplate creates an elasticsearch index template
 func (esc *esClient) PutIndexTemplate(templateName string, body *bytes.Buffer) error {
 	res, err := esc.client.Indices.PutTemplate(templateName, body)
@@ -222,16 +361,56 @@ func exists(res *esapi.Response, err error) bool {
 	}
 }
 
-// DoScrollRequestAllDocuments will perform a documents request using scroll api
+// DoScrollRequestAllDocuments will perform a documents request using scroll api. If the scroll expires (the search
+// context is no longer found) and the request is sorted ascending on a single field, the scroll is restarted from the
+// last sort value read, instead of failing
 func (esc *esClient) DoScrollRequestAllDocuments(
 	index string,
 	body []byte,
 	handlerFunc func(responseBytes []byte) error,
+) error {
+	tracker := &sortValuesTracker{}
+	trackingHandlerFunc := func(responseBytes []byte) error {
+		err := handlerFunc(responseBytes)
+		if err != nil {
+			return err
+		}
+
+		tracker.pageRead(responseBytes)
+		return nil
+	}
+
+	searchBody := body
+	previousLastSortValue := ""
+	for numRestarts := 0; ; numRestarts++ {
+		err := esc.doScrollRequest(index, searchBody, trackingHandlerFunc)
+		if !isSearchContextMissingError(err) {
+			return err
+		}
+		if numRestarts > 0 && tracker.lastSortValue == previousLastSortValue {
+			return fmt.Errorf("%w, consider increasing the scroll keep-alive: %s", errScrollRestartNoProgress, err.Error())
+		}
+
+		searchBody, err = tracker.createRestartBody(body)
+		if err != nil {
+			return fmt.Errorf("%w, the scroll expired, consider increasing the scroll keep-alive", err)
+		}
+
+		previousLastSortValue = tracker.lastSortValue
+		log.Warn("the scroll expired, restarting it from the last sort value",
+			"index", index, "from", tracker.lastSortValue, "num restarts", numRestarts+1)
+	}
+}
+
+func (esc *esClient) doScrollRequest(
+	index string,
+	body []byte,
+	handlerFunc func(responseBytes []byte) error,
 ) error {
 	esc.countScroll++
 	res, err := esc.client.Search(
-		esc.client.Search.WithSize(9000),
-		esc.client.Search.WithScroll(10*time.Minute+time.Duration(esc.countScroll)*time.Millisecond),
+		esc.client.Search.WithSize(pageSize),
+		esc.client.Search.WithScroll(esc.initialScrollKeepAlive+time.Duration(esc.countScroll)*time.Millisecond),
 		esc.client.Search.WithContext(context.Background()),
 		esc.client.Search.WithIndex(index),
 		esc.client.Search.WithBody(bytes.NewBuffer(body)),
@@ -254,6 +433,59 @@ func (esc *esClient) DoScrollRequestAllDocuments(
 	return esc.iterateScroll(scrollID.String(), handlerFunc)
 }
 
+// DoSearchAfterRequestAllDocuments will perform a documents request paginated using search_after instead of the scroll
+// api, so there is no search context to expire. The provided body must be sorted on a field holding unique values, and
+// it can already hold a search_after field, in which case only the documents after it are read
+func (esc *esClient) DoSearchAfterRequestAllDocuments(
+	index string,
+	body []byte,
+	handlerFunc func(responseBytes []byte) error,
+) error {
+	searchBody, err := withSearchAfter(body, gjson.GetBytes(body, searchAfterField).Raw)
+	if err != nil {
+		return err
+	}
+
+	for {
+		res, errSearch := esc.client.Search(
+			esc.client.Search.WithSize(pageSize),
+			esc.client.Search.WithContext(context.Background()),
+			esc.client.Search.WithIndex(index),
+			esc.client.Search.WithBody(bytes.NewBuffer(searchBody)),
+		)
+		if errSearch != nil {
+			return errSearch
+		}
+
+		bodyBytes, errSearch := getBytesFromResponse(res)
+		if errSearch != nil {
+			return errSearch
+		}
+
+		numberOfHits := gjson.GetBytes(bodyBytes, "hits.hits.#").Int()
+		if numberOfHits < 1 {
+			return nil
+		}
+
+		err = handlerFunc(bodyBytes)
+		if err != nil {
+			return err
+		}
+
+		lastSortValues := gjson.GetBytes(bodyBytes, fmt.Sprintf("hits.hits.%d.%s", numberOfHits-1, sortField)).Raw
+		if len(lastSortValues) == 0 {
+			return errSearchNotSorted
+		}
+
+		searchBody, err = withSearchAfter(body, lastSortValues)
+		if err != nil {
+			return err
+		}
+
+		time.Sleep(stepDelayBetweenRequests)
+	}
+}
+
 // DoBulkRequest will do a bulk of request to elastic server
 func (esc *esClient) DoBulkRequest(buff *bytes.Buffer, index string) error {
 	reader := bytes.NewReader(buff.Bytes())
@@ -263,14 +495,15 @@ func (esc *esClient) DoBulkRequest(buff *bytes.Buffer, index string) error {
 		esc.client.Bulk.WithIndex(index),
 	)
 	if err != nil {
-		return err
-	}
-	if res.IsError() {
-		return fmt.Errorf("%s", res.String())
+		return fmt.Errorf("%w while doing the bulk request, max retries %d", err, esc.maxRetries)
 	}
 
 	defer closeBody(res)
 
+	if res.IsError() {
+		return fmt.Errorf("%w, max retries %d, last response: %s", errBulkRequestFailed, esc.maxRetries, res.String())
+	}
+
 	bodyBytes, err := ioutil.ReadAll(res.Body)
 	if err != nil {
 		return err
@@ -283,7 +516,7 @@ func (esc *esClient) DoBulkRequest(buff *bytes.Buffer, index string) error {
 	}
 
 	if bulkResponse.Errors {
-		return extractErrorFromBulkResponse(bulkResponse)
+		return extractErrorFromBulkResponse(bulkResponse, buff.Bytes())
 	}
 
 	return nil
@@ -326,7 +559,7 @@ func (esc *esClient) getScrollResponse(scrollID string) ([]byte, error) {
 	esc.countScroll++
 	res, err := esc.client.Scroll(
 		esc.client.Scroll.WithScrollID(scrollID),
-		esc.client.Scroll.WithScroll(2*time.Minute+time.Duration(esc.countScroll)*time.Millisecond),
+		esc.client.Scroll.WithScroll(esc.scrollKeepAlive+time.Duration(esc.countScroll)*time.Millisecond),
 	)
 	if err != nil {
 		return nil, err
@@ -351,6 +584,52 @@ func (esc *esClient) clearScroll(scrollID string) error {
 	return nil
 }
 
+// DoDeleteByQuery deletes all the documents of the provided index matching the query of the provided search body and
+// returns the number of deleted documents. The documents changed while deleting do not fail the request, and the index
+// is refreshed once done, so the deleted documents are no longer counted
+func (esc *esClient) DoDeleteByQuery(index string, body []byte) (uint64, error) {
+	res, err := esc.client.DeleteByQuery(
+		[]string{index},
+		bytes.NewBuffer(body),
+		esc.client.DeleteByQuery.WithConflicts("proceed"),
+		esc.client.DeleteByQuery.WithRefresh(true),
+		esc.client.DeleteByQuery.WithContext(context.Background()),
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	respBytes, err := getBytesFromResponse(res)
+	if err != nil {
+		return 0, err
+	}
+
+	failures := gjson.GetBytes(respBytes, "failures")
+	if len(failures.Array()) > 0 {
+		return gjson.GetBytes(respBytes, "deleted").Uint(), fmt.Errorf("%w, failures: %s", errDeleteByQueryFailed, failures.Raw)
+	}
+
+	return gjson.GetBytes(respBytes, "deleted").Uint(), nil
+}
+
+// RefreshIndex refreshes the provided index, so all the indexed documents become visible for search and count requests
+func (esc *esClient) RefreshIndex(index string) error {
+	res, err := esc.client.Indices.Refresh(
+		esc.client.Indices.Refresh.WithIndex(index),
+	)
+	if err != nil {
+		return err
+	}
+
+	defer closeBody(res)
+
+	if res.IsError() {
+		return fmt.Errorf("%s", res.String())
+	}
+
+	return nil
+}
+
 // PutAlias will set the provided alias to the provided index
 func (esc *esClient) PutAlias(index string, alias string) error {
 	res, err := esc.client.Indices.PutAlias([]string{index}, alias)
@@ -367,6 +646,56 @@ func (esc *esClient) PutAlias(index string, alias string) error {
 	return nil
 }
 
+// SwapAlias will atomically move the provided alias from one index to another, in a single aliases request, so the
+// readers of the alias never see it missing. If the alias is not set on the source index (or the source index is empty),
+// the alias is only added to the destination index
+func (esc *esClient) SwapAlias(alias string, fromIndex string, toIndex string) error {
+	actions := make([]map[string]interface{}, 0, 2)
+	if len(fromIndex) > 0 && esc.doesIndexHaveAlias(fromIndex, alias) {
+		actions = append(actions, map[string]interface{}{
+			"remove": map[string]string{
+				"index": fromIndex,
+				"alias": alias,
+			},
+		})
+	}
+	actions = append(actions, map[string]interface{}{
+		"add": map[string]string{
+			"index": toIndex,
+			"alias": alias,
+		},
+	})
+
+	bodyBytes, err := json.Marshal(map[string]interface{}{
+		"actions": actions,
+	})
+	if err != nil {
+		return err
+	}
+
+	res, err := esc.client.Indices.UpdateAliases(bytes.NewBuffer(bodyBytes))
+	if err != nil {
+		return err
+	}
+
+	defer closeBody(res)
+
+	if res.IsError() {
+		return fmt.Errorf("%s", res.String())
+	}
+
+	return nil
+}
+
+func (esc *esClient) doesIndexHaveAlias(index string, alias string) bool {
+	res, err := esc.client.Indices.ExistsAlias(
+		[]string{alias},
+		esc.client.Indices.ExistsAlias.WithIndex(index),
+	)
+
+	return exists(res, err)
+}
+
 // IsInterfaceNil returns true if there is no value under the interface
 func (esc *esClient) IsInterfaceNil() bool {
 	return esc == nil
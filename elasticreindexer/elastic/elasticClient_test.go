@@ -0,0 +1,551 @@
+package elastic
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/multiversx/mx-chain-tools-go/elasticreindexer/config"
+	"github.com/stretchr/testify/require"
+)
+
+const successfulBulkResponse = `{"errors":false,"items":[{"index":{"status":201}}]}`
+
+func createTestServer(numCalls *uint32, responder func(callIndex uint32, w http.ResponseWriter)) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		callIndex := atomic.AddUint32(numCalls, 1)
+		responder(callIndex, w)
+	}))
+}
+
+func createTestClient(t *testing.T, url string, maxRetries int) *esClient {
+	client, err := NewElasticClient(config.ElasticInstanceConfig{
+		URL:            url,
+		MaxRetries:     maxRetries,
+		RetryBaseDelay: time.Millisecond,
+	})
+	require.Nil(t, err)
+
+	return client
+}
+
+func TestNewElasticClient(t *testing.T) {
+	t.Parallel()
+
+	t.Run("negative max retries should error", func(t *testing.T) {
+		t.Parallel()
+
+		client, err := NewElasticClient(config.ElasticInstanceConfig{MaxRetries: -1})
+		require.Nil(t, client)
+		require.True(t, errors.Is(err, errInvalidMaxRetries))
+	})
+	t.Run("negative retry base delay should error", func(t *testing.T) {
+		t.Parallel()
+
+		client, err := NewElasticClient(config.ElasticInstanceConfig{RetryBaseDelay: -time.Second})
+		require.Nil(t, client)
+		require.True(t, errors.Is(err, errInvalidRetryBaseDelay))
+	})
+	t.Run("zero values should use the defaults, without retries", func(t *testing.T) {
+		t.Parallel()
+
+		client, err := NewElasticClient(config.ElasticInstanceConfig{})
+		require.Nil(t, err)
+		require.Equal(t, 0, client.maxRetries)
+		require.Equal(t, initialScrollKeepAlive, client.initialScrollKeepAlive)
+		require.Equal(t, scrollKeepAlive, client.scrollKeepAlive)
+	})
+	t.Run("negative scroll keep-alive should error", func(t *testing.T) {
+		t.Parallel()
+
+		client, err := NewElasticClient(config.ElasticInstanceConfig{ScrollKeepAlive: -time.Second})
+		require.Nil(t, client)
+		require.True(t, errors.Is(err, errInvalidScrollKeepAlive))
+	})
+	t.Run("scroll keep-alive should be used for all the scroll requests", func(t *testing.T) {
+		t.Parallel()
+
+		client, err := NewElasticClient(config.ElasticInstanceConfig{ScrollKeepAlive: 15 * time.Minute})
+		require.Nil(t, err)
+		require.Equal(t, 15*time.Minute, client.initialScrollKeepAlive)
+		require.Equal(t, 15*time.Minute, client.scrollKeepAlive)
+	})
+}
+
+func TestComputeRetryBackoff(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, 2*time.Second, computeRetryBackoff(time.Second, 1))
+	require.Equal(t, 8*time.Second, computeRetryBackoff(time.Second, 3))
+	require.Equal(t, 400*time.Millisecond, computeRetryBackoff(100*time.Millisecond, 2))
+}
+
+func TestEsClient_DoBulkRequest(t *testing.T) {
+	t.Parallel()
+
+	t.Run("transient errors should be retried", func(t *testing.T) {
+		t.Parallel()
+
+		numCalls := uint32(0)
+		server := createTestServer(&numCalls, func(callIndex uint32, w http.ResponseWriter) {
+			switch callIndex {
+			case 1:
+				w.WriteHeader(http.StatusTooManyRequests)
+			case 2:
+				w.WriteHeader(http.StatusServiceUnavailable)
+			default:
+				_, _ = w.Write([]byte(successfulBulkResponse))
+			}
+		})
+		defer server.Close()
+
+		client := createTestClient(t, server.URL, 5)
+		err := client.DoBulkRequest(bytes.NewBufferString("{}\n"), "index")
+		require.Nil(t, err)
+		require.Equal(t, uint32(3), atomic.LoadUint32(&numCalls))
+	})
+	t.Run("exhausted retries should return the last error", func(t *testing.T) {
+		t.Parallel()
+
+		numCalls := uint32(0)
+		server := createTestServer(&numCalls, func(callIndex uint32, w http.ResponseWriter) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		})
+		defer server.Close()
+
+		client := createTestClient(t, server.URL, 2)
+		err := client.DoBulkRequest(bytes.NewBufferString("{}\n"), "index")
+		require.True(t, errors.Is(err, errBulkRequestFailed))
+		require.True(t, strings.Contains(err.Error(), "503"))
+		require.Equal(t, uint32(3), atomic.LoadUint32(&numCalls))
+	})
+	t.Run("zero max retries should not retry", func(t *testing.T) {
+		t.Parallel()
+
+		numCalls := uint32(0)
+		server := createTestServer(&numCalls, func(callIndex uint32, w http.ResponseWriter) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		})
+		defer server.Close()
+
+		client := createTestClient(t, server.URL, 0)
+		err := client.DoBulkRequest(bytes.NewBufferString("{}\n"), "index")
+		require.True(t, errors.Is(err, errBulkRequestFailed))
+		require.Equal(t, uint32(1), atomic.LoadUint32(&numCalls))
+	})
+	t.Run("non retryable status should not be retried", func(t *testing.T) {
+		t.Parallel()
+
+		numCalls := uint32(0)
+		server := createTestServer(&numCalls, func(callIndex uint32, w http.ResponseWriter) {
+			w.WriteHeader(http.StatusBadRequest)
+		})
+		defer server.Close()
+
+		client := createTestClient(t, server.URL, 5)
+		err := client.DoBulkRequest(bytes.NewBufferString("{}\n"), "index")
+		require.True(t, errors.Is(err, errBulkRequestFailed))
+		require.Equal(t, uint32(1), atomic.LoadUint32(&numCalls))
+	})
+	t.Run("partial failures should error", func(t *testing.T) {
+		t.Parallel()
+
+		numCalls := uint32(0)
+		server := createTestServer(&numCalls, func(callIndex uint32, w http.ResponseWriter) {
+			_, _ = w.Write([]byte(`{"errors":true,"items":[` +
+				`{"index":{"status":201}},` +
+				`{"index":{"status":400,"error":{"type":"mapper_parsing_exception","reason":"failed to parse"}}},` +
+				`{"index":{"status":201}}]}`))
+		})
+		defer server.Close()
+
+		client := createTestClient(t, server.URL, 5)
+		err := client.DoBulkRequest(bytes.NewBufferString("{}\n"), "index")
+		require.True(t, errors.Is(err, errBulkItemsFailed))
+		require.True(t, strings.Contains(err.Error(), "1 out of 3 items failed"))
+		require.True(t, strings.Contains(err.Error(), "mapper_parsing_exception"))
+	})
+}
+
+func TestCreateIndexBody(t *testing.T) {
+	t.Parallel()
+
+	t.Run("invalid settings should error", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := createIndexBody(nil, config.IndexSettings{NumberOfShards: -1})
+		require.True(t, errors.Is(err, errInvalidIndexSettings))
+
+		numReplicas := -1
+		_, err = createIndexBody(nil, config.IndexSettings{NumberOfReplicas: &numReplicas})
+		require.True(t, errors.Is(err, errInvalidIndexSettings))
+	})
+	t.Run("invalid mapping should error", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := createIndexBody(bytes.NewBufferString("not a mapping"), config.IndexSettings{})
+		require.NotNil(t, err)
+	})
+	t.Run("should add the settings next to the mapping", func(t *testing.T) {
+		t.Parallel()
+
+		numReplicas := 0
+		body, err := createIndexBody(bytes.NewBufferString(`{"mappings":{"properties":{"nonce":{"type":"long"}}}}`), config.IndexSettings{
+			NumberOfShards:   3,
+			NumberOfReplicas: &numReplicas,
+			RefreshInterval:  "-1",
+		})
+		require.Nil(t, err)
+		require.JSONEq(t, `{
+			"mappings":{"properties":{"nonce":{"type":"long"}}},
+			"settings":{"index":{"number_of_shards":3,"number_of_replicas":0,"refresh_interval":"-1"}}
+		}`, body.String())
+	})
+	t.Run("unset settings should not be applied", func(t *testing.T) {
+		t.Parallel()
+
+		body, err := createIndexBody(nil, config.IndexSettings{NumberOfShards: 1})
+		require.Nil(t, err)
+		require.JSONEq(t, `{"settings":{"index":{"number_of_shards":1}}}`, body.String())
+	})
+}
+
+func TestEsClient_SetRefreshInterval(t *testing.T) {
+	t.Parallel()
+
+	createServer := func(receivedPath *string, receivedBody *string) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			bodyBytes, _ := io.ReadAll(r.Body)
+			*receivedPath = r.URL.Path
+			*receivedBody = string(bodyBytes)
+			_, _ = w.Write([]byte(`{"acknowledged":true}`))
+		}))
+	}
+
+	t.Run("should set the refresh interval", func(t *testing.T) {
+		t.Parallel()
+
+		receivedPath, receivedBody := "", ""
+		server := createServer(&receivedPath, &receivedBody)
+		defer server.Close()
+
+		client := createTestClient(t, server.URL, 1)
+		err := client.SetRefreshInterval("index", "-1")
+		require.Nil(t, err)
+		require.Equal(t, "/index/_settings", receivedPath)
+		require.JSONEq(t, `{"index":{"refresh_interval":"-1"}}`, receivedBody)
+	})
+	t.Run("empty refresh interval should reset to the default", func(t *testing.T) {
+		t.Parallel()
+
+		receivedPath, receivedBody := "", ""
+		server := createServer(&receivedPath, &receivedBody)
+		defer server.Close()
+
+		client := createTestClient(t, server.URL, 1)
+		err := client.SetRefreshInterval("index", "")
+		require.Nil(t, err)
+		require.JSONEq(t, `{"index":{"refresh_interval":null}}`, receivedBody)
+	})
+}
+
+func TestEsClient_GetMapping(t *testing.T) {
+	t.Parallel()
+
+	createServer := func(response string) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(response))
+		}))
+	}
+
+	t.Run("should return the mapping of the index behind the alias, whatever its suffix", func(t *testing.T) {
+		t.Parallel()
+
+		server := createServer(`{"accounts-000003":{"mappings":{"properties":{"balance":{"type":"keyword"}}}}}`)
+		defer server.Close()
+
+		client := createTestClient(t, server.URL, 1)
+		mapping, err := client.GetMapping("accounts")
+		require.Nil(t, err)
+		require.JSONEq(t, `{"mappings":{"properties":{"balance":{"type":"keyword"}}}}`, mapping.String())
+	})
+	t.Run("no mapping in the response should error", func(t *testing.T) {
+		t.Parallel()
+
+		server := createServer(`{}`)
+		defer server.Close()
+
+		client := createTestClient(t, server.URL, 1)
+		mapping, err := client.GetMapping("accounts")
+		require.True(t, errors.Is(err, errUnexpectedMappingsCount))
+		require.Nil(t, mapping)
+	})
+	t.Run("more mappings in the response should error", func(t *testing.T) {
+		t.Parallel()
+
+		server := createServer(`{"accounts-000001":{"mappings":{}},"accounts-000002":{"mappings":{}}}`)
+		defer server.Close()
+
+		client := createTestClient(t, server.URL, 1)
+		mapping, err := client.GetMapping("accounts")
+		require.True(t, errors.Is(err, errUnexpectedMappingsCount))
+		require.Nil(t, mapping)
+	})
+}
+
+func TestEsClient_SwapAlias(t *testing.T) {
+	t.Parallel()
+
+	createServer := func(aliasExists bool, receivedBody *string) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == http.MethodHead {
+				require.Equal(t, "/old-index/_alias/alias", r.URL.Path)
+				if !aliasExists {
+					w.WriteHeader(http.StatusNotFound)
+				}
+				return
+			}
+
+			require.Equal(t, "/_aliases", r.URL.Path)
+			bodyBytes, _ := io.ReadAll(r.Body)
+			*receivedBody = string(bodyBytes)
+			_, _ = w.Write([]byte(`{"acknowledged":true}`))
+		}))
+	}
+
+	t.Run("existing alias should be moved in a single request", func(t *testing.T) {
+		t.Parallel()
+
+		receivedBody := ""
+		server := createServer(true, &receivedBody)
+		defer server.Close()
+
+		client := createTestClient(t, server.URL, 1)
+		err := client.SwapAlias("alias", "old-index", "new-index")
+		require.Nil(t, err)
+		require.JSONEq(t, `{"actions":[
+			{"remove":{"index":"old-index","alias":"alias"}},
+			{"add":{"index":"new-index","alias":"alias"}}
+		]}`, receivedBody)
+	})
+	t.Run("missing alias should only be added", func(t *testing.T) {
+		t.Parallel()
+
+		receivedBody := ""
+		server := createServer(false, &receivedBody)
+		defer server.Close()
+
+		client := createTestClient(t, server.URL, 1)
+		err := client.SwapAlias("alias", "old-index", "new-index")
+		require.Nil(t, err)
+		require.JSONEq(t, `{"actions":[{"add":{"index":"new-index","alias":"alias"}}]}`, receivedBody)
+	})
+	t.Run("error response should error", func(t *testing.T) {
+		t.Parallel()
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadRequest)
+		}))
+		defer server.Close()
+
+		client := createTestClient(t, server.URL, 1)
+		err := client.SwapAlias("alias", "", "new-index")
+		require.NotNil(t, err)
+	})
+}
+
+func TestEsClient_DoScrollRequestAllDocuments(t *testing.T) {
+	t.Parallel()
+
+	const searchContextMissingResponse = `{"error":{"root_cause":[{"type":"search_context_missing_exception","reason":"No search context found for id [1]"}]},"status":404}`
+	createServer := func(searchBodies *[]string, scrollResponder func(scrollIndex int, w http.ResponseWriter)) *httptest.Server {
+		numScrolls := 0
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			bodyBytes, _ := io.ReadAll(r.Body)
+			switch {
+			case r.Method == http.MethodDelete:
+				_, _ = w.Write([]byte(`{"succeeded":true}`))
+			case strings.HasSuffix(r.URL.Path, "/_search"):
+				*searchBodies = append(*searchBodies, string(bodyBytes))
+				page := `{"_scroll_id":"first","hits":{"hits":[{"_id":"1","sort":[1]},{"_id":"2","sort":[2]},{"_id":"3","sort":[2]}]}}`
+				if len(*searchBodies) > 1 {
+					page = `{"_scroll_id":"second","hits":{"hits":[{"_id":"2","sort":[2]},{"_id":"3","sort":[2]},{"_id":"4","sort":[3]}]}}`
+				}
+				_, _ = w.Write([]byte(page))
+			default:
+				numScrolls++
+				scrollResponder(numScrolls, w)
+			}
+		}))
+	}
+	expireFirstScroll := func(scrollIndex int, w http.ResponseWriter) {
+		if scrollIndex == 1 {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(searchContextMissingResponse))
+			return
+		}
+		_, _ = w.Write([]byte(`{"_scroll_id":"second","hits":{"hits":[]}}`))
+	}
+
+	t.Run("expired sorted scroll should restart from the last sort value", func(t *testing.T) {
+		t.Parallel()
+
+		searchBodies := make([]string, 0)
+		server := createServer(&searchBodies, expireFirstScroll)
+		defer server.Close()
+
+		numPages := 0
+		client := createTestClient(t, server.URL, 1)
+		err := client.DoScrollRequestAllDocuments("index", []byte(`{"query":{"match_all":{}},"sort":[{"timestamp":"asc"}]}`), func(_ []byte) error {
+			numPages++
+			return nil
+		})
+		require.Nil(t, err)
+		require.Equal(t, 2, numPages)
+		require.Len(t, searchBodies, 2)
+		require.JSONEq(t, `{"query":{"bool":{"filter":[{"match_all":{}},{"range":{"timestamp":{"gte":2}}}]}},"sort":[{"timestamp":"asc"}]}`, searchBodies[1])
+	})
+	t.Run("expired unsorted scroll should error", func(t *testing.T) {
+		t.Parallel()
+
+		searchBodies := make([]string, 0)
+		server := createServer(&searchBodies, expireFirstScroll)
+		defer server.Close()
+
+		client := createTestClient(t, server.URL, 1)
+		err := client.DoScrollRequestAllDocuments("index", []byte(`{"query":{"match_all":{}}}`), func(_ []byte) error {
+			return nil
+		})
+		require.True(t, errors.Is(err, errScrollNotSorted))
+		require.Len(t, searchBodies, 1)
+	})
+	t.Run("scroll expiring again without progress should error", func(t *testing.T) {
+		t.Parallel()
+
+		searchBodies := make([]string, 0)
+		server := createServer(&searchBodies, func(_ int, w http.ResponseWriter) {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(searchContextMissingResponse))
+		})
+		defer server.Close()
+
+		client := createTestClient(t, server.URL, 1)
+		err := client.DoScrollRequestAllDocuments("index", []byte(`{"sort":[{"timestamp":"asc"}]}`), func(_ []byte) error {
+			return nil
+		})
+		require.True(t, errors.Is(err, errScrollRestartNoProgress))
+		require.Len(t, searchBodies, 3)
+	})
+	t.Run("other scroll errors should not restart", func(t *testing.T) {
+		t.Parallel()
+
+		searchBodies := make([]string, 0)
+		server := createServer(&searchBodies, func(_ int, w http.ResponseWriter) {
+			w.WriteHeader(http.StatusBadRequest)
+		})
+		defer server.Close()
+
+		client := createTestClient(t, server.URL, 1)
+		err := client.DoScrollRequestAllDocuments("index", []byte(`{"sort":[{"timestamp":"asc"}]}`), func(_ []byte) error {
+			return nil
+		})
+		require.NotNil(t, err)
+		require.Len(t, searchBodies, 1)
+	})
+}
+
+func TestEsClient_DoSearchAfterRequestAllDocuments(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should paginate after the sort values of the last hit", func(t *testing.T) {
+		t.Parallel()
+
+		searchBodies := make([]string, 0)
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			bodyBytes, _ := io.ReadAll(r.Body)
+			searchBodies = append(searchBodies, string(bodyBytes))
+			require.NotContains(t, r.URL.RawQuery, "scroll")
+
+			switch len(searchBodies) {
+			case 1:
+				_, _ = w.Write([]byte(`{"hits":{"hits":[{"_id":"a","sort":["a"]},{"_id":"b","sort":["b"]}]}}`))
+			case 2:
+				_, _ = w.Write([]byte(`{"hits":{"hits":[{"_id":"c","sort":["c"]}]}}`))
+			default:
+				_, _ = w.Write([]byte(`{"hits":{"hits":[]}}`))
+			}
+		}))
+		defer server.Close()
+
+		numPages := 0
+		client := createTestClient(t, server.URL, 1)
+		err := client.DoSearchAfterRequestAllDocuments("index", []byte(`{"query":{"match_all":{}},"sort":[{"address":{"order":"asc"}}],"search_after":["0"]}`), func(_ []byte) error {
+			numPages++
+			return nil
+		})
+		require.Nil(t, err)
+		require.Equal(t, 2, numPages)
+		require.Len(t, searchBodies, 3)
+		require.JSONEq(t, `{"query":{"match_all":{}},"sort":[{"address":{"order":"asc"}}],"search_after":["0"]}`, searchBodies[0])
+		require.JSONEq(t, `{"query":{"match_all":{}},"sort":[{"address":{"order":"asc"}}],"search_after":["b"]}`, searchBodies[1])
+		require.JSONEq(t, `{"query":{"match_all":{}},"sort":[{"address":{"order":"asc"}}],"search_after":["c"]}`, searchBodies[2])
+	})
+	t.Run("unsorted body should error", func(t *testing.T) {
+		t.Parallel()
+
+		numCalls := uint32(0)
+		server := createTestServer(&numCalls, func(_ uint32, w http.ResponseWriter) {
+			_, _ = w.Write([]byte(`{"hits":{"hits":[]}}`))
+		})
+		defer server.Close()
+
+		client := createTestClient(t, server.URL, 1)
+		err := client.DoSearchAfterRequestAllDocuments("index", []byte(`{"query":{"match_all":{}}}`), func(_ []byte) error {
+			return nil
+		})
+		require.True(t, errors.Is(err, errSearchNotSorted))
+		require.Equal(t, uint32(0), atomic.LoadUint32(&numCalls))
+	})
+}
+
+func TestEsClient_DoDeleteByQuery(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should return the number of deleted documents", func(t *testing.T) {
+		t.Parallel()
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, "/index/_delete_by_query", r.URL.Path)
+			require.Equal(t, "proceed", r.URL.Query().Get("conflicts"))
+			require.Equal(t, "true", r.URL.Query().Get("refresh"))
+			bodyBytes, _ := io.ReadAll(r.Body)
+			require.JSONEq(t, `{"query":{"match_all":{}}}`, string(bodyBytes))
+			_, _ = w.Write([]byte(`{"deleted":12,"failures":[]}`))
+		}))
+		defer server.Close()
+
+		client := createTestClient(t, server.URL, 1)
+		numDeleted, err := client.DoDeleteByQuery("index", []byte(`{"query":{"match_all":{}}}`))
+		require.Nil(t, err)
+		require.Equal(t, uint64(12), numDeleted)
+	})
+	t.Run("failures should error", func(t *testing.T) {
+		t.Parallel()
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(`{"deleted":2,"failures":[{"cause":{"type":"es_rejected_execution_exception"}}]}`))
+		}))
+		defer server.Close()
+
+		client := createTestClient(t, server.URL, 1)
+		numDeleted, err := client.DoDeleteByQuery("index", []byte(`{}`))
+		require.True(t, errors.Is(err, errDeleteByQueryFailed))
+		require.Equal(t, uint64(2), numDeleted)
+	})
+}
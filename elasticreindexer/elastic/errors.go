@@ -0,0 +1,15 @@
+package elastic
+
+import "errors"
+
+var errInvalidMaxRetries = errors.New("invalid max retries")
+var errInvalidRetryBaseDelay = errors.New("invalid retry base delay")
+var errBulkRequestFailed = errors.New("bulk request failed")
+var errBulkItemsFailed = errors.New("bulk request items failed")
+var errInvalidIndexSettings = errors.New("invalid index settings")
+var errInvalidScrollKeepAlive = errors.New("invalid scroll keep-alive")
+var errScrollNotSorted = errors.New("the scroll request is not sorted ascending on a single field, so it can not be restarted")
+var errScrollRestartNoProgress = errors.New("the scroll expired again before reaching a new sort value")
+var errDeleteByQueryFailed = errors.New("delete by query failed")
+var errSearchNotSorted = errors.New("the search request is not sorted, so it can not be paginated using search_after")
+var errUnexpectedMappingsCount = errors.New("expected exactly one mapping in the response")
@@ -0,0 +1,104 @@
+package elastic
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/tidwall/gjson"
+)
+
+const (
+	searchContextMissingException = "search_context_missing_exception"
+	sortField                     = "sort"
+	searchAfterField              = "search_after"
+	queryField                    = "query"
+	ascendingOrder                = "asc"
+)
+
+// isSearchContextMissingError returns true if the provided scroll error was caused by an expired scroll, that is,
+// the search context was freed by the cluster after the keep-alive duration elapsed without a scroll request
+func isSearchContextMissingError(err error) bool {
+	return err != nil && strings.Contains(err.Error(), searchContextMissingException)
+}
+
+// sortValuesTracker keeps the sort value of the last document read from a scroll sorted ascending on a single field, so
+// that an expired scroll can be restarted from it. The search_after parameter can not be used in a scroll context, so
+// the scroll is restarted with a range query matching the documents with the last sort value or a greater one. Since
+// the documents sharing the last sort value might not have been read entirely, they are read again. This is fine for
+// reindexing, as the documents are indexed by their IDs
+type sortValuesTracker struct {
+	lastSortValue string
+}
+
+// pageRead registers the sort values of the hits of the provided scroll page. It must be called in the scroll order
+func (tracker *sortValuesTracker) pageRead(responseBytes []byte) {
+	hits := gjson.GetBytes(responseBytes, "hits.hits")
+	hits.ForEach(func(_, hit gjson.Result) bool {
+		tracker.lastSortValue = hit.Get(sortField + ".0").Raw
+
+		return true
+	})
+}
+
+// createRestartBody returns the provided search body, with its query restricted to the documents having the sort
+// field greater than or equal to the last sort value read. An empty last sort value means that the scroll is
+// restarted from the beginning. Only the requests sorted ascending on a single field can be restarted
+func (tracker *sortValuesTracker) createRestartBody(body []byte) ([]byte, error) {
+	searchBody := make(map[string]json.RawMessage)
+	err := json.Unmarshal(body, &searchBody)
+	if err != nil {
+		return nil, fmt.Errorf("%w while decoding the search body", err)
+	}
+
+	fieldName, err := getAscendingSortField(searchBody[sortField])
+	if err != nil {
+		return nil, err
+	}
+	if len(tracker.lastSortValue) == 0 {
+		return body, nil
+	}
+
+	query, hasQuery := searchBody[queryField]
+	if !hasQuery {
+		query = json.RawMessage(`{"match_all":{}}`)
+	}
+	restartRange := fmt.Sprintf(`{"range":{%q:{"gte":%s}}}`, fieldName, tracker.lastSortValue)
+	searchBody[queryField] = json.RawMessage(fmt.Sprintf(`{"bool":{"filter":[%s,%s]}}`, query, restartRange))
+
+	return json.Marshal(searchBody)
+}
+
+// getAscendingSortField returns the name of the field of the provided sort, if the sort is ascending on a single field,
+// written as "field", {"field": "asc"} or {"field": {"order": "asc"}}
+func getAscendingSortField(sort json.RawMessage) (string, error) {
+	sortFields := gjson.ParseBytes(sort)
+	if len(sort) == 0 || !sortFields.IsArray() || len(sortFields.Array()) != 1 {
+		return "", errScrollNotSorted
+	}
+
+	sortEntry := sortFields.Array()[0]
+	if sortEntry.Type == gjson.String {
+		return sortEntry.String(), nil
+	}
+
+	fieldName := ""
+	isAscending := false
+	numFields := 0
+	sortEntry.ForEach(func(key, value gjson.Result) bool {
+		fieldName = key.String()
+		order := value.String()
+		if value.IsObject() {
+			order = value.Get("order").String()
+		}
+		isAscending = order == ascendingOrder
+		numFields++
+
+		return true
+	})
+	if numFields != 1 || !isAscending {
+		return "", errScrollNotSorted
+	}
+
+	return fieldName, nil
+}
@@ -0,0 +1,69 @@
+package elastic
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsSearchContextMissingError(t *testing.T) {
+	t.Parallel()
+
+	require.False(t, isSearchContextMissingError(nil))
+	require.False(t, isSearchContextMissingError(errors.New("error response: [400 Bad Request]")))
+	require.True(t, isSearchContextMissingError(errors.New(`error response: [404 Not Found] {"type":"search_context_missing_exception"}`)))
+}
+
+func TestSortValuesTracker(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should restart from the beginning if nothing was read", func(t *testing.T) {
+		t.Parallel()
+
+		tracker := &sortValuesTracker{}
+		tracker.pageRead([]byte(`{"hits":{"hits":[]}}`))
+		require.Equal(t, "", tracker.lastSortValue)
+
+		body, err := tracker.createRestartBody([]byte(`{"query":{"match_all":{}},"sort":[{"timestamp":"asc"}]}`))
+		require.Nil(t, err)
+		require.JSONEq(t, `{"query":{"match_all":{}},"sort":[{"timestamp":"asc"}]}`, string(body))
+	})
+	t.Run("should restart from the last sort value, using a range query", func(t *testing.T) {
+		t.Parallel()
+
+		tracker := &sortValuesTracker{}
+		tracker.pageRead([]byte(`{"hits":{"hits":[{"sort":[1]},{"sort":[2]}]}}`))
+		tracker.pageRead([]byte(`{"hits":{"hits":[{"sort":[3]},{"sort":[3]}]}}`))
+		require.Equal(t, "3", tracker.lastSortValue)
+
+		body, err := tracker.createRestartBody([]byte(`{"query":{"term":{"status":"success"}},"sort":[{"timestamp":{"order":"asc"}}],"_source":true}`))
+		require.Nil(t, err)
+		require.JSONEq(t, `{
+			"query":{"bool":{"filter":[{"term":{"status":"success"}},{"range":{"timestamp":{"gte":3}}}]}},
+			"sort":[{"timestamp":{"order":"asc"}}],
+			"_source":true
+		}`, string(body))
+		require.NotContains(t, string(body), searchAfterField)
+
+		body, err = tracker.createRestartBody([]byte(`{"sort":["timestamp"]}`))
+		require.Nil(t, err)
+		require.JSONEq(t, `{"query":{"bool":{"filter":[{"match_all":{}},{"range":{"timestamp":{"gte":3}}}]}},"sort":["timestamp"]}`, string(body))
+	})
+	t.Run("unsorted, not ascending, multiple fields or invalid body should error", func(t *testing.T) {
+		t.Parallel()
+
+		tracker := &sortValuesTracker{lastSortValue: "3"}
+		_, err := tracker.createRestartBody([]byte(`{"query":{"match_all":{}}}`))
+		require.True(t, errors.Is(err, errScrollNotSorted))
+
+		_, err = tracker.createRestartBody([]byte(`{"sort":[{"timestamp":"desc"}]}`))
+		require.True(t, errors.Is(err, errScrollNotSorted))
+
+		_, err = tracker.createRestartBody([]byte(`{"sort":[{"timestamp":"asc"},{"nonce":"asc"}]}`))
+		require.True(t, errors.Is(err, errScrollNotSorted))
+
+		_, err = tracker.createRestartBody([]byte(`not a json`))
+		require.NotNil(t, err)
+	})
+}
@@ -0,0 +1,26 @@
+package elastic
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// withSearchAfter returns the provided search body, set to match the documents after the provided sort values. Empty
+// sort values mean that the documents are matched from the beginning. Only the sorted requests can be paginated
+func withSearchAfter(body []byte, sortValues string) ([]byte, error) {
+	searchBody := make(map[string]json.RawMessage)
+	err := json.Unmarshal(body, &searchBody)
+	if err != nil {
+		return nil, fmt.Errorf("%w while decoding the search body", err)
+	}
+	if _, isSorted := searchBody[sortField]; !isSorted {
+		return nil, errSearchNotSorted
+	}
+
+	delete(searchBody, searchAfterField)
+	if len(sortValues) > 0 {
+		searchBody[searchAfterField] = json.RawMessage(sortValues)
+	}
+
+	return json.Marshal(searchBody)
+}
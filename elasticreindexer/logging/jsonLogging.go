@@ -0,0 +1,104 @@
+package logging
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+	"time"
+
+	logger "github.com/multiversx/mx-chain-logger-go"
+	"github.com/urfave/cli"
+)
+
+// Copy of trieTools/trieToolsCommon/jsonLogging.go: the elasticreindexer module does not depend on trieTools
+
+const jsonLogTimeLayout = "2006-01-02T15:04:05.000Z07:00"
+
+// LogJSON is used when the log lines need to be written as JSON objects, one per line
+var LogJSON = cli.BoolFlag{
+	Name:  "log-json",
+	Usage: "Boolean option for writing the logs as JSON lines.",
+}
+
+type jsonLogCorrelation struct {
+	Shard    string `json:"shard"`
+	Epoch    uint32 `json:"epoch"`
+	Round    int64  `json:"round"`
+	SubRound string `json:"subRound"`
+}
+
+type jsonLogLine struct {
+	Timestamp   string              `json:"timestamp"`
+	Level       string              `json:"level"`
+	Logger      string              `json:"logger,omitempty"`
+	Correlation *jsonLogCorrelation `json:"correlation,omitempty"`
+	Message     string              `json:"message"`
+	Fields      map[string]string   `json:"fields,omitempty"`
+}
+
+// JSONFormatter implements the formatter interface and outputs each log line as a JSON object, followed by a new line.
+// The arguments of the log line are written as the fields object, with the same names and values as in the plain output
+type JSONFormatter struct {
+}
+
+// Output converts the provided LogLineHandler into a JSON line
+func (jf *JSONFormatter) Output(line logger.LogLineHandler) []byte {
+	if line == nil {
+		return nil
+	}
+
+	jsonLine := jsonLogLine{
+		Timestamp: time.Unix(0, line.GetTimestamp()).Format(jsonLogTimeLayout),
+		Level:     strings.TrimSpace(logger.LogLevel(line.GetLogLevel()).String()),
+		Message:   line.GetMessage(),
+		Fields:    argsToFields(line.GetArgs()),
+	}
+	if logger.IsEnabledLoggerName() {
+		jsonLine.Logger = line.GetLoggerName()
+	}
+	if logger.IsEnabledCorrelation() {
+		correlation := line.GetCorrelation()
+		jsonLine.Correlation = &jsonLogCorrelation{
+			Shard:    correlation.GetShard(),
+			Epoch:    correlation.GetEpoch(),
+			Round:    correlation.GetRound(),
+			SubRound: correlation.GetSubRound(),
+		}
+	}
+
+	buff, err := json.Marshal(jsonLine)
+	if err != nil {
+		return nil
+	}
+
+	return append(buff, '\n')
+}
+
+// argsToFields pairs the provided arguments as name, value. As for the plain formatter, an odd argument is ignored
+func argsToFields(args []string) map[string]string {
+	if len(args) < 2 {
+		return nil
+	}
+
+	fields := make(map[string]string, len(args)/2)
+	for index := 1; index < len(args); index += 2 {
+		fields[args[index-1]] = args[index]
+	}
+
+	return fields
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (jf *JSONFormatter) IsInterfaceNil() bool {
+	return jf == nil
+}
+
+// SetConsoleJSONFormatter replaces the formatter of the console logs with the JSON formatter
+func SetConsoleJSONFormatter() error {
+	err := logger.RemoveLogObserver(os.Stdout)
+	if err != nil {
+		return err
+	}
+
+	return logger.AddLogObserver(os.Stdout, &JSONFormatter{})
+}
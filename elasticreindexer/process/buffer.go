@@ -2,21 +2,31 @@ package process
 
 import "bytes"
 
-// bulkSizeThreshold is the constant for the maximum size of one bulk request that is sent to the elasticsearch database
-const bulkSizeThreshold = 838860 // 0.8MB
+// DefaultBulkMaxBytes is the default maximum size of one bulk request that is sent to the elasticsearch database
+const DefaultBulkMaxBytes = 838860 // 0.8MB
 
 // BufferSlice extend structure bytes.Buffer with new methods
 type bufferSlice struct {
 	buffSlice         []*bytes.Buffer
+	numDocuments      []int
 	bulkSizeThreshold int
+	bulkMaxDocuments  int
 	idx               int
 }
 
-// newBufferSlice will create a new buffer
-func newBufferSlice() *bufferSlice {
+// newBufferSlice will create a new buffer. A new bulk is started whenever the current one would exceed the maximum
+// number of documents or the maximum size, whichever is reached first. A zero maximum number of documents means
+// unlimited, while a zero maximum size means the default size
+func newBufferSlice(bulkMaxDocuments int, bulkMaxBytes int) *bufferSlice {
+	if bulkMaxBytes <= 0 {
+		bulkMaxBytes = DefaultBulkMaxBytes
+	}
+
 	return &bufferSlice{
 		buffSlice:         make([]*bytes.Buffer, 0),
-		bulkSizeThreshold: bulkSizeThreshold,
+		numDocuments:      make([]int, 0),
+		bulkSizeThreshold: bulkMaxBytes,
+		bulkMaxDocuments:  bulkMaxDocuments,
 		idx:               0,
 	}
 }
@@ -25,6 +35,7 @@ func newBufferSlice() *bufferSlice {
 func (bs *bufferSlice) PutData(meta []byte, serializedData []byte) error {
 	if len(bs.buffSlice) == 0 {
 		bs.buffSlice = append(bs.buffSlice, &bytes.Buffer{})
+		bs.numDocuments = append(bs.numDocuments, 0)
 	}
 
 	currentBuff := bs.buffSlice[bs.idx]
@@ -32,8 +43,10 @@ func (bs *bufferSlice) PutData(meta []byte, serializedData []byte) error {
 	if bs.aNewElementIsNeeded(meta, serializedData) {
 		currentBuff = &bytes.Buffer{}
 		bs.buffSlice = append(bs.buffSlice, currentBuff)
+		bs.numDocuments = append(bs.numDocuments, 0)
 		bs.idx++
 	}
+	bs.numDocuments[bs.idx]++
 
 	if len(serializedData) > 0 {
 		serializedData = append(serializedData, "\n"...)
@@ -60,7 +73,14 @@ func (bs *bufferSlice) Buffers() []*bytes.Buffer {
 func (bs *bufferSlice) aNewElementIsNeeded(meta []byte, serializedData []byte) bool {
 	currentBuff := bs.buffSlice[bs.idx]
 
+	if currentBuff.Len() == 0 {
+		return false
+	}
+	if bs.bulkMaxDocuments > 0 && bs.numDocuments[bs.idx] >= bs.bulkMaxDocuments {
+		return true
+	}
+
 	buffLenWithCurrentAcc := currentBuff.Len() + len(meta) + len(serializedData)
 
-	return buffLenWithCurrentAcc > bs.bulkSizeThreshold && currentBuff.Len() != 0
+	return buffLenWithCurrentAcc > bs.bulkSizeThreshold
 }
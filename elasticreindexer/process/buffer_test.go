@@ -0,0 +1,64 @@
+package process
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBufferSlice_PutData(t *testing.T) {
+	t.Parallel()
+
+	meta := []byte("{ \"index\" : { \"_id\" : \"id\" } }\n")
+	document := []byte("{\"field\":\"value\"}")
+	documentSize := len(meta) + len(document) + 1
+
+	putDocuments := func(bs *bufferSlice, numDocuments int) {
+		for i := 0; i < numDocuments; i++ {
+			err := bs.PutData(meta, append([]byte{}, document...))
+			require.Nil(t, err)
+		}
+	}
+	countDocuments := func(buffers []*bytes.Buffer) []uint64 {
+		counts := make([]uint64, 0, len(buffers))
+		for _, buff := range buffers {
+			counts = append(counts, countBulkDocuments(buff))
+		}
+
+		return counts
+	}
+
+	t.Run("zero values should use the default size and no documents limit", func(t *testing.T) {
+		t.Parallel()
+
+		bs := newBufferSlice(0, 0)
+		putDocuments(bs, 100)
+		require.Equal(t, DefaultBulkMaxBytes, bs.bulkSizeThreshold)
+		require.Equal(t, []uint64{100}, countDocuments(bs.Buffers()))
+	})
+	t.Run("documents threshold reached first", func(t *testing.T) {
+		t.Parallel()
+
+		bs := newBufferSlice(4, 100*documentSize)
+		putDocuments(bs, 10)
+		require.Equal(t, []uint64{4, 4, 2}, countDocuments(bs.Buffers()))
+	})
+	t.Run("size threshold reached first", func(t *testing.T) {
+		t.Parallel()
+
+		bs := newBufferSlice(100, 3*documentSize)
+		putDocuments(bs, 10)
+		require.Equal(t, []uint64{3, 3, 3, 1}, countDocuments(bs.Buffers()))
+		for _, buff := range bs.Buffers() {
+			require.LessOrEqual(t, buff.Len(), 3*documentSize)
+		}
+	})
+	t.Run("document larger than the size threshold should be sent alone", func(t *testing.T) {
+		t.Parallel()
+
+		bs := newBufferSlice(0, documentSize/2)
+		putDocuments(bs, 3)
+		require.Equal(t, []uint64{1, 1, 1}, countDocuments(bs.Buffers()))
+	})
+}
@@ -0,0 +1,336 @@
+package process
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sync"
+	"time"
+)
+
+const (
+	checkpointFilePerms    = 0644
+	checkpointSaveInterval = 10 * time.Second
+)
+
+type intervalCheckpoint struct {
+	Start         int64  `json:"start"`
+	Stop          int64  `json:"stop"`
+	LastTimestamp int64  `json:"lastTimestamp"`
+	HasWatermark  bool   `json:"hasWatermark"`
+	NumProcessed  uint64 `json:"numProcessed"`
+	// NumAtLastTimestamp is the number of processed documents having the watermark timestamp, included in NumProcessed
+	NumAtLastTimestamp uint64 `json:"numAtLastTimestamp"`
+	Completed          bool   `json:"completed"`
+}
+
+type indexCheckpoint struct {
+	SourceIndex  string `json:"sourceIndex"`
+	TargetIndex  string `json:"targetIndex"`
+	Completed    bool   `json:"completed"`
+	NumProcessed uint64 `json:"numProcessed"`
+	// LastID holds the raw JSON sort values of the last reindexed document of an index without timestamp, sorted by
+	// its configured sort field, used as search_after when the index is resumed
+	LastID    string                `json:"lastID,omitempty"`
+	Intervals []*intervalCheckpoint `json:"intervals,omitempty"`
+}
+
+type checkpointData struct {
+	SourceURL      string                      `json:"sourceURL"`
+	DestinationURL string                      `json:"destinationURL"`
+	Indices        map[string]*indexCheckpoint `json:"indices"`
+}
+
+// ArgsCheckpointStore holds the arguments needed for creating a checkpoint store
+type ArgsCheckpointStore struct {
+	FilePath       string
+	SourceURL      string
+	DestinationURL string
+	Indices        []string
+	Resume         bool
+}
+
+// checkpointStore keeps the reindexing progress of each index and periodically persists it in a JSON file
+type checkpointStore struct {
+	mut      sync.Mutex
+	filePath string
+	data     *checkpointData
+	lastSave time.Time
+}
+
+// NewCheckpointStore creates a new checkpoint store. If resume is set, the previous checkpoint is loaded from the
+// provided file and it is refused if it was created for a different source/destination pair, either of clusters or
+// of the provided indices
+func NewCheckpointStore(args ArgsCheckpointStore) (*checkpointStore, error) {
+	store := &checkpointStore{
+		filePath: args.FilePath,
+		data: &checkpointData{
+			SourceURL:      args.SourceURL,
+			DestinationURL: args.DestinationURL,
+			Indices:        make(map[string]*indexCheckpoint),
+		},
+	}
+	if !args.Resume {
+		return store, nil
+	}
+
+	fileBytes, err := ioutil.ReadFile(args.FilePath)
+	if err != nil {
+		return nil, fmt.Errorf("%w while reading the checkpoint file", err)
+	}
+
+	loadedData := &checkpointData{}
+	err = json.Unmarshal(fileBytes, loadedData)
+	if err != nil {
+		return nil, fmt.Errorf("%w while decoding the checkpoint file", err)
+	}
+	if loadedData.SourceURL != args.SourceURL || loadedData.DestinationURL != args.DestinationURL {
+		return nil, fmt.Errorf("%w, checkpoint: %s -> %s, provided: %s -> %s", errCheckpointMismatch,
+			loadedData.SourceURL, loadedData.DestinationURL, args.SourceURL, args.DestinationURL)
+	}
+	if loadedData.Indices == nil {
+		loadedData.Indices = make(map[string]*indexCheckpoint)
+	}
+	for _, index := range args.Indices {
+		indexData, found := loadedData.Indices[index]
+		if !found {
+			continue
+		}
+
+		targetIndex := index + indexSuffix
+		if indexData.SourceIndex != index || indexData.TargetIndex != targetIndex {
+			return nil, fmt.Errorf("%w, checkpoint: %s -> %s, provided: %s -> %s", errCheckpointMismatch,
+				indexData.SourceIndex, indexData.TargetIndex, index, targetIndex)
+		}
+	}
+	for _, indexData := range loadedData.Indices {
+		for _, intervalData := range indexData.Intervals {
+			intervalData.rewindToWatermarkTimestamp()
+		}
+	}
+
+	store.data = loadedData
+	log.Info("resuming from checkpoint", "file", args.FilePath, "num indices", len(loadedData.Indices))
+
+	return store, nil
+}
+
+// IsIndexCompleted returns true if the provided index was completely reindexed
+func (cs *checkpointStore) IsIndexCompleted(index string) bool {
+	cs.mut.Lock()
+	defer cs.mut.Unlock()
+
+	indexData, found := cs.data.Indices[index]
+
+	return found && indexData.Completed
+}
+
+// MarkIndexCompleted marks the provided index as completely reindexed and saves the checkpoint
+func (cs *checkpointStore) MarkIndexCompleted(index string, numProcessed uint64) error {
+	cs.mut.Lock()
+	defer cs.mut.Unlock()
+
+	indexData := cs.getOrCreateIndexCheckpoint(index)
+	indexData.Completed = true
+	indexData.NumProcessed = numProcessed
+
+	return cs.save()
+}
+
+// ResolveIntervals returns the intervals previously stored for the provided index, if any, so that a resumed job
+// keeps the same intervals. The stop of the last stored interval is extended to the stop of the last provided one.
+// Otherwise, the provided intervals are stored and returned
+func (cs *checkpointStore) ResolveIntervals(index string, intervals []*interval) ([]*interval, error) {
+	cs.mut.Lock()
+	defer cs.mut.Unlock()
+
+	indexData := cs.getOrCreateIndexCheckpoint(index)
+	if len(indexData.Intervals) > 0 {
+		lastInterval := indexData.Intervals[len(indexData.Intervals)-1]
+		if len(intervals) > 0 && intervals[len(intervals)-1].stop > lastInterval.Stop {
+			lastInterval.Stop = intervals[len(intervals)-1].stop
+			lastInterval.Completed = false
+		}
+
+		storedIntervals := make([]*interval, 0, len(indexData.Intervals))
+		for _, intervalData := range indexData.Intervals {
+			storedIntervals = append(storedIntervals, &interval{
+				start: intervalData.Start,
+				stop:  intervalData.Stop,
+			})
+		}
+
+		return storedIntervals, cs.save()
+	}
+
+	for _, interv := range intervals {
+		indexData.Intervals = append(indexData.Intervals, &intervalCheckpoint{
+			Start: interv.start,
+			Stop:  interv.stop,
+		})
+	}
+
+	return intervals, cs.save()
+}
+
+// GetIntervalStart returns the timestamp from where the reindexing of the provided interval should start and
+// whether the interval was already completed
+func (cs *checkpointStore) GetIntervalStart(index string, start, stop int64) (int64, bool) {
+	cs.mut.Lock()
+	defer cs.mut.Unlock()
+
+	intervalData := cs.getIntervalCheckpoint(index, start, stop)
+	if intervalData == nil {
+		return start, false
+	}
+	if intervalData.Completed {
+		return start, true
+	}
+	if intervalData.HasWatermark && intervalData.LastTimestamp > start {
+		// the documents with the watermark timestamp are reindexed again as they might not be all processed, they
+		// were already removed from the processed count when the checkpoint was loaded
+		return intervalData.LastTimestamp, false
+	}
+
+	return start, false
+}
+
+// UpdateIntervalWatermark records that all the documents of the provided interval up until the provided watermark
+// were indexed. The checkpoint is saved periodically
+func (cs *checkpointStore) UpdateIntervalWatermark(index string, start, stop int64, mark watermark, numDocuments uint64) error {
+	cs.mut.Lock()
+	defer cs.mut.Unlock()
+
+	intervalData := cs.getIntervalCheckpoint(index, start, stop)
+	if intervalData == nil {
+		return nil
+	}
+
+	intervalData.LastTimestamp = mark.lastTimestamp
+	intervalData.NumAtLastTimestamp = mark.numAtLastTimestamp
+	intervalData.HasWatermark = true
+	intervalData.NumProcessed += numDocuments
+
+	return cs.saveIfNecessary()
+}
+
+// GetIndexWatermark returns the sort values of the last document reindexed for the provided index, if any, and the
+// number of documents processed up until it. The documents are reindexed in the ascending order of the sort field
+func (cs *checkpointStore) GetIndexWatermark(index string) (string, uint64) {
+	cs.mut.Lock()
+	defer cs.mut.Unlock()
+
+	indexData, found := cs.data.Indices[index]
+	if !found {
+		return "", 0
+	}
+
+	return indexData.LastID, indexData.NumProcessed
+}
+
+// UpdateIndexWatermark records that all the documents of the provided index up until the provided sort values were
+// indexed. The checkpoint is saved periodically
+func (cs *checkpointStore) UpdateIndexWatermark(index string, lastSortValues string, numDocuments uint64) error {
+	cs.mut.Lock()
+	defer cs.mut.Unlock()
+
+	indexData := cs.getOrCreateIndexCheckpoint(index)
+	indexData.LastID = lastSortValues
+	indexData.NumProcessed += numDocuments
+
+	return cs.saveIfNecessary()
+}
+
+// MarkIntervalCompleted marks the provided interval as completely reindexed and saves the checkpoint. It returns
+// the number of documents processed for the interval, including the ones processed before resuming
+func (cs *checkpointStore) MarkIntervalCompleted(index string, start, stop int64) (uint64, error) {
+	cs.mut.Lock()
+	defer cs.mut.Unlock()
+
+	intervalData := cs.getIntervalCheckpoint(index, start, stop)
+	if intervalData == nil {
+		return 0, nil
+	}
+
+	intervalData.Completed = true
+
+	return intervalData.NumProcessed, cs.save()
+}
+
+func (cs *checkpointStore) getOrCreateIndexCheckpoint(index string) *indexCheckpoint {
+	indexData, found := cs.data.Indices[index]
+	if found {
+		return indexData
+	}
+
+	indexData = &indexCheckpoint{
+		SourceIndex: index,
+		TargetIndex: index + indexSuffix,
+	}
+	cs.data.Indices[index] = indexData
+
+	return indexData
+}
+
+func (cs *checkpointStore) getIntervalCheckpoint(index string, start, stop int64) *intervalCheckpoint {
+	indexData, found := cs.data.Indices[index]
+	if !found {
+		return nil
+	}
+
+	for _, intervalData := range indexData.Intervals {
+		if intervalData.Start == start && intervalData.Stop == stop {
+			return intervalData
+		}
+	}
+
+	return nil
+}
+
+// rewindToWatermarkTimestamp removes the documents having the watermark timestamp from the processed count, as they
+// are reindexed again (and counted again) when the interval is resumed
+func (intervalData *intervalCheckpoint) rewindToWatermarkTimestamp() {
+	if intervalData.Completed || !intervalData.HasWatermark {
+		return
+	}
+
+	intervalData.NumProcessed -= intervalData.NumAtLastTimestamp
+	intervalData.NumAtLastTimestamp = 0
+}
+
+func (cs *checkpointStore) saveIfNecessary() error {
+	if time.Since(cs.lastSave) < checkpointSaveInterval {
+		return nil
+	}
+
+	return cs.save()
+}
+
+// save writes the checkpoint in a temporary file that is then renamed, so a crash will not leave a corrupted checkpoint
+func (cs *checkpointStore) save() error {
+	dataBytes, err := json.MarshalIndent(cs.data, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tempFilePath := cs.filePath + ".tmp"
+	err = ioutil.WriteFile(tempFilePath, dataBytes, checkpointFilePerms)
+	if err != nil {
+		return err
+	}
+
+	err = os.Rename(tempFilePath, cs.filePath)
+	if err != nil {
+		return err
+	}
+
+	cs.lastSave = time.Now()
+
+	return nil
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (cs *checkpointStore) IsInterfaceNil() bool {
+	return cs == nil
+}
@@ -0,0 +1,152 @@
+package process
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func createTestCheckpointArgs(t *testing.T) ArgsCheckpointStore {
+	return ArgsCheckpointStore{
+		FilePath:       filepath.Join(t.TempDir(), "checkpoint.json"),
+		SourceURL:      "http://source:9200",
+		DestinationURL: "http://destination:9200",
+	}
+}
+
+func TestNewCheckpointStore(t *testing.T) {
+	t.Parallel()
+
+	t.Run("resume without checkpoint file should error", func(t *testing.T) {
+		t.Parallel()
+
+		args := createTestCheckpointArgs(t)
+		args.Resume = true
+
+		store, err := NewCheckpointStore(args)
+		require.Nil(t, store)
+		require.Error(t, err)
+	})
+	t.Run("resume against a different pair should error", func(t *testing.T) {
+		t.Parallel()
+
+		args := createTestCheckpointArgs(t)
+		store, err := NewCheckpointStore(args)
+		require.Nil(t, err)
+		require.Nil(t, store.MarkIndexCompleted(testIndex, 10))
+
+		args.Resume = true
+		args.DestinationURL = "http://other:9200"
+		store, err = NewCheckpointStore(args)
+		require.Nil(t, store)
+		require.ErrorIs(t, err, errCheckpointMismatch)
+	})
+	t.Run("resume against a different index pair should error", func(t *testing.T) {
+		t.Parallel()
+
+		args := createTestCheckpointArgs(t)
+		store, err := NewCheckpointStore(args)
+		require.Nil(t, err)
+		require.Nil(t, store.MarkIndexCompleted(testIndex, 10))
+		store.data.Indices[testIndex].TargetIndex = "other" + indexSuffix
+		require.Nil(t, store.save())
+
+		args.Resume = true
+		args.Indices = []string{"other"}
+		_, err = NewCheckpointStore(args)
+		require.Nil(t, err)
+
+		args.Indices = []string{"other", testIndex}
+		store, err = NewCheckpointStore(args)
+		require.Nil(t, store)
+		require.ErrorIs(t, err, errCheckpointMismatch)
+	})
+	t.Run("resume should load the completed indices", func(t *testing.T) {
+		t.Parallel()
+
+		args := createTestCheckpointArgs(t)
+		store, err := NewCheckpointStore(args)
+		require.Nil(t, err)
+		require.Nil(t, store.MarkIndexCompleted(testIndex, 10))
+
+		args.Resume = true
+		args.Indices = []string{testIndex}
+		store, err = NewCheckpointStore(args)
+		require.Nil(t, err)
+		require.True(t, store.IsIndexCompleted(testIndex))
+		require.False(t, store.IsIndexCompleted("other"))
+	})
+}
+
+func TestCheckpointStore_Intervals(t *testing.T) {
+	t.Parallel()
+
+	args := createTestCheckpointArgs(t)
+	store, err := NewCheckpointStore(args)
+	require.Nil(t, err)
+
+	intervals := []*interval{{start: 0, stop: 100}, {start: 100, stop: 200}}
+	resolvedIntervals, err := store.ResolveIntervals(testIndex, intervals)
+	require.Nil(t, err)
+	require.Equal(t, intervals, resolvedIntervals)
+
+	require.Nil(t, store.UpdateIntervalWatermark(testIndex, 0, 100, watermark{lastTimestamp: 60, numAtLastTimestamp: 2}, 5))
+	numProcessed, err := store.MarkIntervalCompleted(testIndex, 100, 200)
+	require.Nil(t, err)
+	require.Equal(t, uint64(0), numProcessed)
+
+	args.Resume = true
+	store, err = NewCheckpointStore(args)
+	require.Nil(t, err)
+
+	// the intervals are kept, the last one being extended up to the new stop
+	resolvedIntervals, err = store.ResolveIntervals(testIndex, []*interval{{start: 0, stop: 150}, {start: 150, stop: 300}})
+	require.Nil(t, err)
+	require.Equal(t, []*interval{{start: 0, stop: 100}, {start: 100, stop: 300}}, resolvedIntervals)
+
+	start, completed := store.GetIntervalStart(testIndex, 0, 100)
+	require.Equal(t, int64(60), start)
+	require.False(t, completed)
+
+	// the extended interval has to be reindexed again
+	start, completed = store.GetIntervalStart(testIndex, 100, 300)
+	require.Equal(t, int64(100), start)
+	require.False(t, completed)
+
+	// the 2 documents with the watermark timestamp are reindexed again, so they are counted only once
+	require.Nil(t, store.UpdateIntervalWatermark(testIndex, 0, 100, watermark{lastTimestamp: 100, numAtLastTimestamp: 1}, 4))
+	numProcessed, err = store.MarkIntervalCompleted(testIndex, 0, 100)
+	require.Nil(t, err)
+	require.Equal(t, uint64(7), numProcessed)
+	_, completed = store.GetIntervalStart(testIndex, 0, 100)
+	require.True(t, completed)
+}
+
+func TestCheckpointStore_IndexWatermark(t *testing.T) {
+	t.Parallel()
+
+	args := createTestCheckpointArgs(t)
+	store, err := NewCheckpointStore(args)
+	require.Nil(t, err)
+
+	lastSortValues, numProcessed := store.GetIndexWatermark(testIndex)
+	require.Empty(t, lastSortValues)
+	require.Equal(t, uint64(0), numProcessed)
+
+	require.Nil(t, store.UpdateIndexWatermark(testIndex, `["id1"]`, 3))
+	require.Nil(t, store.UpdateIndexWatermark(testIndex, `["id2"]`, 2))
+	lastSortValues, numProcessed = store.GetIndexWatermark(testIndex)
+	require.Equal(t, `["id2"]`, lastSortValues)
+	require.Equal(t, uint64(5), numProcessed)
+
+	// the checkpoint is saved periodically, so only the first watermark was saved
+	args.Resume = true
+	store, err = NewCheckpointStore(args)
+	require.Nil(t, err)
+
+	lastSortValues, numProcessed = store.GetIndexWatermark(testIndex)
+	require.Equal(t, `["id1"]`, lastSortValues)
+	require.Equal(t, uint64(3), numProcessed)
+	require.False(t, store.IsIndexCompleted(testIndex))
+}
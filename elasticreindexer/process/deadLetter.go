@@ -0,0 +1,139 @@
+package process
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/multiversx/mx-chain-tools-go/elasticreindexer/elastic"
+)
+
+const deadLetterFilePerms = 0644
+
+type deadLetterRecord struct {
+	Index string `json:"index"`
+	elastic.BulkItemFailure
+}
+
+// ArgsDeadLetterWriter holds the arguments needed for creating a dead-letter writer
+type ArgsDeadLetterWriter struct {
+	FilePath string
+	// MaxFailures is the number of failed documents accepted before the reindexing is aborted
+	MaxFailures uint64
+}
+
+// deadLetterWriter appends the documents that failed to be indexed in a JSON lines file, one document per line,
+// along with the index and the error reported by the bulk request, so they can be inspected and retried later
+type deadLetterWriter struct {
+	mut         sync.Mutex
+	file        *os.File
+	writer      *bufio.Writer
+	maxFailures uint64
+	numFailures uint64
+}
+
+// NewDeadLetterWriter creates a new dead-letter writer. The failed documents are appended to the provided file,
+// which is created if it does not exist, so the failures of a resumed reindexing are kept next to the previous ones
+func NewDeadLetterWriter(args ArgsDeadLetterWriter) (*deadLetterWriter, error) {
+	if len(args.FilePath) == 0 {
+		return nil, errEmptyDeadLetterFile
+	}
+
+	file, err := os.OpenFile(args.FilePath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, deadLetterFilePerms)
+	if err != nil {
+		return nil, fmt.Errorf("%w while opening the dead-letter file", err)
+	}
+
+	return &deadLetterWriter{
+		file:        file,
+		writer:      bufio.NewWriter(file),
+		maxFailures: args.MaxFailures,
+	}, nil
+}
+
+// AddFailedDocuments writes the failed documents of a bulk request. It returns an error if the documents could not
+// be written, or if the total number of failed documents exceeds the maximum number of failures
+func (dlw *deadLetterWriter) AddFailedDocuments(index string, bulkErr *elastic.BulkItemsError) error {
+	dlw.mut.Lock()
+	defer dlw.mut.Unlock()
+
+	for _, failure := range bulkErr.Failures {
+		recordBytes, err := json.Marshal(&deadLetterRecord{
+			Index:           index,
+			BulkItemFailure: failure,
+		})
+		if err != nil {
+			return err
+		}
+
+		_, err = dlw.writer.Write(append(recordBytes, '\n'))
+		if err != nil {
+			return fmt.Errorf("%w while writing the dead-letter file", err)
+		}
+	}
+	err := dlw.writer.Flush()
+	if err != nil {
+		return fmt.Errorf("%w while writing the dead-letter file", err)
+	}
+
+	dlw.numFailures += uint64(len(bulkErr.Failures))
+	log.Warn("documents failed to be indexed, written in the dead-letter file", "index", index,
+		"num failed documents", len(bulkErr.Failures), "total failed documents", dlw.numFailures)
+	if dlw.numFailures > dlw.maxFailures {
+		return fmt.Errorf("%w: %d failed documents, max failures %d, last error: %s", errTooManyFailures, dlw.numFailures, dlw.maxFailures, bulkErr.Error())
+	}
+
+	return nil
+}
+
+// NumFailedDocuments returns the number of failed documents written so far
+func (dlw *deadLetterWriter) NumFailedDocuments() uint64 {
+	dlw.mut.Lock()
+	defer dlw.mut.Unlock()
+
+	return dlw.numFailures
+}
+
+// Close closes the dead-letter file
+func (dlw *deadLetterWriter) Close() error {
+	dlw.mut.Lock()
+	defer dlw.mut.Unlock()
+
+	err := dlw.writer.Flush()
+	if err != nil {
+		_ = dlw.file.Close()
+		return err
+	}
+
+	return dlw.file.Close()
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (dlw *deadLetterWriter) IsInterfaceNil() bool {
+	return dlw == nil
+}
+
+type disabledFailedDocumentsHandler struct{}
+
+// newDisabledFailedDocumentsHandler returns a failed documents handler that does not accept any failure, so the
+// first failed bulk request aborts the reindexing
+func newDisabledFailedDocumentsHandler() *disabledFailedDocumentsHandler {
+	return &disabledFailedDocumentsHandler{}
+}
+
+// AddFailedDocuments returns the provided bulk error
+func (handler *disabledFailedDocumentsHandler) AddFailedDocuments(_ string, bulkErr *elastic.BulkItemsError) error {
+	return bulkErr
+}
+
+// NumFailedDocuments returns 0
+func (handler *disabledFailedDocumentsHandler) NumFailedDocuments() uint64 {
+	return 0
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (handler *disabledFailedDocumentsHandler) IsInterfaceNil() bool {
+	return handler == nil
+}
@@ -0,0 +1,157 @@
+package process
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/multiversx/mx-chain-tools-go/elasticreindexer/elastic"
+	"github.com/multiversx/mx-chain-tools-go/elasticreindexer/process/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func createTestBulkItemsError(ids ...string) *elastic.BulkItemsError {
+	bulkErr := &elastic.BulkItemsError{
+		NumItems: len(ids) + 1,
+	}
+	for _, id := range ids {
+		bulkErr.Failures = append(bulkErr.Failures, elastic.BulkItemFailure{
+			ID:        id,
+			Status:    400,
+			ErrorType: "mapper_parsing_exception",
+			Reason:    "failed to parse",
+			Document:  json.RawMessage(`{"field":"` + id + `"}`),
+		})
+	}
+
+	return bulkErr
+}
+
+func readDeadLetterRecords(t *testing.T, filePath string) []*deadLetterRecord {
+	fileBytes, err := os.ReadFile(filePath)
+	require.Nil(t, err)
+
+	records := make([]*deadLetterRecord, 0)
+	for _, line := range strings.Split(strings.TrimSpace(string(fileBytes)), "\n") {
+		record := &deadLetterRecord{}
+		require.Nil(t, json.Unmarshal([]byte(line), record))
+		records = append(records, record)
+	}
+
+	return records
+}
+
+func TestNewDeadLetterWriter(t *testing.T) {
+	t.Parallel()
+
+	t.Run("empty file path should error", func(t *testing.T) {
+		t.Parallel()
+
+		writer, err := NewDeadLetterWriter(ArgsDeadLetterWriter{})
+		require.Nil(t, writer)
+		require.Equal(t, errEmptyDeadLetterFile, err)
+	})
+	t.Run("missing directory should error", func(t *testing.T) {
+		t.Parallel()
+
+		writer, err := NewDeadLetterWriter(ArgsDeadLetterWriter{FilePath: filepath.Join(t.TempDir(), "missing", "dead-letter.json")})
+		require.Nil(t, writer)
+		require.NotNil(t, err)
+	})
+}
+
+func TestDeadLetterWriter_AddFailedDocuments(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should append the failed documents", func(t *testing.T) {
+		t.Parallel()
+
+		filePath := filepath.Join(t.TempDir(), "dead-letter.json")
+		writer, err := NewDeadLetterWriter(ArgsDeadLetterWriter{FilePath: filePath, MaxFailures: 10})
+		require.Nil(t, err)
+		require.Nil(t, writer.AddFailedDocuments("blocks", createTestBulkItemsError("id1", "id2")))
+		require.Nil(t, writer.Close())
+
+		// a resumed reindexing appends to the same file
+		writer, err = NewDeadLetterWriter(ArgsDeadLetterWriter{FilePath: filePath, MaxFailures: 10})
+		require.Nil(t, err)
+		require.Nil(t, writer.AddFailedDocuments("logs", createTestBulkItemsError("id3")))
+		require.Equal(t, uint64(1), writer.NumFailedDocuments())
+		require.Nil(t, writer.Close())
+
+		records := readDeadLetterRecords(t, filePath)
+		require.Len(t, records, 3)
+		require.Equal(t, "blocks", records[0].Index)
+		require.Equal(t, "id1", records[0].ID)
+		require.Equal(t, "mapper_parsing_exception", records[0].ErrorType)
+		require.JSONEq(t, `{"field":"id1"}`, string(records[0].Document))
+		require.Equal(t, "logs", records[2].Index)
+		require.Equal(t, "id3", records[2].ID)
+	})
+	t.Run("exceeding the max failures should error", func(t *testing.T) {
+		t.Parallel()
+
+		filePath := filepath.Join(t.TempDir(), "dead-letter.json")
+		writer, err := NewDeadLetterWriter(ArgsDeadLetterWriter{FilePath: filePath, MaxFailures: 2})
+		require.Nil(t, err)
+
+		require.Nil(t, writer.AddFailedDocuments("blocks", createTestBulkItemsError("id1", "id2")))
+		err = writer.AddFailedDocuments("blocks", createTestBulkItemsError("id3"))
+		require.True(t, errors.Is(err, errTooManyFailures))
+		require.Nil(t, writer.Close())
+
+		// the documents that exceeded the threshold are still written
+		require.Len(t, readDeadLetterRecords(t, filePath), 3)
+	})
+}
+
+func TestReindexer_FailedDocuments(t *testing.T) {
+	t.Parallel()
+
+	bulkErr := createTestBulkItemsError("id2")
+	createReindexer := func() *reindexer {
+		r, _ := newReindexer(&mock.ElasticClientStub{}, &mock.ElasticClientStub{
+			DoBulkRequestCalled: func(_ *bytes.Buffer, _ string) error {
+				return bulkErr
+			},
+		}, []string{testIndex})
+
+		return r
+	}
+
+	t.Run("nil handler should error", func(t *testing.T) {
+		t.Parallel()
+
+		r := createReindexer()
+		require.Equal(t, errNilFailedDocuments, r.SetFailedDocumentsHandler(nil))
+	})
+	t.Run("without a dead-letter writer the failures should abort", func(t *testing.T) {
+		t.Parallel()
+
+		r := createReindexer()
+		handlerFunc := r.createScrollRequestHandlerFunction(newProgressReporter(testIndex, 0, 0), testIndex)
+
+		err := handlerFunc([]byte(testScrollPage))
+		require.True(t, errors.Is(err, bulkErr))
+	})
+	t.Run("with a dead-letter writer the reindexing should continue", func(t *testing.T) {
+		t.Parallel()
+
+		r := createReindexer()
+		filePath := filepath.Join(t.TempDir(), "dead-letter.json")
+		writer, err := NewDeadLetterWriter(ArgsDeadLetterWriter{FilePath: filePath, MaxFailures: 1})
+		require.Nil(t, err)
+		require.Nil(t, r.SetFailedDocumentsHandler(writer))
+		handlerFunc := r.createScrollRequestHandlerFunction(newProgressReporter(testIndex, 0, 0), testIndex)
+
+		require.Nil(t, handlerFunc([]byte(testScrollPage)))
+		err = handlerFunc([]byte(testScrollPage))
+		require.True(t, errors.Is(err, errTooManyFailures))
+		require.Nil(t, writer.Close())
+		require.Len(t, readDeadLetterRecords(t, filePath), 2)
+	})
+}
@@ -0,0 +1,53 @@
+package process
+
+type disabledCheckpointHandler struct{}
+
+// NewDisabledCheckpointHandler returns a checkpoint handler that does not keep any progress
+func NewDisabledCheckpointHandler() *disabledCheckpointHandler {
+	return &disabledCheckpointHandler{}
+}
+
+// IsIndexCompleted returns false
+func (dch *disabledCheckpointHandler) IsIndexCompleted(_ string) bool {
+	return false
+}
+
+// MarkIndexCompleted does nothing
+func (dch *disabledCheckpointHandler) MarkIndexCompleted(_ string, _ uint64) error {
+	return nil
+}
+
+// GetIndexWatermark returns an empty watermark
+func (dch *disabledCheckpointHandler) GetIndexWatermark(_ string) (string, uint64) {
+	return "", 0
+}
+
+// UpdateIndexWatermark does nothing
+func (dch *disabledCheckpointHandler) UpdateIndexWatermark(_ string, _ string, _ uint64) error {
+	return nil
+}
+
+// ResolveIntervals returns the provided intervals
+func (dch *disabledCheckpointHandler) ResolveIntervals(_ string, intervals []*interval) ([]*interval, error) {
+	return intervals, nil
+}
+
+// GetIntervalStart returns the start of the provided interval
+func (dch *disabledCheckpointHandler) GetIntervalStart(_ string, start, _ int64) (int64, bool) {
+	return start, false
+}
+
+// UpdateIntervalWatermark does nothing
+func (dch *disabledCheckpointHandler) UpdateIntervalWatermark(_ string, _, _ int64, _ watermark, _ uint64) error {
+	return nil
+}
+
+// MarkIntervalCompleted returns 0
+func (dch *disabledCheckpointHandler) MarkIntervalCompleted(_ string, _, _ int64) (uint64, error) {
+	return 0, nil
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (dch *disabledCheckpointHandler) IsInterfaceNil() bool {
+	return dch == nil
+}
@@ -0,0 +1,14 @@
+package process
+
+import "errors"
+
+// ErrSkipDocument can be returned by a DocumentTransformer in order to drop the document from the destination index
+var ErrSkipDocument = errors.New("skip document")
+
+// DocumentTransformer is able to mutate the source of a document (rename, drop or add fields) right before it is
+// written in the destination index
+type DocumentTransformer func(source []byte) ([]byte, error)
+
+func noTransform(source []byte) ([]byte, error) {
+	return source, nil
+}
@@ -0,0 +1,109 @@
+package process
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/multiversx/mx-chain-tools-go/elasticreindexer/process/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const testScrollPage = `{"hits":{"hits":[` +
+	`{"_id":"id1","_source":{"field":"a"}},` +
+	`{"_id":"id2","_source":{"field":"b"}},` +
+	`{"_id":"id3","_source":{"field":"c"}}]}}`
+
+func createReindexerWithTransformer(t *testing.T, transformer DocumentTransformer) *reindexer {
+	r, _ := newReindexer(&mock.ElasticClientStub{}, &mock.ElasticClientStub{}, []string{testIndex})
+	if transformer != nil {
+		err := r.SetDocumentTransformer(transformer)
+		require.Nil(t, err)
+	}
+
+	return r
+}
+
+func TestReindexer_SetDocumentTransformerNilShouldError(t *testing.T) {
+	t.Parallel()
+
+	r := createReindexerWithTransformer(t, nil)
+	err := r.SetDocumentTransformer(nil)
+	require.Equal(t, errNilTransformer, err)
+}
+
+func TestReindexer_PrepareDataForIndexing(t *testing.T) {
+	t.Parallel()
+
+	t.Run("default transformer should not change the documents", func(t *testing.T) {
+		t.Parallel()
+
+		r := createReindexerWithTransformer(t, nil)
+		buffers, err := r.prepareDataForIndexing([]byte(testScrollPage), testIndex, 1)
+		require.Nil(t, err)
+		require.Equal(t, 1, len(buffers))
+
+		data := buffers[0].String()
+		require.Equal(t, 3, strings.Count(data, `"_id"`))
+		require.True(t, strings.Contains(data, `{"field":"a"}`))
+		require.True(t, strings.Contains(data, `{"field":"b"}`))
+		require.True(t, strings.Contains(data, `{"field":"c"}`))
+		require.Equal(t, 3, strings.Count(data, `{ "index" : {`))
+	})
+	t.Run("create only should use the create action", func(t *testing.T) {
+		t.Parallel()
+
+		r := createReindexerWithTransformer(t, nil)
+		r.createOnly = true
+		buffers, err := r.prepareDataForIndexing([]byte(testScrollPage), testIndex, 1)
+		require.Nil(t, err)
+		require.Equal(t, 1, len(buffers))
+
+		data := buffers[0].String()
+		require.Equal(t, 3, strings.Count(data, `{ "create" : {`))
+		require.False(t, strings.Contains(data, `"index"`))
+	})
+	t.Run("transformer should mutate and skip documents", func(t *testing.T) {
+		t.Parallel()
+
+		r := createReindexerWithTransformer(t, func(source []byte) ([]byte, error) {
+			if bytes.Contains(source, []byte(`"b"`)) {
+				return nil, ErrSkipDocument
+			}
+
+			return bytes.Replace(source, []byte(`"field"`), []byte(`"renamed"`), 1), nil
+		})
+		buffers, err := r.prepareDataForIndexing([]byte(testScrollPage), testIndex, 1)
+		require.Nil(t, err)
+		require.Equal(t, 1, len(buffers))
+
+		data := buffers[0].String()
+		require.Equal(t, 2, strings.Count(data, `"_id"`))
+		require.False(t, strings.Contains(data, "id2"))
+		require.True(t, strings.Contains(data, `{"renamed":"a"}`))
+		require.True(t, strings.Contains(data, `{"renamed":"c"}`))
+	})
+	t.Run("all documents skipped should not create buffers", func(t *testing.T) {
+		t.Parallel()
+
+		r := createReindexerWithTransformer(t, func(source []byte) ([]byte, error) {
+			return nil, ErrSkipDocument
+		})
+		buffers, err := r.prepareDataForIndexing([]byte(testScrollPage), testIndex, 1)
+		require.Nil(t, err)
+		require.Equal(t, 0, len(buffers))
+	})
+	t.Run("transformer error should error", func(t *testing.T) {
+		t.Parallel()
+
+		expectedErr := errors.New("expected error")
+		r := createReindexerWithTransformer(t, func(source []byte) ([]byte, error) {
+			return nil, expectedErr
+		})
+		buffers, err := r.prepareDataForIndexing([]byte(testScrollPage), testIndex, 1)
+		require.Nil(t, buffers)
+		require.True(t, errors.Is(err, expectedErr))
+		require.True(t, strings.Contains(err.Error(), "while transforming document with id"))
+	})
+}
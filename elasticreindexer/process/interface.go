@@ -1,28 +1,72 @@
 package process
 
-import "bytes"
+import (
+	"bytes"
+
+	"github.com/multiversx/mx-chain-tools-go/elasticreindexer/config"
+	"github.com/multiversx/mx-chain-tools-go/elasticreindexer/elastic"
+)
 
 // ElasticClientHandler defines the behaviour of an elastic search client handler
 type ElasticClientHandler interface {
 	GetMapping(index string) (*bytes.Buffer, error)
 	CreateIndexWithMapping(targetIndex string, body *bytes.Buffer) error
+	CreateIndexWithSettings(targetIndex string, mapping *bytes.Buffer, settings config.IndexSettings) error
+	SetRefreshInterval(index string, refreshInterval string) error
 	DoScrollRequestAllDocuments(
 		index string,
 		body []byte,
 		handlerFunc func(responseBytes []byte) error,
 	) error
+	DoSearchAfterRequestAllDocuments(
+		index string,
+		body []byte,
+		handlerFunc func(responseBytes []byte) error,
+	) error
 	GetCount(index string) (uint64, error)
 	GetCountWithBody(index string, body []byte) (uint64, error)
 	DoesAliasExist(alias string) bool
 	DoBulkRequest(buff *bytes.Buffer, index string) error
 	DoesIndexExist(index string) bool
 	PutAlias(index string, alias string) error
+	SwapAlias(alias string, fromIndex string, toIndex string) error
+	RefreshIndex(index string) error
+	DoDeleteByQuery(index string, body []byte) (uint64, error)
 	IsInterfaceNil() bool
 }
 
 // ReindexerHandler defines the behaviour of an reindexer handler
 type ReindexerHandler interface {
 	Process(overwrite bool, skipMappings bool, indices ...string) error
-	ProcessIndexWithTimestamp(index string, overwrite bool, skipMappings bool, start, stop int64, count *uint64) error
+	ProcessIndexWithTimestamp(index string, overwrite bool, skipMappings bool, start, stop int64, progress *progressReporter) error
+	CreateProgressReporter(index string, start, stop int64) (*progressReporter, error)
 	GetCountsForInterval(index string, start, stop int64) (uint64, uint64, error)
+	ValidateCount(index string, tolerance uint64) error
+	ValidateCountForInterval(index string, start, stop int64, tolerance uint64) error
+	DisableRefresh(index string) error
+	RestoreRefresh(index string) error
+	CheckMappingCompatibility(index string) ([]string, error)
+	PreClean(index string) (uint64, error)
+}
+
+// CheckpointHandler defines the behaviour of a component that keeps the reindexing progress, so that an interrupted
+// reindexing can be resumed
+type CheckpointHandler interface {
+	IsIndexCompleted(index string) bool
+	MarkIndexCompleted(index string, numProcessed uint64) error
+	GetIndexWatermark(index string) (string, uint64)
+	UpdateIndexWatermark(index string, lastSortValues string, numDocuments uint64) error
+	ResolveIntervals(index string, intervals []*interval) ([]*interval, error)
+	GetIntervalStart(index string, start, stop int64) (int64, bool)
+	UpdateIntervalWatermark(index string, start, stop int64, mark watermark, numDocuments uint64) error
+	MarkIntervalCompleted(index string, start, stop int64) (uint64, error)
+	IsInterfaceNil() bool
+}
+
+// FailedDocumentsHandler defines the behaviour of a component that handles the documents that failed to be indexed,
+// so that the reindexing can continue
+type FailedDocumentsHandler interface {
+	AddFailedDocuments(index string, bulkErr *elastic.BulkItemsError) error
+	NumFailedDocuments() uint64
+	IsInterfaceNil() bool
 }
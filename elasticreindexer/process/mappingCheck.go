@@ -0,0 +1,106 @@
+package process
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+const objectFieldType = "object"
+
+type fieldMapping struct {
+	Type       string                  `json:"type"`
+	Properties map[string]fieldMapping `json:"properties"`
+}
+
+type indexMapping struct {
+	Mappings struct {
+		Properties map[string]fieldMapping `json:"properties"`
+	} `json:"mappings"`
+}
+
+// CheckMappingCompatibility compares the mapping of the provided index in the source with the mapping of the same
+// index in the destination and returns the fields that have different types, sorted by name. The index is not checked
+// if it does not exist in the destination, as it will be created by the tool (or by the dynamic mapping)
+func (r *reindexer) CheckMappingCompatibility(index string) ([]string, error) {
+	if !r.destinationElastic.DoesAliasExist(index) {
+		return nil, nil
+	}
+
+	sourceMapping, err := r.sourceElastic.GetMapping(index)
+	if err != nil {
+		return nil, fmt.Errorf("%w while getting the source mapping of index %s", err, index)
+	}
+	destinationMapping, err := r.destinationElastic.GetMapping(index)
+	if err != nil {
+		return nil, fmt.Errorf("%w while getting the destination mapping of index %s", err, index)
+	}
+
+	sourceTypes, err := extractFieldTypes(bufferBytes(sourceMapping))
+	if err != nil {
+		return nil, fmt.Errorf("%w while decoding the source mapping of index %s", err, index)
+	}
+	destinationTypes, err := extractFieldTypes(bufferBytes(destinationMapping))
+	if err != nil {
+		return nil, fmt.Errorf("%w while decoding the destination mapping of index %s", err, index)
+	}
+
+	return compareFieldTypes(sourceTypes, destinationTypes), nil
+}
+
+func bufferBytes(buff *bytes.Buffer) []byte {
+	if buff == nil {
+		return nil
+	}
+
+	return buff.Bytes()
+}
+
+// extractFieldTypes returns the type of each field of the provided mapping, by the full field path (e.g. "a.b.c").
+// The fields holding sub-fields without an explicit type are objects
+func extractFieldTypes(mapping []byte) (map[string]string, error) {
+	fieldTypes := make(map[string]string)
+	if len(mapping) == 0 {
+		return fieldTypes, nil
+	}
+
+	parsedMapping := &indexMapping{}
+	err := json.Unmarshal(mapping, parsedMapping)
+	if err != nil {
+		return nil, err
+	}
+
+	addFieldTypes("", parsedMapping.Mappings.Properties, fieldTypes)
+
+	return fieldTypes, nil
+}
+
+func addFieldTypes(prefix string, properties map[string]fieldMapping, fieldTypes map[string]string) {
+	for name, field := range properties {
+		path := prefix + name
+		fieldType := field.Type
+		if len(fieldType) == 0 {
+			fieldType = objectFieldType
+		}
+
+		fieldTypes[path] = fieldType
+		addFieldTypes(path+".", field.Properties, fieldTypes)
+	}
+}
+
+func compareFieldTypes(sourceTypes map[string]string, destinationTypes map[string]string) []string {
+	incompatibilities := make([]string, 0)
+	for path, sourceType := range sourceTypes {
+		destinationType, found := destinationTypes[path]
+		if !found || destinationType == sourceType {
+			continue
+		}
+
+		incompatibilities = append(incompatibilities, fmt.Sprintf("field %s: source type %s, destination type %s", path, sourceType, destinationType))
+	}
+
+	sort.Strings(incompatibilities)
+
+	return incompatibilities
+}
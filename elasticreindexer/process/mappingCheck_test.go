@@ -0,0 +1,136 @@
+package process
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/multiversx/mx-chain-tools-go/elasticreindexer/config"
+	"github.com/multiversx/mx-chain-tools-go/elasticreindexer/process/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const sourceMapping = `{"mappings":{"properties":{
+	"nonce":{"type":"long"},
+	"hash":{"type":"keyword"},
+	"data":{"properties":{"value":{"type":"keyword"},"timestamp":{"type":"date"}}}
+}}}`
+
+func TestExtractFieldTypes(t *testing.T) {
+	t.Parallel()
+
+	fieldTypes, err := extractFieldTypes([]byte(sourceMapping))
+	require.Nil(t, err)
+	require.Equal(t, map[string]string{
+		"nonce":          "long",
+		"hash":           "keyword",
+		"data":           objectFieldType,
+		"data.value":     "keyword",
+		"data.timestamp": "date",
+	}, fieldTypes)
+
+	fieldTypes, err = extractFieldTypes(nil)
+	require.Nil(t, err)
+	require.Empty(t, fieldTypes)
+
+	_, err = extractFieldTypes([]byte("not a mapping"))
+	require.NotNil(t, err)
+}
+
+func createReindexerWithMappings(t *testing.T, destinationExists bool, destinationMapping string) *reindexer {
+	sourceClient := &mock.ElasticClientStub{
+		GetMappingCalled: func(_ string) (*bytes.Buffer, error) {
+			return bytes.NewBufferString(sourceMapping), nil
+		},
+	}
+	destinationClient := &mock.ElasticClientStub{
+		DoesAliasExistCalled: func(_ string) bool {
+			return destinationExists
+		},
+		GetMappingCalled: func(_ string) (*bytes.Buffer, error) {
+			return bytes.NewBufferString(destinationMapping), nil
+		},
+	}
+
+	r, err := newReindexer(sourceClient, destinationClient, []string{testIndex})
+	require.Nil(t, err)
+
+	return r
+}
+
+func TestReindexer_CheckMappingCompatibility(t *testing.T) {
+	t.Parallel()
+
+	t.Run("missing destination index should not be checked", func(t *testing.T) {
+		t.Parallel()
+
+		r := createReindexerWithMappings(t, false, `{"mappings":{"properties":{"nonce":{"type":"keyword"}}}}`)
+		incompatibilities, err := r.CheckMappingCompatibility(testIndex)
+		require.Nil(t, err)
+		require.Empty(t, incompatibilities)
+	})
+	t.Run("source mapping error should error", func(t *testing.T) {
+		t.Parallel()
+
+		expectedErr := errors.New("expected error")
+		r := createReindexerWithMappings(t, true, sourceMapping)
+		r.sourceElastic = &mock.ElasticClientStub{
+			GetMappingCalled: func(_ string) (*bytes.Buffer, error) {
+				return nil, expectedErr
+			},
+		}
+
+		_, err := r.CheckMappingCompatibility(testIndex)
+		require.ErrorIs(t, err, expectedErr)
+	})
+	t.Run("same types and missing fields should be compatible", func(t *testing.T) {
+		t.Parallel()
+
+		r := createReindexerWithMappings(t, true, `{"mappings":{"properties":{"nonce":{"type":"long"},"extra":{"type":"text"}}}}`)
+		incompatibilities, err := r.CheckMappingCompatibility(testIndex)
+		require.Nil(t, err)
+		require.Empty(t, incompatibilities)
+	})
+	t.Run("changed types should be reported", func(t *testing.T) {
+		t.Parallel()
+
+		r := createReindexerWithMappings(t, true, `{"mappings":{"properties":{
+			"nonce":{"type":"keyword"},
+			"hash":{"type":"keyword"},
+			"data":{"type":"text"}
+		}}}`)
+		incompatibilities, err := r.CheckMappingCompatibility(testIndex)
+		require.Nil(t, err)
+		require.Equal(t, []string{
+			"field data: source type object, destination type text",
+			"field nonce: source type long, destination type keyword",
+		}, incompatibilities)
+	})
+}
+
+func TestReindexerMultiWrite_CheckMappings(t *testing.T) {
+	t.Parallel()
+
+	cfg := config.IndicesConfig{Indices: []string{testIndex}}
+	cfg.WithTimestamp.BlockchainStartTime = 1
+
+	t.Run("incompatible mapping should error only in strict mode", func(t *testing.T) {
+		t.Parallel()
+
+		r := createReindexerWithMappings(t, true, `{"mappings":{"properties":{"nonce":{"type":"keyword"}}}}`)
+		rmw, err := NewReindexerMultiWrite(r, cfg, NewDisabledCheckpointHandler())
+		require.Nil(t, err)
+
+		require.Nil(t, rmw.CheckMappings(false))
+		require.ErrorIs(t, rmw.CheckMappings(true), errIncompatibleMapping)
+	})
+	t.Run("compatible mapping should work", func(t *testing.T) {
+		t.Parallel()
+
+		r := createReindexerWithMappings(t, true, sourceMapping)
+		rmw, err := NewReindexerMultiWrite(r, cfg, NewDisabledCheckpointHandler())
+		require.Nil(t, err)
+
+		require.Nil(t, rmw.CheckMappings(true))
+	})
+}
@@ -2,18 +2,27 @@ package mock
 
 import (
 	"bytes"
+
+	"github.com/multiversx/mx-chain-tools-go/elasticreindexer/config"
 )
 
 // ElasticClientStub -
 type ElasticClientStub struct {
-	GetMappingCalled                  func(index string) (*bytes.Buffer, error)
-	CreateIndexWithMappingCalled      func(targetIndex string, body *bytes.Buffer) error
-	DoScrollRequestAllDocumentsCalled func(index string, body []byte, handlerFunc func(responseBytes []byte) error) error
-	GetCountCalled                    func(index string) (uint64, error)
-	DoesAliasExistCalled              func(alias string) bool
-	DoBulkRequestCalled               func(buff *bytes.Buffer, index string) error
-	DoesIndexExistCalled              func(index string) bool
-	PutAliasCalled                    func(index string, alias string) error
+	GetMappingCalled                       func(index string) (*bytes.Buffer, error)
+	CreateIndexWithMappingCalled           func(targetIndex string, body *bytes.Buffer) error
+	CreateIndexWithSettingsCalled          func(targetIndex string, mapping *bytes.Buffer, settings config.IndexSettings) error
+	SetRefreshIntervalCalled               func(index string, refreshInterval string) error
+	DoScrollRequestAllDocumentsCalled      func(index string, body []byte, handlerFunc func(responseBytes []byte) error) error
+	DoSearchAfterRequestAllDocumentsCalled func(index string, body []byte, handlerFunc func(responseBytes []byte) error) error
+	GetCountCalled                         func(index string) (uint64, error)
+	GetCountWithBodyCalled                 func(index string, body []byte) (uint64, error)
+	DoesAliasExistCalled                   func(alias string) bool
+	DoBulkRequestCalled                    func(buff *bytes.Buffer, index string) error
+	DoesIndexExistCalled                   func(index string) bool
+	PutAliasCalled                         func(index string, alias string) error
+	SwapAliasCalled                        func(alias string, fromIndex string, toIndex string) error
+	RefreshIndexCalled                     func(index string) error
+	DoDeleteByQueryCalled                  func(index string, body []byte) (uint64, error)
 }
 
 // GetMapping -
@@ -26,7 +35,11 @@ func (e *ElasticClientStub) GetMapping(index string) (*bytes.Buffer, error) {
 }
 
 // GetCountWithBody -
-func (e *ElasticClientStub) GetCountWithBody(_ string, _ []byte) (uint64, error) {
+func (e *ElasticClientStub) GetCountWithBody(index string, body []byte) (uint64, error) {
+	if e.GetCountWithBodyCalled != nil {
+		return e.GetCountWithBodyCalled(index, body)
+	}
+
 	return 0, nil
 }
 
@@ -39,6 +52,24 @@ func (e *ElasticClientStub) CreateIndexWithMapping(targetIndex string, body *byt
 	return nil
 }
 
+// CreateIndexWithSettings -
+func (e *ElasticClientStub) CreateIndexWithSettings(targetIndex string, mapping *bytes.Buffer, settings config.IndexSettings) error {
+	if e.CreateIndexWithSettingsCalled != nil {
+		return e.CreateIndexWithSettingsCalled(targetIndex, mapping, settings)
+	}
+
+	return nil
+}
+
+// SetRefreshInterval -
+func (e *ElasticClientStub) SetRefreshInterval(index string, refreshInterval string) error {
+	if e.SetRefreshIntervalCalled != nil {
+		return e.SetRefreshIntervalCalled(index, refreshInterval)
+	}
+
+	return nil
+}
+
 // DoScrollRequestAllDocuments -
 func (e *ElasticClientStub) DoScrollRequestAllDocuments(index string, body []byte, handlerFunc func(responseBytes []byte) error) error {
 	if e.DoScrollRequestAllDocumentsCalled != nil {
@@ -48,6 +79,15 @@ func (e *ElasticClientStub) DoScrollRequestAllDocuments(index string, body []byt
 	return nil
 }
 
+// DoSearchAfterRequestAllDocuments -
+func (e *ElasticClientStub) DoSearchAfterRequestAllDocuments(index string, body []byte, handlerFunc func(responseBytes []byte) error) error {
+	if e.DoSearchAfterRequestAllDocumentsCalled != nil {
+		return e.DoSearchAfterRequestAllDocumentsCalled(index, body, handlerFunc)
+	}
+
+	return nil
+}
+
 // GetCount -
 func (e *ElasticClientStub) GetCount(index string) (uint64, error) {
 	if e.GetCountCalled != nil {
@@ -57,6 +97,24 @@ func (e *ElasticClientStub) GetCount(index string) (uint64, error) {
 	return 0, nil
 }
 
+// RefreshIndex -
+func (e *ElasticClientStub) RefreshIndex(index string) error {
+	if e.RefreshIndexCalled != nil {
+		return e.RefreshIndexCalled(index)
+	}
+
+	return nil
+}
+
+// DoDeleteByQuery -
+func (e *ElasticClientStub) DoDeleteByQuery(index string, body []byte) (uint64, error) {
+	if e.DoDeleteByQueryCalled != nil {
+		return e.DoDeleteByQueryCalled(index, body)
+	}
+
+	return 0, nil
+}
+
 // DoesAliasExist -
 func (e *ElasticClientStub) DoesAliasExist(alias string) bool {
 	if e.DoesAliasExistCalled != nil {
@@ -93,6 +151,15 @@ func (e *ElasticClientStub) PutAlias(index string, alias string) error {
 	return nil
 }
 
+// SwapAlias -
+func (e *ElasticClientStub) SwapAlias(alias string, fromIndex string, toIndex string) error {
+	if e.SwapAliasCalled != nil {
+		return e.SwapAliasCalled(alias, fromIndex, toIndex)
+	}
+
+	return nil
+}
+
 // IsInterfaceNil -
 func (e *ElasticClientStub) IsInterfaceNil() bool {
 	return e == nil
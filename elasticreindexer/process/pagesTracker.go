@@ -0,0 +1,129 @@
+package process
+
+import (
+	"sync"
+
+	"github.com/tidwall/gjson"
+)
+
+// watermark is the position, in the scroll order, of the last document of a contiguous range of handled pages
+type watermark struct {
+	lastTimestamp int64
+	// numAtLastTimestamp is the number of trailing documents of the range having the last timestamp
+	numAtLastTimestamp uint64
+	// lastSortValues are the raw JSON sort values of the last document, set if the pages are sorted
+	lastSortValues string
+}
+
+type trackedPage struct {
+	mark         watermark
+	numDocuments uint64
+	handled      bool
+}
+
+// pagesTracker keeps the order in which the scroll pages were read, so that, even if the pages are handled by
+// multiple workers, the watermark only advances over a contiguous range of handled pages. Since the pages are
+// sorted ascending (by timestamp or by the sort field of the index), all the documents before the watermark were indexed
+type pagesTracker struct {
+	mut               sync.Mutex
+	nextPageIndex     uint64
+	nextPageToAdvance uint64
+	pages             map[uint64]*trackedPage
+	numDocuments      uint64
+	mark              watermark
+	onAdvance         func(mark watermark, numDocuments uint64)
+}
+
+func newPagesTracker(onAdvance func(mark watermark, numDocuments uint64)) *pagesTracker {
+	return &pagesTracker{
+		pages:     make(map[uint64]*trackedPage),
+		onAdvance: onAdvance,
+	}
+}
+
+// pageRead registers a page read from the scroll and returns its index. It must be called in the scroll order
+func (pt *pagesTracker) pageRead(responseBytes []byte) uint64 {
+	page := &trackedPage{}
+	hits := gjson.GetBytes(responseBytes, "hits.hits")
+	hits.ForEach(func(_, hit gjson.Result) bool {
+		timestamp := hit.Get("_source.timestamp").Int()
+		if page.numDocuments == 0 || timestamp != page.mark.lastTimestamp {
+			page.mark.numAtLastTimestamp = 0
+		}
+
+		page.mark.lastTimestamp = timestamp
+		page.mark.numAtLastTimestamp++
+		page.mark.lastSortValues = hit.Get("sort").Raw
+		page.numDocuments++
+
+		return true
+	})
+
+	pt.mut.Lock()
+	defer pt.mut.Unlock()
+
+	pageIndex := pt.nextPageIndex
+	pt.pages[pageIndex] = page
+	pt.nextPageIndex++
+
+	return pageIndex
+}
+
+// pageHandled marks the provided page as indexed and advances the watermark over the contiguous handled pages
+func (pt *pagesTracker) pageHandled(pageIndex uint64) {
+	pt.mut.Lock()
+	defer pt.mut.Unlock()
+
+	page, found := pt.pages[pageIndex]
+	if !found {
+		return
+	}
+	page.handled = true
+
+	numDocuments := uint64(0)
+	for {
+		nextPage, ok := pt.pages[pt.nextPageToAdvance]
+		if !ok || !nextPage.handled {
+			break
+		}
+
+		pt.advanceOverPage(nextPage)
+		numDocuments += nextPage.numDocuments
+		delete(pt.pages, pt.nextPageToAdvance)
+		pt.nextPageToAdvance++
+	}
+
+	if numDocuments == 0 {
+		return
+	}
+
+	pt.numDocuments += numDocuments
+	if pt.onAdvance != nil {
+		pt.onAdvance(pt.mark, numDocuments)
+	}
+}
+
+func (pt *pagesTracker) advanceOverPage(page *trackedPage) {
+	if page.numDocuments == 0 {
+		return
+	}
+
+	// the run of documents having the last timestamp continues over the pages entirely made of that timestamp
+	hasPreviousDocuments := pt.mark.numAtLastTimestamp > 0
+	isWholePageAtLastTimestamp := page.mark.numAtLastTimestamp == page.numDocuments
+	if hasPreviousDocuments && isWholePageAtLastTimestamp && page.mark.lastTimestamp == pt.mark.lastTimestamp {
+		pt.mark.numAtLastTimestamp += page.numDocuments
+		pt.mark.lastSortValues = page.mark.lastSortValues
+		return
+	}
+
+	pt.mark = page.mark
+}
+
+// numHandledDocuments returns the number of documents from the contiguous range of handled pages
+func (pt *pagesTracker) numHandledDocuments() uint64 {
+	pt.mut.Lock()
+	defer pt.mut.Unlock()
+
+	return pt.numDocuments
+}
@@ -0,0 +1,74 @@
+package process
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func createTestPage(timestamps ...int64) []byte {
+	hits := ""
+	for idx, timestamp := range timestamps {
+		if idx > 0 {
+			hits += ","
+		}
+		hits += fmt.Sprintf(`{"_id":"%d","_source":{"timestamp":%d},"sort":[%d]}`, timestamp, timestamp, timestamp)
+	}
+
+	return []byte(fmt.Sprintf(`{"hits":{"hits":[%s]}}`, hits))
+}
+
+func TestPagesTracker_ShouldAdvanceOnlyOverContiguousHandledPages(t *testing.T) {
+	t.Parallel()
+
+	lastTimestamp := int64(0)
+	numDocuments := uint64(0)
+	tracker := newPagesTracker(func(mark watermark, numDocs uint64) {
+		lastTimestamp = mark.lastTimestamp
+		numDocuments += numDocs
+	})
+
+	firstPage := tracker.pageRead(createTestPage(1, 2))
+	secondPage := tracker.pageRead(createTestPage(3, 4, 5))
+	thirdPage := tracker.pageRead(createTestPage(6))
+
+	tracker.pageHandled(thirdPage)
+	require.Equal(t, int64(0), lastTimestamp)
+	require.Equal(t, uint64(0), tracker.numHandledDocuments())
+
+	tracker.pageHandled(firstPage)
+	require.Equal(t, int64(2), lastTimestamp)
+	require.Equal(t, uint64(2), numDocuments)
+
+	tracker.pageHandled(secondPage)
+	require.Equal(t, int64(6), lastTimestamp)
+	require.Equal(t, uint64(6), numDocuments)
+	require.Equal(t, uint64(6), tracker.numHandledDocuments())
+}
+
+func TestPagesTracker_ShouldCountTheDocumentsWithTheLastTimestamp(t *testing.T) {
+	t.Parallel()
+
+	lastMark := watermark{}
+	numAdvances := 0
+	tracker := newPagesTracker(func(mark watermark, _ uint64) {
+		lastMark = mark
+		numAdvances++
+	})
+
+	tracker.pageHandled(tracker.pageRead(createTestPage(1, 2, 2)))
+	require.Equal(t, watermark{lastTimestamp: 2, numAtLastTimestamp: 2, lastSortValues: "[2]"}, lastMark)
+
+	// the run of documents with the last timestamp continues over the next page
+	tracker.pageHandled(tracker.pageRead(createTestPage(2, 2)))
+	require.Equal(t, uint64(4), lastMark.numAtLastTimestamp)
+
+	tracker.pageHandled(tracker.pageRead(createTestPage(2, 3)))
+	require.Equal(t, watermark{lastTimestamp: 3, numAtLastTimestamp: 1, lastSortValues: "[3]"}, lastMark)
+
+	// the empty pages do not move the watermark
+	tracker.pageHandled(tracker.pageRead(createTestPage()))
+	require.Equal(t, 3, numAdvances)
+	require.Equal(t, uint64(7), tracker.numHandledDocuments())
+}
@@ -0,0 +1,75 @@
+package process
+
+import (
+	"fmt"
+	"strings"
+)
+
+// PreClean deletes, from the destination index, all the documents matching the pre-clean query, before the index is
+// bulk loaded. It returns the number of deleted documents. A destination index that does not exist yet is skipped.
+// It refuses to run if the destination cluster is also the source cluster, as the destination index would be the
+// source index
+func (r *reindexer) PreClean(index string) (uint64, error) {
+	if r.preCleanQuery == nil {
+		return 0, errNilPreCleanQuery
+	}
+	if r.isDestinationSameAsSource {
+		return 0, fmt.Errorf("%w, index %s", errPreCleanSourceIndex, index)
+	}
+	if !r.destinationElastic.DoesAliasExist(index) && !r.destinationElastic.DoesIndexExist(index) {
+		log.Info("the destination index does not exist, nothing to pre-clean", "index", index)
+		return 0, nil
+	}
+
+	body, err := encodeQuery(object{
+		"query": r.preCleanQuery,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	numDeleted, err := r.destinationElastic.DoDeleteByQuery(index, body.Bytes())
+	if err != nil {
+		return numDeleted, fmt.Errorf("%w while pre-cleaning index %s, deleted %d documents", err, index, numDeleted)
+	}
+
+	log.Info("pre-cleaned the destination index", "index", index, "num deleted documents", numDeleted)
+
+	return numDeleted, nil
+}
+
+// isSameCluster returns true if the provided URLs point to the same cluster, ignoring the letter case and the
+// trailing slashes
+func isSameCluster(firstURL string, secondURL string) bool {
+	normalize := func(url string) string {
+		return strings.TrimRight(strings.ToLower(strings.TrimSpace(url)), "/")
+	}
+
+	return normalize(firstURL) == normalize(secondURL)
+}
+
+// PreClean deletes, from all the configured destination indices, the documents matching the pre-clean query. It
+// should be called before any index is reindexed
+func (rmw *reindexerMultiWrite) PreClean() error {
+	indices := append([]string{}, rmw.indicesNoTimestamp...)
+	if rmw.enabled {
+		indices = append(indices, rmw.indicesWithTimestamp...)
+	}
+
+	totalDeleted := uint64(0)
+	for _, index := range indices {
+		if index == "" {
+			continue
+		}
+
+		numDeleted, err := rmw.reindexerClient.PreClean(index)
+		totalDeleted += numDeleted
+		if err != nil {
+			return err
+		}
+	}
+
+	log.Info("pre-clean done", "num deleted documents", totalDeleted)
+
+	return nil
+}
@@ -0,0 +1,131 @@
+package process
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/multiversx/mx-chain-tools-go/elasticreindexer/config"
+	"github.com/multiversx/mx-chain-tools-go/elasticreindexer/process/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func createReindexerForPreClean(t *testing.T, destination *mock.ElasticClientStub) *reindexer {
+	r, err := newReindexer(&mock.ElasticClientStub{}, destination, []string{testIndex})
+	require.Nil(t, err)
+	r.preCleanQuery = object{"term": object{"status": "pending"}}
+
+	return r
+}
+
+func TestIsSameCluster(t *testing.T) {
+	t.Parallel()
+
+	require.True(t, isSameCluster("http://127.0.0.1:9200", "http://127.0.0.1:9200"))
+	require.True(t, isSameCluster("http://127.0.0.1:9200/", " HTTP://127.0.0.1:9200"))
+	require.False(t, isSameCluster("http://127.0.0.1:9200", "http://127.0.0.1:9201"))
+}
+
+func TestReindexer_PreClean(t *testing.T) {
+	t.Parallel()
+
+	t.Run("nil query should error", func(t *testing.T) {
+		t.Parallel()
+
+		r := createReindexerForPreClean(t, &mock.ElasticClientStub{})
+		r.preCleanQuery = nil
+
+		_, err := r.PreClean(testIndex)
+		require.Equal(t, errNilPreCleanQuery, err)
+	})
+	t.Run("destination being the source cluster should refuse", func(t *testing.T) {
+		t.Parallel()
+
+		r := createReindexerForPreClean(t, &mock.ElasticClientStub{
+			DoDeleteByQueryCalled: func(_ string, _ []byte) (uint64, error) {
+				require.Fail(t, "should have not deleted")
+				return 0, nil
+			},
+		})
+		r.isDestinationSameAsSource = true
+
+		_, err := r.PreClean(testIndex)
+		require.True(t, errors.Is(err, errPreCleanSourceIndex))
+	})
+	t.Run("missing destination index should skip", func(t *testing.T) {
+		t.Parallel()
+
+		r := createReindexerForPreClean(t, &mock.ElasticClientStub{
+			DoDeleteByQueryCalled: func(_ string, _ []byte) (uint64, error) {
+				require.Fail(t, "should have not deleted")
+				return 0, nil
+			},
+		})
+
+		numDeleted, err := r.PreClean(testIndex)
+		require.Nil(t, err)
+		require.Equal(t, uint64(0), numDeleted)
+	})
+	t.Run("delete error should error", func(t *testing.T) {
+		t.Parallel()
+
+		expectedErr := errors.New("expected error")
+		r := createReindexerForPreClean(t, &mock.ElasticClientStub{
+			DoesAliasExistCalled: func(_ string) bool {
+				return true
+			},
+			DoDeleteByQueryCalled: func(_ string, _ []byte) (uint64, error) {
+				return 3, expectedErr
+			},
+		})
+
+		numDeleted, err := r.PreClean(testIndex)
+		require.True(t, errors.Is(err, expectedErr))
+		require.Equal(t, uint64(3), numDeleted)
+	})
+	t.Run("should delete the documents matching the query", func(t *testing.T) {
+		t.Parallel()
+
+		r := createReindexerForPreClean(t, &mock.ElasticClientStub{
+			DoesIndexExistCalled: func(_ string) bool {
+				return true
+			},
+			DoDeleteByQueryCalled: func(index string, body []byte) (uint64, error) {
+				require.Equal(t, testIndex, index)
+				require.JSONEq(t, `{"query":{"term":{"status":"pending"}}}`, string(body))
+				return 7, nil
+			},
+		})
+
+		numDeleted, err := r.PreClean(testIndex)
+		require.Nil(t, err)
+		require.Equal(t, uint64(7), numDeleted)
+	})
+}
+
+func TestReindexerMultiWrite_PreClean(t *testing.T) {
+	t.Parallel()
+
+	cleanedIndices := make([]string, 0)
+	r := createReindexerForPreClean(t, &mock.ElasticClientStub{
+		DoesAliasExistCalled: func(_ string) bool {
+			return true
+		},
+		DoDeleteByQueryCalled: func(index string, _ []byte) (uint64, error) {
+			cleanedIndices = append(cleanedIndices, index)
+			return 1, nil
+		},
+	})
+
+	indicesConfig := config.IndicesConfig{
+		Indices: []string{"accounts", ""},
+	}
+	indicesConfig.WithTimestamp.Enabled = true
+	indicesConfig.WithTimestamp.BlockchainStartTime = 1
+	indicesConfig.WithTimestamp.IndicesWithTimestamp = []string{"blocks"}
+	rmw, err := NewReindexerMultiWrite(r, indicesConfig, NewDisabledCheckpointHandler())
+	require.Nil(t, err)
+
+	err = rmw.PreClean()
+	require.Nil(t, err)
+	require.Equal(t, []string{"accounts", "blocks"}, cleanedIndices)
+}
@@ -0,0 +1,145 @@
+package process
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// progressReporter counts the pages and the documents reindexed for an index and, if an interval is set, periodically
+// logs the number of processed documents, the percentage out of the source count captured at the start, the current
+// throughput and the estimated remaining time. It is safe to use from the concurrent scrolls and workers of an index
+type progressReporter struct {
+	index          string
+	totalDocuments uint64
+	numPages       uint64
+	numDocuments   uint64
+	numConflicts   uint64
+	startTime      time.Time
+
+	closeOnce sync.Once
+	closeChan chan struct{}
+	wg        sync.WaitGroup
+}
+
+// newProgressReporter creates a progress reporter for the provided index. A non-positive interval disables the
+// periodic logging, while the pages and documents are still counted
+func newProgressReporter(index string, totalDocuments uint64, interval time.Duration) *progressReporter {
+	reporter := &progressReporter{
+		index:          index,
+		totalDocuments: totalDocuments,
+		startTime:      time.Now(),
+		closeChan:      make(chan struct{}),
+	}
+	if interval <= 0 {
+		return reporter
+	}
+
+	reporter.wg.Add(1)
+	go reporter.reportContinuously(interval)
+
+	return reporter
+}
+
+// pageRead registers a page read from the source and returns the number of pages read so far
+func (pr *progressReporter) pageRead() uint64 {
+	return atomic.AddUint64(&pr.numPages, 1)
+}
+
+// documentsProcessed registers the provided number of documents as reindexed
+func (pr *progressReporter) documentsProcessed(numDocuments uint64) {
+	atomic.AddUint64(&pr.numDocuments, numDocuments)
+}
+
+// conflictsSkipped registers the provided number of documents as skipped, since they already exist in the destination
+func (pr *progressReporter) conflictsSkipped(numConflicts uint64) {
+	atomic.AddUint64(&pr.numConflicts, numConflicts)
+}
+
+func (pr *progressReporter) reportContinuously(interval time.Duration) {
+	defer pr.wg.Done()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	lastReportTime := pr.startTime
+	lastNumDocuments := uint64(0)
+	for {
+		select {
+		case now := <-ticker.C:
+			numDocuments := atomic.LoadUint64(&pr.numDocuments)
+			throughput := computeThroughput(numDocuments-lastNumDocuments, now.Sub(lastReportTime))
+			pr.report(numDocuments, throughput, now.Sub(pr.startTime))
+
+			lastReportTime = now
+			lastNumDocuments = numDocuments
+		case <-pr.closeChan:
+			return
+		}
+	}
+}
+
+func (pr *progressReporter) report(numDocuments uint64, throughput float64, elapsed time.Duration) {
+	percentage, eta, ok := computeReindexProgress(numDocuments, pr.totalDocuments, elapsed)
+	if !ok {
+		log.Info("reindexing progress", "index", pr.index, "processed", numDocuments,
+			"docs/sec", fmt.Sprintf("%.1f", throughput), "elapsed", elapsed.Round(time.Second))
+		return
+	}
+
+	log.Info("reindexing progress",
+		"index", pr.index,
+		"processed", numDocuments,
+		"total", pr.totalDocuments,
+		"progress", fmt.Sprintf("%.2f%%", percentage),
+		"docs/sec", fmt.Sprintf("%.1f", throughput),
+		"skipped conflicts", atomic.LoadUint64(&pr.numConflicts),
+		"elapsed", elapsed.Round(time.Second),
+		"eta", eta.Round(time.Second),
+	)
+}
+
+// close stops the periodic logging and logs the number of skipped documents, if any. It can be called more than once
+func (pr *progressReporter) close() {
+	pr.closeOnce.Do(func() {
+		close(pr.closeChan)
+		pr.wg.Wait()
+
+		numConflicts := atomic.LoadUint64(&pr.numConflicts)
+		if numConflicts > 0 {
+			log.Info("skipped the documents that already exist in the destination", "index", pr.index, "num skipped documents", numConflicts)
+		}
+	})
+}
+
+// computeThroughput returns the number of documents processed per second in the provided duration
+func computeThroughput(numDocuments uint64, duration time.Duration) float64 {
+	if duration <= 0 {
+		return 0
+	}
+
+	return float64(numDocuments) / duration.Seconds()
+}
+
+// computeReindexProgress returns the completion percentage and the estimated remaining time, based on the average
+// throughput since the start. It returns false if the total is 0 or already exceeded (e.g. the source still receives
+// new documents), in which case the percentage is meaningless
+func computeReindexProgress(numDocuments uint64, totalDocuments uint64, elapsed time.Duration) (float64, time.Duration, bool) {
+	if totalDocuments == 0 || numDocuments > totalDocuments {
+		return 0, 0, false
+	}
+
+	percentage := float64(numDocuments) * 100 / float64(totalDocuments)
+	if numDocuments == 0 {
+		return percentage, 0, true
+	}
+
+	averageThroughput := computeThroughput(numDocuments, elapsed)
+	if averageThroughput == 0 {
+		return percentage, 0, true
+	}
+	eta := time.Duration(float64(totalDocuments-numDocuments) / averageThroughput * float64(time.Second))
+
+	return percentage, eta, true
+}
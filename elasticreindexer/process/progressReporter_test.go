@@ -0,0 +1,143 @@
+package process
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/multiversx/mx-chain-tools-go/elasticreindexer/process/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestComputeThroughput(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, float64(0), computeThroughput(100, 0))
+	require.Equal(t, float64(50), computeThroughput(100, 2*time.Second))
+	require.Equal(t, float64(250), computeThroughput(50, 200*time.Millisecond))
+}
+
+func TestComputeReindexProgress(t *testing.T) {
+	t.Parallel()
+
+	t.Run("unknown or exceeded total should not compute", func(t *testing.T) {
+		t.Parallel()
+
+		_, _, ok := computeReindexProgress(10, 0, time.Second)
+		require.False(t, ok)
+
+		_, _, ok = computeReindexProgress(11, 10, time.Second)
+		require.False(t, ok)
+	})
+	t.Run("nothing processed should have no eta", func(t *testing.T) {
+		t.Parallel()
+
+		percentage, eta, ok := computeReindexProgress(0, 10, time.Second)
+		require.True(t, ok)
+		require.Equal(t, float64(0), percentage)
+		require.Equal(t, time.Duration(0), eta)
+	})
+	t.Run("should use the average throughput", func(t *testing.T) {
+		t.Parallel()
+
+		percentage, eta, ok := computeReindexProgress(250, 1000, 10*time.Second)
+		require.True(t, ok)
+		require.Equal(t, float64(25), percentage)
+		require.Equal(t, 30*time.Second, eta)
+
+		percentage, eta, ok = computeReindexProgress(1000, 1000, time.Minute)
+		require.True(t, ok)
+		require.Equal(t, float64(100), percentage)
+		require.Equal(t, time.Duration(0), eta)
+	})
+}
+
+func TestProgressReporter(t *testing.T) {
+	t.Parallel()
+
+	t.Run("concurrent updates should be counted", func(t *testing.T) {
+		t.Parallel()
+
+		reporter := newProgressReporter(testIndex, 1000, time.Millisecond)
+		wg := sync.WaitGroup{}
+		wg.Add(10)
+		for i := 0; i < 10; i++ {
+			go func() {
+				defer wg.Done()
+
+				for j := 0; j < 10; j++ {
+					reporter.pageRead()
+					reporter.documentsProcessed(10)
+					time.Sleep(time.Millisecond)
+				}
+			}()
+		}
+		wg.Wait()
+		reporter.close()
+		reporter.close()
+
+		require.Equal(t, uint64(101), reporter.pageRead())
+		require.Equal(t, uint64(1000), reporter.numDocuments)
+	})
+	t.Run("disabled logging should still count", func(t *testing.T) {
+		t.Parallel()
+
+		reporter := newProgressReporter(testIndex, 0, 0)
+		reporter.documentsProcessed(5)
+		reporter.close()
+
+		require.Equal(t, uint64(5), reporter.numDocuments)
+	})
+}
+
+func TestReindexer_CreateProgressReporter(t *testing.T) {
+	t.Parallel()
+
+	t.Run("count error should error", func(t *testing.T) {
+		t.Parallel()
+
+		expectedErr := errors.New("expected error")
+		r, _ := newReindexer(&mock.ElasticClientStub{
+			GetCountWithBodyCalled: func(_ string, _ []byte) (uint64, error) {
+				return 0, expectedErr
+			},
+		}, &mock.ElasticClientStub{}, []string{testIndex})
+
+		reporter, err := r.CreateProgressReporter(testIndex, 10, 20)
+		require.Nil(t, reporter)
+		require.True(t, errors.Is(err, expectedErr))
+	})
+	t.Run("should count the interval documents matching the query filter", func(t *testing.T) {
+		t.Parallel()
+
+		r, _ := newReindexer(&mock.ElasticClientStub{}, &mock.ElasticClientStub{}, []string{testIndex})
+		r.queryFilter = object{"term": object{"status": "success"}}
+		r.sourceElastic = &mock.ElasticClientStub{
+			GetCountWithBodyCalled: func(_ string, body []byte) (uint64, error) {
+				require.JSONEq(t, getWithTimestamp(10, 20, false, false, r.queryFilter).String(), string(body))
+				return 42, nil
+			},
+		}
+
+		reporter, err := r.CreateProgressReporter(testIndex, 10, 20)
+		require.Nil(t, err)
+		require.Equal(t, uint64(42), reporter.totalDocuments)
+		reporter.close()
+	})
+}
+
+func TestReindexer_ScrollRequestHandlerShouldCountDocuments(t *testing.T) {
+	t.Parallel()
+
+	r, _ := newReindexer(&mock.ElasticClientStub{}, &mock.ElasticClientStub{}, []string{testIndex})
+	reporter := newProgressReporter(testIndex, 10, 0)
+	handlerFunc := r.createScrollRequestHandlerFunction(reporter, testIndex)
+
+	require.Nil(t, handlerFunc([]byte(testScrollPage)))
+	require.Nil(t, handlerFunc([]byte(testScrollPage)))
+	reporter.close()
+
+	require.Equal(t, uint64(3), reporter.pageRead())
+	require.Equal(t, uint64(6), reporter.numDocuments)
+}
@@ -17,28 +17,53 @@ func encodeQuery(query object) (bytes.Buffer, error) {
 	return buff, nil
 }
 
-func getAll() *bytes.Buffer {
+func getAll(filter object) *bytes.Buffer {
 	obj := object{
-		"query": object{
+		"query": withFilter(object{
 			"match_all": object{},
+		}, filter),
+	}
+
+	encoded, _ := encodeQuery(obj)
+
+	return &encoded
+}
+
+// getAllSortedBy returns the query matching all the documents, sorted ascending by the provided field, which should
+// hold unique values. If sort values are provided (as returned in the hits of a previous search), only the documents
+// after them are matched, using search_after
+func getAllSortedBy(sortField string, searchAfter string, filter object) *bytes.Buffer {
+	obj := object{
+		"query": withFilter(object{
+			"match_all": object{},
+		}, filter),
+		"sort": []interface{}{
+			object{
+				sortField: object{
+					"order": "asc",
+				},
+			},
 		},
 	}
+	if len(searchAfter) > 0 {
+		obj["search_after"] = json.RawMessage(searchAfter)
+	}
 
 	encoded, _ := encodeQuery(obj)
 
 	return &encoded
 }
 
-func getWithTimestamp(start, stop int64, withSource bool, withSortAsc bool) *bytes.Buffer {
+func getWithTimestamp(start, stop int64, withSource bool, withSortAsc bool, filter object) *bytes.Buffer {
 	obj := object{
-		"query": object{
+		"query": withFilter(object{
 			"range": object{
 				"timestamp": object{
 					"gte": start,
 					"lte": stop,
 				},
 			},
-		},
+		}, filter),
 	}
 
 	if withSortAsc {
@@ -59,6 +84,52 @@ func getWithTimestamp(start, stop int64, withSource bool, withSortAsc bool) *byt
 	return &encoded
 }
 
+// withFilter returns a query matching the documents that match both the provided query and the provided filter.
+// A nil filter returns the query as it is
+func withFilter(query object, filter object) object {
+	if filter == nil {
+		return query
+	}
+
+	return object{
+		"bool": object{
+			"filter": []interface{}{query, filter},
+		},
+	}
+}
+
+// ParseQueryFilter parses a search request body holding a "query" object, as read from a query file, and returns
+// the query, used to filter the reindexed documents
+func ParseQueryFilter(body []byte) (map[string]interface{}, error) {
+	searchBody := make(map[string]json.RawMessage)
+	err := json.Unmarshal(body, &searchBody)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", errInvalidQueryFilter, err.Error())
+	}
+
+	for key := range searchBody {
+		if key != "query" {
+			return nil, fmt.Errorf("%w: unsupported field %s, only the query field is allowed", errInvalidQueryFilter, key)
+		}
+	}
+
+	rawQuery, found := searchBody["query"]
+	if !found {
+		return nil, fmt.Errorf("%w: missing query field", errInvalidQueryFilter)
+	}
+
+	query := make(object)
+	err = json.Unmarshal(rawQuery, &query)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", errInvalidQueryFilter, err.Error())
+	}
+	if len(query) == 0 {
+		return nil, fmt.Errorf("%w: empty query", errInvalidQueryFilter)
+	}
+
+	return query, nil
+}
+
 type generalElasticResponse struct {
 	Hits struct {
 		Hits []struct {
@@ -0,0 +1,73 @@
+package process
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseQueryFilter(t *testing.T) {
+	t.Parallel()
+
+	t.Run("malformed JSON should error", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := ParseQueryFilter([]byte(`{"query": {`))
+		require.ErrorIs(t, err, errInvalidQueryFilter)
+	})
+	t.Run("missing query should error", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := ParseQueryFilter([]byte(`{}`))
+		require.ErrorIs(t, err, errInvalidQueryFilter)
+		require.Contains(t, err.Error(), "missing query field")
+	})
+	t.Run("empty query should error", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := ParseQueryFilter([]byte(`{"query": {}}`))
+		require.ErrorIs(t, err, errInvalidQueryFilter)
+	})
+	t.Run("unsupported field should error", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := ParseQueryFilter([]byte(`{"query": {"match_all": {}}, "size": 10}`))
+		require.ErrorIs(t, err, errInvalidQueryFilter)
+		require.Contains(t, err.Error(), "size")
+	})
+	t.Run("should work", func(t *testing.T) {
+		t.Parallel()
+
+		query, err := ParseQueryFilter([]byte(`{"query": {"term": {"status": "success"}}}`))
+		require.Nil(t, err)
+		require.Equal(t, object{"term": object{"status": "success"}}, query)
+	})
+}
+
+func TestGetAllSortedBy_ShouldResumeUsingSearchAfter(t *testing.T) {
+	t.Parallel()
+
+	require.JSONEq(t, `{"query":{"match_all":{}},"sort":[{"address":{"order":"asc"}}],"search_after":["erd1"]}`,
+		getAllSortedBy("address", `["erd1"]`, nil).String())
+	require.JSONEq(t, `{
+		"query":{"bool":{"filter":[{"match_all":{}},{"term":{"status":"success"}}]}},
+		"sort":[{"nonce":{"order":"asc"}}],
+		"search_after":[7]
+	}`, getAllSortedBy("nonce", "[7]", object{"term": object{"status": "success"}}).String())
+}
+
+func TestQueriesWithFilter(t *testing.T) {
+	t.Parallel()
+
+	filter := object{"term": object{"status": "success"}}
+
+	require.JSONEq(t, `{"query":{"match_all":{}}}`, getAll(nil).String())
+	require.JSONEq(t, `{"query":{"bool":{"filter":[{"match_all":{}},{"term":{"status":"success"}}]}}}`, getAll(filter).String())
+	require.JSONEq(t, `{"query":{"bool":{"filter":[{"match_all":{}},{"term":{"status":"success"}}]}},"sort":[{"address":{"order":"asc"}}]}`,
+		getAllSortedBy("address", "", filter).String())
+	require.JSONEq(t, `{
+		"query":{"bool":{"filter":[{"range":{"timestamp":{"gte":1,"lte":2}}},{"term":{"status":"success"}}]}},
+		"sort":[{"timestamp":{"order":"asc"}}],
+		"_source":true
+	}`, getWithTimestamp(1, 2, true, true, filter).String())
+}
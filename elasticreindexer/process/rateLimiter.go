@@ -0,0 +1,83 @@
+package process
+
+import (
+	"bytes"
+	"sync"
+	"time"
+)
+
+// tokenBucket is a token bucket rate limiter, refilled with rate tokens per second, up to one second worth of tokens.
+// A request for more tokens than available is still granted, but the caller is blocked until the bucket is refilled,
+// so the concurrent callers are served in order and the limit is kept on average
+type tokenBucket struct {
+	mut        sync.Mutex
+	rate       float64
+	capacity   float64
+	tokens     float64
+	lastRefill time.Time
+
+	// now and sleep are the clock of the bucket, replaced in tests
+	now   func() time.Time
+	sleep func(duration time.Duration)
+}
+
+func newTokenBucket(rate uint64) *tokenBucket {
+	return &tokenBucket{
+		rate:       float64(rate),
+		capacity:   float64(rate),
+		tokens:     float64(rate),
+		lastRefill: time.Now(),
+		now:        time.Now,
+		sleep:      time.Sleep,
+	}
+}
+
+// wait blocks until the provided number of tokens can be consumed
+func (tb *tokenBucket) wait(numTokens uint64) {
+	tb.mut.Lock()
+	now := tb.now()
+	tb.tokens += now.Sub(tb.lastRefill).Seconds() * tb.rate
+	if tb.tokens > tb.capacity {
+		tb.tokens = tb.capacity
+	}
+	tb.lastRefill = now
+	tb.tokens -= float64(numTokens)
+	deficit := -tb.tokens
+	tb.mut.Unlock()
+
+	if deficit > 0 {
+		tb.sleep(time.Duration(deficit / tb.rate * float64(time.Second)))
+	}
+}
+
+// rateLimitedElasticClient wraps an elastic client handler so that the bulk requests do not index more documents
+// per second than the configured rate. The bulk requests are delayed, never dropped
+type rateLimitedElasticClient struct {
+	ElasticClientHandler
+	bucket *tokenBucket
+}
+
+func newRateLimitedElasticClient(client ElasticClientHandler, documentsPerSecond uint64) *rateLimitedElasticClient {
+	return &rateLimitedElasticClient{
+		ElasticClientHandler: client,
+		bucket:               newTokenBucket(documentsPerSecond),
+	}
+}
+
+// DoBulkRequest waits until the documents of the bulk request are allowed by the rate limiter, then does the request
+func (client *rateLimitedElasticClient) DoBulkRequest(buff *bytes.Buffer, index string) error {
+	client.bucket.wait(countBulkDocuments(buff))
+
+	return client.ElasticClientHandler.DoBulkRequest(buff, index)
+}
+
+// countBulkDocuments returns the number of documents of a bulk request body, as each document is written on two
+// lines: the action meta line and the document source line
+func countBulkDocuments(buff *bytes.Buffer) uint64 {
+	return uint64(bytes.Count(buff.Bytes(), []byte("\n")) / 2)
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (client *rateLimitedElasticClient) IsInterfaceNil() bool {
+	return client == nil
+}
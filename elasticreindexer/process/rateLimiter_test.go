@@ -0,0 +1,107 @@
+package process
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/multiversx/mx-chain-tools-go/elasticreindexer/process/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func createBulkBody(numDocuments int) *bytes.Buffer {
+	return bytes.NewBufferString(strings.Repeat("{ \"index\" : { \"_id\" : \"id\" } }\n{\"field\":\"value\"}\n", numDocuments))
+}
+
+func TestCountBulkDocuments(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, uint64(0), countBulkDocuments(&bytes.Buffer{}))
+	require.Equal(t, uint64(3), countBulkDocuments(createBulkBody(3)))
+}
+
+// fakeClock is a clock that only advances when sleeping, so the rate limiter delays can be asserted exactly
+type fakeClock struct {
+	mut          sync.Mutex
+	currentTime  time.Time
+	totalSlept   time.Duration
+	numSleepings int
+}
+
+func (clock *fakeClock) now() time.Time {
+	clock.mut.Lock()
+	defer clock.mut.Unlock()
+
+	return clock.currentTime
+}
+
+func (clock *fakeClock) sleep(duration time.Duration) {
+	clock.mut.Lock()
+	defer clock.mut.Unlock()
+
+	clock.currentTime = clock.currentTime.Add(duration)
+	clock.totalSlept += duration
+	clock.numSleepings++
+}
+
+func createRateLimitedClientWithFakeClock(rate uint64, numIndexedDocuments *uint64) (*rateLimitedElasticClient, *fakeClock) {
+	client := newRateLimitedElasticClient(&mock.ElasticClientStub{
+		DoBulkRequestCalled: func(buff *bytes.Buffer, index string) error {
+			*numIndexedDocuments += countBulkDocuments(buff)
+			return nil
+		},
+	}, rate)
+
+	clock := &fakeClock{currentTime: time.Unix(1000, 0)}
+	client.bucket.now = clock.now
+	client.bucket.sleep = clock.sleep
+	client.bucket.lastRefill = clock.currentTime
+
+	return client, clock
+}
+
+func TestRateLimitedElasticClient_DoBulkRequest(t *testing.T) {
+	t.Parallel()
+
+	t.Run("the burst should not be delayed", func(t *testing.T) {
+		t.Parallel()
+
+		numIndexedDocuments := uint64(0)
+		client, clock := createRateLimitedClientWithFakeClock(2000, &numIndexedDocuments)
+
+		for i := 0; i < 40; i++ {
+			require.Nil(t, client.DoBulkRequest(createBulkBody(50), testIndex))
+		}
+		require.Equal(t, uint64(2000), numIndexedDocuments)
+		require.Equal(t, 0, clock.numSleepings)
+	})
+	t.Run("the documents after the burst should be delayed at the configured rate", func(t *testing.T) {
+		t.Parallel()
+
+		numIndexedDocuments := uint64(0)
+		client, clock := createRateLimitedClientWithFakeClock(2000, &numIndexedDocuments)
+
+		// consume the initial burst, so only the refill rate is measured
+		client.bucket.wait(2000)
+		for i := 0; i < 20; i++ {
+			require.Nil(t, client.DoBulkRequest(createBulkBody(50), testIndex))
+		}
+
+		// 1000 documents at 2000 documents/s should be delayed by 500ms, 25ms for each bulk request
+		require.Equal(t, uint64(1000), numIndexedDocuments)
+		require.Equal(t, 20, clock.numSleepings)
+		require.Equal(t, 500*time.Millisecond, clock.totalSlept)
+	})
+	t.Run("a bulk request larger than the burst should be granted after the deficit is refilled", func(t *testing.T) {
+		t.Parallel()
+
+		numIndexedDocuments := uint64(0)
+		client, clock := createRateLimitedClientWithFakeClock(100, &numIndexedDocuments)
+
+		require.Nil(t, client.DoBulkRequest(createBulkBody(150), testIndex))
+		require.Equal(t, uint64(150), numIndexedDocuments)
+		require.Equal(t, 500*time.Millisecond, clock.totalSlept)
+	})
+}
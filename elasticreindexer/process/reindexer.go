@@ -5,23 +5,59 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"sync/atomic"
+	"net/http"
+	"time"
 
 	"github.com/multiversx/mx-chain-core-go/core/check"
 	logger "github.com/multiversx/mx-chain-logger-go"
+	"github.com/multiversx/mx-chain-tools-go/elasticreindexer/config"
+	"github.com/multiversx/mx-chain-tools-go/elasticreindexer/elastic"
+	"github.com/tidwall/gjson"
 )
 
 var (
-	errNilElasticHandler = errors.New("nil elastic handler")
-	log                  = logger.GetOrCreate("process")
+	errNilElasticHandler   = errors.New("nil elastic handler")
+	errScrollStopped       = errors.New("scroll stopped because of a failed bulk request")
+	errNilTransformer      = errors.New("nil document transformer")
+	errNilCheckpoint       = errors.New("nil checkpoint handler")
+	errCheckpointMismatch  = errors.New("the checkpoint was created for a different source/destination pair")
+	errCountMismatch       = errors.New("source and destination counts differ")
+	errInvalidQueryFilter  = errors.New("invalid query filter")
+	errIncompatibleMapping = errors.New("incompatible source and destination mappings")
+	errNilPreCleanQuery    = errors.New("nil pre-clean query")
+	errPreCleanSourceIndex = errors.New("the pre-clean refused to run, the destination is the source cluster")
+	errNilFailedDocuments  = errors.New("nil failed documents handler")
+	errEmptyDeadLetterFile = errors.New("empty dead-letter file path")
+	errTooManyFailures     = errors.New("too many documents failed to be indexed")
+	log                    = logger.GetOrCreate("process")
 )
 
-const indexSuffix = "-000001"
+const (
+	indexSuffix             = "-000001"
+	disabledRefreshInterval = "-1"
+	bulkActionIndex         = "index"
+	bulkActionCreate        = "create"
+)
 
 type reindexer struct {
 	sourceElastic      ElasticClientHandler
 	destinationElastic ElasticClientHandler
 	indices            []string
+	numWorkers         int
+	transform          DocumentTransformer
+	checkpoints        CheckpointHandler
+	failedDocuments    FailedDocumentsHandler
+	indexSettings      config.IndexSettings
+	disableRefresh     bool
+	bulkMaxDocuments   int
+	bulkMaxBytes       int
+	queryFilter        object
+	sortFields         map[string]string
+	progressInterval   time.Duration
+	createOnly         bool
+
+	preCleanQuery             object
+	isDestinationSameAsSource bool
 }
 
 // newReindexer returns a new instance of reindexer if the provided params aren't nil, or error otherwise
@@ -37,9 +73,48 @@ func newReindexer(sourceElastic ElasticClientHandler, destinationElastic Elastic
 		sourceElastic:      sourceElastic,
 		destinationElastic: destinationElastic,
 		indices:            indices,
+		numWorkers:         1,
+		bulkMaxBytes:       DefaultBulkMaxBytes,
+		transform:          noTransform,
+		checkpoints:        NewDisabledCheckpointHandler(),
+		failedDocuments:    newDisabledFailedDocumentsHandler(),
 	}, nil
 }
 
+// SetDocumentTransformer sets the transformer applied on each document before being written in the destination
+func (r *reindexer) SetDocumentTransformer(transformer DocumentTransformer) error {
+	if transformer == nil {
+		return errNilTransformer
+	}
+
+	r.transform = transformer
+
+	return nil
+}
+
+// SetCheckpointHandler sets the handler that keeps the reindexing progress
+func (r *reindexer) SetCheckpointHandler(checkpoints CheckpointHandler) error {
+	if check.IfNil(checkpoints) {
+		return errNilCheckpoint
+	}
+
+	r.checkpoints = checkpoints
+
+	return nil
+}
+
+// SetFailedDocumentsHandler sets the handler of the documents that failed to be indexed. By default, the first failed
+// document aborts the reindexing
+func (r *reindexer) SetFailedDocumentsHandler(failedDocuments FailedDocumentsHandler) error {
+	if check.IfNil(failedDocuments) {
+		return errNilFailedDocuments
+	}
+
+	r.failedDocuments = failedDocuments
+
+	return nil
+}
+
 // Process will handle the reindexing from source Elastic client to destination Elastic client
 func (r *reindexer) Process(overwrite bool, skipMappings bool, indices ...string) error {
 	providedIndices := indices
@@ -62,23 +137,50 @@ func (r *reindexer) Process(overwrite bool, skipMappings bool, indices ...string
 }
 
 func (r *reindexer) processIndex(index string, overwrite bool, skipMappings bool) error {
-	originalSourceCount, err := r.sourceElastic.GetCount(index)
+	if r.checkpoints.IsIndexCompleted(index) {
+		log.Info("index was already reindexed, skipping", "index", index)
+		return nil
+	}
+
+	originalSourceCount, err := r.getCount(r.sourceElastic, index)
 	if err != nil {
 		return fmt.Errorf("%w while getting the source count for index %s", err, index)
 	}
+	if r.queryFilter != nil {
+		log.Info("documents matching the query filter", "index", index, "count", originalSourceCount)
+	}
+
+	err = r.DisableRefresh(index)
+	if err != nil {
+		return err
+	}
 
 	err = r.copyMappingIfNecessary(index, overwrite, skipMappings)
 	if err != nil {
+		r.restoreRefreshAfterError(index)
 		return fmt.Errorf("%w while copying the mapping for index %s", err, index)
 	}
 
 	log.Info("starting reindexing", "index", index)
 
-	err = r.reindexData(index)
+	progress := newProgressReporter(index, originalSourceCount, r.progressInterval)
+	numProcessed, err := r.reindexData(index, progress)
+	progress.close()
 	if err != nil {
+		r.restoreRefreshAfterError(index)
 		return fmt.Errorf("%w while reindexing data for index %s", err, index)
 	}
 
+	err = r.RestoreRefresh(index)
+	if err != nil {
+		return err
+	}
+
+	err = r.checkpoints.MarkIndexCompleted(index, numProcessed)
+	if err != nil {
+		return fmt.Errorf("%w while saving the checkpoint for index %s", err, index)
+	}
+
 	destinationCount, err := r.destinationElastic.GetCount(index)
 	if err != nil {
 		return fmt.Errorf("%w while getting the destination count for index %s", err, index)
@@ -87,8 +189,16 @@ func (r *reindexer) processIndex(index string, overwrite bool, skipMappings bool
 	log.Info("finished indexing for index",
 		"index", index,
 		"original source count", originalSourceCount,
+		"processed count", numProcessed,
 		"destination count (estimation)", destinationCount)
 
+	if numProcessed != originalSourceCount {
+		log.Warn("the number of processed documents differs from the original source count",
+			"index", index,
+			"original source count", originalSourceCount,
+			"processed count", numProcessed)
+	}
+
 	return nil
 }
 
@@ -117,7 +227,7 @@ func (r *reindexer) copyMappingIfNecessary(index string, overwrite bool, skipMap
 			return fmt.Errorf("error while getting mapping from source: %w", err)
 		}
 
-		err = r.destinationElastic.CreateIndexWithMapping(indexWithSuffix, sourceMapping)
+		err = r.createIndex(indexWithSuffix, sourceMapping)
 		if err != nil {
 			return fmt.Errorf("error while creating index with mapping to destination: %w", err)
 		}
@@ -130,34 +240,106 @@ func (r *reindexer) copyMappingIfNecessary(index string, overwrite bool, skipMap
 	return r.destinationElastic.PutAlias(indexWithSuffix, index)
 }
 
-func (r *reindexer) reindexData(index string) error {
-	count := 0
-	handlerFunc := func(responseBytes []byte) error {
-		count++
-		dataBuffers, err := prepareDataForIndexing(responseBytes, index, count)
-		if err != nil {
-			return fmt.Errorf("%w while preparing data for indexing", err)
-		}
+func (r *reindexer) createIndex(indexWithSuffix string, mapping *bytes.Buffer) error {
+	settings := r.indexSettings
+	if r.disableRefresh {
+		settings.RefreshInterval = disabledRefreshInterval
+	}
 
-		for i := 0; i < len(dataBuffers); i++ {
-			err = r.destinationElastic.DoBulkRequest(dataBuffers[i], index)
-			if err != nil {
-				return fmt.Errorf("%w while r.destinationElastic.DoBulkRequest", err)
-			}
-		}
+	if settings.IsEmpty() {
+		return r.destinationElastic.CreateIndexWithMapping(indexWithSuffix, mapping)
+	}
 
+	return r.destinationElastic.CreateIndexWithSettings(indexWithSuffix, mapping, settings)
+}
+
+// DisableRefresh disables the refresh of the provided destination index, if the refresh should be disabled while bulk
+// loading. The indices that do not exist yet are created with the refresh already disabled
+func (r *reindexer) DisableRefresh(index string) error {
+	if !r.disableRefresh || !r.destinationElastic.DoesAliasExist(index) {
 		return nil
 	}
 
-	err := r.sourceElastic.DoScrollRequestAllDocuments(index, getAll().Bytes(), handlerFunc)
+	err := r.destinationElastic.SetRefreshInterval(index, disabledRefreshInterval)
 	if err != nil {
-		return fmt.Errorf("%w while r.sourceElastic.DoScrollRequestAllDocuments", err)
+		return fmt.Errorf("%w while disabling the refresh of the destination index %s", err, index)
+	}
+
+	log.Debug("refresh disabled", "index", index)
+
+	return nil
+}
+
+// RestoreRefresh restores the refresh interval of the provided destination index, as configured (or the cluster
+// default, if not configured), and refreshes the index. It does nothing if the refresh was not disabled
+func (r *reindexer) RestoreRefresh(index string) error {
+	if !r.disableRefresh {
+		return nil
 	}
 
+	err := r.destinationElastic.SetRefreshInterval(index, r.indexSettings.RefreshInterval)
+	if err != nil {
+		return fmt.Errorf("%w while restoring the refresh interval of the destination index %s", err, index)
+	}
+
+	err = r.destinationElastic.RefreshIndex(index)
+	if err != nil {
+		return fmt.Errorf("%w while refreshing the destination index %s", err, index)
+	}
+
+	log.Debug("refresh interval restored", "index", index, "refresh interval", r.indexSettings.RefreshInterval)
+
 	return nil
 }
 
-func prepareDataForIndexing(responseBytes []byte, index string, count int) ([]*bytes.Buffer, error) {
+func (r *reindexer) restoreRefreshAfterError(index string) {
+	err := r.RestoreRefresh(index)
+	if err != nil {
+		log.Warn("cannot restore the refresh interval, restore it manually", "index", index, "error", err)
+	}
+}
+
+// reindexData reindexes the documents of the provided index. If a sort field is configured for the index, the
+// documents are read in its ascending order, using search_after, so that an interrupted reindexing is resumed after
+// the sort values of the last document saved in the checkpoint. Otherwise, the documents are scrolled unsorted and an
+// interrupted reindexing starts over. It returns the number of processed documents, including the ones processed
+// before resuming
+func (r *reindexer) reindexData(index string, progress *progressReporter) (uint64, error) {
+	handlerFunc := r.createScrollRequestHandlerFunction(progress, index)
+
+	sortField, isSorted := r.sortFields[index]
+	if !isSorted {
+		tracker := newPagesTracker(nil)
+		err := r.doScrollRequestWithWorkers(index, getAll(r.queryFilter).Bytes(), handlerFunc, tracker, r.sourceElastic.DoScrollRequestAllDocuments)
+		if err != nil {
+			return 0, fmt.Errorf("%w while r.sourceElastic.DoScrollRequestAllDocuments", err)
+		}
+
+		return tracker.numHandledDocuments(), nil
+	}
+
+	lastSortValues, numProcessedBefore := r.checkpoints.GetIndexWatermark(index)
+	if len(lastSortValues) > 0 {
+		log.Info("resuming index from checkpoint", "index", index, "sort field", sortField,
+			"watermark", lastSortValues, "processed count", numProcessedBefore)
+	}
+
+	tracker := newPagesTracker(func(mark watermark, numDocuments uint64) {
+		errSave := r.checkpoints.UpdateIndexWatermark(index, mark.lastSortValues, numDocuments)
+		if errSave != nil {
+			log.Warn("cannot save the checkpoint", "index", index, "error", errSave)
+		}
+	})
+	body := getAllSortedBy(sortField, lastSortValues, r.queryFilter).Bytes()
+	err := r.doScrollRequestWithWorkers(index, body, handlerFunc, tracker, r.sourceElastic.DoSearchAfterRequestAllDocuments)
+	if err != nil {
+		return 0, fmt.Errorf("%w while r.sourceElastic.DoSearchAfterRequestAllDocuments", err)
+	}
+
+	return numProcessedBefore + tracker.numHandledDocuments(), nil
+}
+
+func (r *reindexer) prepareDataForIndexing(responseBytes []byte, index string, count int) ([]*bytes.Buffer, error) {
 	var esResponse generalElasticResponse
 	err := json.Unmarshal(responseBytes, &esResponse)
 	if err != nil {
@@ -165,30 +347,54 @@ func prepareDataForIndexing(responseBytes []byte, index string, count int) ([]*b
 	}
 
 	resultsMap := extractSourceFromEsResponse(esResponse)
-	log.Info("\tindexing", "index", index, "bulk size", len(resultsMap), "count", count)
-	buffSlice := newBufferSlice()
+	buffSlice := newBufferSlice(r.bulkMaxDocuments, r.bulkMaxBytes)
+	numSkipped := 0
 	for id, source := range resultsMap {
-		meta := []byte(fmt.Sprintf(`{ "index" : { "_id" : "%s" } }%s`, id, "\n"))
+		transformedSource, errTransform := r.transform(source)
+		if errors.Is(errTransform, ErrSkipDocument) {
+			numSkipped++
+			continue
+		}
+		if errTransform != nil {
+			return nil, fmt.Errorf("%w while transforming document with id %s", errTransform, id)
+		}
 
-		err = buffSlice.PutData(meta, source)
+		meta := []byte(fmt.Sprintf(`{ "%s" : { "_id" : "%s" } }%s`, r.bulkAction(), id, "\n"))
+
+		err = buffSlice.PutData(meta, transformedSource)
 		if err != nil {
 			return nil, err
 		}
 
 	}
+	log.Info("\tindexing", "index", index, "bulk size", len(resultsMap)-numSkipped, "skipped", numSkipped, "count", count)
 
 	return buffSlice.Buffers(), nil
 }
 
-// ProcessIndexWithTimestamp will handle the reindexing from source Elastic client to destination Elastic client based on the provided interval
-func (r *reindexer) ProcessIndexWithTimestamp(index string, overwrite bool, skipMappings bool, start, stop int64, count *uint64) error {
+// ProcessIndexWithTimestamp will handle the reindexing from source Elastic client to destination Elastic client based on the provided interval.
+// If the interval was partially reindexed before, the reindexing starts from the last saved watermark of the interval.
+// The reindexed documents are registered in the provided progress reporter, shared by all the intervals of the index
+func (r *reindexer) ProcessIndexWithTimestamp(index string, overwrite bool, skipMappings bool, start, stop int64, progress *progressReporter) error {
 	err := r.copyMappingIfNecessary(index, overwrite, skipMappings)
 	if err != nil {
 		return fmt.Errorf("%w while copying the mapping for index %s", err, index)
 	}
 
-	scrollRequestHandlerFunc := r.createScrollRequestHandlerFunction(count, index)
-	err = r.sourceElastic.DoScrollRequestAllDocuments(index, getWithTimestamp(start, stop, true, true).Bytes(), scrollRequestHandlerFunc)
+	scrollStart, _ := r.checkpoints.GetIntervalStart(index, start, stop)
+	if scrollStart != start {
+		log.Info("resuming interval from checkpoint", "index", index, "start", start, "stop", stop, "watermark", scrollStart)
+	}
+
+	tracker := newPagesTracker(func(mark watermark, numDocuments uint64) {
+		errSave := r.checkpoints.UpdateIntervalWatermark(index, start, stop, mark, numDocuments)
+		if errSave != nil {
+			log.Warn("cannot save the checkpoint", "index", index, "error", errSave)
+		}
+	})
+
+	scrollRequestHandlerFunc := r.createScrollRequestHandlerFunction(progress, index)
+	err = r.doScrollRequestWithWorkers(index, getWithTimestamp(scrollStart, stop, true, true, r.queryFilter).Bytes(), scrollRequestHandlerFunc, tracker, r.sourceElastic.DoScrollRequestAllDocuments)
 	if err != nil {
 		return fmt.Errorf("%w while r.sourceElastic.DoScrollRequestAllDocuments", err)
 	}
@@ -198,7 +404,7 @@ func (r *reindexer) ProcessIndexWithTimestamp(index string, overwrite bool, skip
 
 // GetCountsForInterval will return the counts from source and destination client based on the provided intervals
 func (r *reindexer) GetCountsForInterval(index string, start, stop int64) (uint64, uint64, error) {
-	body := getWithTimestamp(start, stop, false, false).Bytes()
+	body := getWithTimestamp(start, stop, false, false, r.queryFilter).Bytes()
 
 	countFromSource, err := r.sourceElastic.GetCountWithBody(index, body)
 	if err != nil {
@@ -212,20 +418,133 @@ func (r *reindexer) GetCountsForInterval(index string, start, stop int64) (uint6
 	return countFromSource, countFromDestination, nil
 }
 
-func (r *reindexer) createScrollRequestHandlerFunction(count *uint64, index string) func([]byte) error {
+// CreateProgressReporter captures the number of source documents of the provided index with the timestamp in the
+// provided interval (matching the query filter, if any) and returns a progress reporter for the index
+func (r *reindexer) CreateProgressReporter(index string, start, stop int64) (*progressReporter, error) {
+	totalDocuments, err := r.sourceElastic.GetCountWithBody(index, getWithTimestamp(start, stop, false, false, r.queryFilter).Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("%w while getting the source count for index %s", err, index)
+	}
+
+	return newProgressReporter(index, totalDocuments, r.progressInterval), nil
+}
+
+func (r *reindexer) createScrollRequestHandlerFunction(progress *progressReporter, index string) func([]byte) error {
 	return func(responseBytes []byte) error {
-		atomic.AddUint64(count, 1)
-		dataBuffers, errP := prepareDataForIndexing(responseBytes, index, int(atomic.LoadUint64(count)))
+		currentCount := progress.pageRead()
+		dataBuffers, errP := r.prepareDataForIndexing(responseBytes, index, int(currentCount))
 		if errP != nil {
 			return fmt.Errorf("%w while preparing data for indexing", errP)
 		}
 
 		for i := 0; i < len(dataBuffers); i++ {
 			err := r.destinationElastic.DoBulkRequest(dataBuffers[i], index)
+			var bulkItemsErr *elastic.BulkItemsError
+			if errors.As(err, &bulkItemsErr) {
+				err = r.handleBulkItemsError(index, bulkItemsErr, progress)
+			}
 			if err != nil {
 				return fmt.Errorf("%w while r.destinationElastic.DoBulkRequest", err)
 			}
 		}
+
+		progress.documentsProcessed(uint64(gjson.GetBytes(responseBytes, "hits.hits.#").Int()))
+		return nil
+	}
+}
+
+// bulkAction returns the action of the bulk requests: create, which fails for the documents that already exist in the
+// destination, if the create only mode is set, or index otherwise
+func (r *reindexer) bulkAction() string {
+	if r.createOnly {
+		return bulkActionCreate
+	}
+
+	return bulkActionIndex
+}
+
+// handleBulkItemsError handles the failed items of a bulk request. In the create only mode, the version conflicts
+// are the documents that already exist in the destination, so they are only counted as skipped. The other failures
+// are passed to the failed documents handler
+func (r *reindexer) handleBulkItemsError(index string, bulkErr *elastic.BulkItemsError, progress *progressReporter) error {
+	if !r.createOnly {
+		return r.failedDocuments.AddFailedDocuments(index, bulkErr)
+	}
+
+	failures := make([]elastic.BulkItemFailure, 0, len(bulkErr.Failures))
+	for _, failure := range bulkErr.Failures {
+		if failure.Status != http.StatusConflict {
+			failures = append(failures, failure)
+		}
+	}
+	progress.conflictsSkipped(uint64(len(bulkErr.Failures) - len(failures)))
+	if len(failures) == 0 {
 		return nil
 	}
+
+	return r.failedDocuments.AddFailedDocuments(index, &elastic.BulkItemsError{
+		NumItems: bulkErr.NumItems,
+		Failures: failures,
+	})
+}
+
+// ValidateCount refreshes the destination index and checks that the source and destination counts of the provided
+// index do not differ by more than the provided tolerance
+func (r *reindexer) ValidateCount(index string, tolerance uint64) error {
+	err := r.destinationElastic.RefreshIndex(index)
+	if err != nil {
+		return fmt.Errorf("%w while refreshing the destination index %s", err, index)
+	}
+
+	sourceCount, err := r.getCount(r.sourceElastic, index)
+	if err != nil {
+		return fmt.Errorf("%w while getting the source count for index %s", err, index)
+	}
+	destinationCount, err := r.getCount(r.destinationElastic, index)
+	if err != nil {
+		return fmt.Errorf("%w while getting the destination count for index %s", err, index)
+	}
+
+	return checkCounts(index, sourceCount, destinationCount, tolerance)
+}
+
+// ValidateCountForInterval is the same as ValidateCount, but only the documents with the timestamp in the provided
+// interval are counted
+func (r *reindexer) ValidateCountForInterval(index string, start, stop int64, tolerance uint64) error {
+	err := r.destinationElastic.RefreshIndex(index)
+	if err != nil {
+		return fmt.Errorf("%w while refreshing the destination index %s", err, index)
+	}
+
+	sourceCount, destinationCount, err := r.GetCountsForInterval(index, start, stop)
+	if err != nil {
+		return fmt.Errorf("%w while getting the counts for index %s", err, index)
+	}
+
+	return checkCounts(index, sourceCount, destinationCount, tolerance)
+}
+
+// getCount returns the number of documents of the provided index matching the query filter, if any
+func (r *reindexer) getCount(client ElasticClientHandler, index string) (uint64, error) {
+	if r.queryFilter == nil {
+		return client.GetCount(index)
+	}
+
+	return client.GetCountWithBody(index, getAll(r.queryFilter).Bytes())
+}
+
+func checkCounts(index string, sourceCount uint64, destinationCount uint64, tolerance uint64) error {
+	difference := sourceCount - destinationCount
+	if destinationCount > sourceCount {
+		difference = destinationCount - sourceCount
+	}
+
+	if difference > tolerance {
+		return fmt.Errorf("%w for index %s: source count %d, destination count %d, tolerance %d",
+			errCountMismatch, index, sourceCount, destinationCount, tolerance)
+	}
+
+	log.Info("count validated", "index", index, "source count", sourceCount, "destination count", destinationCount)
+
+	return nil
 }
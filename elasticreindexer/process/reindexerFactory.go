@@ -2,6 +2,7 @@ package process
 
 import (
 	"errors"
+	"fmt"
 
 	"github.com/multiversx/mx-chain-tools-go/elasticreindexer/config"
 	"github.com/multiversx/mx-chain-tools-go/elasticreindexer/elastic"
@@ -15,6 +16,15 @@ func CreateReindexer(cfg *config.GeneralConfig) (*reindexer, error) {
 	if cfg.Indexers.Output.URL == "" {
 		return nil, errors.New("empty url for the output cluster")
 	}
+	if cfg.Indexers.BulkMaxDocuments < 0 || cfg.Indexers.BulkMaxBytes < 0 {
+		return nil, errors.New("negative bulk max documents or bulk max bytes")
+	}
+
+	for index, sortField := range cfg.Indexers.IndicesConfig.SortFields {
+		if len(sortField) == 0 {
+			return nil, fmt.Errorf("empty sort field for index %s", index)
+		}
+	}
 
 	sourceElastic, err := elastic.NewElasticClient(cfg.Indexers.Input)
 	if err != nil {
@@ -26,5 +36,31 @@ func CreateReindexer(cfg *config.GeneralConfig) (*reindexer, error) {
 		return nil, err
 	}
 
-	return newReindexer(sourceElastic, destinationElastic, cfg.Indexers.IndicesConfig.Indices)
+	var destinationHandler ElasticClientHandler = destinationElastic
+	if cfg.Indexers.BulkRate > 0 {
+		destinationHandler = newRateLimitedElasticClient(destinationElastic, cfg.Indexers.BulkRate)
+	}
+
+	r, err := newReindexer(sourceElastic, destinationHandler, cfg.Indexers.IndicesConfig.Indices)
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.Indexers.NumReindexWorkers > 1 {
+		r.numWorkers = cfg.Indexers.NumReindexWorkers
+	}
+	r.indexSettings = cfg.Indexers.IndexSettings
+	r.disableRefresh = cfg.Indexers.DisableRefresh
+	r.bulkMaxDocuments = cfg.Indexers.BulkMaxDocuments
+	r.queryFilter = cfg.Indexers.QueryFilter
+	r.sortFields = cfg.Indexers.IndicesConfig.SortFields
+	r.progressInterval = cfg.Indexers.ProgressInterval
+	r.createOnly = cfg.Indexers.CreateOnly
+	r.preCleanQuery = cfg.Indexers.PreCleanQuery
+	r.isDestinationSameAsSource = isSameCluster(cfg.Indexers.Input.URL, cfg.Indexers.Output.URL)
+	if cfg.Indexers.BulkMaxBytes > 0 {
+		r.bulkMaxBytes = cfg.Indexers.BulkMaxBytes
+	}
+
+	return r, nil
 }
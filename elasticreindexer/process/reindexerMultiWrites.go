@@ -2,10 +2,13 @@ package process
 
 import (
 	"errors"
+	"fmt"
 	"sync"
 	"time"
 
+	"github.com/multiversx/mx-chain-core-go/core/check"
 	"github.com/multiversx/mx-chain-tools-go/elasticreindexer/config"
+	"github.com/multiversx/mx-chain-tools-go/elasticreindexer/exitcodes"
 )
 
 type interval struct {
@@ -19,20 +22,26 @@ type reindexerMultiWrite struct {
 	numParallelWrite     int
 	blockChainStartTime  int64
 	enabled              bool
+	reindexedUntil       int64
 
 	reindexerClient ReindexerHandler
+	checkpoints     CheckpointHandler
 }
 
-func NewReindexerMultiWrite(reindexer ReindexerHandler, cfg config.IndicesConfig) (*reindexerMultiWrite, error) {
+func NewReindexerMultiWrite(reindexer ReindexerHandler, cfg config.IndicesConfig, checkpoints CheckpointHandler) (*reindexerMultiWrite, error) {
 	if reindexer == nil {
 		return nil, errors.New("nil ReindexerHandler")
 	}
+	if check.IfNil(checkpoints) {
+		return nil, errNilCheckpoint
+	}
 	if cfg.WithTimestamp.BlockchainStartTime <= 0 {
 		return nil, errors.New("blockchainStartTime cannot be less than zero")
 	}
 
 	return &reindexerMultiWrite{
 		reindexerClient:      reindexer,
+		checkpoints:          checkpoints,
 		indicesNoTimestamp:   cfg.Indices,
 		indicesWithTimestamp: cfg.WithTimestamp.IndicesWithTimestamp,
 		numParallelWrite:     cfg.WithTimestamp.NumParallelWrites,
@@ -62,6 +71,7 @@ func (rmw *reindexerMultiWrite) ProcessWithTimestamp(overwrite bool, skipMapping
 	}
 
 	currentTimestampUnix := time.Now().Unix()
+	rmw.reindexedUntil = currentTimestampUnix
 	intervals, err := computeIntervals(rmw.blockChainStartTime, currentTimestampUnix, int64(rmw.numParallelWrite))
 	if err != nil {
 		return err
@@ -72,7 +82,12 @@ func (rmw *reindexerMultiWrite) ProcessWithTimestamp(overwrite bool, skipMapping
 			continue
 		}
 
-		err = rmw.reindexBasedOnIntervals(index, intervals, overwrite, skipMappings)
+		indexIntervals, err := rmw.checkpoints.ResolveIntervals(index, intervals)
+		if err != nil {
+			return err
+		}
+
+		err = rmw.reindexBasedOnIntervals(index, indexIntervals, overwrite, skipMappings)
 		if err != nil {
 			return err
 		}
@@ -81,21 +96,122 @@ func (rmw *reindexerMultiWrite) ProcessWithTimestamp(overwrite bool, skipMapping
 	return nil
 }
 
+// ValidateCounts checks, for all the configured indices, that the source and destination counts do not differ by more
+// than the provided tolerance. For the indices with timestamp, only the documents up until the moment the reindexing
+// started are counted, as the source might still receive new documents
+func (rmw *reindexerMultiWrite) ValidateCounts(tolerance uint64) error {
+	numMismatches := 0
+	validateIndex := func(index string, validate func() error) {
+		if index == "" {
+			return
+		}
+
+		err := validate()
+		if err != nil {
+			log.Error("count validation failed", "index", index, "error", err)
+			numMismatches++
+		}
+	}
+
+	for _, index := range rmw.indicesNoTimestamp {
+		validateIndex(index, func() error {
+			return rmw.reindexerClient.ValidateCount(index, tolerance)
+		})
+	}
+
+	if rmw.enabled && rmw.reindexedUntil > 0 {
+		for _, index := range rmw.indicesWithTimestamp {
+			validateIndex(index, func() error {
+				return rmw.reindexerClient.ValidateCountForInterval(index, rmw.blockChainStartTime, rmw.reindexedUntil, tolerance)
+			})
+		}
+	}
+
+	if numMismatches > 0 {
+		return exitcodes.NewIntegrityError(fmt.Errorf("%w for %d indices", errCountMismatch, numMismatches))
+	}
+
+	return nil
+}
+
+// CheckMappings compares, for all the configured indices that already exist in the destination, the source and the
+// destination mappings, before any data is moved. The fields with different types are reported as warnings or, if
+// strict is set, as an error. A mapping that can not be read is also an error in the strict mode
+func (rmw *reindexerMultiWrite) CheckMappings(strict bool) error {
+	indices := append([]string{}, rmw.indicesNoTimestamp...)
+	if rmw.enabled {
+		indices = append(indices, rmw.indicesWithTimestamp...)
+	}
+
+	numIncompatibleIndices := 0
+	for _, index := range indices {
+		if index == "" {
+			continue
+		}
+
+		incompatibilities, err := rmw.reindexerClient.CheckMappingCompatibility(index)
+		if err != nil {
+			if strict {
+				return err
+			}
+
+			log.Warn("cannot check the mapping compatibility", "index", index, "error", err)
+			continue
+		}
+		if len(incompatibilities) == 0 {
+			continue
+		}
+
+		numIncompatibleIndices++
+		for _, incompatibility := range incompatibilities {
+			log.Warn("incompatible mapping", "index", index, "incompatibility", incompatibility)
+		}
+	}
+
+	if numIncompatibleIndices > 0 && strict {
+		return exitcodes.NewIntegrityError(fmt.Errorf("%w for %d indices", errIncompatibleMapping, numIncompatibleIndices))
+	}
+
+	return nil
+}
+
 func (rmw *reindexerMultiWrite) reindexBasedOnIntervals(
 	index string,
 	intervals []*interval,
 	overwrite bool,
 	skipMappings bool,
 ) error {
+	progress, err := rmw.reindexerClient.CreateProgressReporter(index, rmw.blockChainStartTime, rmw.reindexedUntil)
+	if err != nil {
+		return err
+	}
+	defer progress.close()
+
+	err = rmw.reindexerClient.DisableRefresh(index)
+	if err != nil {
+		return err
+	}
+
 	wg := &sync.WaitGroup{}
-	wg.Add(len(intervals))
+	errs := &firstError{}
 
 	log.Info("starting reindexing", "index", index)
 
-	count := uint64(0)
-
 	for idx, interv := range intervals {
+		if errs.get() != nil {
+			log.Warn("an interval failed, the next intervals are not started", "index", index, "interval nr", idx)
+			break
+		}
+
+		_, completed := rmw.checkpoints.GetIntervalStart(index, interv.start, interv.stop)
+		if completed {
+			log.Info("interval was already reindexed, skipping", "interval nr", idx, "index", index)
+			continue
+		}
+
+		wg.Add(1)
 		go func(startTime, stopTime int64, idx int, w *sync.WaitGroup) {
+			numProcessed := uint64(0)
 			defer func() {
 				time.Sleep(time.Second)
 				countSource, countDestination, err := rmw.reindexerClient.GetCountsForInterval(index, startTime, stopTime)
@@ -107,13 +223,21 @@ func (rmw *reindexerMultiWrite) reindexBasedOnIntervals(
 					log.Warn("counts are not equals", "interval nr", idx, "count source", countSource, "count destination", countDestination)
 				}
 
-				log.Info("done", "interval nr", idx, "count source", countSource, "count destination", countDestination)
+				log.Info("done", "interval nr", idx, "count source", countSource, "processed count", numProcessed, "count destination", countDestination)
 				w.Done()
 			}()
 
-			errIndex := rmw.reindexerClient.ProcessIndexWithTimestamp(index, overwrite, skipMappings, startTime, stopTime, &count)
+			errIndex := rmw.reindexerClient.ProcessIndexWithTimestamp(index, overwrite, skipMappings, startTime, stopTime, progress)
 			if errIndex != nil {
-				log.Warn("rmw.processIndexWithTimestamp", "index", index, "error", errIndex.Error())
+				log.Warn("rmw.processIndexWithTimestamp", "index", index, "interval nr", idx, "error", errIndex.Error())
+				errs.set(fmt.Errorf("%w while reindexing the interval %d of index %s", errIndex, idx, index))
+				return
+			}
+
+			var errCheckpoint error
+			numProcessed, errCheckpoint = rmw.checkpoints.MarkIntervalCompleted(index, startTime, stopTime)
+			if errCheckpoint != nil {
+				log.Warn("cannot save the checkpoint", "interval nr", idx, "index", index, "error", errCheckpoint)
 			}
 		}(interv.start, interv.stop, idx, wg)
 
@@ -122,7 +246,38 @@ func (rmw *reindexerMultiWrite) reindexBasedOnIntervals(
 
 	wg.Wait()
 
-	return nil
+	errRestore := rmw.reindexerClient.RestoreRefresh(index)
+	errInterval := errs.get()
+	if errInterval == nil {
+		return errRestore
+	}
+	if errRestore != nil {
+		log.Warn("cannot restore the refresh interval, restore it manually", "index", index, "error", errRestore)
+	}
+
+	return errInterval
+}
+
+// firstError keeps the first error set by concurrent goroutines
+type firstError struct {
+	mut sync.Mutex
+	err error
+}
+
+func (fe *firstError) set(err error) {
+	fe.mut.Lock()
+	defer fe.mut.Unlock()
+
+	if fe.err == nil {
+		fe.err = err
+	}
+}
+
+func (fe *firstError) get() error {
+	fe.mut.Lock()
+	defer fe.mut.Unlock()
+
+	return fe.err
 }
 
 func computeIntervals(startTime, endTime int64, numIntervals int64) ([]*interval, error) {
@@ -1,8 +1,14 @@
 package process
 
 import (
+	"errors"
+	"fmt"
+	"sync/atomic"
 	"testing"
+	"time"
 
+	"github.com/multiversx/mx-chain-tools-go/elasticreindexer/config"
+	"github.com/multiversx/mx-chain-tools-go/elasticreindexer/process/mock"
 	"github.com/stretchr/testify/require"
 )
 
@@ -27,3 +33,43 @@ func TestComputeIntervals(t *testing.T) {
 		},
 	}, res)
 }
+
+func TestReindexerMultiWrite_ProcessWithTimestampShouldFailIfAnIntervalFails(t *testing.T) {
+	t.Parallel()
+
+	numScrolledIntervals := uint32(0)
+	sourceClient := &mock.ElasticClientStub{
+		DoScrollRequestAllDocumentsCalled: func(_ string, _ []byte, _ func(responseBytes []byte) error) error {
+			atomic.AddUint32(&numScrolledIntervals, 1)
+			return fmt.Errorf("%w: 1 failed documents, max failures 0", errTooManyFailures)
+		},
+	}
+	numRefreshes := uint32(0)
+	destinationClient := &mock.ElasticClientStub{
+		DoesAliasExistCalled: func(_ string) bool {
+			return true
+		},
+		RefreshIndexCalled: func(_ string) error {
+			atomic.AddUint32(&numRefreshes, 1)
+			return nil
+		},
+	}
+	r, err := newReindexer(sourceClient, destinationClient, nil)
+	require.Nil(t, err)
+	r.disableRefresh = true
+
+	indicesConfig := config.IndicesConfig{}
+	indicesConfig.WithTimestamp.Enabled = true
+	indicesConfig.WithTimestamp.BlockchainStartTime = time.Now().Unix() - 100
+	indicesConfig.WithTimestamp.NumParallelWrites = 2
+	indicesConfig.WithTimestamp.IndicesWithTimestamp = []string{testIndex}
+	rmw, err := NewReindexerMultiWrite(r, indicesConfig, NewDisabledCheckpointHandler())
+	require.Nil(t, err)
+
+	err = rmw.ProcessWithTimestamp(false, true)
+	require.True(t, errors.Is(err, errTooManyFailures))
+	require.Contains(t, err.Error(), testIndex)
+	// the failed interval stops the next intervals from being started, while the refresh is still restored
+	require.Equal(t, uint32(1), atomic.LoadUint32(&numScrolledIntervals))
+	require.Equal(t, uint32(1), atomic.LoadUint32(&numRefreshes))
+}
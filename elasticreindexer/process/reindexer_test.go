@@ -2,8 +2,11 @@ package process
 
 import (
 	"bytes"
+	"errors"
 	"testing"
 
+	"github.com/multiversx/mx-chain-tools-go/elasticreindexer/config"
+	"github.com/multiversx/mx-chain-tools-go/elasticreindexer/elastic"
 	"github.com/multiversx/mx-chain-tools-go/elasticreindexer/process/mock"
 	"github.com/stretchr/testify/require"
 )
@@ -265,3 +268,331 @@ func testSkipMappings(t *testing.T) {
 	require.NoError(t, err)
 	require.False(t, called)
 }
+
+func TestReindexer_ValidateCount(t *testing.T) {
+	t.Parallel()
+
+	createClients := func(sourceCount uint64, destinationCount uint64, refreshCalled *bool) (*mock.ElasticClientStub, *mock.ElasticClientStub) {
+		sourceClient := &mock.ElasticClientStub{
+			GetCountCalled: func(_ string) (uint64, error) {
+				return sourceCount, nil
+			},
+		}
+		destinationClient := &mock.ElasticClientStub{
+			RefreshIndexCalled: func(_ string) error {
+				*refreshCalled = true
+				return nil
+			},
+			GetCountCalled: func(_ string) (uint64, error) {
+				require.True(t, *refreshCalled)
+				return destinationCount, nil
+			},
+		}
+
+		return sourceClient, destinationClient
+	}
+
+	t.Run("refresh error should error", func(t *testing.T) {
+		t.Parallel()
+
+		expectedErr := errors.New("expected error")
+		destinationClient := &mock.ElasticClientStub{
+			RefreshIndexCalled: func(_ string) error {
+				return expectedErr
+			},
+		}
+		r, _ := newReindexer(&mock.ElasticClientStub{}, destinationClient, []string{testIndex})
+
+		err := r.ValidateCount(testIndex, 0)
+		require.ErrorIs(t, err, expectedErr)
+	})
+	t.Run("difference above tolerance should error", func(t *testing.T) {
+		t.Parallel()
+
+		refreshCalled := false
+		sourceClient, destinationClient := createClients(100, 97, &refreshCalled)
+		r, _ := newReindexer(sourceClient, destinationClient, []string{testIndex})
+
+		err := r.ValidateCount(testIndex, 2)
+		require.ErrorIs(t, err, errCountMismatch)
+		require.Contains(t, err.Error(), "source count 100, destination count 97")
+	})
+	t.Run("difference within tolerance should work", func(t *testing.T) {
+		t.Parallel()
+
+		refreshCalled := false
+		sourceClient, destinationClient := createClients(100, 103, &refreshCalled)
+		r, _ := newReindexer(sourceClient, destinationClient, []string{testIndex})
+
+		require.Nil(t, r.ValidateCount(testIndex, 3))
+		require.True(t, refreshCalled)
+	})
+}
+
+func TestReindexer_CreateIndex(t *testing.T) {
+	t.Parallel()
+
+	t.Run("without settings should create the index with the mapping", func(t *testing.T) {
+		t.Parallel()
+
+		createIndexWithMappingCalled := false
+		destinationClient := &mock.ElasticClientStub{
+			CreateIndexWithMappingCalled: func(_ string, _ *bytes.Buffer) error {
+				createIndexWithMappingCalled = true
+				return nil
+			},
+			CreateIndexWithSettingsCalled: func(_ string, _ *bytes.Buffer, _ config.IndexSettings) error {
+				require.Fail(t, "should have not called CreateIndexWithSettings")
+				return nil
+			},
+		}
+		r, _ := newReindexer(&mock.ElasticClientStub{}, destinationClient, []string{testIndex})
+
+		err := r.createIndex(testIndex+indexSuffix, nil)
+		require.Nil(t, err)
+		require.True(t, createIndexWithMappingCalled)
+	})
+	t.Run("disabled refresh should be applied at creation", func(t *testing.T) {
+		t.Parallel()
+
+		var appliedSettings config.IndexSettings
+		destinationClient := &mock.ElasticClientStub{
+			CreateIndexWithSettingsCalled: func(_ string, _ *bytes.Buffer, settings config.IndexSettings) error {
+				appliedSettings = settings
+				return nil
+			},
+		}
+		r, _ := newReindexer(&mock.ElasticClientStub{}, destinationClient, []string{testIndex})
+		r.indexSettings = config.IndexSettings{NumberOfShards: 2, RefreshInterval: "30s"}
+		r.disableRefresh = true
+
+		err := r.createIndex(testIndex+indexSuffix, nil)
+		require.Nil(t, err)
+		require.Equal(t, config.IndexSettings{NumberOfShards: 2, RefreshInterval: disabledRefreshInterval}, appliedSettings)
+		require.Equal(t, "30s", r.indexSettings.RefreshInterval)
+	})
+}
+
+func TestReindexer_DisableAndRestoreRefresh(t *testing.T) {
+	t.Parallel()
+
+	t.Run("not enabled should do nothing", func(t *testing.T) {
+		t.Parallel()
+
+		destinationClient := &mock.ElasticClientStub{
+			DoesAliasExistCalled: func(_ string) bool {
+				return true
+			},
+			SetRefreshIntervalCalled: func(_ string, _ string) error {
+				require.Fail(t, "should have not called SetRefreshInterval")
+				return nil
+			},
+		}
+		r, _ := newReindexer(&mock.ElasticClientStub{}, destinationClient, []string{testIndex})
+
+		require.Nil(t, r.DisableRefresh(testIndex))
+		require.Nil(t, r.RestoreRefresh(testIndex))
+	})
+	t.Run("missing index should not be updated before creation", func(t *testing.T) {
+		t.Parallel()
+
+		destinationClient := &mock.ElasticClientStub{
+			SetRefreshIntervalCalled: func(_ string, _ string) error {
+				require.Fail(t, "should have not called SetRefreshInterval")
+				return nil
+			},
+		}
+		r, _ := newReindexer(&mock.ElasticClientStub{}, destinationClient, []string{testIndex})
+		r.disableRefresh = true
+
+		require.Nil(t, r.DisableRefresh(testIndex))
+	})
+	t.Run("should disable and restore the configured refresh interval", func(t *testing.T) {
+		t.Parallel()
+
+		refreshIntervals := make([]string, 0)
+		refreshCalled := false
+		destinationClient := &mock.ElasticClientStub{
+			DoesAliasExistCalled: func(_ string) bool {
+				return true
+			},
+			SetRefreshIntervalCalled: func(index string, refreshInterval string) error {
+				require.Equal(t, testIndex, index)
+				refreshIntervals = append(refreshIntervals, refreshInterval)
+				return nil
+			},
+			RefreshIndexCalled: func(_ string) error {
+				refreshCalled = true
+				return nil
+			},
+		}
+		r, _ := newReindexer(&mock.ElasticClientStub{}, destinationClient, []string{testIndex})
+		r.indexSettings = config.IndexSettings{RefreshInterval: "30s"}
+		r.disableRefresh = true
+
+		require.Nil(t, r.DisableRefresh(testIndex))
+		require.Nil(t, r.RestoreRefresh(testIndex))
+		require.Equal(t, []string{disabledRefreshInterval, "30s"}, refreshIntervals)
+		require.True(t, refreshCalled)
+	})
+	t.Run("failed reindexing should restore the refresh interval", func(t *testing.T) {
+		t.Parallel()
+
+		expectedErr := errors.New("expected error")
+		refreshIntervals := make([]string, 0)
+		sourceClient := &mock.ElasticClientStub{
+			DoScrollRequestAllDocumentsCalled: func(_ string, _ []byte, _ func(responseBytes []byte) error) error {
+				return expectedErr
+			},
+		}
+		destinationClient := &mock.ElasticClientStub{
+			DoesAliasExistCalled: func(_ string) bool {
+				return true
+			},
+			SetRefreshIntervalCalled: func(_ string, refreshInterval string) error {
+				refreshIntervals = append(refreshIntervals, refreshInterval)
+				return nil
+			},
+		}
+		r, _ := newReindexer(sourceClient, destinationClient, []string{testIndex})
+		r.disableRefresh = true
+
+		err := r.processIndex(testIndex, true, true)
+		require.ErrorIs(t, err, expectedErr)
+		require.Equal(t, []string{disabledRefreshInterval, ""}, refreshIntervals)
+	})
+}
+
+func TestReindexer_GetCountWithQueryFilter(t *testing.T) {
+	t.Parallel()
+
+	client := &mock.ElasticClientStub{
+		GetCountCalled: func(_ string) (uint64, error) {
+			return 100, nil
+		},
+	}
+	r, _ := newReindexer(client, &mock.ElasticClientStub{}, []string{testIndex})
+
+	count, err := r.getCount(client, testIndex)
+	require.Nil(t, err)
+	require.Equal(t, uint64(100), count)
+
+	r.queryFilter = object{"term": object{"status": "success"}}
+	client.GetCountWithBodyCalled = func(_ string, body []byte) (uint64, error) {
+		require.JSONEq(t, getAll(r.queryFilter).String(), string(body))
+		return 10, nil
+	}
+
+	count, err = r.getCount(client, testIndex)
+	require.Nil(t, err)
+	require.Equal(t, uint64(10), count)
+}
+
+func TestReindexer_CreateOnlyShouldSkipVersionConflicts(t *testing.T) {
+	t.Parallel()
+
+	createBulkErr := func(statuses ...int) *elastic.BulkItemsError {
+		bulkErr := &elastic.BulkItemsError{NumItems: len(statuses)}
+		for _, status := range statuses {
+			bulkErr.Failures = append(bulkErr.Failures, elastic.BulkItemFailure{Status: status})
+		}
+
+		return bulkErr
+	}
+
+	t.Run("only conflicts should not error", func(t *testing.T) {
+		t.Parallel()
+
+		r, _ := newReindexer(&mock.ElasticClientStub{}, &mock.ElasticClientStub{}, []string{testIndex})
+		r.createOnly = true
+		progress := newProgressReporter(testIndex, 0, 0)
+
+		err := r.handleBulkItemsError(testIndex, createBulkErr(409, 409), progress)
+		require.Nil(t, err)
+		require.Equal(t, uint64(2), progress.numConflicts)
+	})
+	t.Run("other failures should be handled as failed documents", func(t *testing.T) {
+		t.Parallel()
+
+		r, _ := newReindexer(&mock.ElasticClientStub{}, &mock.ElasticClientStub{}, []string{testIndex})
+		r.createOnly = true
+		progress := newProgressReporter(testIndex, 0, 0)
+
+		err := r.handleBulkItemsError(testIndex, createBulkErr(409, 400), progress)
+		bulkErr := &elastic.BulkItemsError{}
+		require.True(t, errors.As(err, &bulkErr))
+		require.Equal(t, 2, bulkErr.NumItems)
+		require.Len(t, bulkErr.Failures, 1)
+		require.Equal(t, 400, bulkErr.Failures[0].Status)
+		require.Equal(t, uint64(1), progress.numConflicts)
+	})
+	t.Run("conflicts should fail without create only", func(t *testing.T) {
+		t.Parallel()
+
+		r, _ := newReindexer(&mock.ElasticClientStub{}, &mock.ElasticClientStub{}, []string{testIndex})
+		progress := newProgressReporter(testIndex, 0, 0)
+
+		err := r.handleBulkItemsError(testIndex, createBulkErr(409), progress)
+		require.NotNil(t, err)
+		require.Equal(t, uint64(0), progress.numConflicts)
+	})
+}
+
+func TestReindexer_ReindexDataShouldResumeFromTheCheckpoint(t *testing.T) {
+	t.Parallel()
+
+	args := createTestCheckpointArgs(t)
+	checkpoints, err := NewCheckpointStore(args)
+	require.Nil(t, err)
+	require.Nil(t, checkpoints.UpdateIndexWatermark(testIndex, "[2]", 2))
+
+	var searchBody []byte
+	sourceClient := &mock.ElasticClientStub{
+		DoSearchAfterRequestAllDocumentsCalled: func(index string, body []byte, handlerFunc func(responseBytes []byte) error) error {
+			searchBody = body
+			return handlerFunc(createTestPage(3, 4, 5))
+		},
+		DoScrollRequestAllDocumentsCalled: func(index string, body []byte, handlerFunc func(responseBytes []byte) error) error {
+			require.Fail(t, "the sorted index should not be scrolled")
+			return nil
+		},
+	}
+	r, err := newReindexer(sourceClient, &mock.ElasticClientStub{}, []string{testIndex})
+	require.Nil(t, err)
+	require.Nil(t, r.SetCheckpointHandler(checkpoints))
+	r.sortFields = map[string]string{testIndex: "nonce"}
+
+	numProcessed, err := r.reindexData(testIndex, newProgressReporter(testIndex, 5, 0))
+	require.Nil(t, err)
+	require.Equal(t, uint64(5), numProcessed)
+	require.JSONEq(t, `{"query":{"match_all":{}},"sort":[{"nonce":{"order":"asc"}}],"search_after":[2]}`, string(searchBody))
+
+	lastSortValues, numProcessedInCheckpoint := checkpoints.GetIndexWatermark(testIndex)
+	require.Equal(t, "[5]", lastSortValues)
+	require.Equal(t, uint64(5), numProcessedInCheckpoint)
+}
+
+func TestReindexer_ReindexDataWithoutSortFieldShouldScrollAllTheDocuments(t *testing.T) {
+	t.Parallel()
+
+	args := createTestCheckpointArgs(t)
+	checkpoints, err := NewCheckpointStore(args)
+	require.Nil(t, err)
+	require.Nil(t, checkpoints.UpdateIndexWatermark(testIndex, "[2]", 2))
+
+	var scrollBody []byte
+	sourceClient := &mock.ElasticClientStub{
+		DoScrollRequestAllDocumentsCalled: func(index string, body []byte, handlerFunc func(responseBytes []byte) error) error {
+			scrollBody = body
+			return handlerFunc(createTestPage(1, 2, 3))
+		},
+	}
+	r, err := newReindexer(sourceClient, &mock.ElasticClientStub{}, []string{testIndex})
+	require.Nil(t, err)
+	require.Nil(t, r.SetCheckpointHandler(checkpoints))
+
+	numProcessed, err := r.reindexData(testIndex, newProgressReporter(testIndex, 3, 0))
+	require.Nil(t, err)
+	require.Equal(t, uint64(3), numProcessed)
+	require.JSONEq(t, `{"query":{"match_all":{}}}`, string(scrollBody))
+}
@@ -0,0 +1,91 @@
+package process
+
+import (
+	"sync"
+)
+
+// requestAllDocumentsFunc reads all the documents of the provided index matching the provided body, calling the
+// handler for each page, in order
+type requestAllDocumentsFunc func(index string, body []byte, handlerFunc func(responseBytes []byte) error) error
+
+type scrollPage struct {
+	pageIndex     uint64
+	responseBytes []byte
+}
+
+// doScrollRequestWithWorkers will read all the documents of the provided index in the current goroutine, using the
+// provided request function (a scroll or a search_after pagination), while the pages are handled by numWorkers
+// goroutines. The pages channel is bounded, so the reading is paused whenever all the workers are busy and the channel
+// is full. The first error found stops both the reading and the workers. Each page is registered in the provided
+// tracker when read and acknowledged once it was successfully handled
+func (r *reindexer) doScrollRequestWithWorkers(
+	index string,
+	body []byte,
+	handlerFunc func(responseBytes []byte) error,
+	tracker *pagesTracker,
+	requestAllDocuments requestAllDocumentsFunc,
+) error {
+	if r.numWorkers <= 1 {
+		return requestAllDocuments(index, body, func(responseBytes []byte) error {
+			pageIndex := tracker.pageRead(responseBytes)
+			err := handlerFunc(responseBytes)
+			if err != nil {
+				return err
+			}
+
+			tracker.pageHandled(pageIndex)
+			return nil
+		})
+	}
+
+	pages := make(chan *scrollPage, r.numWorkers)
+	done := make(chan struct{})
+	var errOnce sync.Once
+	var workerErr error
+	setWorkerErr := func(err error) {
+		errOnce.Do(func() {
+			workerErr = err
+			close(done)
+		})
+	}
+
+	wg := &sync.WaitGroup{}
+	wg.Add(r.numWorkers)
+	for i := 0; i < r.numWorkers; i++ {
+		go func() {
+			defer wg.Done()
+
+			for page := range pages {
+				err := handlerFunc(page.responseBytes)
+				if err != nil {
+					setWorkerErr(err)
+					return
+				}
+
+				tracker.pageHandled(page.pageIndex)
+			}
+		}()
+	}
+
+	scrollErr := requestAllDocuments(index, body, func(responseBytes []byte) error {
+		page := &scrollPage{
+			pageIndex:     tracker.pageRead(responseBytes),
+			responseBytes: responseBytes,
+		}
+
+		select {
+		case pages <- page:
+			return nil
+		case <-done:
+			return errScrollStopped
+		}
+	})
+	close(pages)
+	wg.Wait()
+
+	if workerErr != nil {
+		return workerErr
+	}
+
+	return scrollErr
+}
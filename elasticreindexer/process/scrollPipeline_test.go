@@ -0,0 +1,109 @@
+package process
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/multiversx/mx-chain-tools-go/elasticreindexer/process/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func createReindexerWithScrollPages(t *testing.T, numWorkers int, numPages int, numSentPages *uint32) *reindexer {
+	sourceClient := &mock.ElasticClientStub{
+		DoScrollRequestAllDocumentsCalled: func(index string, body []byte, handlerFunc func(responseBytes []byte) error) error {
+			for i := 0; i < numPages; i++ {
+				err := handlerFunc([]byte("page"))
+				if err != nil {
+					return err
+				}
+
+				atomic.AddUint32(numSentPages, 1)
+			}
+
+			return nil
+		},
+	}
+
+	r, err := newReindexer(sourceClient, &mock.ElasticClientStub{}, []string{testIndex})
+	require.Nil(t, err)
+	r.numWorkers = numWorkers
+
+	return r
+}
+
+func TestReindexer_DoScrollRequestWithWorkers(t *testing.T) {
+	t.Parallel()
+
+	t.Run("all pages should be handled", func(t *testing.T) {
+		t.Parallel()
+
+		for _, numWorkers := range []int{1, 4} {
+			numSentPages := uint32(0)
+			numHandledPages := uint32(0)
+			r := createReindexerWithScrollPages(t, numWorkers, 50, &numSentPages)
+
+			err := r.doScrollRequestWithWorkers(testIndex, nil, func(responseBytes []byte) error {
+				atomic.AddUint32(&numHandledPages, 1)
+				return nil
+			}, newPagesTracker(nil), r.sourceElastic.DoScrollRequestAllDocuments)
+			require.Nil(t, err)
+			require.Equal(t, uint32(50), atomic.LoadUint32(&numHandledPages))
+		}
+	})
+	t.Run("slow workers should pause the scroll", func(t *testing.T) {
+		t.Parallel()
+
+		numWorkers := 2
+		numSentPages := uint32(0)
+		unblock := make(chan struct{})
+		r := createReindexerWithScrollPages(t, numWorkers, 50, &numSentPages)
+
+		errChan := make(chan error, 1)
+		go func() {
+			errChan <- r.doScrollRequestWithWorkers(testIndex, nil, func(responseBytes []byte) error {
+				<-unblock
+				return nil
+			}, newPagesTracker(nil), r.sourceElastic.DoScrollRequestAllDocuments)
+		}()
+
+		time.Sleep(100 * time.Millisecond)
+		// each worker holds one page and the channel buffers another numWorkers pages
+		require.Equal(t, uint32(2*numWorkers), atomic.LoadUint32(&numSentPages))
+
+		close(unblock)
+		require.Nil(t, <-errChan)
+		require.Equal(t, uint32(50), atomic.LoadUint32(&numSentPages))
+	})
+	t.Run("worker error should stop the scroll", func(t *testing.T) {
+		t.Parallel()
+
+		expectedErr := errors.New("expected error")
+		numSentPages := uint32(0)
+		r := createReindexerWithScrollPages(t, 3, 10000, &numSentPages)
+
+		err := r.doScrollRequestWithWorkers(testIndex, nil, func(responseBytes []byte) error {
+			return expectedErr
+		}, newPagesTracker(nil), r.sourceElastic.DoScrollRequestAllDocuments)
+		require.Equal(t, expectedErr, err)
+		require.Less(t, atomic.LoadUint32(&numSentPages), uint32(10000))
+	})
+	t.Run("scroll error should be returned", func(t *testing.T) {
+		t.Parallel()
+
+		expectedErr := errors.New("expected error")
+		sourceClient := &mock.ElasticClientStub{
+			DoScrollRequestAllDocumentsCalled: func(index string, body []byte, handlerFunc func(responseBytes []byte) error) error {
+				return expectedErr
+			},
+		}
+		r, _ := newReindexer(sourceClient, &mock.ElasticClientStub{}, []string{testIndex})
+		r.numWorkers = 3
+
+		err := r.doScrollRequestWithWorkers(testIndex, nil, func(responseBytes []byte) error {
+			return nil
+		}, newPagesTracker(nil), r.sourceElastic.DoScrollRequestAllDocuments)
+		require.Equal(t, expectedErr, err)
+	})
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"fmt"
 	"io"
 	"io/ioutil"
 	"os"
@@ -10,7 +11,10 @@ import (
 
 	logger "github.com/multiversx/mx-chain-logger-go"
 	"github.com/multiversx/mx-chain-tools-go/tgbot/config"
+	"github.com/multiversx/mx-chain-tools-go/tgbot/exitcodes"
+	"github.com/multiversx/mx-chain-tools-go/tgbot/logging"
 	"github.com/multiversx/mx-chain-tools-go/tgbot/process"
+	"github.com/multiversx/mx-chain-tools-go/tgbot/version"
 	"github.com/pelletier/go-toml"
 	"github.com/urfave/cli"
 )
@@ -41,8 +45,11 @@ func main() {
 	app := cli.NewApp()
 	cli.AppHelpTemplate = helpTemplate
 	app.Name = "Bot balance notifier"
-	app.Version = "v1.0.0"
+	app.Version = version.GetVersion()
 	app.Usage = "This is the entry point for balance notifier tool"
+	app.Flags = []cli.Flag{
+		logging.LogJSON,
+	}
 	app.Authors = []cli.Author{
 		{
 			Name:  "The Multiversx Team",
@@ -51,19 +58,26 @@ func main() {
 	}
 
 	app.Action = startTelegramBot
+	app.OnUsageError = exitcodes.OnUsageError
 
 	err := app.Run(os.Args)
 	if err != nil {
 		log.Error(err.Error())
-		os.Exit(1)
+		os.Exit(exitcodes.ExitCode(err))
 	}
 }
 
-func startTelegramBot(_ *cli.Context) error {
+func startTelegramBot(ctx *cli.Context) error {
+	if ctx.Bool(logging.LogJSON.Name) {
+		err := logging.SetConsoleJSONFormatter()
+		if err != nil {
+			return err
+		}
+	}
+
 	cfg, err := loadConfig()
 	if err != nil {
-		log.Error("cannot load configuration", "error", err)
-		return nil
+		return exitcodes.NewUsageError(fmt.Errorf("%w while loading the configuration", err))
 	}
 
 	interrupt := make(chan os.Signal, 1)
@@ -73,8 +87,8 @@ func startTelegramBot(_ *cli.Context) error {
 	for _, botCfg := range cfg.BotConfigs {
 		notifier, errC := process.NewBalanceNotifier(botCfg)
 		if errC != nil {
-			log.Error("cannot start balance notifier", "error", errC)
-			return nil
+			closeNotifiers(notifiers)
+			return exitcodes.NewUsageError(fmt.Errorf("%w while starting the balance notifier", errC))
 		}
 		notifiers = append(notifiers, notifier)
 
@@ -83,14 +97,18 @@ func startTelegramBot(_ *cli.Context) error {
 
 	<-interrupt
 	log.Info("closing app at user's signal")
-	for _, notifier := range notifiers {
-		_ = notifier.Close()
-	}
+	closeNotifiers(notifiers)
 
 	time.Sleep(1 * time.Millisecond)
 	return nil
 }
 
+func closeNotifiers(notifiers []io.Closer) {
+	for _, notifier := range notifiers {
+		_ = notifier.Close()
+	}
+}
+
 func loadConfig() (*config.GeneralConfig, error) {
 	tomlBytes, err := loadBytesFromFile(tomlFile)
 	if err != nil {
@@ -11,7 +11,11 @@ require (
 	github.com/urfave/cli v1.22.10
 )
 
-require github.com/multiversx/mx-chain-crypto-go v1.2.5
+require (
+	github.com/multiversx/mx-chain-core-go v1.1.30
+	github.com/multiversx/mx-chain-crypto-go v1.2.5
+	github.com/multiversx/mx-chain-go v1.4.4
+)
 
 require (
 	github.com/btcsuite/btcd/btcutil v1.1.3 // indirect
@@ -36,8 +40,6 @@ require (
 	github.com/modern-go/reflect2 v1.0.2 // indirect
 	github.com/mr-tron/base58 v1.2.0 // indirect
 	github.com/multiversx/concurrent-map v0.1.4 // indirect
-	github.com/multiversx/mx-chain-core-go v1.1.30 // indirect
-	github.com/multiversx/mx-chain-go v1.4.4 // indirect
 	github.com/multiversx/mx-chain-p2p-go v1.0.10 // indirect
 	github.com/multiversx/mx-chain-storage-go v1.0.7 // indirect
 	github.com/multiversx/mx-chain-vm-common-go v1.3.36 // indirect
@@ -0,0 +1,130 @@
+package intervals
+
+import "sort"
+
+// Interval holds a range of consecutive nonces of a token, both ends included
+type Interval struct {
+	Start uint64
+	End   uint64
+}
+
+// NumNonces returns the number of nonces in the interval
+func (i *Interval) NumNonces() uint64 {
+	return i.End - i.Start + 1
+}
+
+// Split splits the interval in two intervals: the first one holds the first numNonces nonces and the second one
+// the remaining nonces. numNonces should be in the range [1, NumNonces()-1]
+func (i *Interval) Split(numNonces uint64) (*Interval, *Interval) {
+	first := &Interval{
+		Start: i.Start,
+		End:   i.Start + numNonces - 1,
+	}
+
+	second := &Interval{
+		Start: first.End + 1,
+		End:   i.End,
+	}
+
+	return first, second
+}
+
+// GroupTokensByIntervals groups the nonces of each token in sorted intervals of consecutive nonces. The nonces do not
+// need to be sorted and can contain duplicates. The tokens without nonces (e.g. left empty after filtering the input)
+// have no intervals, so they are not part of the returned map
+func GroupTokensByIntervals(tokens map[string][]uint64) map[string][]*Interval {
+	ret := make(map[string][]*Interval)
+	for token, nonces := range tokens {
+		if len(nonces) == 0 {
+			continue
+		}
+
+		ret[token] = GetIntervals(SortAndRemoveDuplicateNonces(nonces))
+	}
+
+	return ret
+}
+
+// SortAndRemoveDuplicateNonces returns a sorted copy of the provided nonces, without duplicates. The provided slice is not modified
+func SortAndRemoveDuplicateNonces(nonces []uint64) []uint64 {
+	sortedNonces := make([]uint64, len(nonces))
+	copy(sortedNonces, nonces)
+	sort.Slice(sortedNonces, func(i, j int) bool {
+		return sortedNonces[i] < sortedNonces[j]
+	})
+
+	uniqueNonces := sortedNonces[:0]
+	for idx, nonce := range sortedNonces {
+		if idx > 0 && nonce == sortedNonces[idx-1] {
+			continue
+		}
+
+		uniqueNonces = append(uniqueNonces, nonce)
+	}
+
+	return uniqueNonces
+}
+
+// GetIntervals groups the provided sorted nonces in intervals of consecutive nonces. No nonces result in no intervals
+func GetIntervals(nonces []uint64) []*Interval {
+	numNonces := len(nonces)
+	intervals := make([]*Interval, 0)
+	for idx := 0; idx < numNonces; idx++ {
+		nonce := nonces[idx]
+		if idx+1 >= numNonces {
+			intervals = append(intervals, &Interval{
+				Start: nonce,
+				End:   nonce,
+			})
+			break
+		}
+
+		currInterval := &Interval{Start: nonce}
+		for idx < numNonces-1 {
+			currNonce := nonces[idx]
+			nextNonce := nonces[idx+1]
+			if nextNonce-currNonce > 1 {
+				break
+			}
+
+			// duplicate consecutive nonces (nextNonce == currNonce) are collapsed in the same interval
+			idx++
+		}
+
+		currInterval.End = nonces[idx]
+		intervals = append(intervals, currInterval)
+	}
+
+	return intervals
+}
+
+// MergeCloseIntervals coalesces, for each token, the consecutive intervals separated by at most mergeGap missing nonces,
+// e.g. [1, 3] and [5, 6] are merged in [1, 6] for a gap of 1. The missing nonces are then also deleted, which can lead
+// to fewer and cheaper transactions. A zero mergeGap returns the intervals as they are. The provided intervals must
+// be sorted and must not overlap, as returned by GroupTokensByIntervals
+func MergeCloseIntervals(tokens map[string][]*Interval, mergeGap uint64) map[string][]*Interval {
+	if mergeGap == 0 {
+		return tokens
+	}
+
+	ret := make(map[string][]*Interval, len(tokens))
+	for token, intervals := range tokens {
+		mergedIntervals := make([]*Interval, 0, len(intervals))
+		for _, currInterval := range intervals {
+			numMerged := len(mergedIntervals)
+			if numMerged > 0 && currInterval.Start-mergedIntervals[numMerged-1].End-1 <= mergeGap {
+				mergedIntervals[numMerged-1].End = currInterval.End
+				continue
+			}
+
+			mergedIntervals = append(mergedIntervals, &Interval{
+				Start: currInterval.Start,
+				End:   currInterval.End,
+			})
+		}
+
+		ret[token] = mergedIntervals
+	}
+
+	return ret
+}
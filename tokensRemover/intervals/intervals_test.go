@@ -0,0 +1,180 @@
+package intervals
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGroupTokensByIntervals(t *testing.T) {
+	tokens := map[string][]uint64{
+		"token1": {1, 2, 3, 8, 9, 10},
+		"token2": {1},
+		"token3": {3, 9},
+		"token4": {11, 12},
+		"token5": {10, 100, 101, 102, 111},
+		"token6": {4, 5, 6, 7},
+	}
+
+	sortedTokens := GroupTokensByIntervals(tokens)
+	require.Equal(t, sortedTokens,
+		map[string][]*Interval{
+			"token1": {
+				{
+					Start: 1,
+					End:   3,
+				},
+				{
+					Start: 8,
+					End:   10,
+				},
+			},
+			"token2": {
+				{
+					Start: 1,
+					End:   1,
+				},
+			},
+			"token3": {
+				{
+					Start: 3,
+					End:   3,
+				},
+				{
+					Start: 9,
+					End:   9,
+				},
+			},
+			"token4": {
+				{
+					Start: 11,
+					End:   12,
+				},
+			},
+			"token5": {
+				{
+					Start: 10,
+					End:   10,
+				},
+				{
+					Start: 100,
+					End:   102,
+				},
+				{
+					Start: 111,
+					End:   111,
+				},
+			},
+			"token6": {
+				{
+					Start: 4,
+					End:   7,
+				},
+			},
+		},
+	)
+}
+
+func TestGroupTokensByIntervals_UnsortedNoncesWithDuplicates(t *testing.T) {
+	t.Parallel()
+
+	sortedTokens := map[string][]uint64{
+		"token1": {1, 2, 3, 8, 9, 10},
+		"token2": {10, 100, 101, 102, 111},
+	}
+	shuffledTokens := map[string][]uint64{
+		"token1": {9, 3, 1, 10, 3, 2, 8, 9, 1},
+		"token2": {102, 111, 10, 101, 100, 111, 10},
+	}
+
+	require.Equal(t, GroupTokensByIntervals(sortedTokens), GroupTokensByIntervals(shuffledTokens))
+	// input should not be modified
+	require.Equal(t, []uint64{102, 111, 10, 101, 100, 111, 10}, shuffledTokens["token2"])
+}
+
+func TestGetIntervals_DuplicateConsecutiveNonces(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, []*Interval{{Start: 10, End: 11}}, GetIntervals([]uint64{10, 10, 11}))
+	require.Equal(t, []*Interval{{Start: 10, End: 10}}, GetIntervals([]uint64{10, 10}))
+	require.Equal(t, []*Interval{{Start: 1, End: 2}, {Start: 5, End: 5}, {Start: 7, End: 8}},
+		GetIntervals([]uint64{1, 1, 2, 2, 5, 5, 5, 7, 8, 8}))
+}
+
+func TestGroupTokensByIntervals_EmptyNonces(t *testing.T) {
+	t.Parallel()
+
+	tokens := map[string][]uint64{
+		"token1": {5},
+		"token2": {},
+		"token3": nil,
+	}
+
+	require.Equal(t, map[string][]*Interval{
+		"token1": {{Start: 5, End: 5}},
+	}, GroupTokensByIntervals(tokens))
+	require.Empty(t, GroupTokensByIntervals(map[string][]uint64{"token1": nil}))
+	require.Empty(t, GroupTokensByIntervals(nil))
+}
+
+func TestGetIntervals_EmptyAndSingleNonce(t *testing.T) {
+	t.Parallel()
+
+	require.Empty(t, GetIntervals(nil))
+	require.Empty(t, GetIntervals([]uint64{}))
+	require.Equal(t, []*Interval{{Start: 0, End: 0}}, GetIntervals([]uint64{0}))
+	require.Equal(t, []*Interval{{Start: 7, End: 7}}, GetIntervals([]uint64{7}))
+	require.Empty(t, SortAndRemoveDuplicateNonces(nil))
+}
+
+func TestMergeCloseIntervals(t *testing.T) {
+	t.Parallel()
+
+	createIntervals := func() map[string][]*Interval {
+		return GroupTokensByIntervals(map[string][]uint64{
+			"token1": {1, 2, 3, 5, 6, 9, 20},
+			"token2": {7},
+		})
+	}
+
+	t.Run("gap 0 should keep the exact intervals", func(t *testing.T) {
+		t.Parallel()
+
+		require.Equal(t, createIntervals(), MergeCloseIntervals(createIntervals(), 0))
+	})
+	t.Run("gap 1 should merge the intervals separated by one nonce", func(t *testing.T) {
+		t.Parallel()
+
+		require.Equal(t, map[string][]*Interval{
+			"token1": {{Start: 1, End: 6}, {Start: 9, End: 9}, {Start: 20, End: 20}},
+			"token2": {{Start: 7, End: 7}},
+		}, MergeCloseIntervals(createIntervals(), 1))
+	})
+	t.Run("larger gap should merge in chain", func(t *testing.T) {
+		t.Parallel()
+
+		require.Equal(t, map[string][]*Interval{
+			"token1": {{Start: 1, End: 9}, {Start: 20, End: 20}},
+			"token2": {{Start: 7, End: 7}},
+		}, MergeCloseIntervals(createIntervals(), 9))
+	})
+	t.Run("input should not be modified", func(t *testing.T) {
+		t.Parallel()
+
+		intervals := createIntervals()
+		_ = MergeCloseIntervals(intervals, 100)
+		require.Equal(t, createIntervals(), intervals)
+	})
+}
+
+func TestInterval_Split(t *testing.T) {
+	t.Parallel()
+
+	currInterval := &Interval{Start: 10, End: 14}
+	require.Equal(t, uint64(5), currInterval.NumNonces())
+
+	first, second := currInterval.Split(2)
+	require.Equal(t, &Interval{Start: 10, End: 11}, first)
+	require.Equal(t, &Interval{Start: 12, End: 14}, second)
+	require.Equal(t, currInterval.NumNonces(), first.NumNonces()+second.NumNonces())
+}
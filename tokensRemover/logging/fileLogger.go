@@ -0,0 +1,50 @@
+package logging
+
+import (
+	"fmt"
+
+	nodeFactory "github.com/multiversx/mx-chain-go/cmd/node/factory"
+	logger "github.com/multiversx/mx-chain-logger-go"
+	"github.com/multiversx/mx-chain-tools-go/trieTools/trieToolsCommon"
+	"github.com/urfave/cli"
+)
+
+const defaultLogsPath = "logs"
+
+// LogJSON is used when the log lines need to be written as JSON objects, one per line
+var LogJSON = cli.BoolFlag{
+	Name:  "log-json",
+	Usage: "Boolean option for writing the logs as JSON lines, both in the console and in the log file (if log-save is set).",
+}
+
+// AttachFileLogger will attach the file logger, using provided flags. If logJSON is set, both the console and the
+// file logs are written as JSON lines
+func AttachFileLogger(log logger.Logger, logFilePrefix string, flagsConfig trieToolsCommon.ContextFlagsConfig, logJSON bool) (nodeFactory.FileLoggingHandler, error) {
+	if !logJSON {
+		return trieToolsCommon.AttachFileLogger(log, logFilePrefix, flagsConfig)
+	}
+
+	saveLogFile := flagsConfig.SaveLogFile
+	flagsConfig.SaveLogFile = false
+	flagsConfig.DisableAnsiColor = false
+	_, err := trieToolsCommon.AttachFileLogger(log, logFilePrefix, flagsConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	err = SetConsoleJSONFormatter()
+	if err != nil {
+		return nil, err
+	}
+
+	if !saveLogFile {
+		return nil, nil
+	}
+
+	fileLogging, err := NewJSONFileLogging(flagsConfig.WorkingDir, defaultLogsPath, logFilePrefix)
+	if err != nil {
+		return nil, fmt.Errorf("%w creating a log file", err)
+	}
+
+	return fileLogging, nil
+}
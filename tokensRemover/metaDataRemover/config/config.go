@@ -5,9 +5,22 @@ import "github.com/multiversx/mx-chain-tools-go/trieTools/trieToolsCommon"
 // ContextFlagsMetaDataRemover is the flags config for meta data remover
 type ContextFlagsMetaDataRemover struct {
 	trieToolsCommon.ContextFlagsConfig
-	Outfile string
-	Tokens  string
-	Pems    string
+	LogJSON      bool
+	ConfigFile   string
+	Outfile      string
+	Tokens       string
+	Pems         string
+	StartNonce   string
+	ProxyURL     string
+	SendRate     uint64
+	RetryFile    string
+	DryRun       bool
+	MergeGap     uint64
+	IntervalsOut string
+	SummaryOut   string
+	OutputPerms  string
+	NoOverwrite  bool
+	Force        bool
 }
 
 // Config holds the config for meta data remover tool
@@ -15,4 +28,9 @@ type Config struct {
 	ProxyUrl                     string `toml:"ProxyUrl"`
 	TokensToDeletePerTransaction uint64 `toml:"TokensToDeletePerTransaction"`
 	AdditionalGasLimit           uint64 `toml:"AdditionalGasLimit"`
+	MaxTransactionsPerShard      uint64 `toml:"MaxTransactionsPerShard"`
+	MaxTransactionsPerShardMode  string `toml:"MaxTransactionsPerShardMode"`
+	MinGasLimit                  uint64 `toml:"MinGasLimit"`
+	GasPerDataByte               uint64 `toml:"GasPerDataByte"`
+	MaxGasLimitPerTransaction    uint64 `toml:"MaxGasLimitPerTransaction"`
 }
@@ -9,3 +9,29 @@ var errCouldNotConvertNonceToBigInt = errors.New("could not convert nonce to big
 var errNilPemProvider = errors.New("received nil pem provider")
 
 var errNilFileHandler = errors.New("received nil file handler")
+
+var errInvalidTokensToDeletePerTransaction = errors.New("invalid TokensToDeletePerTransaction")
+
+var errInvalidStartNonce = errors.New("invalid start nonce")
+
+var errNilProxy = errors.New("received nil proxy")
+
+var errInvalidSendRate = errors.New("invalid send rate, expected at least one tx per second")
+
+var errInvalidMaxTransactionsPerShardMode = errors.New("invalid MaxTransactionsPerShardMode")
+
+var errTooManyTransactionsInShard = errors.New("too many transactions in shard")
+
+var errStartNonceWithMultipleSenders = errors.New("start nonce can not be used for a shard with multiple senders")
+
+var errInvalidOutputFilePerms = errors.New("invalid output file permissions")
+
+var errOutputAlreadyExists = errors.New("output already exists")
+
+var errOutputParentDirNotFound = errors.New("output parent directory not found")
+
+var errInvalidOutputPath = errors.New("invalid output path")
+
+var errGasLimitTooHigh = errors.New("gas limit too high")
+
+var errConfigFileNotFound = errors.New("config file not found")
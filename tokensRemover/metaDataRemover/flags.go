@@ -1,6 +1,7 @@
 package main
 
 import (
+	"github.com/multiversx/mx-chain-tools-go/tokensRemover/logging"
 	"github.com/multiversx/mx-chain-tools-go/tokensRemover/metaDataRemover/config"
 	"github.com/multiversx/mx-chain-tools-go/trieTools/trieToolsCommon"
 	"github.com/urfave/cli"
@@ -19,9 +20,66 @@ var (
 	}
 	pems = cli.StringFlag{
 		Name:  "pem",
-		Usage: "This flag specifies pems directory, which should contain multiple pems to be used to sign txs. It expects each pem/shardID to be named shard[ID].pem",
+		Usage: "This flag specifies pems directory, which should contain multiple pems to be used to sign txs. It expects each pem/shardID to be named shard[ID].pem. Multiple senders can be used for a shard by naming the pems shard[ID]_[index].pem (e.g. shard0_1.pem, shard0_2.pem); the txs of the shard are then distributed round-robin across the senders, each one with its own nonce",
 		Value: "pems",
 	}
+	startNonce = cli.StringFlag{
+		Name:  "start-nonce",
+		Usage: "This flag overrides the senders nonces, which are otherwise fetched from the proxy. It expects a list of shardID:nonce pairs, e.g. 0:15,1:4. The shards which are not listed will use the nonce fetched from the proxy. It can not be used for shards with multiple senders",
+		Value: "",
+	}
+	proxyURL = cli.StringFlag{
+		Name:  "proxy-url",
+		Usage: "If set, the signed txs are broadcast through this proxy instead of being written in the outfile. It also overrides the ProxyUrl from the config",
+		Value: "",
+	}
+	sendRate = cli.Uint64Flag{
+		Name:  "send-rate",
+		Usage: "This flag specifies the maximum number of txs per second that are broadcast when the proxy-url flag is set",
+		Value: 10,
+	}
+	retryFile = cli.StringFlag{
+		Name:  "retry-file",
+		Usage: "This flag specifies the file where the txs that could not be broadcast are written. The file can be provided as input to the txsSender tool",
+		Value: "retry-txs.json",
+	}
+	mergeGap = cli.Uint64Flag{
+		Name:  "merge-gap",
+		Usage: "This flag specifies the max number of missing nonces between two intervals of the same token for which the intervals are merged into one, also deleting the missing nonces. The default 0 only deletes the provided nonces",
+		Value: 0,
+	}
+	intervalsOut = cli.StringFlag{
+		Name:  "intervals-out",
+		Usage: "If set, the nonces intervals to be deleted (after sorting, removing duplicates and merging) are written in this json file, as a map<shardID, map<tokenID, intervals>>, before the txs are created",
+		Value: "",
+	}
+	configFile = cli.StringFlag{
+		Name:  "config",
+		Usage: "This flag specifies the path of the toml config file",
+		Value: "./config.toml",
+	}
+	summaryOut = cli.StringFlag{
+		Name:  "summary-out",
+		Usage: "If set, a summary of the run (per shard: the num of txs, tokens and nonces to be deleted and the senders used) is written in this json file, after the txs are created. The summary is logged in any case",
+		Value: "",
+	}
+	outputPerms = cli.StringFlag{
+		Name:  "output-perms",
+		Usage: "This flag specifies the octal permissions of the written files (the signed txs, the retry file and the intervals file). Since the signed txs can be broadcast by anyone, the default only allows the owner to read them",
+		Value: "0600",
+	}
+	noOverwrite = cli.BoolFlag{
+		Name:  "no-overwrite",
+		Usage: "If set, the tool refuses to start if an output file already exists (for the outfile directory, any txsShard*.json file), instead of overwriting it",
+	}
+	force = cli.BoolFlag{
+		Name:  "force",
+		Usage: "If set, the existing output files are overwritten, even if the no-overwrite flag is set",
+	}
+	dryRun = cli.BoolFlag{
+		Name:  "dry-run",
+		Usage: "If set, the txs are created and summarized in the logs (num of txs per shard, total tokens affected and a sample of the txs data), but the outfile is not written",
+	}
 )
 
 func getFlags() []cli.Flag {
@@ -29,11 +87,24 @@ func getFlags() []cli.Flag {
 		trieToolsCommon.LogLevel,
 		trieToolsCommon.DisableAnsiColor,
 		trieToolsCommon.LogSaveFile,
+		logging.LogJSON,
 		trieToolsCommon.LogWithLoggerName,
 		trieToolsCommon.ProfileMode,
+		configFile,
 		outfile,
 		tokens,
 		pems,
+		startNonce,
+		proxyURL,
+		sendRate,
+		retryFile,
+		mergeGap,
+		intervalsOut,
+		summaryOut,
+		dryRun,
+		outputPerms,
+		noOverwrite,
+		force,
 	}
 }
 
@@ -42,11 +113,24 @@ func getFlagsConfig(ctx *cli.Context) config.ContextFlagsMetaDataRemover {
 
 	flagsConfig.LogLevel = ctx.GlobalString(trieToolsCommon.LogLevel.Name)
 	flagsConfig.SaveLogFile = ctx.GlobalBool(trieToolsCommon.LogSaveFile.Name)
+	flagsConfig.LogJSON = ctx.GlobalBool(logging.LogJSON.Name)
 	flagsConfig.EnableLogName = ctx.GlobalBool(trieToolsCommon.LogWithLoggerName.Name)
 	flagsConfig.EnablePprof = ctx.GlobalBool(trieToolsCommon.ProfileMode.Name)
+	flagsConfig.ConfigFile = ctx.GlobalString(configFile.Name)
 	flagsConfig.Outfile = ctx.GlobalString(outfile.Name)
 	flagsConfig.Tokens = ctx.GlobalString(tokens.Name)
 	flagsConfig.Pems = ctx.GlobalString(pems.Name)
+	flagsConfig.StartNonce = ctx.GlobalString(startNonce.Name)
+	flagsConfig.ProxyURL = ctx.GlobalString(proxyURL.Name)
+	flagsConfig.SendRate = ctx.GlobalUint64(sendRate.Name)
+	flagsConfig.RetryFile = ctx.GlobalString(retryFile.Name)
+	flagsConfig.DryRun = ctx.GlobalBool(dryRun.Name)
+	flagsConfig.MergeGap = ctx.GlobalUint64(mergeGap.Name)
+	flagsConfig.IntervalsOut = ctx.GlobalString(intervalsOut.Name)
+	flagsConfig.SummaryOut = ctx.GlobalString(summaryOut.Name)
+	flagsConfig.OutputPerms = ctx.GlobalString(outputPerms.Name)
+	flagsConfig.NoOverwrite = ctx.GlobalBool(noOverwrite.Name)
+	flagsConfig.Force = ctx.GlobalBool(force.Name)
 
 	return flagsConfig
 }
@@ -16,6 +16,10 @@ type proxyProvider interface {
 	) (data.ArgCreateTransaction, error)
 }
 
+type txsSenderProxy interface {
+	SendTransaction(ctx context.Context, tx *data.Transaction) (string, error)
+}
+
 type transactionInteractor interface {
 	ApplySignatureAndGenerateTx(cryptoHolder core.CryptoComponentsHolder, arg data.ArgCreateTransaction) (*data.Transaction, error)
 }
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+
+	"github.com/multiversx/mx-chain-tools-go/tokensRemover/intervals"
+)
+
+type intervalOutput struct {
+	Start uint64 `json:"start"`
+	End   uint64 `json:"end"`
+}
+
+func createIntervalsOutput(shardTokensIntervals map[uint32]map[string][]*intervals.Interval) map[uint32]map[string][]intervalOutput {
+	ret := make(map[uint32]map[string][]intervalOutput, len(shardTokensIntervals))
+	for shardID, tokensIntervals := range shardTokensIntervals {
+		ret[shardID] = make(map[string][]intervalOutput, len(tokensIntervals))
+		for token, intervals := range tokensIntervals {
+			tokenIntervals := make([]intervalOutput, 0, len(intervals))
+			for _, currInterval := range intervals {
+				tokenIntervals = append(tokenIntervals, intervalOutput{
+					Start: currInterval.Start,
+					End:   currInterval.End,
+				})
+			}
+
+			ret[shardID][token] = tokenIntervals
+		}
+	}
+
+	return ret
+}
+
+// saveIntervals writes the nonces intervals to be deleted, for each token of each shard, in the provided json file.
+// The shards and the tokens are sorted by the json encoder, so the file can be easily reviewed or diffed
+func saveIntervals(shardTokensIntervals map[uint32]map[string][]*intervals.Interval, outfile string, perms os.FileMode) error {
+	jsonBytes, err := json.MarshalIndent(createIntervalsOutput(shardTokensIntervals), "", " ")
+	if err != nil {
+		return err
+	}
+
+	log.Info("writing intervals in", "file", outfile)
+
+	return writeOutputFile(outfile, jsonBytes, perms)
+}
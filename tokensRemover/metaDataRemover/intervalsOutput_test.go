@@ -0,0 +1,43 @@
+package main
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSaveIntervals(t *testing.T) {
+	t.Parallel()
+
+	shardTokensMap := map[uint32]map[string]struct{}{
+		0: {
+			"token1-r-01": {},
+			"token1-r-02": {},
+			"token1-r-04": {},
+			"token2-r-0a": {},
+		},
+		1: {
+			"token3-r-02": {},
+		},
+	}
+	shardTokensIntervals, err := createShardTokensIntervals(shardTokensMap, 1)
+	require.Nil(t, err)
+
+	outfile := filepath.Join(t.TempDir(), "intervals.json")
+	err = saveIntervals(shardTokensIntervals, outfile, 0600)
+	require.Nil(t, err)
+
+	jsonBytes, err := ioutil.ReadFile(outfile)
+	require.Nil(t, err)
+	require.JSONEq(t, `{
+		"0": {
+			"token1-r": [{"start": 1, "end": 4}],
+			"token2-r": [{"start": 10, "end": 10}]
+		},
+		"1": {
+			"token3-r": [{"start": 2, "end": 2}]
+		}
+	}`, string(jsonBytes))
+}
@@ -1,27 +1,41 @@
 package main
 
 import (
+	"fmt"
 	"io/ioutil"
 	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
 
 	logger "github.com/multiversx/mx-chain-logger-go"
+	"github.com/multiversx/mx-chain-tools-go/tokensRemover/exitcodes"
+	"github.com/multiversx/mx-chain-tools-go/tokensRemover/logging"
 	"github.com/multiversx/mx-chain-tools-go/tokensRemover/metaDataRemover/config"
-	"github.com/multiversx/mx-chain-tools-go/trieTools/trieToolsCommon"
+	"github.com/multiversx/mx-chain-tools-go/tokensRemover/version"
 	"github.com/multiversx/mx-chain-tools-go/trieTools/zeroBalanceSystemAccountChecker/common"
 	"github.com/pelletier/go-toml"
 	"github.com/urfave/cli"
 )
 
 const (
-	logFilePrefix   = "meta-data-remover"
-	tomlFile        = "./config.toml"
-	outputFilePerms = 0644
+	logFilePrefix = "meta-data-remover"
+
+	numDryRunSampleTxs = 3
+
+	maxTokensToDeletePerTransaction = 1000
+
+	defaultMaxGasLimitPerTransaction = 600000000
+
+	maxTxsPerShardModeError = "error"
+	maxTxsPerShardModeSplit = "split"
 )
 
 func main() {
 	app := cli.NewApp()
 	app.Name = "Tokens exporter CLI app"
 	app.Usage = "This is the entry point for the tool that deletes tokens meta-data"
+	app.Version = version.GetVersion()
 	app.Flags = getFlags()
 	app.Authors = []cli.Author{
 		{
@@ -33,11 +47,12 @@ func main() {
 	app.Action = func(c *cli.Context) error {
 		return startProcess(c)
 	}
+	app.OnUsageError = exitcodes.OnUsageError
 
 	err := app.Run(os.Args)
 	if err != nil {
 		log.Error(err.Error())
-		os.Exit(1)
+		os.Exit(exitcodes.ExitCode(err))
 		return
 	}
 }
@@ -45,42 +60,143 @@ func main() {
 func startProcess(c *cli.Context) error {
 	flagsConfig := getFlagsConfig(c)
 
-	_, errLogger := trieToolsCommon.AttachFileLogger(log, logFilePrefix, flagsConfig.ContextFlagsConfig)
+	_, errLogger := logging.AttachFileLogger(log, logFilePrefix, flagsConfig.ContextFlagsConfig, flagsConfig.LogJSON)
 	if errLogger != nil {
-		return errLogger
+		return exitcodes.NewIOError(errLogger)
 	}
 
 	err := logger.SetLogLevel(flagsConfig.LogLevel)
 	if err != nil {
-		return err
+		return exitcodes.NewUsageError(err)
 	}
 
 	log.Info("starting processing", "pid", os.Getpid())
 
+	outputFilePerms, err := parseOutputFilePerms(flagsConfig.OutputPerms)
+	if err != nil {
+		return exitcodes.NewUsageError(err)
+	}
+
+	broadcast := len(flagsConfig.ProxyURL) > 0
+	err = checkOutputPaths(argsCheckOutputPaths{
+		outFile:      flagsConfig.Outfile,
+		retryFile:    flagsConfig.RetryFile,
+		intervalsOut: flagsConfig.IntervalsOut,
+		summaryOut:   flagsConfig.SummaryOut,
+		dryRun:       flagsConfig.DryRun,
+		broadcast:    broadcast,
+		overwrite:    !flagsConfig.NoOverwrite || flagsConfig.Force,
+	})
+	if err != nil {
+		return exitcodes.NewUsageError(err)
+	}
+
 	shardTokensMap, err := readTokensInput(flagsConfig.Tokens)
 	if err != nil {
-		return err
+		return exitcodes.NewUsageError(err)
+	}
+
+	cfg, err := loadConfig(flagsConfig.ConfigFile)
+	if err != nil {
+		return exitcodes.NewUsageError(err)
+	}
+
+	shardTokensIntervals, err := createShardTokensIntervals(shardTokensMap, flagsConfig.MergeGap)
+	if err != nil {
+		return exitcodes.NewUsageError(err)
+	}
+
+	if len(flagsConfig.IntervalsOut) > 0 {
+		err = saveIntervals(shardTokensIntervals, flagsConfig.IntervalsOut, outputFilePerms)
+		if err != nil {
+			return exitcodes.NewIOError(err)
+		}
 	}
 
-	cfg, err := loadConfig()
+	shardTxsDataMap, err := createShardTxsDataMap(shardTokensIntervals, cfg.TokensToDeletePerTransaction)
 	if err != nil {
-		return err
+		return exitcodes.NewUsageError(err)
 	}
 
-	shardTxsDataMap, err := createShardTxsDataMap(shardTokensMap, cfg.TokensToDeletePerTransaction)
+	err = checkNumTxsPerShard(shardTxsDataMap, cfg)
 	if err != nil {
-		return err
+		return exitcodes.NewUsageError(err)
 	}
 
 	shardPemsDataMap, err := getShardPemsDataMap(flagsConfig.Pems)
 	if err != nil {
-		return err
+		return exitcodes.NewUsageError(err)
+	}
+
+	startNonces, err := parseStartNonces(flagsConfig.StartNonce)
+	if err != nil {
+		return exitcodes.NewUsageError(err)
+	}
+
+	if flagsConfig.DryRun {
+		log.Info("dry run: the outfile will not be written", "total tokens affected", countTokens(shardTokensMap))
+	}
+
+	if broadcast {
+		cfg.ProxyUrl = flagsConfig.ProxyURL
+	}
+
+	err = createShardTxs(argsCreateShardTxs{
+		outFile:              flagsConfig.Outfile,
+		cfg:                  cfg,
+		shardPemsDataMap:     shardPemsDataMap,
+		shardTxsDataMap:      shardTxsDataMap,
+		shardTokensIntervals: shardTokensIntervals,
+		startNonces:          startNonces,
+		dryRun:               flagsConfig.DryRun,
+		broadcast:            broadcast,
+		txsPerSecond:         flagsConfig.SendRate,
+		retryFile:            flagsConfig.RetryFile,
+		outputFilePerms:      outputFilePerms,
+		summaryOut:           flagsConfig.SummaryOut,
+	})
+
+	return exitcodes.NewIOError(err)
+}
+
+// parseStartNonces parses a list of shardID:nonce pairs, e.g. 0:15,1:4
+func parseStartNonces(startNonces string) (map[uint32]uint64, error) {
+	ret := make(map[uint32]uint64)
+	if len(startNonces) == 0 {
+		return ret, nil
+	}
+
+	for _, pair := range strings.Split(startNonces, ",") {
+		splits := strings.Split(strings.TrimSpace(pair), ":")
+		if len(splits) != 2 {
+			return nil, fmt.Errorf("%w: %s; expected format = shardID:nonce", errInvalidStartNonce, pair)
+		}
+
+		shardID, err := strconv.ParseUint(splits[0], 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s; invalid shard ID", errInvalidStartNonce, pair)
+		}
+		nonce, err := strconv.ParseUint(splits[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s; invalid nonce", errInvalidStartNonce, pair)
+		}
+
+		ret[uint32(shardID)] = nonce
+	}
+
+	return ret, nil
+}
+
+func countTokens(shardTokensMap map[uint32]map[string]struct{}) int {
+	numTokens := 0
+	for _, tokens := range shardTokensMap {
+		numTokens += len(tokens)
 	}
 
-	return createShardTxs(flagsConfig.Outfile, cfg, shardPemsDataMap, shardTxsDataMap)
+	return numTokens
 }
 
-func getShardPemsDataMap(pemsFile string) (map[uint32]*skAddress, error) {
+func getShardPemsDataMap(pemsFile string) (map[uint32][]*skAddress, error) {
 	osFileHandler := common.NewOSFileHandler()
 	pemsReader, err := newPemsDataReader(&pemDataProvider{}, osFileHandler)
 	if err != nil {
@@ -90,17 +206,94 @@ func getShardPemsDataMap(pemsFile string) (map[uint32]*skAddress, error) {
 	return pemsReader.readPemsData(pemsFile)
 }
 
-func loadConfig() (*config.Config, error) {
-	tomlBytes, err := ioutil.ReadFile(tomlFile)
+func loadConfig(configPath string) (*config.Config, error) {
+	tomlBytes, err := ioutil.ReadFile(configPath)
 	if err != nil {
-		return nil, err
+		return nil, createReadConfigError(configPath, err)
 	}
 
+	return parseConfig(tomlBytes)
+}
+
+func parseConfig(tomlBytes []byte) (*config.Config, error) {
 	var cfg config.Config
-	err = toml.Unmarshal(tomlBytes, &cfg)
+	err := toml.Unmarshal(tomlBytes, &cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	err = checkTokensToDeletePerTransaction(cfg.TokensToDeletePerTransaction)
+	if err != nil {
+		return nil, err
+	}
+
+	err = checkMaxTransactionsPerShardMode(cfg.MaxTransactionsPerShardMode)
 	if err != nil {
 		return nil, err
 	}
 
 	return &cfg, nil
 }
+
+// checkTokensToDeletePerTransaction bounds the number of nonces deleted by a tx. In the worst case, where each nonce is
+// a separate interval of a different token, a nonce adds roughly 72 bytes of tx data. At 1500 gas per data byte,
+// maxTokensToDeletePerTransaction nonces need about 108M gas, which leaves enough room for AdditionalGasLimit
+// below the max gas limit of 600M per tx
+func checkTokensToDeletePerTransaction(tokensToDeletePerTransaction uint64) error {
+	if tokensToDeletePerTransaction < 1 || tokensToDeletePerTransaction > maxTokensToDeletePerTransaction {
+		return fmt.Errorf("%w: got %d, expected a value in the range [1, %d]",
+			errInvalidTokensToDeletePerTransaction, tokensToDeletePerTransaction, maxTokensToDeletePerTransaction)
+	}
+
+	return nil
+}
+
+func checkMaxTransactionsPerShardMode(mode string) error {
+	switch mode {
+	case "", maxTxsPerShardModeError, maxTxsPerShardModeSplit:
+		return nil
+	default:
+		return fmt.Errorf("%w: got %s, expected %s or %s",
+			errInvalidMaxTransactionsPerShardMode, mode, maxTxsPerShardModeError, maxTxsPerShardModeSplit)
+	}
+}
+
+// checkNumTxsPerShard errors if a shard exceeds MaxTransactionsPerShard, unless the txs are split across multiple files
+func checkNumTxsPerShard(shardTxsDataMap map[uint32][][]byte, cfg *config.Config) error {
+	if cfg.MaxTransactionsPerShard == 0 {
+		return nil
+	}
+
+	for shardID, txsData := range shardTxsDataMap {
+		numTxs := uint64(len(txsData))
+		if numTxs <= cfg.MaxTransactionsPerShard {
+			continue
+		}
+
+		if cfg.MaxTransactionsPerShardMode == maxTxsPerShardModeSplit {
+			log.Info("txs will be split across multiple files", "shardID", shardID, "num of txs", numTxs,
+				"max txs per file", cfg.MaxTransactionsPerShard)
+			continue
+		}
+
+		return fmt.Errorf("%w: shard %d has %d txs, while MaxTransactionsPerShard = %d",
+			errTooManyTransactionsInShard, shardID, numTxs, cfg.MaxTransactionsPerShard)
+	}
+
+	return nil
+}
+
+// createReadConfigError includes the absolute path of the config file in the returned error, since a relative path
+// is resolved against the working directory, which might not be the one of the tool
+func createReadConfigError(configPath string, err error) error {
+	absPath, errAbs := filepath.Abs(configPath)
+	if errAbs != nil {
+		absPath = configPath
+	}
+
+	if os.IsNotExist(err) {
+		return fmt.Errorf("%w: %s, the path can be set using the --%s flag", errConfigFileNotFound, absPath, configFile.Name)
+	}
+
+	return fmt.Errorf("%w while reading the config file %s", err, absPath)
+}
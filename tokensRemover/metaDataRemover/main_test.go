@@ -0,0 +1,162 @@
+package main
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/multiversx/mx-chain-tools-go/tokensRemover/metaDataRemover/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseConfig(t *testing.T) {
+	t.Parallel()
+
+	t.Run("zero tokens to delete per transaction, should error", func(t *testing.T) {
+		t.Parallel()
+
+		cfg, err := parseConfig([]byte("TokensToDeletePerTransaction = 0"))
+		require.Nil(t, cfg)
+		require.ErrorIs(t, err, errInvalidTokensToDeletePerTransaction)
+	})
+	t.Run("negative tokens to delete per transaction, should error", func(t *testing.T) {
+		t.Parallel()
+
+		cfg, err := parseConfig([]byte("TokensToDeletePerTransaction = -5"))
+		require.Nil(t, cfg)
+		require.Error(t, err)
+	})
+	t.Run("too many tokens to delete per transaction, should error", func(t *testing.T) {
+		t.Parallel()
+
+		cfg, err := parseConfig([]byte("TokensToDeletePerTransaction = 1001"))
+		require.Nil(t, cfg)
+		require.ErrorIs(t, err, errInvalidTokensToDeletePerTransaction)
+		require.Contains(t, err.Error(), "[1, 1000]")
+	})
+	t.Run("should work", func(t *testing.T) {
+		t.Parallel()
+
+		cfg, err := parseConfig([]byte("ProxyUrl = \"url\"\nTokensToDeletePerTransaction = 1000\nAdditionalGasLimit = 5"))
+		require.Nil(t, err)
+		require.Equal(t, uint64(1000), cfg.TokensToDeletePerTransaction)
+		require.Equal(t, uint64(5), cfg.AdditionalGasLimit)
+	})
+	t.Run("invalid max transactions per shard mode, should error", func(t *testing.T) {
+		t.Parallel()
+
+		cfg, err := parseConfig([]byte("TokensToDeletePerTransaction = 10\nMaxTransactionsPerShardMode = \"drop\""))
+		require.Nil(t, cfg)
+		require.ErrorIs(t, err, errInvalidMaxTransactionsPerShardMode)
+	})
+	t.Run("max transactions per shard, should work", func(t *testing.T) {
+		t.Parallel()
+
+		cfg, err := parseConfig([]byte("TokensToDeletePerTransaction = 10\nMaxTransactionsPerShard = 50\nMaxTransactionsPerShardMode = \"split\""))
+		require.Nil(t, err)
+		require.Equal(t, uint64(50), cfg.MaxTransactionsPerShard)
+		require.Equal(t, maxTxsPerShardModeSplit, cfg.MaxTransactionsPerShardMode)
+	})
+}
+
+func TestLoadConfig(t *testing.T) {
+	t.Parallel()
+
+	t.Run("missing file, should error with the absolute path", func(t *testing.T) {
+		t.Parallel()
+
+		configPath := filepath.Join(t.TempDir(), "missing.toml")
+		cfg, err := loadConfig(configPath)
+		require.Nil(t, cfg)
+		require.ErrorIs(t, err, errConfigFileNotFound)
+		require.Contains(t, err.Error(), configPath)
+	})
+	t.Run("relative path, should error with the resolved path", func(t *testing.T) {
+		t.Parallel()
+
+		absPath, err := filepath.Abs("missing.toml")
+		require.Nil(t, err)
+
+		cfg, err := loadConfig("missing.toml")
+		require.Nil(t, cfg)
+		require.ErrorIs(t, err, errConfigFileNotFound)
+		require.Contains(t, err.Error(), absPath)
+	})
+	t.Run("should work", func(t *testing.T) {
+		t.Parallel()
+
+		configPath := filepath.Join(t.TempDir(), "custom.toml")
+		err := ioutil.WriteFile(configPath, []byte("TokensToDeletePerTransaction = 7"), 0644)
+		require.Nil(t, err)
+
+		cfg, err := loadConfig(configPath)
+		require.Nil(t, err)
+		require.Equal(t, uint64(7), cfg.TokensToDeletePerTransaction)
+	})
+}
+
+func TestCheckNumTxsPerShard(t *testing.T) {
+	t.Parallel()
+
+	shardTxsDataMap := map[uint32][][]byte{
+		0: {[]byte("tx1"), []byte("tx2")},
+		1: {[]byte("tx1"), []byte("tx2"), []byte("tx3")},
+	}
+
+	t.Run("no max transactions per shard, should work", func(t *testing.T) {
+		t.Parallel()
+
+		err := checkNumTxsPerShard(shardTxsDataMap, &config.Config{})
+		require.Nil(t, err)
+	})
+	t.Run("max transactions per shard not exceeded, should work", func(t *testing.T) {
+		t.Parallel()
+
+		err := checkNumTxsPerShard(shardTxsDataMap, &config.Config{MaxTransactionsPerShard: 3})
+		require.Nil(t, err)
+	})
+	t.Run("max transactions per shard exceeded, should error", func(t *testing.T) {
+		t.Parallel()
+
+		err := checkNumTxsPerShard(shardTxsDataMap, &config.Config{MaxTransactionsPerShard: 2})
+		require.ErrorIs(t, err, errTooManyTransactionsInShard)
+		require.Contains(t, err.Error(), "shard 1 has 3 txs")
+	})
+	t.Run("max transactions per shard exceeded in split mode, should work", func(t *testing.T) {
+		t.Parallel()
+
+		err := checkNumTxsPerShard(shardTxsDataMap, &config.Config{
+			MaxTransactionsPerShard:     2,
+			MaxTransactionsPerShardMode: maxTxsPerShardModeSplit,
+		})
+		require.Nil(t, err)
+	})
+}
+
+func TestParseStartNonces(t *testing.T) {
+	t.Parallel()
+
+	t.Run("empty string, should return empty map", func(t *testing.T) {
+		t.Parallel()
+
+		startNonces, err := parseStartNonces("")
+		require.Nil(t, err)
+		require.Empty(t, startNonces)
+	})
+	t.Run("invalid format, should error", func(t *testing.T) {
+		t.Parallel()
+
+		for _, input := range []string{"15", "0:15,1", "a:15", "0:b", "0:-1"} {
+			startNonces, err := parseStartNonces(input)
+			require.Nil(t, startNonces)
+			require.ErrorIs(t, err, errInvalidStartNonce)
+		}
+	})
+	t.Run("should work", func(t *testing.T) {
+		t.Parallel()
+
+		startNonces, err := parseStartNonces("0:15, 1:4,4294967295:0")
+		require.Nil(t, err)
+		require.Equal(t, map[uint32]uint64{0: 15, 1: 4, 4294967295: 0}, startNonces)
+	})
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+)
+
+const maxOutputFilePerms = 0777
+
+type argsCheckOutputPaths struct {
+	outFile      string
+	retryFile    string
+	intervalsOut string
+	summaryOut   string
+	dryRun       bool
+	broadcast    bool
+	overwrite    bool
+}
+
+// parseOutputFilePerms parses the octal permissions of the output files, e.g. 0600
+func parseOutputFilePerms(perms string) (os.FileMode, error) {
+	value, err := strconv.ParseUint(perms, 8, 32)
+	if err != nil || value > maxOutputFilePerms {
+		return 0, fmt.Errorf("%w: got %s, expected an octal value in the range [0, 0777]", errInvalidOutputFilePerms, perms)
+	}
+
+	return os.FileMode(value), nil
+}
+
+// checkOutputPaths checks, before any tx is created, the paths of the output files which are going to be written:
+// their parent directories should exist and, unless overwrite is set, the files should not exist
+func checkOutputPaths(args argsCheckOutputPaths) error {
+	for _, file := range []string{args.intervalsOut, args.summaryOut} {
+		if len(file) == 0 {
+			continue
+		}
+
+		err := checkOutputFilePath(file, args.overwrite)
+		if err != nil {
+			return err
+		}
+	}
+
+	switch {
+	case args.dryRun:
+		return nil
+	case args.broadcast:
+		return checkOutputFilePath(args.retryFile, args.overwrite)
+	default:
+		return checkOutputDirPath(args.outFile, args.overwrite)
+	}
+}
+
+func checkOutputFilePath(file string, overwrite bool) error {
+	err := checkParentDirExists(file)
+	if err != nil {
+		return err
+	}
+
+	_, err = os.Stat(file)
+	if err == nil && !overwrite {
+		return fmt.Errorf("%w: %s; use --%s to overwrite it", errOutputAlreadyExists, file, force.Name)
+	}
+
+	return nil
+}
+
+// checkOutputDirPath checks the directory where the txs of each shard are saved. Any existing txsShard*.json file
+// is considered a previous output, since the num of files of a shard depends on MaxTransactionsPerShard
+func checkOutputDirPath(outDir string, overwrite bool) error {
+	err := checkParentDirExists(outDir)
+	if err != nil {
+		return err
+	}
+
+	info, err := os.Stat(outDir)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("%w: %s is not a directory", errInvalidOutputPath, outDir)
+	}
+	if overwrite {
+		return nil
+	}
+
+	existingFiles, err := filepath.Glob(filepath.Join(outDir, "txsShard*.json"))
+	if err != nil {
+		return err
+	}
+	if len(existingFiles) > 0 {
+		return fmt.Errorf("%w: %s; use --%s to overwrite it", errOutputAlreadyExists, existingFiles[0], force.Name)
+	}
+
+	return nil
+}
+
+func checkParentDirExists(path string) error {
+	parentDir := filepath.Dir(filepath.Clean(path))
+	info, err := os.Stat(parentDir)
+	if err != nil {
+		return fmt.Errorf("%w: %s, for output %s", errOutputParentDirNotFound, parentDir, path)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("%w: %s is not a directory, for output %s", errOutputParentDirNotFound, parentDir, path)
+	}
+
+	return nil
+}
+
+// writeOutputFile writes the provided data with the provided permissions. The permissions are also set on an
+// already existing file, which would otherwise keep its previous ones
+func writeOutputFile(file string, data []byte, perms os.FileMode) error {
+	err := ioutil.WriteFile(file, data, perms)
+	if err != nil {
+		return err
+	}
+
+	return os.Chmod(file, perms)
+}
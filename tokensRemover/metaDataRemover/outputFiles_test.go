@@ -0,0 +1,113 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseOutputFilePerms(t *testing.T) {
+	t.Parallel()
+
+	perms, err := parseOutputFilePerms("0600")
+	require.Nil(t, err)
+	require.Equal(t, os.FileMode(0600), perms)
+
+	perms, err = parseOutputFilePerms("644")
+	require.Nil(t, err)
+	require.Equal(t, os.FileMode(0644), perms)
+
+	_, err = parseOutputFilePerms("0800")
+	require.ErrorIs(t, err, errInvalidOutputFilePerms)
+
+	_, err = parseOutputFilePerms("01777")
+	require.ErrorIs(t, err, errInvalidOutputFilePerms)
+
+	_, err = parseOutputFilePerms("")
+	require.ErrorIs(t, err, errInvalidOutputFilePerms)
+}
+
+func TestCheckOutputPaths(t *testing.T) {
+	t.Parallel()
+
+	t.Run("missing parent directory, should error", func(t *testing.T) {
+		t.Parallel()
+
+		err := checkOutputPaths(argsCheckOutputPaths{
+			outFile: filepath.Join(t.TempDir(), "missing", "output"),
+		})
+		require.ErrorIs(t, err, errOutputParentDirNotFound)
+
+		err = checkOutputPaths(argsCheckOutputPaths{
+			retryFile: filepath.Join(t.TempDir(), "missing", "retry.json"),
+			broadcast: true,
+		})
+		require.ErrorIs(t, err, errOutputParentDirNotFound)
+	})
+	t.Run("outfile is a file, should error", func(t *testing.T) {
+		t.Parallel()
+
+		outFile := filepath.Join(t.TempDir(), "output")
+		require.Nil(t, ioutil.WriteFile(outFile, []byte("{}"), 0600))
+
+		err := checkOutputPaths(argsCheckOutputPaths{outFile: outFile, overwrite: true})
+		require.ErrorIs(t, err, errInvalidOutputPath)
+	})
+	t.Run("existing shard txs file, should error unless overwrite", func(t *testing.T) {
+		t.Parallel()
+
+		outDir := t.TempDir()
+		err := checkOutputPaths(argsCheckOutputPaths{outFile: outDir})
+		require.Nil(t, err)
+
+		require.Nil(t, ioutil.WriteFile(filepath.Join(outDir, "txsShard0_2.json"), []byte("[]"), 0600))
+		err = checkOutputPaths(argsCheckOutputPaths{outFile: outDir})
+		require.ErrorIs(t, err, errOutputAlreadyExists)
+
+		err = checkOutputPaths(argsCheckOutputPaths{outFile: outDir, overwrite: true})
+		require.Nil(t, err)
+	})
+	t.Run("existing retry and intervals files, should error unless overwrite", func(t *testing.T) {
+		t.Parallel()
+
+		dir := t.TempDir()
+		retryFile := filepath.Join(dir, "retry.json")
+		intervalsOut := filepath.Join(dir, "intervals.json")
+		require.Nil(t, ioutil.WriteFile(retryFile, []byte("[]"), 0600))
+
+		err := checkOutputPaths(argsCheckOutputPaths{retryFile: retryFile, broadcast: true})
+		require.ErrorIs(t, err, errOutputAlreadyExists)
+
+		// the retry file is not written in the dry run mode
+		err = checkOutputPaths(argsCheckOutputPaths{retryFile: retryFile, broadcast: true, dryRun: true})
+		require.Nil(t, err)
+
+		require.Nil(t, ioutil.WriteFile(intervalsOut, []byte("{}"), 0600))
+		err = checkOutputPaths(argsCheckOutputPaths{intervalsOut: intervalsOut, dryRun: true})
+		require.ErrorIs(t, err, errOutputAlreadyExists)
+
+		err = checkOutputPaths(argsCheckOutputPaths{retryFile: retryFile, intervalsOut: intervalsOut, broadcast: true, overwrite: true})
+		require.Nil(t, err)
+	})
+}
+
+func TestWriteOutputFile(t *testing.T) {
+	t.Parallel()
+
+	file := filepath.Join(t.TempDir(), "txs.json")
+	require.Nil(t, ioutil.WriteFile(file, []byte("old"), 0644))
+
+	err := writeOutputFile(file, []byte("new"), 0600)
+	require.Nil(t, err)
+
+	content, err := ioutil.ReadFile(file)
+	require.Nil(t, err)
+	require.Equal(t, "new", string(content))
+
+	info, err := os.Stat(file)
+	require.Nil(t, err)
+	require.Equal(t, os.FileMode(0600), info.Mode().Perm())
+}
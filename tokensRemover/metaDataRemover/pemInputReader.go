@@ -28,7 +28,9 @@ func newPemsDataReader(pemProvider pemProvider, fileHandler common.FileHandler)
 	}, nil
 }
 
-func (pdr *pemsDataReader) readPemsData(pemsFile string) (map[uint32]*skAddress, error) {
+// readPemsData reads the senders of each shard from the pem files found in the provided directory. A shard can have
+// one sender (shardX.pem) or more senders (shardX_1.pem, shardX_2.pem, ...), returned in the order of the file names
+func (pdr *pemsDataReader) readPemsData(pemsFile string) (map[uint32][]*skAddress, error) {
 	workingDir, err := pdr.fileHandler.Getwd()
 	if err != nil {
 		return nil, err
@@ -40,7 +42,7 @@ func (pdr *pemsDataReader) readPemsData(pemsFile string) (map[uint32]*skAddress,
 		return nil, err
 	}
 
-	shardPemDataMap := make(map[uint32]*skAddress)
+	shardPemDataMap := make(map[uint32][]*skAddress)
 	for _, file := range contents {
 		if file.IsDir() {
 			continue
@@ -56,7 +58,7 @@ func (pdr *pemsDataReader) readPemsData(pemsFile string) (map[uint32]*skAddress,
 			return nil, err
 		}
 
-		shardPemDataMap[shardID] = pemData
+		shardPemDataMap[shardID] = append(shardPemDataMap[shardID], pemData)
 	}
 
 	return shardPemDataMap, nil
@@ -65,9 +67,13 @@ func (pdr *pemsDataReader) readPemsData(pemsFile string) (map[uint32]*skAddress,
 func getShardID(file string) (uint32, error) {
 	shardIDStr := strings.TrimPrefix(file, "shard")
 	shardIDStr = strings.TrimSuffix(shardIDStr, ".pem")
-	shardID, err := strconv.Atoi(shardIDStr)
+	splits := strings.SplitN(shardIDStr, "_", 2)
+	shardID, err := strconv.Atoi(splits[0])
+	if err == nil && len(splits) == 2 {
+		_, err = strconv.Atoi(splits[1])
+	}
 	if err != nil {
-		return 0, fmt.Errorf("invalid file input name = %s; expected pem file name to be <shardX.pem>, where X = number(e.g. shard0.pem)", file)
+		return 0, fmt.Errorf("invalid file input name = %s; expected pem file name to be <shardX.pem> or <shardX_Y.pem>, where X, Y = numbers(e.g. shard0.pem, shard0_1.pem)", file)
 	}
 
 	return uint32(shardID), nil
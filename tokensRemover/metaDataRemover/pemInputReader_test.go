@@ -52,15 +52,37 @@ func TestReadPemsData_RealData(t *testing.T) {
 	skShard1, err := hex.DecodeString("e253a571ca153dc2aee845819f74bcc9773b0586edead15a94cb7235a5027436")
 	require.Nil(t, err)
 
-	expectedPemsData := map[uint32]*skAddress{
+	expectedPemsData := map[uint32][]*skAddress{
 		0: {
-			secretKey: skShard0,
-			address:   addrShard0,
+			{
+				secretKey: skShard0,
+				address:   addrShard0,
+			},
 		},
 		1: {
-			secretKey: skShard1,
-			address:   addrShard1,
+			{
+				secretKey: skShard1,
+				address:   addrShard1,
+			},
 		},
 	}
 	require.Equal(t, pemsData, expectedPemsData)
 }
+
+func TestGetShardID(t *testing.T) {
+	t.Parallel()
+
+	shardID, err := getShardID("shard2.pem")
+	require.Nil(t, err)
+	require.Equal(t, uint32(2), shardID)
+
+	shardID, err = getShardID("shard1_3.pem")
+	require.Nil(t, err)
+	require.Equal(t, uint32(1), shardID)
+
+	_, err = getShardID("shardX.pem")
+	require.Error(t, err)
+
+	_, err = getShardID("shard1_a.pem")
+	require.Error(t, err)
+}
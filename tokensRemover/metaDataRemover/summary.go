@@ -0,0 +1,114 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"sort"
+)
+
+const (
+	summaryModeOutfile   = "outfile"
+	summaryModeBroadcast = "broadcast"
+	summaryModeDryRun    = "dry-run"
+)
+
+type shardSummary struct {
+	ShardID   uint32   `json:"shardID"`
+	NumTxs    int      `json:"numTxs"`
+	NumTokens int      `json:"numTokens"`
+	NumNonces uint64   `json:"numNonces"`
+	Senders   []string `json:"senders"`
+}
+
+// txsSummary holds the figures of a run, so that the created txs can be reviewed and archived. The nonces are counted
+// from the intervals, so they also include the missing nonces merged by the merge-gap flag
+type txsSummary struct {
+	Mode         string          `json:"mode"`
+	TotalTxs     int             `json:"totalTxs"`
+	TotalTokens  int             `json:"totalTokens"`
+	TotalNonces  uint64          `json:"totalNonces"`
+	NumFailedTxs int             `json:"numFailedTxs"`
+	Shards       []*shardSummary `json:"shards"`
+}
+
+func createTxsSummary(args argsCreateShardTxs, numFailedTxs int) *txsSummary {
+	summary := &txsSummary{
+		Mode:         getSummaryMode(args),
+		NumFailedTxs: numFailedTxs,
+		Shards:       make([]*shardSummary, 0, len(args.shardTxsDataMap)),
+	}
+
+	for shardID, txsData := range args.shardTxsDataMap {
+		currShardSummary := &shardSummary{
+			ShardID: shardID,
+			NumTxs:  len(txsData),
+			Senders: getUsedSenders(args.shardPemsDataMap[shardID], len(txsData)),
+		}
+		for _, intervals := range args.shardTokensIntervals[shardID] {
+			currShardSummary.NumTokens++
+			for _, currInterval := range intervals {
+				currShardSummary.NumNonces += currInterval.NumNonces()
+			}
+		}
+
+		summary.TotalTxs += currShardSummary.NumTxs
+		summary.TotalTokens += currShardSummary.NumTokens
+		summary.TotalNonces += currShardSummary.NumNonces
+		summary.Shards = append(summary.Shards, currShardSummary)
+	}
+
+	sort.Slice(summary.Shards, func(i, j int) bool {
+		return summary.Shards[i].ShardID < summary.Shards[j].ShardID
+	})
+
+	return summary
+}
+
+func getSummaryMode(args argsCreateShardTxs) string {
+	switch {
+	case args.dryRun:
+		return summaryModeDryRun
+	case args.broadcast:
+		return summaryModeBroadcast
+	default:
+		return summaryModeOutfile
+	}
+}
+
+// getUsedSenders returns the addresses of the senders which sign at least one tx. The txs are assigned round-robin,
+// so if there are fewer txs than senders, only the first senders are used
+func getUsedSenders(pemsData []*skAddress, numTxs int) []string {
+	numUsedSenders := len(pemsData)
+	if numTxs < numUsedSenders {
+		numUsedSenders = numTxs
+	}
+
+	senders := make([]string, 0, numUsedSenders)
+	for _, pemData := range pemsData[:numUsedSenders] {
+		senders = append(senders, pemData.address.AddressAsBech32String())
+	}
+
+	return senders
+}
+
+func logTxsSummary(summary *txsSummary) {
+	for _, currShardSummary := range summary.Shards {
+		log.Info("shard summary", "shardID", currShardSummary.ShardID, "num of txs", currShardSummary.NumTxs,
+			"num of tokens", currShardSummary.NumTokens, "num of nonces", currShardSummary.NumNonces,
+			"senders", currShardSummary.Senders)
+	}
+
+	log.Info("summary", "mode", summary.Mode, "total txs", summary.TotalTxs, "total tokens", summary.TotalTokens,
+		"total nonces", summary.TotalNonces, "num of failed txs", summary.NumFailedTxs)
+}
+
+func saveSummary(summary *txsSummary, outfile string, perms os.FileMode) error {
+	jsonBytes, err := json.MarshalIndent(summary, "", " ")
+	if err != nil {
+		return err
+	}
+
+	log.Info("writing summary in", "file", outfile)
+
+	return writeOutputFile(outfile, jsonBytes, perms)
+}
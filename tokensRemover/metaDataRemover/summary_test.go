@@ -0,0 +1,95 @@
+package main
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/multiversx/mx-chain-tools-go/tokensRemover/intervals"
+	"github.com/multiversx/mx-sdk-go/data"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateTxsSummary(t *testing.T) {
+	t.Parallel()
+
+	addr1, err := data.NewAddressFromBech32String("erd1qyu5wthldzr8wx5c9ucg8kjagg0jfs53s8nr3zpz3hypefsdd8ssycr6th")
+	require.Nil(t, err)
+	addr2, err := data.NewAddressFromBech32String("erd1k2s324ww2g0yj38qn2ch2jwctdy8mnfxep94q9arncc6xecg3xaq6mjse8")
+	require.Nil(t, err)
+
+	args := argsCreateShardTxs{
+		broadcast: true,
+		shardPemsDataMap: map[uint32][]*skAddress{
+			0: {{address: addr1}, {address: addr2}},
+			1: {{address: addr2}},
+		},
+		shardTxsDataMap: map[uint32][][]byte{
+			0: {[]byte("txData1")},
+			1: {[]byte("txData2"), []byte("txData3")},
+		},
+		shardTokensIntervals: map[uint32]map[string][]*intervals.Interval{
+			0: {
+				"token1-r": {{Start: 1, End: 4}, {Start: 10, End: 10}},
+				"token2-r": {{Start: 2, End: 3}},
+			},
+			1: {
+				"token3-r": {{Start: 5, End: 5}},
+			},
+		},
+	}
+
+	summary := createTxsSummary(args, 1)
+	require.Equal(t, &txsSummary{
+		Mode:         summaryModeBroadcast,
+		TotalTxs:     3,
+		TotalTokens:  3,
+		TotalNonces:  8,
+		NumFailedTxs: 1,
+		Shards: []*shardSummary{
+			{
+				ShardID:   0,
+				NumTxs:    1,
+				NumTokens: 2,
+				NumNonces: 7,
+				// a single tx, so only the first sender is used
+				Senders: []string{addr1.AddressAsBech32String()},
+			},
+			{
+				ShardID:   1,
+				NumTxs:    2,
+				NumTokens: 1,
+				NumNonces: 1,
+				Senders:   []string{addr2.AddressAsBech32String()},
+			},
+		},
+	}, summary)
+
+	args.dryRun = true
+	require.Equal(t, summaryModeDryRun, createTxsSummary(args, 0).Mode)
+}
+
+func TestSaveSummary(t *testing.T) {
+	t.Parallel()
+
+	summary := &txsSummary{
+		Mode:        summaryModeOutfile,
+		TotalTxs:    1,
+		TotalTokens: 1,
+		TotalNonces: 4,
+		Shards:      []*shardSummary{{ShardID: 2, NumTxs: 1, NumTokens: 1, NumNonces: 4, Senders: []string{"erd1"}}},
+	}
+
+	outfile := filepath.Join(t.TempDir(), "summary.json")
+	err := saveSummary(summary, outfile, 0600)
+	require.Nil(t, err)
+
+	jsonBytes, err := ioutil.ReadFile(outfile)
+	require.Nil(t, err)
+
+	savedSummary := &txsSummary{}
+	err = json.Unmarshal(jsonBytes, savedSummary)
+	require.Nil(t, err)
+	require.Equal(t, summary, savedSummary)
+}
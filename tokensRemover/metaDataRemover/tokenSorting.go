@@ -5,35 +5,18 @@ import (
 	"math/big"
 	"sort"
 	"strings"
-)
-
-type interval struct {
-	start uint64
-	end   uint64
-}
-
-func (i *interval) split(index uint64) (*interval, *interval) {
-	first := &interval{
-		start: i.start,
-		end:   i.start + index - 1,
-	}
-
-	second := &interval{
-		start: first.end + 1,
-		end:   i.end,
-	}
 
-	return first, second
-}
+	"github.com/multiversx/mx-chain-tools-go/tokensRemover/intervals"
+)
 
 type tokenWithInterval struct {
 	tokenID  string
-	interval *interval
+	interval *intervals.Interval
 }
 
 type tokenData struct {
 	tokenID   string
-	intervals []*interval
+	intervals []*intervals.Interval
 }
 
 func sortTokensIDByNonce(tokens map[string]struct{}) (map[string][]uint64, error) {
@@ -65,49 +48,7 @@ func sortTokensIDByNonce(tokens map[string]struct{}) (map[string][]uint64, error
 	return ret, nil
 }
 
-func groupTokensByIntervals(tokens map[string][]uint64) map[string][]*interval {
-	ret := make(map[string][]*interval)
-	for token, nonces := range tokens {
-		ret[token] = getIntervals(nonces)
-	}
-
-	return ret
-}
-
-func getIntervals(nonces []uint64) []*interval {
-	numNonces := len(nonces)
-	intervals := make([]*interval, 0)
-	for idx := 0; idx < numNonces; idx++ {
-		nonce := nonces[idx]
-		if idx+1 >= numNonces {
-			intervals = append(intervals, &interval{
-				start: nonce,
-				end:   nonce,
-			})
-			break
-		}
-
-		currInterval := &interval{start: nonce}
-		numConsecutiveNonces := uint64(0)
-		for idx < numNonces-1 {
-			currNonce := nonces[idx]
-			nextNonce := nonces[idx+1]
-			if nextNonce-currNonce > 1 {
-				break
-			}
-
-			numConsecutiveNonces++
-			idx++
-		}
-
-		currInterval.end = currInterval.start + numConsecutiveNonces
-		intervals = append(intervals, currInterval)
-	}
-
-	return intervals
-}
-
-func sortTokenIntervalsByMaxConsecutiveNonces(tokens map[string][]*interval) []*tokenWithInterval {
+func sortTokenIntervalsByMaxConsecutiveNonces(tokens map[string][]*intervals.Interval) []*tokenWithInterval {
 	ret := make([]*tokenWithInterval, 0)
 	for token, intervals := range tokens {
 		for _, currInterval := range intervals {
@@ -120,8 +61,8 @@ func sortTokenIntervalsByMaxConsecutiveNonces(tokens map[string][]*interval) []*
 	}
 
 	sort.SliceStable(ret, func(i, j int) bool {
-		consecutiveNonces1 := ret[i].interval.end - ret[i].interval.start + 1
-		consecutiveNonces2 := ret[j].interval.end - ret[j].interval.start + 1
+		consecutiveNonces1 := ret[i].interval.NumNonces()
+		consecutiveNonces2 := ret[j].interval.NumNonces()
 
 		if consecutiveNonces1 == consecutiveNonces2 {
 			return ret[i].tokenID < ret[j].tokenID
@@ -135,7 +76,7 @@ func sortTokenIntervalsByMaxConsecutiveNonces(tokens map[string][]*interval) []*
 
 func groupTokenIntervalsInBulks(tokens []*tokenWithInterval, bulkSize uint64) [][]*tokenData {
 	bulks := make([][]*tokenData, 0, bulkSize)
-	currBulk := make(map[string][]*interval, 0)
+	currBulk := make(map[string][]*intervals.Interval, 0)
 	numNoncesInBulk := uint64(0)
 
 	tokensCopy := make([]*tokenWithInterval, len(tokens))
@@ -147,14 +88,14 @@ func groupTokenIntervalsInBulks(tokens []*tokenWithInterval, bulkSize uint64) []
 		currInterval := currTokenData.interval
 		currTokenID := currTokenData.tokenID
 
-		noncesInInterval := currInterval.end - currInterval.start + 1
+		noncesInInterval := currInterval.NumNonces()
 		availableSlots := bulkSize - numNoncesInBulk
 		if availableSlots >= noncesInInterval {
 			numNoncesInBulk += noncesInInterval
 			currBulk[currTokenID] = append(currBulk[currTokenID], currInterval)
 
 		} else {
-			first, second := currInterval.split(availableSlots)
+			first, second := currInterval.Split(availableSlots)
 
 			numNoncesInBulk += availableSlots
 			currBulk[currTokenID] = append(currBulk[currTokenID], first)
@@ -166,7 +107,7 @@ func groupTokenIntervalsInBulks(tokens []*tokenWithInterval, bulkSize uint64) []
 		if bulkFull || lastInterval {
 			bulks = append(bulks, tokensMapToOrderedArray(currBulk))
 
-			currBulk = make(map[string][]*interval, 0)
+			currBulk = make(map[string][]*intervals.Interval, 0)
 			numNoncesInBulk = 0
 		}
 
@@ -186,7 +127,7 @@ func insert(tokens []*tokenWithInterval, index int, token *tokenWithInterval) []
 	return tokens
 }
 
-func tokensMapToOrderedArray(tokens map[string][]*interval) []*tokenData {
+func tokensMapToOrderedArray(tokens map[string][]*intervals.Interval) []*tokenData {
 	ret := make([]*tokenData, 0, len(tokens))
 
 	for token, intervals := range tokens {
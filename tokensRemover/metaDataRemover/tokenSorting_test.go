@@ -4,6 +4,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/multiversx/mx-chain-tools-go/tokensRemover/intervals"
 	"github.com/stretchr/testify/require"
 )
 
@@ -63,113 +64,62 @@ func TestSortTokensIDByNonce(t *testing.T) {
 	})
 }
 
-func TestGroupTokensByIntervals(t *testing.T) {
-	tokens := map[string][]uint64{
-		"token1": {1, 2, 3, 8, 9, 10},
-		"token2": {1},
-		"token3": {3, 9},
-		"token4": {11, 12},
-		"token5": {10, 100, 101, 102, 111},
-		"token6": {4, 5, 6, 7},
-	}
+func TestGroupTokenIntervalsInBulks_DuplicateConsecutiveNonces(t *testing.T) {
+	t.Parallel()
 
-	sortedTokens := groupTokensByIntervals(tokens)
-	require.Equal(t, sortedTokens,
-		map[string][]*interval{
-			"token1": {
-				{
-					start: 1,
-					end:   3,
-				},
-				{
-					start: 8,
-					end:   10,
-				},
-			},
-			"token2": {
-				{
-					start: 1,
-					end:   1,
-				},
-			},
-			"token3": {
-				{
-					start: 3,
-					end:   3,
-				},
-				{
-					start: 9,
-					end:   9,
-				},
-			},
-			"token4": {
-				{
-					start: 11,
-					end:   12,
-				},
-			},
-			"token5": {
-				{
-					start: 10,
-					end:   10,
-				},
-				{
-					start: 100,
-					end:   102,
-				},
-				{
-					start: 111,
-					end:   111,
-				},
-			},
-			"token6": {
-				{
-					start: 4,
-					end:   7,
-				},
+	// the nonces counted in the bulks should match the unique nonces
+	tokens := sortTokenIntervalsByMaxConsecutiveNonces(map[string][]*intervals.Interval{
+		"token1": intervals.GetIntervals([]uint64{10, 10, 11}),
+	})
+	bulks := groupTokenIntervalsInBulks(tokens, 2)
+	require.Equal(t, [][]*tokenData{
+		{
+			{
+				tokenID:   "token1",
+				intervals: []*intervals.Interval{{Start: 10, End: 11}},
 			},
 		},
-	)
+	}, bulks)
 }
 
 func TestSortTokenIntervalsByMaxConsecutiveNonces(t *testing.T) {
-	tokensIntervals := map[string][]*interval{
+	tokensIntervals := map[string][]*intervals.Interval{
 		"token1": {
 			{
-				start: 0,
-				end:   0,
+				Start: 0,
+				End:   0,
 			},
 			{
-				start: 1,
-				end:   1,
+				Start: 1,
+				End:   1,
 			},
 			{
-				start: 2,
-				end:   3,
+				Start: 2,
+				End:   3,
 			},
 			{
-				start: 4,
-				end:   8,
+				Start: 4,
+				End:   8,
 			},
 		},
 		"token2": {
 			{
-				start: 1,
-				end:   5,
+				Start: 1,
+				End:   5,
 			},
 		},
 		"token3": {
 			{
-				start: 0,
-				end:   0,
+				Start: 0,
+				End:   0,
 			},
 			{
-				start: 1,
-				end:   4,
+				Start: 1,
+				End:   4,
 			},
 			{
-				start: 6,
-				end:   7,
+				Start: 6,
+				End:   7,
 			},
 		},
 	}
@@ -178,58 +128,58 @@ func TestSortTokenIntervalsByMaxConsecutiveNonces(t *testing.T) {
 	expectedOutput := []*tokenWithInterval{
 		{
 			tokenID: "token1",
-			interval: &interval{
-				start: 4,
-				end:   8,
+			interval: &intervals.Interval{
+				Start: 4,
+				End:   8,
 			},
 		},
 		{
 			tokenID: "token2",
-			interval: &interval{
-				start: 1,
-				end:   5,
+			interval: &intervals.Interval{
+				Start: 1,
+				End:   5,
 			},
 		},
 		{
 			tokenID: "token3",
-			interval: &interval{
-				start: 1,
-				end:   4,
+			interval: &intervals.Interval{
+				Start: 1,
+				End:   4,
 			},
 		},
 		{
 			tokenID: "token1",
-			interval: &interval{
-				start: 2,
-				end:   3,
+			interval: &intervals.Interval{
+				Start: 2,
+				End:   3,
 			},
 		},
 		{
 			tokenID: "token3",
-			interval: &interval{
-				start: 6,
-				end:   7,
+			interval: &intervals.Interval{
+				Start: 6,
+				End:   7,
 			},
 		},
 		{
 			tokenID: "token1",
-			interval: &interval{
-				start: 0,
-				end:   0,
+			interval: &intervals.Interval{
+				Start: 0,
+				End:   0,
 			},
 		},
 		{
 			tokenID: "token1",
-			interval: &interval{
-				start: 1,
-				end:   1,
+			interval: &intervals.Interval{
+				Start: 1,
+				End:   1,
 			},
 		},
 		{
 			tokenID: "token3",
-			interval: &interval{
-				start: 0,
-				end:   0,
+			interval: &intervals.Interval{
+				Start: 0,
+				End:   0,
 			},
 		},
 	}
@@ -241,58 +191,58 @@ func TestGroupTokenIntervalsInBulks(t *testing.T) {
 	tokensIntervals := []*tokenWithInterval{
 		{
 			tokenID: "token1",
-			interval: &interval{
-				start: 4,
-				end:   8,
+			interval: &intervals.Interval{
+				Start: 4,
+				End:   8,
 			},
 		},
 		{
 			tokenID: "token2",
-			interval: &interval{
-				start: 1,
-				end:   5,
+			interval: &intervals.Interval{
+				Start: 1,
+				End:   5,
 			},
 		},
 		{
 			tokenID: "token3",
-			interval: &interval{
-				start: 1,
-				end:   4,
+			interval: &intervals.Interval{
+				Start: 1,
+				End:   4,
 			},
 		},
 		{
 			tokenID: "token1",
-			interval: &interval{
-				start: 2,
-				end:   3,
+			interval: &intervals.Interval{
+				Start: 2,
+				End:   3,
 			},
 		},
 		{
 			tokenID: "token3",
-			interval: &interval{
-				start: 6,
-				end:   7,
+			interval: &intervals.Interval{
+				Start: 6,
+				End:   7,
 			},
 		},
 		{
 			tokenID: "token1",
-			interval: &interval{
-				start: 0,
-				end:   0,
+			interval: &intervals.Interval{
+				Start: 0,
+				End:   0,
 			},
 		},
 		{
 			tokenID: "token1",
-			interval: &interval{
-				start: 1,
-				end:   1,
+			interval: &intervals.Interval{
+				Start: 1,
+				End:   1,
 			},
 		},
 		{
 			tokenID: "token3",
-			interval: &interval{
-				start: 0,
-				end:   0,
+			interval: &intervals.Interval{
+				Start: 0,
+				End:   0,
 			},
 		},
 	}
@@ -301,19 +251,19 @@ func TestGroupTokenIntervalsInBulks(t *testing.T) {
 	bulk1 := []*tokenData{
 		{
 			tokenID: "token1",
-			intervals: []*interval{
+			intervals: []*intervals.Interval{
 				{
-					start: 4,
-					end:   8,
+					Start: 4,
+					End:   8,
 				},
 			},
 		},
 		{
 			tokenID: "token2",
-			intervals: []*interval{
+			intervals: []*intervals.Interval{
 				{
-					start: 1,
-					end:   1,
+					Start: 1,
+					End:   1,
 				},
 			},
 		},
@@ -321,19 +271,19 @@ func TestGroupTokenIntervalsInBulks(t *testing.T) {
 	bulk2 := []*tokenData{
 		{
 			tokenID: "token2",
-			intervals: []*interval{
+			intervals: []*intervals.Interval{
 				{
-					start: 2,
-					end:   5,
+					Start: 2,
+					End:   5,
 				},
 			},
 		},
 		{
 			tokenID: "token3",
-			intervals: []*interval{
+			intervals: []*intervals.Interval{
 				{
-					start: 1,
-					end:   2,
+					Start: 1,
+					End:   2,
 				},
 			},
 		},
@@ -341,23 +291,23 @@ func TestGroupTokenIntervalsInBulks(t *testing.T) {
 	bulk3 := []*tokenData{
 		{
 			tokenID: "token1",
-			intervals: []*interval{
+			intervals: []*intervals.Interval{
 				{
-					start: 2,
-					end:   3,
+					Start: 2,
+					End:   3,
 				},
 			},
 		},
 		{
 			tokenID: "token3",
-			intervals: []*interval{
+			intervals: []*intervals.Interval{
 				{
-					start: 3,
-					end:   4,
+					Start: 3,
+					End:   4,
 				},
 				{
-					start: 6,
-					end:   7,
+					Start: 6,
+					End:   7,
 				},
 			},
 		},
@@ -365,23 +315,23 @@ func TestGroupTokenIntervalsInBulks(t *testing.T) {
 	bulk4 := []*tokenData{
 		{
 			tokenID: "token1",
-			intervals: []*interval{
+			intervals: []*intervals.Interval{
 				{
-					start: 0,
-					end:   0,
+					Start: 0,
+					End:   0,
 				},
 				{
-					start: 1,
-					end:   1,
+					Start: 1,
+					End:   1,
 				},
 			},
 		},
 		{
 			tokenID: "token3",
-			intervals: []*interval{
+			intervals: []*intervals.Interval{
 				{
-					start: 0,
-					end:   0,
+					Start: 0,
+					End:   0,
 				},
 			},
 		},
@@ -1,10 +1,21 @@
 package main
 
 import (
+	"bytes"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"strings"
+)
+
+const (
+	minTickerLen      = 3
+	maxTickerLen      = 10
+	randomSequenceLen = 6
 )
 
 func readTokensInput(tokensFile string) (map[uint32]map[string]struct{}, error) {
@@ -30,6 +41,11 @@ func readTokensInput(tokensFile string) (map[uint32]map[string]struct{}, error)
 		return nil, err
 	}
 
+	err = checkTokensInput(bytesFromJson)
+	if err != nil {
+		return nil, fmt.Errorf("%w in file %s", err, tokensFile)
+	}
+
 	log.Info("read from input", "file", tokensFile, "num of shards", len(shardTokensMap), getNumTokens(shardTokensMap))
 	return shardTokensMap, nil
 }
@@ -42,3 +58,97 @@ func getNumTokens(shardTokensMap map[uint32]map[string]struct{}) int {
 
 	return numTokensInShard
 }
+
+// checkTokensInput checks all the token identifiers found in the tokens input, which is expected to be a valid json
+// of type map[shardID]map[token]struct{}. All the invalid tokens are reported at once, along with their line numbers
+func checkTokensInput(input []byte) error {
+	decoder := json.NewDecoder(bytes.NewReader(input))
+	invalidTokens := make([]string, 0)
+
+	// the structure is already checked by json.Unmarshal, so only the keys of the inner objects have to be inspected
+	depth := 0
+	for {
+		jsonToken, err := decoder.Token()
+		if err != nil {
+			break
+		}
+
+		switch jsonToken {
+		case json.Delim('{'):
+			depth++
+			continue
+		case json.Delim('}'):
+			depth--
+			continue
+		}
+
+		token, isString := jsonToken.(string)
+		if !isString || depth != 2 {
+			continue
+		}
+
+		err = checkTokenIdentifier(token)
+		if err != nil {
+			line := bytes.Count(input[:decoder.InputOffset()], []byte("\n")) + 1
+			invalidTokens = append(invalidTokens, fmt.Sprintf("line %d: %s (%s)", line, token, err.Error()))
+		}
+	}
+
+	if len(invalidTokens) == 0 {
+		return nil
+	}
+
+	return fmt.Errorf("%w: found %d invalid tokens: %s", errInvalidTokenFormat, len(invalidTokens), strings.Join(invalidTokens, "; "))
+}
+
+// checkTokenIdentifier checks that the token is of type TICKER-xxxxxx-nonce, where TICKER has 3 to 10 uppercase
+// alphanumeric characters, xxxxxx are 6 lowercase hex characters and nonce is a non-zero, hex encoded, number
+// with an even number of digits
+func checkTokenIdentifier(token string) error {
+	splits := strings.Split(token, "-")
+	if len(splits) != 3 {
+		return errors.New("expected format = [ticker-randSequence-nonce]")
+	}
+
+	ticker, randomSequence, nonce := splits[0], splits[1], splits[2]
+	if len(ticker) < minTickerLen || len(ticker) > maxTickerLen || !isUpperAlphanumeric(ticker) {
+		return fmt.Errorf("invalid ticker, expected %d to %d uppercase alphanumeric characters", minTickerLen, maxTickerLen)
+	}
+	if len(randomSequence) != randomSequenceLen || !isLowerHex(randomSequence) {
+		return fmt.Errorf("invalid random sequence, expected %d lowercase hex characters", randomSequenceLen)
+	}
+
+	nonceBytes, err := hex.DecodeString(nonce)
+	if err != nil || len(nonceBytes) == 0 {
+		return errors.New("invalid nonce, expected an even number of hex characters")
+	}
+	if len(bytes.Trim(nonceBytes, "\x00")) == 0 {
+		return errors.New("invalid nonce, expected a non-zero nonce")
+	}
+
+	return nil
+}
+
+func isUpperAlphanumeric(str string) bool {
+	for _, c := range str {
+		isUpper := c >= 'A' && c <= 'Z'
+		isDigit := c >= '0' && c <= '9'
+		if !isUpper && !isDigit {
+			return false
+		}
+	}
+
+	return true
+}
+
+func isLowerHex(str string) bool {
+	for _, c := range str {
+		isLowerLetter := c >= 'a' && c <= 'f'
+		isDigit := c >= '0' && c <= '9'
+		if !isLowerLetter && !isDigit {
+			return false
+		}
+	}
+
+	return true
+}
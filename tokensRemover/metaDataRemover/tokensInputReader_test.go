@@ -1,6 +1,7 @@
 package main
 
 import (
+	"io/ioutil"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -27,3 +28,60 @@ func TestReadTokensInput(t *testing.T) {
 	}
 	require.Equal(t, expectedMap, tokensMap)
 }
+
+func TestCheckTokenIdentifier(t *testing.T) {
+	t.Parallel()
+
+	require.Nil(t, checkTokenIdentifier("ZZZ0-c5aa13-01"))
+	require.Nil(t, checkTokenIdentifier("ABCDEFGHIJ-c5aa13-0a1f"))
+
+	invalidTokens := []string{
+		"ZZZ0-c5aa13",
+		"ZZZ0-c5aa13-01-01",
+		"ZZ-c5aa13-01",
+		"ABCDEFGHIJK-c5aa13-01",
+		"zzz0-c5aa13-01",
+		"ZZZ0-c5aa1-01",
+		"ZZZ0-C5AA13-01",
+		"ZZZ0-c5aa1g-01",
+		"ZZZ0-c5aa13-",
+		"ZZZ0-c5aa13-1",
+		"ZZZ0-c5aa13-0x",
+		"ZZZ0-c5aa13-00",
+	}
+	for _, token := range invalidTokens {
+		require.Error(t, checkTokenIdentifier(token), token)
+	}
+}
+
+func TestCheckTokensInput(t *testing.T) {
+	t.Parallel()
+
+	t.Run("valid tokens, should work", func(t *testing.T) {
+		t.Parallel()
+
+		input, err := ioutil.ReadFile("tokensTestData/tokens.json")
+		require.Nil(t, err)
+		require.Nil(t, checkTokensInput(input))
+	})
+	t.Run("invalid tokens, should report all of them with line numbers", func(t *testing.T) {
+		t.Parallel()
+
+		input := []byte(`{
+ "0": {
+  "ZZZ0-c5aa13-01": {},
+  "ZZZ1-c5aa1301": {}
+ },
+ "1": {
+  "AAA0-f1fac9-1": {},
+  "AAA0-f1fac9-03": {}
+ }
+}`)
+		err := checkTokensInput(input)
+		require.ErrorIs(t, err, errInvalidTokenFormat)
+		require.Contains(t, err.Error(), "found 2 invalid tokens")
+		require.Contains(t, err.Error(), "line 4: ZZZ1-c5aa1301")
+		require.Contains(t, err.Error(), "line 7: AAA0-f1fac9-1")
+		require.NotContains(t, err.Error(), "AAA0-f1fac9-03")
+	})
+}
@@ -4,14 +4,14 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"io/fs"
-	"io/ioutil"
 	"os"
 	"strconv"
 	"time"
 
 	"github.com/multiversx/mx-chain-crypto-go/signing"
 	"github.com/multiversx/mx-chain-crypto-go/signing/ed25519"
+	"github.com/multiversx/mx-chain-tools-go/tokensRemover/exitcodes"
+	"github.com/multiversx/mx-chain-tools-go/tokensRemover/intervals"
 	"github.com/multiversx/mx-chain-tools-go/tokensRemover/metaDataRemover/config"
 	"github.com/multiversx/mx-sdk-go/blockchain"
 	"github.com/multiversx/mx-sdk-go/blockchain/cryptoProvider"
@@ -21,24 +21,34 @@ import (
 	"github.com/multiversx/mx-sdk-go/interactors"
 )
 
-func createShardTxs(
-	outFile string,
-	cfg *config.Config,
-	shardPemsDataMap map[uint32]*skAddress,
-	shardTxsDataMap map[uint32][][]byte,
-) error {
-	if len(shardPemsDataMap) != len(shardTxsDataMap) {
-		return fmt.Errorf("provided invalid input; expected number of pem files = number of shards in tokens input; got num shard tokens = %d, num pem files = %d",
-			len(shardPemsDataMap), len(shardTxsDataMap))
+type argsCreateShardTxs struct {
+	outFile              string
+	cfg                  *config.Config
+	shardPemsDataMap     map[uint32][]*skAddress
+	shardTxsDataMap      map[uint32][][]byte
+	shardTokensIntervals map[uint32]map[string][]*intervals.Interval
+	startNonces          map[uint32]uint64
+	dryRun               bool
+	broadcast            bool
+	txsPerSecond         uint64
+	retryFile            string
+	outputFilePerms      os.FileMode
+	summaryOut           string
+}
+
+func createShardTxs(args argsCreateShardTxs) error {
+	if len(args.shardPemsDataMap) != len(args.shardTxsDataMap) {
+		return exitcodes.NewUsageError(fmt.Errorf("provided invalid input; expected number of pem files = number of shards in tokens input; got num shard tokens = %d, num pem files = %d",
+			len(args.shardPemsDataMap), len(args.shardTxsDataMap)))
 	}
 
-	args := blockchain.ArgsProxy{
-		ProxyURL:            cfg.ProxyUrl,
+	argsProxy := blockchain.ArgsProxy{
+		ProxyURL:            args.cfg.ProxyUrl,
 		CacheExpirationTime: time.Minute,
 		EntityType:          core.Proxy,
 	}
 
-	proxy, err := blockchain.NewProxy(args)
+	proxy, err := blockchain.NewProxy(argsProxy)
 	if err != nil {
 		return err
 	}
@@ -53,85 +63,278 @@ func createShardTxs(
 		return err
 	}
 
-	txc, err := newTxCreator(proxy, ti)
+	txc, err := newTxCreator(proxy, ti, gasConfig{
+		minGasLimit:    args.cfg.MinGasLimit,
+		gasPerDataByte: args.cfg.GasPerDataByte,
+		maxGasLimit:    args.cfg.MaxGasLimitPerTransaction,
+	})
 	if err != nil {
 		return err
 	}
 
-	err = createOutputFileIfDoesNotExist(outFile)
+	err = txc.checkGasLimits(args.shardTxsDataMap, args.cfg.AdditionalGasLimit)
 	if err != nil {
-		return err
+		return exitcodes.NewUsageError(err)
 	}
 
-	for shardID, txsData := range shardTxsDataMap {
-		pemData, found := shardPemsDataMap[shardID]
+	var broadcaster *txsBroadcaster
+	switch {
+	case args.dryRun:
+	case args.broadcast:
+		broadcaster, err = newTxsBroadcaster(proxy, args.txsPerSecond)
+		if err != nil {
+			return err
+		}
+	default:
+		err = createOutputFileIfDoesNotExist(args.outFile)
+		if err != nil {
+			return err
+		}
+	}
+
+	failedTxs := make([]*data.Transaction, 0)
+	for shardID, txsData := range args.shardTxsDataMap {
+		pemsData, found := args.shardPemsDataMap[shardID]
 		if !found {
-			return fmt.Errorf("no pem data provided for shard = %d", shardID)
+			return exitcodes.NewUsageError(fmt.Errorf("no pem data provided for shard = %d", shardID))
 		}
 
-		log.Info("starting to create txs", "shardID", shardID, "num of txs", len(txsData))
-		txsInShard, err := txc.createTxs(pemData, txsData, cfg.AdditionalGasLimit)
+		var startNonce *uint64
+		nonce, hasStartNonce := args.startNonces[shardID]
+		if hasStartNonce {
+			if len(pemsData) > 1 {
+				return exitcodes.NewUsageError(fmt.Errorf("%w: shard = %d, num of senders = %d", errStartNonceWithMultipleSenders, shardID, len(pemsData)))
+			}
+			startNonce = &nonce
+		}
+
+		log.Info("starting to create txs", "shardID", shardID, "num of txs", len(txsData), "num of senders", len(pemsData))
+		txsInShard, err := txc.createTxs(pemsData, txsData, args.cfg.AdditionalGasLimit, startNonce)
+		if err != nil {
+			return err
+		}
+
+		if args.dryRun {
+			logDryRunTxs(shardID, txsInShard)
+			continue
+		}
+
+		if broadcaster != nil {
+			log.Info("broadcasting txs", "shardID", shardID, "num of txs", len(txsInShard))
+			failedTxs = append(failedTxs, broadcaster.broadcast(txsInShard)...)
+			continue
+		}
+
+		err = saveShardTxs(txsInShard, args.outFile, shardID, args.cfg.MaxTransactionsPerShard, args.outputFilePerms)
 		if err != nil {
 			return err
 		}
+	}
+
+	summary := createTxsSummary(args, len(failedTxs))
+	logTxsSummary(summary)
 
-		file := outFile + "/txsShard" + strconv.Itoa(int(shardID)) + ".json"
+	if len(failedTxs) > 0 {
+		log.Warn("some txs could not be broadcast", "num of failed txs", len(failedTxs), "retry file", args.retryFile)
+		err = saveResult(failedTxs, args.retryFile, args.outputFilePerms)
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(args.summaryOut) == 0 {
+		return nil
+	}
+
+	return saveSummary(summary, args.summaryOut, args.outputFilePerms)
+}
+
+// saveShardTxs saves the txs of a shard in txsShard<shardID>.json or, if there are more than maxTxsPerFile txs,
+// in multiple numbered files, txsShard<shardID>_<index>.json, starting from 1
+func saveShardTxs(txs []*data.Transaction, outFile string, shardID uint32, maxTxsPerFile uint64, perms os.FileMode) error {
+	filePrefix := outFile + "/txsShard" + strconv.Itoa(int(shardID))
+	chunks := splitTxs(txs, maxTxsPerFile)
+	if len(chunks) == 1 {
+		file := filePrefix + ".json"
 		log.Info("saving txs", "shardID", shardID, "file", file)
-		err = saveResult(txsInShard, file)
+		return saveResult(txs, file, perms)
+	}
+
+	for idx, chunk := range chunks {
+		file := filePrefix + "_" + strconv.Itoa(idx+1) + ".json"
+		log.Info("saving txs", "shardID", shardID, "file", file, "num of txs", len(chunk))
+		err := saveResult(chunk, file, perms)
+		if err != nil {
+			return err
+		}
 	}
 
 	return nil
 }
 
+// splitTxs splits the txs in chunks of at most maxTxsPerChunk txs, keeping their order. If maxTxsPerChunk is 0,
+// a single chunk is returned
+func splitTxs(txs []*data.Transaction, maxTxsPerChunk uint64) [][]*data.Transaction {
+	if maxTxsPerChunk == 0 || uint64(len(txs)) <= maxTxsPerChunk {
+		return [][]*data.Transaction{txs}
+	}
+
+	chunks := make([][]*data.Transaction, 0, (uint64(len(txs))+maxTxsPerChunk-1)/maxTxsPerChunk)
+	for start := uint64(0); start < uint64(len(txs)); start += maxTxsPerChunk {
+		end := start + maxTxsPerChunk
+		if end > uint64(len(txs)) {
+			end = uint64(len(txs))
+		}
+		chunks = append(chunks, txs[start:end])
+	}
+
+	return chunks
+}
+
+func logDryRunTxs(shardID uint32, txs []*data.Transaction) {
+	log.Info("dry run: created txs", "shardID", shardID, "num of txs", len(txs))
+	for _, tx := range getSampleTxs(txs, numDryRunSampleTxs) {
+		log.Info("dry run: sample tx", "shardID", shardID, "nonce", tx.Nonce, "gas limit", tx.GasLimit, "data", string(tx.Data))
+	}
+}
+
+func getSampleTxs(txs []*data.Transaction, numSamples int) []*data.Transaction {
+	if len(txs) <= numSamples {
+		return txs
+	}
+
+	return txs[:numSamples]
+}
+
+// gasConfig holds the costs used to compute the gas limit of a tx: minGasLimit + gasPerDataByte * len(data)
+type gasConfig struct {
+	minGasLimit    uint64
+	gasPerDataByte uint64
+	maxGasLimit    uint64
+}
+
 type txCreator struct {
 	proxy         proxyProvider
 	txInteractor  transactionInteractor
 	networkConfig *data.NetworkConfig
+	gas           gasConfig
 }
 
 // no need to check for nil pointers since this is unexported and only used internally
-func newTxCreator(proxy proxyProvider, txInteractor transactionInteractor) (*txCreator, error) {
+func newTxCreator(proxy proxyProvider, txInteractor transactionInteractor, gasOverrides gasConfig) (*txCreator, error) {
 	netConfigs, err := proxy.GetNetworkConfig(context.Background())
 	if err != nil {
 		return nil, err
 	}
 
+	gas := gasConfig{
+		minGasLimit:    netConfigs.MinGasLimit,
+		gasPerDataByte: netConfigs.GasPerDataByte,
+		maxGasLimit:    defaultMaxGasLimitPerTransaction,
+	}
+	if gasOverrides.minGasLimit > 0 {
+		gas.minGasLimit = gasOverrides.minGasLimit
+	}
+	if gasOverrides.gasPerDataByte > 0 {
+		gas.gasPerDataByte = gasOverrides.gasPerDataByte
+	}
+	if gasOverrides.maxGasLimit > 0 {
+		gas.maxGasLimit = gasOverrides.maxGasLimit
+	}
+	log.Debug("gas config", "min gas limit", gas.minGasLimit, "gas per data byte", gas.gasPerDataByte,
+		"max gas limit per tx", gas.maxGasLimit)
+
 	return &txCreator{
 		proxy:         proxy,
 		txInteractor:  txInteractor,
 		networkConfig: netConfigs,
+		gas:           gas,
 	}, nil
 }
 
+// checkGasLimits errors if the gas limit of a tx to be created exceeds the max gas limit per tx, before any tx is
+// signed. The gas limit grows with the tx data, so the largest tx data of each shard is the one checked
+func (tc *txCreator) checkGasLimits(shardTxsDataMap map[uint32][][]byte, additionalGasLimit uint64) error {
+	for shardID, txsData := range shardTxsDataMap {
+		maxDataLen := 0
+		for _, txData := range txsData {
+			if len(txData) > maxDataLen {
+				maxDataLen = len(txData)
+			}
+		}
+
+		gasLimit := tc.computeGasLimit(uint64(maxDataLen)) + additionalGasLimit
+		if gasLimit > tc.gas.maxGasLimit {
+			return fmt.Errorf("%w: shard %d has a tx with %d data bytes, which needs a gas limit of %d, while the max gas limit per tx is %d; "+
+				"try a smaller TokensToDeletePerTransaction", errGasLimitTooHigh, shardID, maxDataLen, gasLimit, tc.gas.maxGasLimit)
+		}
+	}
+
+	return nil
+}
+
+type txSender struct {
+	args   *data.ArgCreateTransaction
+	holder core.CryptoComponentsHolder
+}
+
+// createTxs creates the txs for the provided txs data, assigned round-robin to the senders. Each sender uses its own
+// nonce, starting from the account nonce. The start nonce, if provided, overrides the nonce of the first sender
+// (createShardTxs only accepts a start nonce for the shards with a single sender)
 func (tc *txCreator) createTxs(
-	pemData *skAddress,
+	pemsData []*skAddress,
 	txsData [][]byte,
 	additionalGasLimit uint64,
+	startNonce *uint64,
 ) ([]*data.Transaction, error) {
-	transactionArguments, err := tc.getDefaultTxsArgs(pemData.address)
-	if err != nil {
-		return nil, err
+	senders := make([]*txSender, 0, len(pemsData))
+	for _, pemData := range pemsData {
+		sender, err := tc.createTxSender(pemData)
+		if err != nil {
+			return nil, err
+		}
+
+		senders = append(senders, sender)
+	}
+	if startNonce != nil && len(senders) > 0 {
+		log.Info("overriding the sender nonce", "address", senders[0].args.SndAddr,
+			"proxy nonce", senders[0].args.Nonce, "start nonce", *startNonce)
+		senders[0].args.Nonce = *startNonce
 	}
 
-	suite := ed25519.NewEd25519()
-	keyGen := signing.NewKeyGenerator(suite)
-	holder, _ := cryptoProvider.NewCryptoComponentsHolder(keyGen, pemData.secretKey)
 	txs := make([]*data.Transaction, 0, len(txsData))
-	for _, txData := range txsData {
-		transactionArguments.Data = txData
-		transactionArguments.GasLimit = tc.computeGasLimit(uint64(len(txData))) + additionalGasLimit
-		tx, err := tc.txInteractor.ApplySignatureAndGenerateTx(holder, *transactionArguments)
+	for idx, txData := range txsData {
+		sender := senders[idx%len(senders)]
+		sender.args.Data = txData
+		sender.args.GasLimit = tc.computeGasLimit(uint64(len(txData))) + additionalGasLimit
+		tx, err := tc.txInteractor.ApplySignatureAndGenerateTx(sender.holder, *sender.args)
 		if err != nil {
 			return nil, err
 		}
 
 		txs = append(txs, tx)
-		transactionArguments.Nonce++
+		sender.args.Nonce++
 	}
 
 	return txs, nil
 }
 
+func (tc *txCreator) createTxSender(pemData *skAddress) (*txSender, error) {
+	transactionArguments, err := tc.getDefaultTxsArgs(pemData.address)
+	if err != nil {
+		return nil, err
+	}
+
+	suite := ed25519.NewEd25519()
+	keyGen := signing.NewKeyGenerator(suite)
+	holder, _ := cryptoProvider.NewCryptoComponentsHolder(keyGen, pemData.secretKey)
+
+	return &txSender{
+		args:   transactionArguments,
+		holder: holder,
+	}, nil
+}
+
 func (tc *txCreator) getDefaultTxsArgs(address core.AddressHandler) (*data.ArgCreateTransaction, error) {
 	transactionArguments, err := tc.proxy.GetDefaultTransactionArguments(context.Background(), address, tc.networkConfig)
 	if err != nil {
@@ -145,7 +348,7 @@ func (tc *txCreator) getDefaultTxsArgs(address core.AddressHandler) (*data.ArgCr
 }
 
 func (tc *txCreator) computeGasLimit(dataLen uint64) uint64 {
-	return tc.networkConfig.MinGasLimit + tc.networkConfig.GasPerDataByte*dataLen
+	return tc.gas.minGasLimit + tc.gas.gasPerDataByte*dataLen
 }
 
 func createOutputFileIfDoesNotExist(outFile string) error {
@@ -161,14 +364,14 @@ func createOutputFileIfDoesNotExist(outFile string) error {
 	return nil
 }
 
-func saveResult(txs []*data.Transaction, outfile string) error {
+func saveResult(txs []*data.Transaction, outfile string, perms os.FileMode) error {
 	jsonBytes, err := json.MarshalIndent(txs, "", " ")
 	if err != nil {
 		return err
 	}
 
 	log.Info("writing result in", "file", outfile)
-	err = ioutil.WriteFile(outfile, jsonBytes, fs.FileMode(outputFilePerms))
+	err = writeOutputFile(outfile, jsonBytes, perms)
 	if err != nil {
 		return err
 	}
@@ -3,6 +3,9 @@ package main
 import (
 	"context"
 	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
 	"testing"
 
 	"github.com/multiversx/mx-chain-tools-go/tokensRemover/metaDataRemover/mocks"
@@ -88,9 +91,215 @@ func TestTxCreator_CreateTxs(t *testing.T) {
 		},
 	}
 
-	txc, err := newTxCreator(proxy, txInteractor)
+	txc, err := newTxCreator(proxy, txInteractor, gasConfig{})
 	require.Nil(t, err)
-	signedTxs, err := txc.createTxs(pemData, txsData, additionalGas)
+	signedTxs, err := txc.createTxs([]*skAddress{pemData}, txsData, additionalGas, nil)
 	require.Nil(t, err)
 	require.Equal(t, signedTxs, txs)
 }
+
+func TestGetSampleTxs(t *testing.T) {
+	t.Parallel()
+
+	txs := []*data.Transaction{{Nonce: 0}, {Nonce: 1}, {Nonce: 2}, {Nonce: 3}}
+	require.Equal(t, txs[:3], getSampleTxs(txs, 3))
+	require.Equal(t, txs[:2], getSampleTxs(txs[:2], 3))
+	require.Empty(t, getSampleTxs(nil, 3))
+}
+
+func TestSplitTxs(t *testing.T) {
+	t.Parallel()
+
+	txs := []*data.Transaction{{Nonce: 0}, {Nonce: 1}, {Nonce: 2}, {Nonce: 3}, {Nonce: 4}}
+	require.Equal(t, [][]*data.Transaction{txs}, splitTxs(txs, 0))
+	require.Equal(t, [][]*data.Transaction{txs}, splitTxs(txs, 5))
+	require.Equal(t, [][]*data.Transaction{txs[:2], txs[2:4], txs[4:]}, splitTxs(txs, 2))
+	require.Equal(t, [][]*data.Transaction{txs[:1], txs[1:2], txs[2:3], txs[3:4], txs[4:]}, splitTxs(txs, 1))
+}
+
+func TestSaveShardTxs(t *testing.T) {
+	t.Parallel()
+
+	readTxs := func(file string) []*data.Transaction {
+		jsonBytes, err := ioutil.ReadFile(file)
+		require.Nil(t, err)
+
+		var txs []*data.Transaction
+		err = json.Unmarshal(jsonBytes, &txs)
+		require.Nil(t, err)
+
+		return txs
+	}
+
+	txs := []*data.Transaction{{Nonce: 0}, {Nonce: 1}, {Nonce: 2}, {Nonce: 3}, {Nonce: 4}}
+
+	t.Run("below max txs, should save a single file", func(t *testing.T) {
+		t.Parallel()
+
+		outDir := t.TempDir()
+		err := saveShardTxs(txs, outDir, 1, 5, 0600)
+		require.Nil(t, err)
+
+		require.Equal(t, txs, readTxs(filepath.Join(outDir, "txsShard1.json")))
+		require.NoFileExists(t, filepath.Join(outDir, "txsShard1_1.json"))
+	})
+	t.Run("above max txs, should split in numbered files", func(t *testing.T) {
+		t.Parallel()
+
+		outDir := t.TempDir()
+		err := saveShardTxs(txs, outDir, 1, 2, 0600)
+		require.Nil(t, err)
+
+		require.NoFileExists(t, filepath.Join(outDir, "txsShard1.json"))
+		require.Equal(t, txs[:2], readTxs(filepath.Join(outDir, "txsShard1_1.json")))
+		require.Equal(t, txs[2:4], readTxs(filepath.Join(outDir, "txsShard1_2.json")))
+		require.Equal(t, txs[4:], readTxs(filepath.Join(outDir, "txsShard1_3.json")))
+		require.NoFileExists(t, filepath.Join(outDir, "txsShard1_4.json"))
+	})
+}
+
+func TestTxCreator_CreateTxsWithStartNonce(t *testing.T) {
+	t.Parallel()
+
+	addr, err := data.NewAddressFromBech32String("erd1qyu5wthldzr8wx5c9ucg8kjagg0jfs53s8nr3zpz3hypefsdd8ssycr6th")
+	require.Nil(t, err)
+	sk, err := hex.DecodeString("413f42575f7f26fad3317a778771212fdb80245850981e48b58a4f25e344e8f9")
+	require.Nil(t, err)
+	pemData := &skAddress{
+		secretKey: sk,
+		address:   addr,
+	}
+
+	proxy := &mocks.ProxyStub{
+		GetNetworkConfigCalled: func(ctx context.Context) (*data.NetworkConfig, error) {
+			return &data.NetworkConfig{}, nil
+		},
+		GetDefaultTransactionArgumentsCalled: func(ctx context.Context, address core.AddressHandler, networkConfigs *data.NetworkConfig) (data.ArgCreateTransaction, error) {
+			return data.ArgCreateTransaction{Nonce: 4}, nil
+		},
+	}
+
+	txInteractor := &mocks.TransactionInteractorStub{
+		ApplySignatureAndGenerateTxCalled: func(cryptoHolder core.CryptoComponentsHolder, arg data.ArgCreateTransaction) (*data.Transaction, error) {
+			return &data.Transaction{Nonce: arg.Nonce}, nil
+		},
+	}
+
+	txc, err := newTxCreator(proxy, txInteractor, gasConfig{})
+	require.Nil(t, err)
+
+	startNonce := uint64(10)
+	signedTxs, err := txc.createTxs([]*skAddress{pemData}, [][]byte{[]byte("txData1"), []byte("txData2")}, 0, &startNonce)
+	require.Nil(t, err)
+	require.Equal(t, []*data.Transaction{{Nonce: 10}, {Nonce: 11}}, signedTxs)
+}
+
+func TestTxCreator_CreateTxsWithMultipleSenders(t *testing.T) {
+	t.Parallel()
+
+	addr1, err := data.NewAddressFromBech32String("erd1qyu5wthldzr8wx5c9ucg8kjagg0jfs53s8nr3zpz3hypefsdd8ssycr6th")
+	require.Nil(t, err)
+	sk1, err := hex.DecodeString("413f42575f7f26fad3317a778771212fdb80245850981e48b58a4f25e344e8f9")
+	require.Nil(t, err)
+	addr2, err := data.NewAddressFromBech32String("erd1k2s324ww2g0yj38qn2ch2jwctdy8mnfxep94q9arncc6xecg3xaq6mjse8")
+	require.Nil(t, err)
+	sk2, err := hex.DecodeString("e253a571ca153dc2aee845819f74bcc9773b0586edead15a94cb7235a5027436")
+	require.Nil(t, err)
+	pemsData := []*skAddress{
+		{secretKey: sk1, address: addr1},
+		{secretKey: sk2, address: addr2},
+	}
+
+	accountNonces := map[string]uint64{
+		addr1.AddressAsBech32String(): 4,
+		addr2.AddressAsBech32String(): 20,
+	}
+	proxy := &mocks.ProxyStub{
+		GetNetworkConfigCalled: func(ctx context.Context) (*data.NetworkConfig, error) {
+			return &data.NetworkConfig{}, nil
+		},
+		GetDefaultTransactionArgumentsCalled: func(ctx context.Context, address core.AddressHandler, networkConfigs *data.NetworkConfig) (data.ArgCreateTransaction, error) {
+			return data.ArgCreateTransaction{
+				Nonce:   accountNonces[address.AddressAsBech32String()],
+				SndAddr: address.AddressAsBech32String(),
+			}, nil
+		},
+	}
+
+	txInteractor := &mocks.TransactionInteractorStub{
+		ApplySignatureAndGenerateTxCalled: func(cryptoHolder core.CryptoComponentsHolder, arg data.ArgCreateTransaction) (*data.Transaction, error) {
+			require.Equal(t, arg.SndAddr, cryptoHolder.GetBech32())
+			require.Equal(t, arg.SndAddr, arg.RcvAddr)
+
+			return &data.Transaction{Nonce: arg.Nonce, SndAddr: arg.SndAddr, Data: arg.Data}, nil
+		},
+	}
+
+	txc, err := newTxCreator(proxy, txInteractor, gasConfig{})
+	require.Nil(t, err)
+
+	txsData := [][]byte{[]byte("txData1"), []byte("txData2"), []byte("txData3"), []byte("txData4"), []byte("txData5")}
+	signedTxs, err := txc.createTxs(pemsData, txsData, 0, nil)
+	require.Nil(t, err)
+
+	sender1 := addr1.AddressAsBech32String()
+	sender2 := addr2.AddressAsBech32String()
+	require.Equal(t, []*data.Transaction{
+		{Nonce: 4, SndAddr: sender1, Data: txsData[0]},
+		{Nonce: 20, SndAddr: sender2, Data: txsData[1]},
+		{Nonce: 5, SndAddr: sender1, Data: txsData[2]},
+		{Nonce: 21, SndAddr: sender2, Data: txsData[3]},
+		{Nonce: 6, SndAddr: sender1, Data: txsData[4]},
+	}, signedTxs)
+}
+
+func TestTxCreator_GasConfig(t *testing.T) {
+	t.Parallel()
+
+	proxy := &mocks.ProxyStub{
+		GetNetworkConfigCalled: func(ctx context.Context) (*data.NetworkConfig, error) {
+			return &data.NetworkConfig{MinGasLimit: 50000, GasPerDataByte: 1500}, nil
+		},
+	}
+
+	t.Run("network config values, should work", func(t *testing.T) {
+		t.Parallel()
+
+		txc, err := newTxCreator(proxy, &mocks.TransactionInteractorStub{}, gasConfig{})
+		require.Nil(t, err)
+		require.Equal(t, uint64(50000+1500*10), txc.computeGasLimit(10))
+		require.Equal(t, uint64(defaultMaxGasLimitPerTransaction), txc.gas.maxGasLimit)
+	})
+	t.Run("overridden values, should work", func(t *testing.T) {
+		t.Parallel()
+
+		txc, err := newTxCreator(proxy, &mocks.TransactionInteractorStub{}, gasConfig{
+			minGasLimit:    1000,
+			gasPerDataByte: 10,
+			maxGasLimit:    5000,
+		})
+		require.Nil(t, err)
+		require.Equal(t, uint64(1000+10*10), txc.computeGasLimit(10))
+		require.Equal(t, uint64(5000), txc.gas.maxGasLimit)
+	})
+	t.Run("gas limit above the max gas limit, should error", func(t *testing.T) {
+		t.Parallel()
+
+		txc, err := newTxCreator(proxy, &mocks.TransactionInteractorStub{}, gasConfig{maxGasLimit: 80000})
+		require.Nil(t, err)
+
+		shardTxsDataMap := map[uint32][][]byte{
+			0: {make([]byte, 10)},
+			1: {make([]byte, 5), make([]byte, 20)},
+		}
+		// 50000 + 1500 * 20 + 100 > 80000
+		err = txc.checkGasLimits(shardTxsDataMap, 100)
+		require.ErrorIs(t, err, errGasLimitTooHigh)
+		require.Contains(t, err.Error(), "shard 1")
+		require.Contains(t, err.Error(), "TokensToDeletePerTransaction")
+
+		// 50000 + 1500 * 20 = 80000
+		err = txc.checkGasLimits(shardTxsDataMap, 0)
+		require.Nil(t, err)
+	})
+}
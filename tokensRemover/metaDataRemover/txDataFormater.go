@@ -3,21 +3,32 @@ package main
 import (
 	"encoding/hex"
 
+	"github.com/multiversx/mx-chain-tools-go/tokensRemover/intervals"
 	"github.com/multiversx/mx-sdk-go/builders"
 )
 
 const esdtDeleteMetadataFunction = "ESDTDeleteMetadata"
 
-func createShardTxsDataMap(shardTokensMap map[uint32]map[string]struct{}, tokensToDeletePerTx uint64) (map[uint32][][]byte, error) {
-	shardTxsDataMap := make(map[uint32][][]byte)
+// createShardTokensIntervals returns, for each shard, the nonces intervals to be deleted for each token
+func createShardTokensIntervals(shardTokensMap map[uint32]map[string]struct{}, mergeGap uint64) (map[uint32]map[string][]*intervals.Interval, error) {
+	shardTokensIntervals := make(map[uint32]map[string][]*intervals.Interval)
 	for shardID, tokens := range shardTokensMap {
-		log.Info("creating txs data", "shardID", shardID, "num tokens", len(tokens))
+		log.Info("computing intervals", "shardID", shardID, "num tokens", len(tokens))
 		tokensSorted, err := sortTokensIDByNonce(tokens)
 		if err != nil {
 			return nil, err
 		}
 
-		tokensIntervals := groupTokensByIntervals(tokensSorted)
+		shardTokensIntervals[shardID] = intervals.MergeCloseIntervals(intervals.GroupTokensByIntervals(tokensSorted), mergeGap)
+	}
+
+	return shardTokensIntervals, nil
+}
+
+func createShardTxsDataMap(shardTokensIntervals map[uint32]map[string][]*intervals.Interval, tokensToDeletePerTx uint64) (map[uint32][][]byte, error) {
+	shardTxsDataMap := make(map[uint32][][]byte)
+	for shardID, tokensIntervals := range shardTokensIntervals {
+		log.Info("creating txs data", "shardID", shardID, "num tokensID", len(tokensIntervals))
 		tokensSortedByNonces := sortTokenIntervalsByMaxConsecutiveNonces(tokensIntervals)
 		tokensInBulks := groupTokenIntervalsInBulks(tokensSortedByNonces, tokensToDeletePerTx)
 
@@ -59,12 +70,12 @@ func tokensBulkAsOnData(bulk []*tokenData) ([]byte, error) {
 	return txDataBuilder.ToDataBytes()
 }
 
-func addIntervalsAsOnData(builder builders.TxDataBuilder, intervals []*interval) {
+func addIntervalsAsOnData(builder builders.TxDataBuilder, intervals []*intervals.Interval) {
 	builder.ArgInt64(int64(len(intervals)))
 
 	for _, interval := range intervals {
 		builder.
-			ArgInt64(int64(interval.start)).
-			ArgInt64(int64(interval.end))
+			ArgInt64(int64(interval.Start)).
+			ArgInt64(int64(interval.End))
 	}
 }
@@ -4,6 +4,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/multiversx/mx-chain-tools-go/tokensRemover/intervals"
 	"github.com/stretchr/testify/require"
 )
 
@@ -19,58 +20,58 @@ func TestCreateTxsData(t *testing.T) {
 	tokensIntervals := []*tokenWithInterval{
 		{
 			tokenID: "token1",
-			interval: &interval{
-				start: 4,
-				end:   8,
+			interval: &intervals.Interval{
+				Start: 4,
+				End:   8,
 			},
 		},
 		{
 			tokenID: "token2",
-			interval: &interval{
-				start: 1,
-				end:   5,
+			interval: &intervals.Interval{
+				Start: 1,
+				End:   5,
 			},
 		},
 		{
 			tokenID: "token3",
-			interval: &interval{
-				start: 1,
-				end:   4,
+			interval: &intervals.Interval{
+				Start: 1,
+				End:   4,
 			},
 		},
 		{
 			tokenID: "token1",
-			interval: &interval{
-				start: 2,
-				end:   3,
+			interval: &intervals.Interval{
+				Start: 2,
+				End:   3,
 			},
 		},
 		{
 			tokenID: "token3",
-			interval: &interval{
-				start: 6,
-				end:   7,
+			interval: &intervals.Interval{
+				Start: 6,
+				End:   7,
 			},
 		},
 		{
 			tokenID: "token1",
-			interval: &interval{
-				start: 0,
-				end:   0,
+			interval: &intervals.Interval{
+				Start: 0,
+				End:   0,
 			},
 		},
 		{
 			tokenID: "token1",
-			interval: &interval{
-				start: 1,
-				end:   1,
+			interval: &intervals.Interval{
+				Start: 1,
+				End:   1,
 			},
 		},
 		{
 			tokenID: "token3",
-			interval: &interval{
-				start: 0,
-				end:   0,
+			interval: &intervals.Interval{
+				Start: 0,
+				End:   0,
 			},
 		},
 	}
@@ -220,7 +221,7 @@ func TestCreateShardTxsDataMap(t *testing.T) {
 			1: tokensShard1,
 		}
 
-		ret, err := createShardTxsDataMap(shardTokensMap, 2)
+		ret, err := createShardTokensIntervals(shardTokensMap, 0)
 		require.Nil(t, ret)
 		require.ErrorIs(t, err, errInvalidTokenFormat)
 		require.True(t, strings.Contains(err.Error(), "token3-r"))
@@ -244,7 +245,9 @@ func TestCreateShardTxsDataMap(t *testing.T) {
 			1: tokensShard1,
 		}
 
-		ret, err := createShardTxsDataMap(shardTokensMap, 2)
+		shardTokensIntervals, err := createShardTokensIntervals(shardTokensMap, 0)
+		require.Nil(t, err)
+		ret, err := createShardTxsDataMap(shardTokensIntervals, 2)
 		require.Nil(t, err)
 		expectedRet := map[uint32][][]byte{
 			0: {
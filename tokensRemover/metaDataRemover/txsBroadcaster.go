@@ -0,0 +1,54 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/multiversx/mx-sdk-go/data"
+)
+
+type txsBroadcaster struct {
+	proxy        txsSenderProxy
+	sendInterval time.Duration
+}
+
+func newTxsBroadcaster(proxy txsSenderProxy, txsPerSecond uint64) (*txsBroadcaster, error) {
+	if proxy == nil {
+		return nil, errNilProxy
+	}
+	if txsPerSecond == 0 {
+		return nil, errInvalidSendRate
+	}
+
+	return &txsBroadcaster{
+		proxy:        proxy,
+		sendInterval: time.Second / time.Duration(txsPerSecond),
+	}, nil
+}
+
+// broadcast sends the provided txs, one every send interval, and returns the txs that could not be sent. A failed
+// tx does not stop the broadcast of the remaining ones
+func (tb *txsBroadcaster) broadcast(txs []*data.Transaction) []*data.Transaction {
+	failedTxs := make([]*data.Transaction, 0)
+	for idx, tx := range txs {
+		if idx > 0 {
+			time.Sleep(tb.sendInterval)
+		}
+
+		hash, err := tb.proxy.SendTransaction(context.Background(), tx)
+		if err != nil {
+			log.Error("failed to send tx", "sender", tx.SndAddr, "sender nonce", tx.Nonce, "error", err)
+			failedTxs = append(failedTxs, tx)
+			continue
+		}
+
+		log.Info("sent transaction",
+			"tx hash", hash,
+			"sender", tx.SndAddr,
+			"sender nonce", tx.Nonce,
+			"num txs sent", idx+1-len(failedTxs),
+			"remaining num of txs", len(txs)-idx-1)
+	}
+
+	return failedTxs
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/multiversx/mx-chain-tools-go/tokensRemover/metaDataRemover/mocks"
+	"github.com/multiversx/mx-sdk-go/data"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewTxsBroadcaster(t *testing.T) {
+	t.Parallel()
+
+	t.Run("nil proxy, should error", func(t *testing.T) {
+		t.Parallel()
+
+		broadcaster, err := newTxsBroadcaster(nil, 1)
+		require.Nil(t, broadcaster)
+		require.Equal(t, errNilProxy, err)
+	})
+	t.Run("zero send rate, should error", func(t *testing.T) {
+		t.Parallel()
+
+		broadcaster, err := newTxsBroadcaster(&mocks.ProxyStub{}, 0)
+		require.Nil(t, broadcaster)
+		require.Equal(t, errInvalidSendRate, err)
+	})
+}
+
+func TestTxsBroadcaster_Broadcast(t *testing.T) {
+	t.Parallel()
+
+	txs := []*data.Transaction{{Nonce: 1}, {Nonce: 2}, {Nonce: 3}}
+	sentNonces := make([]uint64, 0)
+	proxy := &mocks.ProxyStub{
+		SendTransactionCalled: func(ctx context.Context, tx *data.Transaction) (string, error) {
+			sentNonces = append(sentNonces, tx.Nonce)
+			if tx.Nonce == 2 {
+				return "", errors.New("expected error")
+			}
+
+			return "hash", nil
+		},
+	}
+
+	broadcaster, err := newTxsBroadcaster(proxy, 1000)
+	require.Nil(t, err)
+
+	failedTxs := broadcaster.broadcast(txs)
+	require.Equal(t, []uint64{1, 2, 3}, sentNonces)
+	require.Equal(t, []*data.Transaction{txs[1]}, failedTxs)
+}
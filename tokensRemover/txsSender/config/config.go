@@ -5,6 +5,8 @@ import "github.com/multiversx/mx-chain-tools-go/trieTools/trieToolsCommon"
 // ContextFlagsTxsSender is the flags config for txs sender tool
 type ContextFlagsTxsSender struct {
 	trieToolsCommon.ContextFlagsConfig
+	LogJSON    bool
+	ConfigFile string
 	TxsInput   string
 	StartIndex uint64
 }
@@ -5,3 +5,5 @@ import "errors"
 var errMaxRetrialsExceeded = errors.New("max retrials exceeded limit")
 
 var errIndexOutOfRange = errors.New("index is out of range")
+
+var errConfigFileNotFound = errors.New("config file not found")
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"github.com/multiversx/mx-chain-tools-go/tokensRemover/logging"
 	"github.com/multiversx/mx-chain-tools-go/tokensRemover/txsSender/config"
 	"github.com/multiversx/mx-chain-tools-go/trieTools/trieToolsCommon"
 	"github.com/urfave/cli"
@@ -12,6 +13,11 @@ var (
 		Usage: "This flag specifies the input file; it expects the input to be an array of signed txs",
 		Value: "input.json",
 	}
+	configFile = cli.StringFlag{
+		Name:  "config",
+		Usage: "This flag specifies the path of the toml config file",
+		Value: "./config.toml",
+	}
 	startIndex = cli.Uint64Flag{
 		Name:  "start-index",
 		Usage: "This flag specifies the starting index from txs input array. This tool will start to send txs starting from this index",
@@ -24,8 +30,10 @@ func getFlags() []cli.Flag {
 		trieToolsCommon.LogLevel,
 		trieToolsCommon.DisableAnsiColor,
 		trieToolsCommon.LogSaveFile,
+		logging.LogJSON,
 		trieToolsCommon.LogWithLoggerName,
 		trieToolsCommon.ProfileMode,
+		configFile,
 		input,
 		startIndex,
 	}
@@ -36,8 +44,10 @@ func getFlagsConfig(ctx *cli.Context) config.ContextFlagsTxsSender {
 
 	flagsConfig.LogLevel = ctx.GlobalString(trieToolsCommon.LogLevel.Name)
 	flagsConfig.SaveLogFile = ctx.GlobalBool(trieToolsCommon.LogSaveFile.Name)
+	flagsConfig.LogJSON = ctx.GlobalBool(logging.LogJSON.Name)
 	flagsConfig.EnableLogName = ctx.GlobalBool(trieToolsCommon.LogWithLoggerName.Name)
 	flagsConfig.EnablePprof = ctx.GlobalBool(trieToolsCommon.ProfileMode.Name)
+	flagsConfig.ConfigFile = ctx.GlobalString(configFile.Name)
 	flagsConfig.TxsInput = ctx.GlobalString(input.Name)
 	flagsConfig.StartIndex = ctx.GlobalUint64(startIndex.Name)
 
@@ -1,13 +1,17 @@
 package main
 
 import (
+	"fmt"
 	"io/ioutil"
 	"os"
+	"path/filepath"
 	"time"
 
 	logger "github.com/multiversx/mx-chain-logger-go"
+	"github.com/multiversx/mx-chain-tools-go/tokensRemover/exitcodes"
+	"github.com/multiversx/mx-chain-tools-go/tokensRemover/logging"
 	"github.com/multiversx/mx-chain-tools-go/tokensRemover/txsSender/config"
-	"github.com/multiversx/mx-chain-tools-go/trieTools/trieToolsCommon"
+	"github.com/multiversx/mx-chain-tools-go/tokensRemover/version"
 	"github.com/multiversx/mx-sdk-go/blockchain"
 	"github.com/multiversx/mx-sdk-go/core"
 	"github.com/pelletier/go-toml"
@@ -16,7 +20,6 @@ import (
 
 const (
 	logFilePrefix   = "txs-sender"
-	tomlFile        = "./config.toml"
 	outputFilePerms = 0644
 )
 
@@ -24,6 +27,7 @@ func main() {
 	app := cli.NewApp()
 	app.Name = "Transaction sender tool"
 	app.Usage = "This is the entry point for the tool that sends one tx/block from multiple shards"
+	app.Version = version.GetVersion()
 	app.Flags = getFlags()
 	app.Authors = []cli.Author{
 		{
@@ -35,11 +39,12 @@ func main() {
 	app.Action = func(c *cli.Context) error {
 		return startProcess(c)
 	}
+	app.OnUsageError = exitcodes.OnUsageError
 
 	err := app.Run(os.Args)
 	if err != nil {
 		log.Error(err.Error())
-		os.Exit(1)
+		os.Exit(exitcodes.ExitCode(err))
 		return
 	}
 }
@@ -47,21 +52,21 @@ func main() {
 func startProcess(c *cli.Context) error {
 	flagsConfig := getFlagsConfig(c)
 
-	_, errLogger := trieToolsCommon.AttachFileLogger(log, logFilePrefix, flagsConfig.ContextFlagsConfig)
+	_, errLogger := logging.AttachFileLogger(log, logFilePrefix, flagsConfig.ContextFlagsConfig, flagsConfig.LogJSON)
 	if errLogger != nil {
-		return errLogger
+		return exitcodes.NewIOError(errLogger)
 	}
 
 	err := logger.SetLogLevel(flagsConfig.LogLevel)
 	if err != nil {
-		return err
+		return exitcodes.NewUsageError(err)
 	}
 
 	log.Info("starting processing", "pid", os.Getpid())
 
-	cfg, err := loadConfig()
+	cfg, err := loadConfig(flagsConfig.ConfigFile)
 	if err != nil {
-		return err
+		return exitcodes.NewUsageError(err)
 	}
 	args := blockchain.ArgsProxy{
 		ProxyURL:            cfg.ProxyUrl,
@@ -71,12 +76,12 @@ func startProcess(c *cli.Context) error {
 
 	proxy, err := blockchain.NewProxy(args)
 	if err != nil {
-		return err
+		return exitcodes.NewUsageError(err)
 	}
 
 	txs, err := readTxsInput(flagsConfig.TxsInput)
 	if err != nil {
-		return err
+		return exitcodes.NewUsageError(err)
 	}
 
 	ts := &txsSender{
@@ -84,13 +89,13 @@ func startProcess(c *cli.Context) error {
 		waitTimeNonceIncremented: cfg.WaitTimeNonceIncremented,
 	}
 
-	return ts.send(txs, flagsConfig.StartIndex)
+	return exitcodes.NewIOError(ts.send(txs, flagsConfig.StartIndex))
 }
 
-func loadConfig() (*config.Config, error) {
-	tomlBytes, err := ioutil.ReadFile(tomlFile)
+func loadConfig(configPath string) (*config.Config, error) {
+	tomlBytes, err := ioutil.ReadFile(configPath)
 	if err != nil {
-		return nil, err
+		return nil, createReadConfigError(configPath, err)
 	}
 
 	var cfg config.Config
@@ -101,3 +106,18 @@ func loadConfig() (*config.Config, error) {
 
 	return &cfg, nil
 }
+
+// createReadConfigError includes the absolute path of the config file in the returned error, since a relative path
+// is resolved against the working directory, which might not be the one of the tool
+func createReadConfigError(configPath string, err error) error {
+	absPath, errAbs := filepath.Abs(configPath)
+	if errAbs != nil {
+		absPath = configPath
+	}
+
+	if os.IsNotExist(err) {
+		return fmt.Errorf("%w: %s, the path can be set using the --%s flag", errConfigFileNotFound, absPath, configFile.Name)
+	}
+
+	return fmt.Errorf("%w while reading the config file %s", err, absPath)
+}
@@ -0,0 +1,25 @@
+package version
+
+import (
+	"fmt"
+	"runtime"
+)
+
+// Copy of trieTools/trieToolsCommon/version.go: the module pins an older remote trieTools, without it
+
+// The build metadata of the tools. The commit and the build date are meant to be set at build time, e.g.:
+// go build -ldflags "-X github.com/multiversx/mx-chain-tools-go/tokensRemover/version.Commit=$(git rev-parse --short HEAD)
+// -X github.com/multiversx/mx-chain-tools-go/tokensRemover/version.BuildDate=$(date -u +%Y-%m-%dT%H:%M:%SZ)"
+var (
+	// AppVersion is the version of the tools
+	AppVersion = "v1.0.0"
+	// Commit is the git commit the tools were built from
+	Commit = "undefined"
+	// BuildDate is the date the tools were built at
+	BuildDate = "undefined"
+)
+
+// GetVersion returns the version string of the tools, along with the build metadata and the go runtime used
+func GetVersion() string {
+	return fmt.Sprintf("%s (commit %s, built %s, %s %s/%s)", AppVersion, Commit, BuildDate, runtime.Version(), runtime.GOOS, runtime.GOARCH)
+}
@@ -22,6 +22,7 @@ func getFlags() []cli.Flag {
 		trieToolsCommon.LogLevel,
 		trieToolsCommon.DisableAnsiColor,
 		trieToolsCommon.LogSaveFile,
+		trieToolsCommon.LogJSON,
 		trieToolsCommon.LogWithLoggerName,
 		trieToolsCommon.ProfileMode,
 		trieToolsCommon.HexRootHash,
@@ -37,6 +38,7 @@ func getFlagsConfig(ctx *cli.Context) config.ContextFlagsConfigAddr {
 	flagsConfig.LogLevel = ctx.GlobalString(trieToolsCommon.LogLevel.Name)
 	flagsConfig.DisableAnsiColor = ctx.GlobalBool(trieToolsCommon.DisableAnsiColor.Name)
 	flagsConfig.SaveLogFile = ctx.GlobalBool(trieToolsCommon.LogSaveFile.Name)
+	flagsConfig.LogJSON = ctx.GlobalBool(trieToolsCommon.LogJSON.Name)
 	flagsConfig.EnableLogName = ctx.GlobalBool(trieToolsCommon.LogWithLoggerName.Name)
 	flagsConfig.EnablePprof = ctx.GlobalBool(trieToolsCommon.ProfileMode.Name)
 	flagsConfig.HexRootHash = ctx.GlobalString(trieToolsCommon.HexRootHash.Name)
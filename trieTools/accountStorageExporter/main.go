@@ -32,7 +32,9 @@ func main() {
 	app := cli.NewApp()
 	app.Name = "Accounts Storage Exporter CLI app"
 	app.Usage = "This is the entry point for the tool that exports the storage of a given account"
+	app.Version = trieToolsCommon.GetVersion()
 	app.Flags = getFlags()
+	app.OnUsageError = trieToolsCommon.OnUsageError
 	app.Authors = []cli.Author{
 		{
 			Name:  "The MultiversX Team",
@@ -47,7 +49,7 @@ func main() {
 	err := app.Run(os.Args)
 	if err != nil {
 		log.Error(err.Error())
-		os.Exit(1)
+		os.Exit(trieToolsCommon.ExitCode(err))
 		return
 	}
 
@@ -2,14 +2,17 @@ package blocks
 
 import (
 	"fmt"
+	"io"
+	"os"
 	"sort"
-	"strconv"
 
+	"github.com/multiversx/mx-chain-core-go/core"
 	"github.com/multiversx/mx-chain-core-go/data"
 	dataBlock "github.com/multiversx/mx-chain-core-go/data/block"
 	"github.com/multiversx/mx-chain-core-go/marshal"
 	"github.com/multiversx/mx-chain-storage-go/storageUnit"
 	"github.com/multiversx/mx-chain-tools-go/trieTools/balancesExporter/common"
+	"github.com/multiversx/mx-chain-tools-go/trieTools/trieToolsCommon"
 )
 
 const (
@@ -22,26 +25,29 @@ var (
 
 // ArgsNewBlocksRepository holds arguments for creating a blocksRepository
 type ArgsNewBlocksRepository struct {
-	DbPath      string
-	Epoch       uint32
-	Shard       uint32
-	TrieWrapper trieWrapper
+	DbPath        string
+	Epoch         uint32
+	Shard         uint32
+	TrieWrapper   trieWrapper
+	StorageConfig common.StorageConfig
 }
 
 type blocksRepository struct {
-	dbPath string
-	epoch  uint32
-	shard  uint32
-	trie   trieWrapper
+	dbPath        string
+	epoch         uint32
+	shard         uint32
+	trie          trieWrapper
+	storageConfig common.StorageConfig
 }
 
 // NewBlocksRepository creates a new blocksRepository
 func NewBlocksRepository(args ArgsNewBlocksRepository) *blocksRepository {
 	return &blocksRepository{
-		dbPath: args.DbPath,
-		epoch:  args.Epoch,
-		shard:  args.Shard,
-		trie:   args.TrieWrapper,
+		dbPath:        args.DbPath,
+		epoch:         args.Epoch,
+		shard:         args.Shard,
+		trie:          args.TrieWrapper,
+		storageConfig: args.StorageConfig,
 	}
 }
 
@@ -52,6 +58,10 @@ func (repository *blocksRepository) FindBestBlock() (data.HeaderHandler, error)
 		return nil, err
 	}
 
+	if len(blocksInEpoch) == 0 {
+		return nil, fmt.Errorf("no blocks found in the database for epoch %d, shard %s", repository.epoch, core.GetShardIDString(repository.shard))
+	}
+
 	eligibleBlocks := repository.findEligibleBlocks(blocksInEpoch)
 
 	for _, block := range eligibleBlocks {
@@ -61,7 +71,7 @@ func (repository *blocksRepository) FindBestBlock() (data.HeaderHandler, error)
 		}
 	}
 
-	return nil, fmt.Errorf("no best block found")
+	return nil, fmt.Errorf("no best block found for epoch %d, shard %s", repository.epoch, core.GetShardIDString(repository.shard))
 }
 
 func (repository *blocksRepository) findEligibleBlocks(headers []data.HeaderHandler) []data.HeaderHandler {
@@ -91,7 +101,7 @@ func (repository *blocksRepository) loadBlocksInEpoch() ([]data.HeaderHandler, e
 	headers := make([]data.HeaderHandler, 0)
 
 	for _, bytes := range marshalizedBlocks {
-		header := &dataBlock.HeaderV2{}
+		header := repository.createEmptyHeader()
 		err := marshaller.Unmarshal(header, bytes)
 		if err != nil {
 			return nil, err
@@ -108,12 +118,30 @@ func (repository *blocksRepository) loadBlocksInEpoch() ([]data.HeaderHandler, e
 	return headers, nil
 }
 
+func (repository *blocksRepository) createEmptyHeader() data.HeaderHandler {
+	if repository.shard == core.MetachainShardId {
+		return &dataBlock.MetaBlock{}
+	}
+
+	return &dataBlock.HeaderV2{}
+}
+
 func (repository *blocksRepository) loadMarshalizedBlocksInEpoch() ([][]byte, error) {
-	cacheConfig := getCacheConfig()
+	cacheConfig := getCacheConfig(repository.storageConfig)
 	unitPath := repository.getStorageUnitPath()
-	dbConfig := getDbConfig(unitPath)
+	dbConfig := getDbConfig(unitPath, repository.storageConfig)
 
-	unit, err := storageUnit.NewStorageUnitFromConf(cacheConfig, dbConfig)
+	_, err := os.Stat(unitPath)
+	if err != nil {
+		return nil, fmt.Errorf("%w: block headers of epoch %d, shard %s are not present in the database", err, repository.epoch, core.GetShardIDString(repository.shard))
+	}
+
+	var unit *storageUnit.Unit
+	err = trieToolsCommon.RetryOpen(repository.storageConfig.DbOpenRetry, unitPath, func() (io.Closer, error) {
+		var errCreate error
+		unit, errCreate = trieToolsCommon.NewStorageUnit(cacheConfig, dbConfig, repository.storageConfig.ReadOnly)
+		return unit, errCreate
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -130,7 +158,7 @@ func (repository *blocksRepository) loadMarshalizedBlocksInEpoch() ([][]byte, er
 
 func (repository *blocksRepository) getStorageUnitPath() string {
 	pathManager := common.NewSimplePathManager(repository.dbPath)
-	path := pathManager.PathForEpoch(strconv.Itoa(int(repository.shard)), repository.epoch, storageUnitIdentifier)
+	path := pathManager.PathForEpoch(core.GetShardIDString(repository.shard), repository.epoch, storageUnitIdentifier)
 	return path
 }
 
@@ -0,0 +1,40 @@
+package blocks
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/multiversx/mx-chain-core-go/core"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBlocksRepository_FindBestBlockWithMissingEpochShouldError(t *testing.T) {
+	t.Parallel()
+
+	repository := NewBlocksRepository(ArgsNewBlocksRepository{
+		DbPath: t.TempDir(),
+		Epoch:  690,
+		Shard:  1,
+	})
+
+	block, err := repository.FindBestBlock()
+	require.Nil(t, block)
+	require.ErrorIs(t, err, os.ErrNotExist)
+	require.True(t, strings.Contains(err.Error(), "block headers of epoch 690, shard 1 are not present in the database"))
+}
+
+func TestBlocksRepository_FindBestBlockWithMissingMetachainEpochShouldError(t *testing.T) {
+	t.Parallel()
+
+	repository := NewBlocksRepository(ArgsNewBlocksRepository{
+		DbPath: t.TempDir(),
+		Epoch:  690,
+		Shard:  core.MetachainShardId,
+	})
+
+	block, err := repository.FindBestBlock()
+	require.Nil(t, block)
+	require.ErrorIs(t, err, os.ErrNotExist)
+	require.True(t, strings.Contains(err.Error(), "block headers of epoch 690, shard metachain are not present in the database"))
+}
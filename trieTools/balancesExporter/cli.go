@@ -2,10 +2,13 @@ package main
 
 import (
 	"fmt"
+	"runtime"
+	"strings"
 
 	logger "github.com/multiversx/mx-chain-logger-go"
 	"github.com/multiversx/mx-chain-tools-go/trieTools/balancesExporter/common"
 	"github.com/multiversx/mx-chain-tools-go/trieTools/balancesExporter/export"
+	"github.com/multiversx/mx-chain-tools-go/trieTools/trieToolsCommon"
 	"github.com/urfave/cli"
 )
 
@@ -64,6 +67,12 @@ VERSION:
 		Usage: "Boolean option for enabling log saving. If set, it will automatically save all the logs into a file.",
 	}
 
+	cliFlagAddressHrp = cli.StringFlag{
+		Name:  "address-hrp",
+		Usage: "The bech32 human-readable part (prefix) used for encoding the exported addresses (e.g. for the forks of the chain using a different prefix).",
+		Value: trieToolsCommon.DefaultAddressHrp,
+	}
+
 	cliFlagCurrency = cli.StringFlag{
 		Name:  "currency",
 		Usage: "What balances to export.",
@@ -76,12 +85,29 @@ VERSION:
 		Value: 18,
 	}
 
+	cliFlagDenomination = cli.UintFlag{
+		Name:  "denomination",
+		Usage: "The number of decimals of the currency, used to render the balances in whole tokens when --human-readable is set. Defaults to the value of --currency-decimals.",
+		Value: 18,
+	}
+
+	cliFlagHumanReadable = cli.BoolFlag{
+		Name:  "human-readable",
+		Usage: "Whether to export the balances in whole tokens (e.g. 1.500000000000000000), with --denomination fractional digits, instead of the smallest denomination. The csv rawBalance column still holds the balance in the smallest denomination. Not supported by the rosetta-json format.",
+	}
+
 	cliFlagExportFormat = cli.StringFlag{
 		Name:  "format",
 		Usage: fmt.Sprintf("Export format. One of the following: %s", export.AllFormattersNames),
 		Value: export.FormatterNamePlainText,
 	}
 
+	cliFlagColumns = cli.StringFlag{
+		Name:  "columns",
+		Usage: fmt.Sprintf("Comma-separated, ordered list of columns to export, when using the csv format. Any of the following: %s", export.AllCsvColumnsNames),
+		Value: "address,balance",
+	}
+
 	cliFlagWithContracts = cli.BoolFlag{
 		Name:  "with-contracts",
 		Usage: "Whether to include contracts in the export.",
@@ -92,11 +118,119 @@ VERSION:
 		Usage: "Whether to include accounts with zero balance in the export.",
 	}
 
+	cliFlagMinBalance = cli.StringFlag{
+		Name:  "min-balance",
+		Usage: "Accounts with a balance lower than this value (expressed in the smallest denomination) are not included in the export.",
+		Value: "0",
+	}
+
 	cliFlagByProjectedShard = cli.Uint64Flag{
 		Name:     "by-projected-shard",
 		Usage:    "The projected shard to use for export.",
 		Required: false,
 	}
+
+	cliFlagGzip = cli.BoolFlag{
+		Name:  "gzip",
+		Usage: "Whether to compress the exported balances file using gzip (a .gz extension is appended to the file name).",
+	}
+
+	cliFlagExpectedSupply = cli.StringFlag{
+		Name:  "expected-supply",
+		Usage: "If set, the sum of the exported balances (plus --supply-offset) is compared with this value (expressed in the smallest denomination) and the tool fails if the difference exceeds --supply-tolerance.",
+	}
+
+	cliFlagSupplyOffset = cli.StringFlag{
+		Name:  "supply-offset",
+		Usage: "The amount added to the sum of the exported balances when checking the expected supply (e.g. burned or reserved amounts, not held by the exported accounts).",
+		Value: "0",
+	}
+
+	cliFlagSupplyTolerance = cli.StringFlag{
+		Name:  "supply-tolerance",
+		Usage: "The maximum accepted difference between the expected supply and the sum of the exported balances (plus --supply-offset).",
+		Value: "0",
+	}
+
+	cliFlagReportZero = cli.BoolFlag{
+		Name:  "report-zero",
+		Usage: "Whether to count the accounts with zero balance found in the trie (before the export filters), distinguishing the ones that still have code or a nonzero nonce. The figures are logged and saved in a .zero-balance.json file.",
+	}
+
+	cliFlagReportZeroList = cli.BoolFlag{
+		Name:  "report-zero-list",
+		Usage: "Whether to also list the accounts with zero balance in a .zero-balance.csv file (address, nonce, hasCode, hasDataTrie). Implies --report-zero.",
+	}
+
+	cliFlagIncludeStaked = cli.BoolFlag{
+		Name:  "include-staked",
+		Usage: "Whether to also export the staked amounts (active and unstaked, in the staking and delegation system smart contracts), in a separate column. Requires the metachain database of the same epoch.",
+	}
+
+	cliFlagMetachainDbPath = cli.StringFlag{
+		Name:  "metachain-db-path",
+		Usage: "The path to a metachain node's database, used when exporting the staked amounts. Defaults to the value of --db-path.",
+	}
+
+	cliFlagDeltaFromEpoch = cli.Uint64Flag{
+		Name:  "delta-from-epoch",
+		Usage: "If set, the balance changes from the best block of this epoch to the best block of --epoch (or to --delta-to-root-hash) are exported, instead of the balances. Must be lower than --epoch.",
+	}
+
+	cliFlagDeltaFromRootHash = cli.StringFlag{
+		Name:  "delta-from-root-hash",
+		Usage: "If set, the balance changes from this (hex) accounts trie root hash to the best block of --epoch (or to --delta-to-root-hash) are exported, instead of the balances. The root hash must be present in the database of --epoch.",
+	}
+
+	cliFlagDeltaToRootHash = cli.StringFlag{
+		Name:  "delta-to-root-hash",
+		Usage: "The (hex) accounts trie root hash used as the new snapshot of the balances delta, instead of the best block of --epoch. Requires --delta-from-epoch or --delta-from-root-hash.",
+	}
+
+	cliFlagLimit = cli.Uint64Flag{
+		Name:  "limit",
+		Usage: "For testing only: if set, at most this number of accounts is exported (the first matching accounts, in the trie order). The export is NOT complete and its files are suffixed with _preview.",
+	}
+
+	cliFlagSample = cli.StringFlag{
+		Name:  "sample",
+		Usage: "For testing only: if set (as 1/K, e.g. 1/100), only one of every K matching accounts is exported. The export is NOT complete and its files are suffixed with _preview.",
+	}
+
+	cliFlagTotalAccounts = cli.Uint64Flag{
+		Name:  "total-accounts",
+		Usage: "Optional hint: the number of leaves of the accounts trie (e.g. the numAccounts reported by a prior trieChecker run). If set, the progress percentage and the estimated remaining time are logged while reading the trie.",
+	}
+
+	cliFlagNumWorkers = cli.IntFlag{
+		Name:  "num-workers",
+		Usage: "The number of goroutines unmarshalling the accounts read from the trie. It defaults to the number of CPUs. The export order does not depend on it.",
+		Value: runtime.NumCPU(),
+	}
+
+	cliFlagCacheCapacity = cli.UintFlag{
+		Name:  "cache-capacity",
+		Usage: "The capacity (number of entries) of the storage caches.",
+		Value: common.DefaultCacheCapacity,
+	}
+
+	cliFlagCacheSizeInBytes = cli.Uint64Flag{
+		Name:  "cache-size",
+		Usage: "The size of the storage caches, in bytes.",
+		Value: common.DefaultCacheSizeInBytes,
+	}
+
+	cliFlagMaxBatchSize = cli.IntFlag{
+		Name:  "max-batch-size",
+		Usage: "The maximum batch size of the storage units.",
+		Value: common.DefaultMaxBatchSize,
+	}
+
+	cliFlagMaxOpenFiles = cli.IntFlag{
+		Name:  "max-open-files",
+		Usage: "The maximum number of files opened by each database.",
+		Value: common.DefaultMaxOpenFiles,
+	}
 )
 
 func getAllCliFlags() []cli.Flag {
@@ -107,28 +241,79 @@ func getAllCliFlags() []cli.Flag {
 		cliFlagEpoch,
 		cliFlagLogLevel,
 		cliFlagLogSaveFile,
+		trieToolsCommon.LogJSON,
+		cliFlagAddressHrp,
 		cliFlagCurrency,
 		cliFlagCurrencyDecimals,
+		cliFlagDenomination,
+		cliFlagHumanReadable,
 		cliFlagExportFormat,
+		cliFlagColumns,
 		cliFlagWithContracts,
 		cliFlagWithZero,
+		cliFlagMinBalance,
 		cliFlagByProjectedShard,
+		cliFlagGzip,
+		cliFlagExpectedSupply,
+		cliFlagSupplyOffset,
+		cliFlagSupplyTolerance,
+		cliFlagReportZero,
+		cliFlagReportZeroList,
+		cliFlagIncludeStaked,
+		cliFlagMetachainDbPath,
+		cliFlagDeltaFromEpoch,
+		cliFlagDeltaFromRootHash,
+		cliFlagDeltaToRootHash,
+		cliFlagLimit,
+		cliFlagSample,
+		cliFlagTotalAccounts,
+		cliFlagNumWorkers,
+		cliFlagCacheCapacity,
+		cliFlagCacheSizeInBytes,
+		cliFlagMaxBatchSize,
+		cliFlagMaxOpenFiles,
+		trieToolsCommon.DbOpenAttempts,
+		trieToolsCommon.DbOpenRetryDelay,
+		trieToolsCommon.DbOpenTimeout,
+		trieToolsCommon.ReadOnly,
 	}
 }
 
 type parsedCliFlags struct {
-	dbPath           string
-	shard            uint32
-	numShards        uint32
-	epoch            uint32
-	logLevel         string
-	saveLogFile      bool
-	currency         string
-	currencyDecimals uint
-	exportFormat     string
-	withContracts    bool
-	withZero         bool
-	byProjectedShard common.OptionalUint32
+	dbPath            string
+	shard             uint32
+	numShards         uint32
+	epoch             uint32
+	logLevel          string
+	saveLogFile       bool
+	logJSON           bool
+	addressHrp        string
+	currency          string
+	currencyDecimals  uint
+	denomination      uint
+	humanReadable     bool
+	exportFormat      string
+	columns           []string
+	withContracts     bool
+	withZero          bool
+	minBalance        string
+	byProjectedShard  common.OptionalUint32
+	gzip              bool
+	expectedSupply    string
+	supplyOffset      string
+	supplyTolerance   string
+	reportZero        bool
+	reportZeroList    bool
+	includeStaked     bool
+	metachainDbPath   string
+	deltaFromEpoch    common.OptionalUint32
+	deltaFromRootHash string
+	deltaToRootHash   string
+	limit             uint64
+	sample            string
+	totalAccounts     uint64
+	numWorkers        int
+	storageConfig     common.StorageConfig
 }
 
 func getParsedCliFlags(ctx *cli.Context) parsedCliFlags {
@@ -139,14 +324,78 @@ func getParsedCliFlags(ctx *cli.Context) parsedCliFlags {
 		epoch:            uint32(ctx.GlobalUint64(cliFlagEpoch.Name)),
 		logLevel:         ctx.GlobalString(cliFlagLogLevel.Name),
 		saveLogFile:      ctx.GlobalBool(cliFlagLogSaveFile.Name),
+		logJSON:          ctx.GlobalBool(trieToolsCommon.LogJSON.Name),
+		addressHrp:       ctx.GlobalString(cliFlagAddressHrp.Name),
 		currency:         ctx.GlobalString(cliFlagCurrency.Name),
 		currencyDecimals: uint(ctx.GlobalUint(cliFlagCurrencyDecimals.Name)),
+		denomination:     getDenomination(ctx),
+		humanReadable:    ctx.GlobalBool(cliFlagHumanReadable.Name),
 		exportFormat:     ctx.GlobalString(cliFlagExportFormat.Name),
+		columns:          parseColumns(ctx.GlobalString(cliFlagColumns.Name)),
 		withContracts:    ctx.GlobalBool(cliFlagWithContracts.Name),
 		withZero:         ctx.GlobalBool(cliFlagWithZero.Name),
+		minBalance:       ctx.GlobalString(cliFlagMinBalance.Name),
 		byProjectedShard: common.OptionalUint32{
 			Value:    uint32(ctx.GlobalUint64(cliFlagByProjectedShard.Name)),
 			HasValue: ctx.GlobalIsSet(cliFlagByProjectedShard.Name),
 		},
+		gzip:            ctx.GlobalBool(cliFlagGzip.Name),
+		expectedSupply:  ctx.GlobalString(cliFlagExpectedSupply.Name),
+		supplyOffset:    ctx.GlobalString(cliFlagSupplyOffset.Name),
+		supplyTolerance: ctx.GlobalString(cliFlagSupplyTolerance.Name),
+		reportZero:      ctx.GlobalBool(cliFlagReportZero.Name),
+		reportZeroList:  ctx.GlobalBool(cliFlagReportZeroList.Name),
+		includeStaked:   ctx.GlobalBool(cliFlagIncludeStaked.Name),
+		metachainDbPath: getMetachainDbPath(ctx),
+		deltaFromEpoch: common.OptionalUint32{
+			Value:    uint32(ctx.GlobalUint64(cliFlagDeltaFromEpoch.Name)),
+			HasValue: ctx.GlobalIsSet(cliFlagDeltaFromEpoch.Name),
+		},
+		deltaFromRootHash: ctx.GlobalString(cliFlagDeltaFromRootHash.Name),
+		deltaToRootHash:   ctx.GlobalString(cliFlagDeltaToRootHash.Name),
+		limit:             ctx.GlobalUint64(cliFlagLimit.Name),
+		sample:            ctx.GlobalString(cliFlagSample.Name),
+		totalAccounts:     ctx.GlobalUint64(cliFlagTotalAccounts.Name),
+		numWorkers:        ctx.GlobalInt(cliFlagNumWorkers.Name),
+		storageConfig: common.StorageConfig{
+			CacheCapacity:    uint32(ctx.GlobalUint(cliFlagCacheCapacity.Name)),
+			CacheSizeInBytes: ctx.GlobalUint64(cliFlagCacheSizeInBytes.Name),
+			MaxBatchSize:     ctx.GlobalInt(cliFlagMaxBatchSize.Name),
+			MaxOpenFiles:     ctx.GlobalInt(cliFlagMaxOpenFiles.Name),
+			DbOpenRetry: trieToolsCommon.DbOpenRetryConfig{
+				Attempts: ctx.GlobalUint64(trieToolsCommon.DbOpenAttempts.Name),
+				Delay:    ctx.GlobalDuration(trieToolsCommon.DbOpenRetryDelay.Name),
+				Timeout:  ctx.GlobalDuration(trieToolsCommon.DbOpenTimeout.Name),
+			},
+			ReadOnly: ctx.GlobalBoolT(trieToolsCommon.ReadOnly.Name),
+		},
+	}
+}
+
+func getMetachainDbPath(ctx *cli.Context) string {
+	if ctx.GlobalIsSet(cliFlagMetachainDbPath.Name) {
+		return ctx.GlobalString(cliFlagMetachainDbPath.Name)
 	}
+
+	return ctx.GlobalString(cliFlagDbPath.Name)
+}
+
+func getDenomination(ctx *cli.Context) uint {
+	if ctx.GlobalIsSet(cliFlagDenomination.Name) {
+		return uint(ctx.GlobalUint(cliFlagDenomination.Name))
+	}
+
+	return uint(ctx.GlobalUint(cliFlagCurrencyDecimals.Name))
+}
+
+func parseColumns(value string) []string {
+	columns := make([]string, 0)
+	for _, column := range strings.Split(value, ",") {
+		column = strings.TrimSpace(column)
+		if len(column) > 0 {
+			columns = append(columns, column)
+		}
+	}
+
+	return columns
 }
@@ -0,0 +1,47 @@
+package common
+
+import (
+	"fmt"
+
+	"github.com/multiversx/mx-chain-tools-go/trieTools/trieToolsCommon"
+)
+
+const (
+	// DefaultCacheCapacity is the default capacity (number of entries) of the storage caches
+	DefaultCacheCapacity = 500000
+	// DefaultCacheSizeInBytes is the default size of the storage caches
+	DefaultCacheSizeInBytes = 314572800 // 300MB
+	// DefaultMaxBatchSize is the default maximum batch size of the storage units
+	DefaultMaxBatchSize = 45000
+	// DefaultMaxOpenFiles is the default maximum number of files opened by a database
+	DefaultMaxOpenFiles = 10
+)
+
+// StorageConfig holds the tunable parameters of the caches and databases used for reading the node database
+type StorageConfig struct {
+	CacheCapacity    uint32
+	CacheSizeInBytes uint64
+	MaxBatchSize     int
+	MaxOpenFiles     int
+	DbOpenRetry      trieToolsCommon.DbOpenRetryConfig
+	// ReadOnly opens the databases in read-only mode, if supported by the persister type
+	ReadOnly bool
+}
+
+// Check returns an error if any of the parameters is invalid
+func (config StorageConfig) Check() error {
+	if config.CacheCapacity == 0 {
+		return fmt.Errorf("invalid cache capacity: %d", config.CacheCapacity)
+	}
+	if config.CacheSizeInBytes == 0 {
+		return fmt.Errorf("invalid cache size: %d", config.CacheSizeInBytes)
+	}
+	if config.MaxBatchSize <= 0 {
+		return fmt.Errorf("invalid max batch size: %d", config.MaxBatchSize)
+	}
+	if config.MaxOpenFiles <= 0 {
+		return fmt.Errorf("invalid max open files: %d", config.MaxOpenFiles)
+	}
+
+	return config.DbOpenRetry.Check()
+}
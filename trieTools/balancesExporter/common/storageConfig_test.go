@@ -0,0 +1,47 @@
+package common
+
+import (
+	"testing"
+
+	"github.com/multiversx/mx-chain-tools-go/trieTools/trieToolsCommon"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStorageConfig_Check(t *testing.T) {
+	t.Parallel()
+
+	createValidConfig := func() StorageConfig {
+		return StorageConfig{
+			CacheCapacity:    DefaultCacheCapacity,
+			CacheSizeInBytes: DefaultCacheSizeInBytes,
+			MaxBatchSize:     DefaultMaxBatchSize,
+			MaxOpenFiles:     DefaultMaxOpenFiles,
+			DbOpenRetry: trieToolsCommon.DbOpenRetryConfig{
+				Attempts: trieToolsCommon.DefaultDbOpenAttempts,
+				Delay:    trieToolsCommon.DefaultDbOpenRetryDelay,
+			},
+		}
+	}
+
+	require.Nil(t, createValidConfig().Check())
+
+	config := createValidConfig()
+	config.CacheCapacity = 0
+	require.ErrorContains(t, config.Check(), "invalid cache capacity")
+
+	config = createValidConfig()
+	config.CacheSizeInBytes = 0
+	require.ErrorContains(t, config.Check(), "invalid cache size")
+
+	config = createValidConfig()
+	config.MaxBatchSize = -1
+	require.ErrorContains(t, config.Check(), "invalid max batch size")
+
+	config = createValidConfig()
+	config.MaxOpenFiles = 0
+	require.ErrorContains(t, config.Check(), "invalid max open files")
+
+	config = createValidConfig()
+	config.DbOpenRetry.Attempts = 0
+	require.ErrorContains(t, config.Check(), "invalid db open attempts")
+}
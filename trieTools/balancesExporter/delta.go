@@ -0,0 +1,149 @@
+package main
+
+import (
+	"encoding/hex"
+	"fmt"
+
+	"github.com/multiversx/mx-chain-core-go/core"
+	"github.com/multiversx/mx-chain-core-go/data"
+	"github.com/multiversx/mx-chain-go/state"
+	"github.com/multiversx/mx-chain-tools-go/trieTools/balancesExporter/blocks"
+	"github.com/multiversx/mx-chain-tools-go/trieTools/balancesExporter/export"
+	"github.com/multiversx/mx-chain-tools-go/trieTools/trieToolsCommon"
+)
+
+const (
+	rootHashLength         = 32
+	rootHashLabelNumBytes  = 8
+	deltaExportFormatLabel = "csv"
+)
+
+type deltaTrieWrapper interface {
+	IsRootHashAvailable(rootHash []byte) bool
+	GetUserAccounts(rootHash []byte, predicate func(*state.UserAccountData) bool, limit uint64) ([]*state.UserAccountData, error)
+}
+
+type bestBlockFinder interface {
+	FindBestBlock() (data.HeaderHandler, error)
+}
+
+func isDeltaMode(cliFlags parsedCliFlags) bool {
+	return cliFlags.deltaFromEpoch.HasValue || len(cliFlags.deltaFromRootHash) > 0
+}
+
+func checkDeltaFlags(cliFlags parsedCliFlags) error {
+	if !isDeltaMode(cliFlags) {
+		if len(cliFlags.deltaToRootHash) > 0 {
+			return fmt.Errorf("--%s requires --%s or --%s", cliFlagDeltaToRootHash.Name, cliFlagDeltaFromEpoch.Name, cliFlagDeltaFromRootHash.Name)
+		}
+		return nil
+	}
+
+	if cliFlags.deltaFromEpoch.HasValue && len(cliFlags.deltaFromRootHash) > 0 {
+		return fmt.Errorf("--%s and --%s can not be used together", cliFlagDeltaFromEpoch.Name, cliFlagDeltaFromRootHash.Name)
+	}
+	if cliFlags.deltaFromEpoch.HasValue && cliFlags.deltaFromEpoch.Value >= cliFlags.epoch {
+		return fmt.Errorf("--%s must be lower than --%s, got %d and %d",
+			cliFlagDeltaFromEpoch.Name, cliFlagEpoch.Name, cliFlags.deltaFromEpoch.Value, cliFlags.epoch)
+	}
+	if cliFlags.includeStaked {
+		return fmt.Errorf("--%s can not be used when exporting a balances delta", cliFlagIncludeStaked.Name)
+	}
+	if cliFlags.reportZero || cliFlags.reportZeroList {
+		return fmt.Errorf("--%s and --%s can not be used when exporting a balances delta", cliFlagReportZero.Name, cliFlagReportZeroList.Name)
+	}
+	if cliFlags.humanReadable {
+		return fmt.Errorf("--%s can not be used when exporting a balances delta", cliFlagHumanReadable.Name)
+	}
+	if cliFlags.limit > 0 || len(cliFlags.sample) > 0 {
+		return fmt.Errorf("--%s and --%s can not be used when exporting a balances delta", cliFlagLimit.Name, cliFlagSample.Name)
+	}
+
+	return nil
+}
+
+// exportBalancesDelta exports the balance changes between the old snapshot (the best block of --delta-from-epoch, or
+// --delta-from-root-hash) and the new one (the best block of --epoch, or --delta-to-root-hash). Both snapshots are
+// read from the accounts trie of --epoch, which also holds the data of the previous epochs
+func exportBalancesDelta(cliFlags parsedCliFlags, trieWrapper deltaTrieWrapper, blocksRepository bestBlockFinder, addressConverter core.PubkeyConverter) error {
+	log.Info("Exporting the balances delta, the export format is always " + deltaExportFormatLabel)
+
+	oldSnapshot, err := getOldDeltaSnapshot(cliFlags, trieWrapper)
+	if err != nil {
+		return err
+	}
+
+	newSnapshot, err := getNewDeltaSnapshot(cliFlags, trieWrapper, blocksRepository)
+	if err != nil {
+		return err
+	}
+
+	deltaExporter, err := export.NewDeltaExporter(export.ArgsNewDeltaExporter{
+		TrieWrapper:      trieWrapper,
+		ShardID:          cliFlags.shard,
+		Currency:         cliFlags.currency,
+		WithContracts:    cliFlags.withContracts,
+		ByProjectedShard: cliFlags.byProjectedShard,
+		Gzip:             cliFlags.gzip,
+		AddressConverter: addressConverter,
+	})
+	if err != nil {
+		return trieToolsCommon.NewUsageError(err)
+	}
+
+	return deltaExporter.ExportBalancesDelta(oldSnapshot, newSnapshot)
+}
+
+func getOldDeltaSnapshot(cliFlags parsedCliFlags, trieWrapper deltaTrieWrapper) (export.BalancesSnapshot, error) {
+	if len(cliFlags.deltaFromRootHash) > 0 {
+		return getRootHashSnapshot(cliFlags.deltaFromRootHash, trieWrapper)
+	}
+
+	blocksRepository := blocks.NewBlocksRepository(blocks.ArgsNewBlocksRepository{
+		DbPath:        cliFlags.dbPath,
+		Epoch:         cliFlags.deltaFromEpoch.Value,
+		Shard:         cliFlags.shard,
+		TrieWrapper:   trieWrapper,
+		StorageConfig: cliFlags.storageConfig,
+	})
+
+	return getBestBlockSnapshot(blocksRepository)
+}
+
+func getNewDeltaSnapshot(cliFlags parsedCliFlags, trieWrapper deltaTrieWrapper, blocksRepository bestBlockFinder) (export.BalancesSnapshot, error) {
+	if len(cliFlags.deltaToRootHash) > 0 {
+		return getRootHashSnapshot(cliFlags.deltaToRootHash, trieWrapper)
+	}
+
+	return getBestBlockSnapshot(blocksRepository)
+}
+
+func getBestBlockSnapshot(blocksRepository bestBlockFinder) (export.BalancesSnapshot, error) {
+	bestBlock, err := blocksRepository.FindBestBlock()
+	if err != nil {
+		return export.BalancesSnapshot{}, trieToolsCommon.NewIOError(err)
+	}
+
+	return export.BalancesSnapshot{
+		RootHash: bestBlock.GetRootHash(),
+		Label:    fmt.Sprintf("epoch_%d_nonce_%d", bestBlock.GetEpoch(), bestBlock.GetNonce()),
+	}, nil
+}
+
+func getRootHashSnapshot(hexRootHash string, trieWrapper deltaTrieWrapper) (export.BalancesSnapshot, error) {
+	rootHash, err := hex.DecodeString(hexRootHash)
+	if err != nil {
+		return export.BalancesSnapshot{}, trieToolsCommon.NewUsageError(fmt.Errorf("%w when decoding the root hash %s", err, hexRootHash))
+	}
+	if len(rootHash) != rootHashLength {
+		return export.BalancesSnapshot{}, trieToolsCommon.NewUsageError(fmt.Errorf("wrong root hash length: expected %d, got %d", rootHashLength, len(rootHash)))
+	}
+	if !trieWrapper.IsRootHashAvailable(rootHash) {
+		return export.BalancesSnapshot{}, trieToolsCommon.NewIOError(fmt.Errorf("the root hash %s is not present in the database", hexRootHash))
+	}
+
+	return export.BalancesSnapshot{
+		RootHash: rootHash,
+		Label:    "roothash_" + hex.EncodeToString(rootHash[:rootHashLabelNumBytes]),
+	}, nil
+}
@@ -0,0 +1,39 @@
+package export
+
+import (
+	"math/big"
+	"strings"
+)
+
+// formatDecimalBalance renders a balance expressed in the smallest denomination as a decimal string in whole tokens,
+// with exactly denomination fractional digits (e.g. 1500000000000000000 becomes 1.500000000000000000 for 18 decimals).
+// Only integer arithmetic is used, so there is no precision loss
+func formatDecimalBalance(balance *big.Int, denomination uint) string {
+	if denomination == 0 {
+		return balance.String()
+	}
+
+	divisor := big.NewInt(0).Exp(big.NewInt(10), big.NewInt(int64(denomination)), nil)
+	absBalance := big.NewInt(0).Abs(balance)
+	integerPart, fractionalPart := big.NewInt(0).QuoRem(absBalance, divisor, big.NewInt(0))
+
+	fractionalDigits := fractionalPart.String()
+	padding := strings.Repeat("0", int(denomination)-len(fractionalDigits))
+
+	sign := ""
+	if balance.Sign() < 0 {
+		sign = "-"
+	}
+
+	return sign + integerPart.String() + "." + padding + fractionalDigits
+}
+
+// formatBalance renders the provided balance either in the smallest denomination (the default) or, if humanReadable
+// is set, in whole tokens
+func formatBalance(balance *big.Int, args formatterArgs) string {
+	if !args.humanReadable {
+		return balance.String()
+	}
+
+	return formatDecimalBalance(balance, args.denomination)
+}
@@ -0,0 +1,38 @@
+package export
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatDecimalBalance(t *testing.T) {
+	t.Parallel()
+
+	fromString := func(value string) *big.Int {
+		balance, ok := big.NewInt(0).SetString(value, 10)
+		require.True(t, ok)
+
+		return balance
+	}
+
+	require.Equal(t, "0.000000000000000000", formatDecimalBalance(big.NewInt(0), 18))
+	require.Equal(t, "1.000000000000000000", formatDecimalBalance(fromString("1000000000000000000"), 18))
+	require.Equal(t, "1.500000000000000000", formatDecimalBalance(fromString("1500000000000000000"), 18))
+	// leading zeros of the fractional part
+	require.Equal(t, "0.000000000000000001", formatDecimalBalance(big.NewInt(1), 18))
+	require.Equal(t, "0.000000000000012300", formatDecimalBalance(big.NewInt(12300), 18))
+	require.Equal(t, "12.000", formatDecimalBalance(big.NewInt(12000), 3))
+	// larger than an uint64 or a float64 mantissa
+	require.Equal(t, "20000000.000000000000000001", formatDecimalBalance(fromString("20000000000000000000000001"), 18))
+	require.Equal(t, "-0.000000000000000100", formatDecimalBalance(big.NewInt(-100), 18))
+	require.Equal(t, "1234", formatDecimalBalance(big.NewInt(1234), 0))
+}
+
+func TestFormatBalance(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, "1500", formatBalance(big.NewInt(1500), formatterArgs{denomination: 3}))
+	require.Equal(t, "1.500", formatBalance(big.NewInt(1500), formatterArgs{denomination: 3, humanReadable: true}))
+}
@@ -0,0 +1,279 @@
+package export
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+
+	"github.com/multiversx/mx-chain-core-go/core"
+	"github.com/multiversx/mx-chain-core-go/core/check"
+	"github.com/multiversx/mx-chain-go/sharding"
+	"github.com/multiversx/mx-chain-go/state"
+	"github.com/multiversx/mx-chain-tools-go/trieTools/balancesExporter/common"
+	"github.com/multiversx/mx-chain-tools-go/trieTools/trieToolsCommon"
+)
+
+const (
+	// DeltaKindCreated marks an account that only exists in the new snapshot
+	DeltaKindCreated = "created"
+	// DeltaKindRemoved marks an account that only exists in the old snapshot
+	DeltaKindRemoved = "removed"
+	// DeltaKindEmptied marks an account whose balance dropped to zero
+	DeltaKindEmptied = "emptied"
+	// DeltaKindChanged marks an account whose balance changed, without being emptied
+	DeltaKindChanged = "changed"
+)
+
+var deltaCsvColumns = []string{"address", "oldBalance", "newBalance", "delta", "kind"}
+
+// BalancesSnapshot identifies one of the two accounts trie states compared by the delta exporter
+type BalancesSnapshot struct {
+	RootHash []byte
+	// Label identifies the snapshot in the exported file names (e.g. epoch_690_nonce_100)
+	Label string
+}
+
+// ArgsNewDeltaExporter holds arguments for creating a delta exporter
+type ArgsNewDeltaExporter struct {
+	TrieWrapper      trieWrapper
+	ShardID          uint32
+	Currency         string
+	WithContracts    bool
+	ByProjectedShard common.OptionalUint32
+	Gzip             bool
+	// AddressConverter encodes the exported addresses. If not set, the addresses are encoded using the erd hrp
+	AddressConverter core.PubkeyConverter
+}
+
+type deltaExporter struct {
+	trie                      trieWrapper
+	shardID                   uint32
+	currency                  string
+	withContracts             bool
+	byProjectedShard          common.OptionalUint32
+	projectedShardCoordinator sharding.Coordinator
+	gzip                      bool
+	addressConverter          core.PubkeyConverter
+}
+
+type balanceDelta struct {
+	address    []byte
+	oldBalance *big.Int
+	newBalance *big.Int
+	delta      *big.Int
+	kind       string
+}
+
+// NewDeltaExporter creates a new delta exporter, which exports the balance changes between two snapshots
+func NewDeltaExporter(args ArgsNewDeltaExporter) (*deltaExporter, error) {
+	projectedShardCoordinator, err := sharding.NewMultiShardCoordinator(core.MaxNumShards, args.ByProjectedShard.Value)
+	if err != nil {
+		return nil, err
+	}
+
+	addressConverter := args.AddressConverter
+	if check.IfNil(addressConverter) {
+		addressConverter = defaultAddressConverter
+	}
+
+	return &deltaExporter{
+		trie:                      args.TrieWrapper,
+		shardID:                   args.ShardID,
+		currency:                  args.Currency,
+		withContracts:             args.WithContracts,
+		byProjectedShard:          args.ByProjectedShard,
+		projectedShardCoordinator: projectedShardCoordinator,
+		gzip:                      args.Gzip,
+		addressConverter:          addressConverter,
+	}, nil
+}
+
+// ExportBalancesDelta exports, for each account whose balance changed from the old snapshot to the new one, the old
+// balance, the new balance and the delta. The accounts created or removed in between are exported as well
+func (e *deltaExporter) ExportBalancesDelta(oldSnapshot BalancesSnapshot, newSnapshot BalancesSnapshot) error {
+	oldAccounts, err := e.trie.GetUserAccounts(oldSnapshot.RootHash, e.shouldIncludeAccount, 0)
+	if err != nil {
+		return trieToolsCommon.NewIntegrityError(fmt.Errorf("%w while reading the accounts of the old snapshot", err))
+	}
+	newAccounts, err := e.trie.GetUserAccounts(newSnapshot.RootHash, e.shouldIncludeAccount, 0)
+	if err != nil {
+		return trieToolsCommon.NewIntegrityError(fmt.Errorf("%w while reading the accounts of the new snapshot", err))
+	}
+
+	deltas := computeBalancesDeltas(oldAccounts, newAccounts)
+	metadata := e.createMetadata(oldSnapshot, newSnapshot, oldAccounts, newAccounts, deltas)
+
+	log.Info("Exporting balances delta:",
+		"oldRootHash", oldSnapshot.RootHash,
+		"newRootHash", newSnapshot.RootHash,
+		"numCreated", metadata.NumCreated,
+		"numRemoved", metadata.NumRemoved,
+		"numEmptied", metadata.NumEmptied,
+		"numChanged", metadata.NumChanged,
+		"deltaSum", metadata.DeltaSum,
+	)
+
+	text, err := e.deltasToCsv(deltas)
+	if err != nil {
+		return err
+	}
+
+	fileBasename := fmt.Sprintf("shard_%d_delta_%s_to_%s_%s", e.shardID, oldSnapshot.Label, newSnapshot.Label, e.currency)
+	deltaFilename := fileBasename + ".csv"
+	if e.gzip {
+		deltaFilename += gzipFileExtension
+		err = saveGzipFile(deltaFilename, text)
+		if err == nil {
+			log.Info("Saved file:", "file", deltaFilename)
+		}
+	} else {
+		err = saveFile(deltaFilename, text)
+	}
+	if err != nil {
+		return trieToolsCommon.NewIOError(err)
+	}
+
+	metadataJson, err := json.MarshalIndent(metadata, "", fourSpaces)
+	if err != nil {
+		return err
+	}
+
+	return trieToolsCommon.NewIOError(saveFile(fileBasename+".delta.metadata.json", string(metadataJson)))
+}
+
+func (e *deltaExporter) shouldIncludeAccount(account *state.UserAccountData) bool {
+	if !e.withContracts && core.IsSmartContractAddress(account.Address) {
+		return false
+	}
+
+	hasDesiredProjectedShard := e.projectedShardCoordinator.ComputeId(account.Address) == e.projectedShardCoordinator.SelfId()
+	if e.byProjectedShard.HasValue && !hasDesiredProjectedShard {
+		return false
+	}
+
+	return true
+}
+
+// computeBalancesDeltas returns the balance changes, sorted by address. The accounts whose balance did not change
+// are skipped, unless they were created or removed
+func computeBalancesDeltas(oldAccounts []*state.UserAccountData, newAccounts []*state.UserAccountData) []*balanceDelta {
+	oldBalances := make(map[string]*big.Int, len(oldAccounts))
+	for _, account := range oldAccounts {
+		oldBalances[string(account.Address)] = account.Balance
+	}
+
+	deltas := make([]*balanceDelta, 0)
+	for _, account := range newAccounts {
+		oldBalance, found := oldBalances[string(account.Address)]
+		delete(oldBalances, string(account.Address))
+
+		kind := DeltaKindChanged
+		switch {
+		case !found:
+			kind = DeltaKindCreated
+			oldBalance = big.NewInt(0)
+		case oldBalance.Cmp(account.Balance) == 0:
+			continue
+		case account.Balance.Sign() == 0:
+			kind = DeltaKindEmptied
+		}
+
+		deltas = append(deltas, newBalanceDelta(account.Address, oldBalance, account.Balance, kind))
+	}
+	for address, oldBalance := range oldBalances {
+		deltas = append(deltas, newBalanceDelta([]byte(address), oldBalance, big.NewInt(0), DeltaKindRemoved))
+	}
+
+	sort.Slice(deltas, func(i, j int) bool {
+		return bytes.Compare(deltas[i].address, deltas[j].address) < 0
+	})
+
+	return deltas
+}
+
+func newBalanceDelta(address []byte, oldBalance *big.Int, newBalance *big.Int, kind string) *balanceDelta {
+	return &balanceDelta{
+		address:    address,
+		oldBalance: oldBalance,
+		newBalance: newBalance,
+		delta:      big.NewInt(0).Sub(newBalance, oldBalance),
+		kind:       kind,
+	}
+}
+
+func (e *deltaExporter) deltasToCsv(deltas []*balanceDelta) (string, error) {
+	var builder strings.Builder
+	writer := csv.NewWriter(&builder)
+
+	err := writer.Write(deltaCsvColumns)
+	if err != nil {
+		return "", err
+	}
+
+	for _, delta := range deltas {
+		err = writer.Write([]string{
+			e.addressConverter.Encode(delta.address),
+			delta.oldBalance.String(),
+			delta.newBalance.String(),
+			delta.delta.String(),
+			delta.kind,
+		})
+		if err != nil {
+			return "", err
+		}
+	}
+
+	writer.Flush()
+	err = writer.Error()
+	if err != nil {
+		return "", err
+	}
+
+	return builder.String(), nil
+}
+
+func (e *deltaExporter) createMetadata(
+	oldSnapshot BalancesSnapshot,
+	newSnapshot BalancesSnapshot,
+	oldAccounts []*state.UserAccountData,
+	newAccounts []*state.UserAccountData,
+	deltas []*balanceDelta,
+) *deltaExportMetadata {
+	metadata := &deltaExportMetadata{
+		ActualShardID:            e.shardID,
+		OldSnapshot:              oldSnapshot.Label,
+		OldRootHash:              hex.EncodeToString(oldSnapshot.RootHash),
+		NewSnapshot:              newSnapshot.Label,
+		NewRootHash:              hex.EncodeToString(newSnapshot.RootHash),
+		Currency:                 e.currency,
+		WithContracts:            e.withContracts,
+		ByProjectedShardID:       e.byProjectedShard.Value,
+		ByProjectedShardHasValue: e.byProjectedShard.HasValue,
+		OldBalancesSum:           sumBalances(oldAccounts).String(),
+		NewBalancesSum:           sumBalances(newAccounts).String(),
+		Gzip:                     e.gzip,
+	}
+
+	deltaSum := big.NewInt(0)
+	for _, delta := range deltas {
+		deltaSum.Add(deltaSum, delta.delta)
+		switch delta.kind {
+		case DeltaKindCreated:
+			metadata.NumCreated++
+		case DeltaKindRemoved:
+			metadata.NumRemoved++
+		case DeltaKindEmptied:
+			metadata.NumEmptied++
+		case DeltaKindChanged:
+			metadata.NumChanged++
+		}
+	}
+	metadata.DeltaSum = deltaSum.String()
+
+	return metadata
+}
@@ -0,0 +1,126 @@
+package export
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/multiversx/mx-chain-go/state"
+	"github.com/stretchr/testify/require"
+)
+
+func createTestDeltaAccount(addressByte byte, balance string) *state.UserAccountData {
+	value, _ := big.NewInt(0).SetString(balance, 10)
+
+	return &state.UserAccountData{
+		Address: bytes.Repeat([]byte{addressByte}, addressLength),
+		Balance: value,
+	}
+}
+
+func TestComputeBalancesDeltas(t *testing.T) {
+	t.Parallel()
+
+	oldAccounts := []*state.UserAccountData{
+		createTestDeltaAccount(5, "100"),
+		createTestDeltaAccount(1, "100"),
+		createTestDeltaAccount(2, "100"),
+		createTestDeltaAccount(3, "100000000000000000000000"),
+	}
+	newAccounts := []*state.UserAccountData{
+		createTestDeltaAccount(1, "100"),
+		createTestDeltaAccount(3, "100000000000000000000001"),
+		createTestDeltaAccount(2, "0"),
+		createTestDeltaAccount(4, "0"),
+	}
+
+	deltas := computeBalancesDeltas(oldAccounts, newAccounts)
+	require.Len(t, deltas, 4)
+
+	require.Equal(t, bytes.Repeat([]byte{2}, addressLength), deltas[0].address)
+	require.Equal(t, DeltaKindEmptied, deltas[0].kind)
+	require.Equal(t, "-100", deltas[0].delta.String())
+
+	require.Equal(t, bytes.Repeat([]byte{3}, addressLength), deltas[1].address)
+	require.Equal(t, DeltaKindChanged, deltas[1].kind)
+	require.Equal(t, "100000000000000000000000", deltas[1].oldBalance.String())
+	require.Equal(t, "100000000000000000000001", deltas[1].newBalance.String())
+	require.Equal(t, "1", deltas[1].delta.String())
+
+	require.Equal(t, bytes.Repeat([]byte{4}, addressLength), deltas[2].address)
+	require.Equal(t, DeltaKindCreated, deltas[2].kind)
+	require.Equal(t, "0", deltas[2].oldBalance.String())
+	require.Equal(t, "0", deltas[2].delta.String())
+
+	require.Equal(t, bytes.Repeat([]byte{5}, addressLength), deltas[3].address)
+	require.Equal(t, DeltaKindRemoved, deltas[3].kind)
+	require.Equal(t, "0", deltas[3].newBalance.String())
+	require.Equal(t, "-100", deltas[3].delta.String())
+}
+
+func TestDeltaExporter_DeltasToCsv(t *testing.T) {
+	t.Parallel()
+
+	e, err := NewDeltaExporter(ArgsNewDeltaExporter{})
+	require.Nil(t, err)
+
+	deltas := computeBalancesDeltas(
+		[]*state.UserAccountData{createTestDeltaAccount(1, "10")},
+		[]*state.UserAccountData{createTestDeltaAccount(1, "15")},
+	)
+	text, err := e.deltasToCsv(deltas)
+	require.Nil(t, err)
+
+	address := defaultAddressConverter.Encode(bytes.Repeat([]byte{1}, addressLength))
+	require.Equal(t, "address,oldBalance,newBalance,delta,kind\n"+address+",10,15,5,changed\n", text)
+}
+
+func TestDeltaExporter_CreateMetadata(t *testing.T) {
+	t.Parallel()
+
+	e, err := NewDeltaExporter(ArgsNewDeltaExporter{ShardID: 1, Currency: "EGLD"})
+	require.Nil(t, err)
+
+	oldAccounts := []*state.UserAccountData{
+		createTestDeltaAccount(1, "10"),
+		createTestDeltaAccount(2, "20"),
+		createTestDeltaAccount(3, "30"),
+	}
+	newAccounts := []*state.UserAccountData{
+		createTestDeltaAccount(1, "15"),
+		createTestDeltaAccount(2, "0"),
+		createTestDeltaAccount(4, "7"),
+	}
+	deltas := computeBalancesDeltas(oldAccounts, newAccounts)
+
+	metadata := e.createMetadata(
+		BalancesSnapshot{RootHash: []byte{0xaa}, Label: "old"},
+		BalancesSnapshot{RootHash: []byte{0xbb}, Label: "new"},
+		oldAccounts, newAccounts, deltas,
+	)
+	require.Equal(t, "aa", metadata.OldRootHash)
+	require.Equal(t, "bb", metadata.NewRootHash)
+	require.Equal(t, 1, metadata.NumCreated)
+	require.Equal(t, 1, metadata.NumRemoved)
+	require.Equal(t, 1, metadata.NumEmptied)
+	require.Equal(t, 1, metadata.NumChanged)
+	require.Equal(t, "60", metadata.OldBalancesSum)
+	require.Equal(t, "22", metadata.NewBalancesSum)
+	require.Equal(t, "-38", metadata.DeltaSum)
+}
+
+func TestDeltaExporter_ShouldIncludeAccount(t *testing.T) {
+	t.Parallel()
+
+	contract := createTestDeltaAccount(0, "1")
+	user := createTestDeltaAccount(1, "1")
+
+	e, err := NewDeltaExporter(ArgsNewDeltaExporter{})
+	require.Nil(t, err)
+	require.False(t, e.shouldIncludeAccount(contract))
+	require.True(t, e.shouldIncludeAccount(user))
+
+	e, err = NewDeltaExporter(ArgsNewDeltaExporter{WithContracts: true})
+	require.Nil(t, err)
+	require.True(t, e.shouldIncludeAccount(contract))
+}
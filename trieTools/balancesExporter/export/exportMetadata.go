@@ -11,7 +11,36 @@ type exportMetadata struct {
 	CurrencyDecimals         uint   `json:"currencyDecimals"`
 	WithContracts            bool   `json:"withContracts"`
 	WithZero                 bool   `json:"withZero"`
+	MinBalance               string `json:"minBalance"`
 	ByProjectedShardID       uint32 `json:"byProjectedShardID"`
 	ByProjectedShardHasValue bool   `json:"byProjectedShardHasValue"`
 	NumAccounts              int    `json:"numAccounts"`
+	IncludeStaked            bool   `json:"includeStaked"`
+	ExportedBalancesSum      string `json:"exportedBalancesSum"`
+	Preview                  bool   `json:"preview"`
+	PreviewLimit             uint64 `json:"previewLimit"`
+	PreviewSampleRate        uint64 `json:"previewSampleRate"`
+	Gzip                     bool   `json:"gzip"`
+	HumanReadable            bool   `json:"humanReadable"`
+	Denomination             uint   `json:"denomination"`
+}
+
+type deltaExportMetadata struct {
+	ActualShardID            uint32 `json:"actualShardID"`
+	OldSnapshot              string `json:"oldSnapshot"`
+	OldRootHash              string `json:"oldRootHash"`
+	NewSnapshot              string `json:"newSnapshot"`
+	NewRootHash              string `json:"newRootHash"`
+	Currency                 string `json:"currency"`
+	WithContracts            bool   `json:"withContracts"`
+	ByProjectedShardID       uint32 `json:"byProjectedShardID"`
+	ByProjectedShardHasValue bool   `json:"byProjectedShardHasValue"`
+	NumCreated               int    `json:"numCreated"`
+	NumRemoved               int    `json:"numRemoved"`
+	NumEmptied               int    `json:"numEmptied"`
+	NumChanged               int    `json:"numChanged"`
+	OldBalancesSum           string `json:"oldBalancesSum"`
+	NewBalancesSum           string `json:"newBalancesSum"`
+	DeltaSum                 string `json:"deltaSum"`
+	Gzip                     bool   `json:"gzip"`
 }
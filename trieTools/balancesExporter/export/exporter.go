@@ -5,23 +5,40 @@ import (
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
+	"math/big"
+	"sync"
 
 	"github.com/multiversx/mx-chain-core-go/core"
+	"github.com/multiversx/mx-chain-core-go/core/check"
 	"github.com/multiversx/mx-chain-core-go/data"
 	"github.com/multiversx/mx-chain-go/sharding"
 	"github.com/multiversx/mx-chain-go/state"
 	"github.com/multiversx/mx-chain-tools-go/trieTools/balancesExporter/common"
+	"github.com/multiversx/mx-chain-tools-go/trieTools/trieToolsCommon"
 )
 
 // ArgsNewExporter holds arguments for creating an exporter
 type ArgsNewExporter struct {
-	TrieWrapper      trieWrapper
-	Format           string
-	ByProjectedShard common.OptionalUint32
-	Currency         string
-	CurrencyDecimals uint
-	WithContracts    bool
-	WithZero         bool
+	TrieWrapper       trieWrapper
+	Format            string
+	ByProjectedShard  common.OptionalUint32
+	Currency          string
+	CurrencyDecimals  uint
+	WithContracts     bool
+	WithZero          bool
+	MinBalance        *big.Int
+	Columns           []string
+	StakedBalances    map[string]*big.Int
+	SupplyCheck       SupplyCheck
+	Preview           Preview
+	ZeroBalanceReport ZeroBalanceReport
+	Gzip              bool
+	// HumanReadable renders the exported balances in whole tokens, with Denomination fractional digits, instead of
+	// the smallest denomination. It is not supported by the rosetta-json format
+	HumanReadable bool
+	Denomination  uint
+	// AddressConverter encodes the exported addresses. If not set, the addresses are encoded using the erd hrp
+	AddressConverter core.PubkeyConverter
 }
 
 type exporter struct {
@@ -33,15 +50,59 @@ type exporter struct {
 	currencyDecimals          uint
 	withContracts             bool
 	withZero                  bool
+	minBalance                *big.Int
+	columns                   []string
+	stakedBalances            map[string]*big.Int
+	supplyCheck               SupplyCheck
+	preview                   Preview
+	zeroBalanceReport         ZeroBalanceReport
+	gzip                      bool
+	humanReadable             bool
+	denomination              uint
+	addressConverter          core.PubkeyConverter
 }
 
 // NewExporter creates a new exporter
 func NewExporter(args ArgsNewExporter) (*exporter, error) {
+	columns := args.Columns
+	if args.Format == FormatterNameCsv {
+		err := checkCsvColumns(columns)
+		if err != nil {
+			return nil, err
+		}
+
+		hasStakedColumn := hasCsvColumn(columns, csvColumnStaked)
+		if hasStakedColumn && args.StakedBalances == nil {
+			return nil, fmt.Errorf("the %s csv column requires the staked balances to be resolved", csvColumnStaked)
+		}
+		if !hasStakedColumn && args.StakedBalances != nil {
+			columns = append(append(make([]string, 0, len(columns)+1), columns...), csvColumnStaked)
+		}
+	}
+	isStakedFormat := args.Format == FormatterNameCsv || args.Format == FormatterNamePlainJson
+	if args.StakedBalances != nil && !isStakedFormat {
+		return nil, fmt.Errorf("the staked balances can only be exported using the %s or %s formats", FormatterNameCsv, FormatterNamePlainJson)
+	}
+
+	if args.HumanReadable && args.Format == FormatterNameRosettaJson {
+		return nil, fmt.Errorf("the human readable balances can not be exported using the %s format, which holds the balances in the smallest denomination", FormatterNameRosettaJson)
+	}
+
 	projectedShardCoordinator, err := sharding.NewMultiShardCoordinator(core.MaxNumShards, args.ByProjectedShard.Value)
 	if err != nil {
 		return nil, err
 	}
 
+	minBalance := big.NewInt(0)
+	if args.MinBalance != nil {
+		minBalance.Set(args.MinBalance)
+	}
+
+	addressConverter := args.AddressConverter
+	if check.IfNil(addressConverter) {
+		addressConverter = defaultAddressConverter
+	}
+
 	return &exporter{
 		trie:                      args.TrieWrapper,
 		format:                    args.Format,
@@ -51,6 +112,16 @@ func NewExporter(args ArgsNewExporter) (*exporter, error) {
 		currencyDecimals:          args.CurrencyDecimals,
 		withContracts:             args.WithContracts,
 		withZero:                  args.WithZero,
+		minBalance:                minBalance,
+		columns:                   columns,
+		stakedBalances:            args.StakedBalances,
+		supplyCheck:               args.SupplyCheck,
+		preview:                   args.Preview,
+		zeroBalanceReport:         args.ZeroBalanceReport,
+		gzip:                      args.Gzip,
+		humanReadable:             args.HumanReadable,
+		denomination:              args.Denomination,
+		addressConverter:          addressConverter,
 	}, nil
 }
 
@@ -58,29 +129,69 @@ func NewExporter(args ArgsNewExporter) (*exporter, error) {
 func (e *exporter) ExportBalancesAtBlock(block data.HeaderHandler) error {
 	rootHash := block.GetRootHash()
 
-	accounts, err := e.trie.GetUserAccounts(rootHash, e.shouldExportAccount)
+	// the predicate is called concurrently when the accounts are unmarshalled by more than one worker
+	mutAllBalancesSum := sync.Mutex{}
+	allBalancesSum := big.NewInt(0)
+	zeroBalanceAccounts := newZeroBalanceReporter(e.zeroBalanceReport)
+	accounts, err := e.trie.GetUserAccounts(rootHash, func(account *state.UserAccountData) bool {
+		mutAllBalancesSum.Lock()
+		allBalancesSum.Add(allBalancesSum, account.Balance)
+		mutAllBalancesSum.Unlock()
+		zeroBalanceAccounts.addAccount(account)
+
+		return e.shouldExportAccount(account)
+	}, e.preview.numAccountsToRead())
 	if err != nil {
-		return err
+		return trieToolsCommon.NewIntegrityError(err)
+	}
+
+	if e.preview.IsEnabled() {
+		numMatchingAccounts := len(accounts)
+		accounts = e.preview.selectAccounts(accounts)
+		log.Warn("This is a preview export, which is NOT complete: only a subset of the matching accounts is read and exported",
+			"limit", e.preview.Limit,
+			"sampleRate", e.preview.SampleRate,
+			"numReadMatchingAccounts", numMatchingAccounts,
+			"numExportedAccounts", len(accounts),
+		)
 	}
 
+	exportedBalancesSum := sumBalances(accounts)
+
 	log.Info("Exporting:",
 		"numAccounts", len(accounts),
 		"blockNonce", block.GetNonce(),
 		"blockRootHash", block.GetRootHash(),
 		"formatType", e.format,
+		"allBalancesSum", allBalancesSum.String(),
+		"exportedBalancesSum", exportedBalancesSum.String(),
 	)
 
 	err = e.saveBalancesFile(block, accounts)
 	if err != nil {
-		return err
+		return trieToolsCommon.NewIOError(err)
 	}
 
-	err = e.saveMetadataFile(block, len(accounts))
+	err = e.saveMetadataFile(block, len(accounts), exportedBalancesSum)
 	if err != nil {
-		return err
+		return trieToolsCommon.NewIOError(err)
 	}
 
-	return nil
+	err = zeroBalanceAccounts.save(e.getOutputFileBasename(block), e.addressConverter)
+	if err != nil {
+		return trieToolsCommon.NewIOError(err)
+	}
+
+	return e.supplyCheck.check(exportedBalancesSum)
+}
+
+func sumBalances(accounts []*state.UserAccountData) *big.Int {
+	sum := big.NewInt(0)
+	for _, account := range accounts {
+		sum.Add(sum, account.Balance)
+	}
+
+	return sum
 }
 
 func (e *exporter) shouldExportAccount(account *state.UserAccountData) bool {
@@ -89,11 +200,18 @@ func (e *exporter) shouldExportAccount(account *state.UserAccountData) bool {
 		return false
 	}
 
-	hasZeroBalance := account.Balance.Sign() == 0
+	// when the staked balances are resolved, the filters apply on the total holdings
+	holdings := e.getTotalHoldings(account)
+	hasZeroBalance := holdings.Sign() == 0
 	if !e.withZero && hasZeroBalance {
 		return false
 	}
 
+	isBelowMinBalance := holdings.Cmp(e.minBalance) < 0
+	if isBelowMinBalance {
+		return false
+	}
+
 	hasDesiredProjectedShard := e.projectedShardCoordinator.ComputeId(account.Address) == e.projectedShardCoordinator.SelfId()
 	if e.byProjectedShard.HasValue && !hasDesiredProjectedShard {
 		return false
@@ -102,6 +220,15 @@ func (e *exporter) shouldExportAccount(account *state.UserAccountData) bool {
 	return true
 }
 
+func (e *exporter) getTotalHoldings(account *state.UserAccountData) *big.Int {
+	stakedBalance, found := e.stakedBalances[string(account.Address)]
+	if !found {
+		return account.Balance
+	}
+
+	return big.NewInt(0).Add(account.Balance, stakedBalance)
+}
+
 func (e *exporter) saveBalancesFile(block data.HeaderHandler, accounts []*state.UserAccountData) error {
 	formatter, err := e.getFormatter(block)
 	if err != nil {
@@ -109,8 +236,13 @@ func (e *exporter) saveBalancesFile(block data.HeaderHandler, accounts []*state.
 	}
 
 	formatterArgs := formatterArgs{
+		addressConverter: e.addressConverter,
 		currency:         e.currency,
 		currencyDecimals: e.currencyDecimals,
+		shardID:          block.GetShardID(),
+		stakedBalances:   e.stakedBalances,
+		humanReadable:    e.humanReadable,
+		denomination:     e.denomination,
 	}
 
 	text, err := formatter.toText(accounts, formatterArgs)
@@ -120,7 +252,18 @@ func (e *exporter) saveBalancesFile(block data.HeaderHandler, accounts []*state.
 
 	fileBasename := e.getOutputFileBasename(block)
 	balancesFilename := fmt.Sprintf("%s.%s", fileBasename, formatter.getFileExtension())
-	err = e.saveFile(balancesFilename, text)
+	if e.gzip {
+		balancesFilename += gzipFileExtension
+		err = saveGzipFile(balancesFilename, text)
+		if err != nil {
+			return err
+		}
+
+		log.Info("Saved file:", "file", balancesFilename)
+		return nil
+	}
+
+	err = saveFile(balancesFilename, text)
 	if err != nil {
 		return err
 	}
@@ -136,33 +279,43 @@ func (e *exporter) getFormatter(block data.HeaderHandler) (formatter, error) {
 		return &formatterPlainJson{}, nil
 	case FormatterNameRosettaJson:
 		return &formatterRosettaJson{}, nil
+	case FormatterNameCsv:
+		return &formatterCsv{columns: e.columns}, nil
 	}
 
 	return nil, fmt.Errorf("unknown format: %s", e.format)
 }
 
 func (e *exporter) getOutputFileBasename(block data.HeaderHandler) string {
+	// the preview exports are suffixed, so they are not mistaken for complete exports
+	suffix := ""
+	if e.preview.IsEnabled() {
+		suffix = previewFileSuffix
+	}
+
 	if e.byProjectedShard.HasValue {
-		return fmt.Sprintf("%s_shard_%d(%d)_epoch_%d_nonce_%d_%s",
+		return fmt.Sprintf("%s_shard_%d(%d)_epoch_%d_nonce_%d_%s%s",
 			block.GetChainID(),
 			block.GetShardID(),
 			e.byProjectedShard.Value,
 			block.GetEpoch(),
 			block.GetNonce(),
 			e.currency,
+			suffix,
 		)
 	}
 
-	return fmt.Sprintf("%s_shard_%d_epoch_%d_nonce_%d_%s",
+	return fmt.Sprintf("%s_shard_%d_epoch_%d_nonce_%d_%s%s",
 		block.GetChainID(),
 		block.GetShardID(),
 		block.GetEpoch(),
 		block.GetNonce(),
 		e.currency,
+		suffix,
 	)
 }
 
-func (e *exporter) saveMetadataFile(block data.HeaderHandler, numAccounts int) error {
+func (e *exporter) saveMetadataFile(block data.HeaderHandler, numAccounts int, exportedBalancesSum *big.Int) error {
 	metadata := &exportMetadata{
 		ChainID:                  string(block.GetChainID()),
 		ActualShardID:            block.GetShardID(),
@@ -174,9 +327,18 @@ func (e *exporter) saveMetadataFile(block data.HeaderHandler, numAccounts int) e
 		CurrencyDecimals:         e.currencyDecimals,
 		WithContracts:            e.withContracts,
 		WithZero:                 e.withZero,
+		MinBalance:               e.minBalance.String(),
 		ByProjectedShardID:       e.byProjectedShard.Value,
 		ByProjectedShardHasValue: e.byProjectedShard.HasValue,
 		NumAccounts:              numAccounts,
+		IncludeStaked:            e.stakedBalances != nil,
+		ExportedBalancesSum:      exportedBalancesSum.String(),
+		Preview:                  e.preview.IsEnabled(),
+		PreviewLimit:             e.preview.Limit,
+		PreviewSampleRate:        e.preview.SampleRate,
+		Gzip:                     e.gzip,
+		HumanReadable:            e.humanReadable,
+		Denomination:             e.denomination,
 	}
 
 	metadataJson, err := json.MarshalIndent(metadata, "", fourSpaces)
@@ -186,7 +348,7 @@ func (e *exporter) saveMetadataFile(block data.HeaderHandler, numAccounts int) e
 
 	fileBasename := e.getOutputFileBasename(block)
 	metadataFilename := fmt.Sprintf("%s.%s.metadata.json", fileBasename, e.format)
-	err = e.saveFile(metadataFilename, string(metadataJson))
+	err = saveFile(metadataFilename, string(metadataJson))
 	if err != nil {
 		return err
 	}
@@ -194,7 +356,7 @@ func (e *exporter) saveMetadataFile(block data.HeaderHandler, numAccounts int) e
 	return nil
 }
 
-func (e *exporter) saveFile(filename string, text string) error {
+func saveFile(filename string, text string) error {
 	err := ioutil.WriteFile(filename, []byte(text), core.FileModeReadWrite)
 	if err != nil {
 		return err
@@ -0,0 +1,166 @@
+package export
+
+import (
+	"bytes"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/multiversx/mx-chain-go/state"
+	"github.com/multiversx/mx-chain-tools-go/trieTools/trieToolsCommon"
+	"github.com/stretchr/testify/require"
+)
+
+func createTestUserAccount(balance int64) *state.UserAccountData {
+	return &state.UserAccountData{
+		Address: bytes.Repeat([]byte{1}, 32),
+		Balance: big.NewInt(balance),
+	}
+}
+
+func TestExporter_ShouldExportAccountWithMinBalance(t *testing.T) {
+	t.Parallel()
+
+	t.Run("no min balance should export all non-zero accounts", func(t *testing.T) {
+		t.Parallel()
+
+		e, err := NewExporter(ArgsNewExporter{})
+		require.Nil(t, err)
+
+		require.False(t, e.shouldExportAccount(createTestUserAccount(0)))
+		require.True(t, e.shouldExportAccount(createTestUserAccount(1)))
+	})
+	t.Run("accounts around the threshold", func(t *testing.T) {
+		t.Parallel()
+
+		e, err := NewExporter(ArgsNewExporter{
+			MinBalance: big.NewInt(1000),
+		})
+		require.Nil(t, err)
+
+		require.False(t, e.shouldExportAccount(createTestUserAccount(1)))
+		require.False(t, e.shouldExportAccount(createTestUserAccount(999)))
+		require.True(t, e.shouldExportAccount(createTestUserAccount(1000)))
+		require.True(t, e.shouldExportAccount(createTestUserAccount(1001)))
+	})
+	t.Run("thresholds larger than uint64 should work", func(t *testing.T) {
+		t.Parallel()
+
+		minBalance, _ := big.NewInt(0).SetString("100000000000000000000000", 10)
+		e, err := NewExporter(ArgsNewExporter{
+			MinBalance: minBalance,
+		})
+		require.Nil(t, err)
+
+		account := createTestUserAccount(0)
+		account.Balance = big.NewInt(0).Sub(minBalance, big.NewInt(1))
+		require.False(t, e.shouldExportAccount(account))
+
+		account.Balance = big.NewInt(0).Set(minBalance)
+		require.True(t, e.shouldExportAccount(account))
+	})
+	t.Run("with zero and zero min balance should export zero accounts", func(t *testing.T) {
+		t.Parallel()
+
+		e, err := NewExporter(ArgsNewExporter{
+			WithZero:   true,
+			MinBalance: big.NewInt(0),
+		})
+		require.Nil(t, err)
+
+		require.True(t, e.shouldExportAccount(createTestUserAccount(0)))
+	})
+}
+
+func TestNewExporter_AddressConverter(t *testing.T) {
+	t.Parallel()
+
+	t.Run("nil address converter should use the default one", func(t *testing.T) {
+		t.Parallel()
+
+		e, err := NewExporter(ArgsNewExporter{})
+		require.Nil(t, err)
+		require.Equal(t, defaultAddressConverter, e.addressConverter)
+	})
+	t.Run("should use the provided address converter", func(t *testing.T) {
+		t.Parallel()
+
+		addressConverter, err := trieToolsCommon.NewBech32AddressConverter(addressLength, "test", log)
+		require.Nil(t, err)
+
+		e, err := NewExporter(ArgsNewExporter{
+			Format:           FormatterNamePlainText,
+			AddressConverter: addressConverter,
+		})
+		require.Nil(t, err)
+
+		account := createTestUserAccount(1)
+		text, err := (&formatterPlainText{}).toText([]*state.UserAccountData{account}, formatterArgs{
+			addressConverter: e.addressConverter,
+			currency:         "EGLD",
+		})
+		require.Nil(t, err)
+		require.Equal(t, addressConverter.Encode(account.Address)+" 1 EGLD\n", text)
+		require.True(t, strings.HasPrefix(text, "test1"))
+	})
+}
+
+func TestNewExporter_WithStakedBalances(t *testing.T) {
+	t.Parallel()
+
+	t.Run("staked column without staked balances should error", func(t *testing.T) {
+		t.Parallel()
+
+		e, err := NewExporter(ArgsNewExporter{
+			Format:  FormatterNameCsv,
+			Columns: []string{csvColumnAddress, csvColumnStaked},
+		})
+		require.Nil(t, e)
+		require.NotNil(t, err)
+		require.True(t, strings.Contains(err.Error(), "requires the staked balances"))
+	})
+	t.Run("unsupported format should error", func(t *testing.T) {
+		t.Parallel()
+
+		e, err := NewExporter(ArgsNewExporter{
+			Format:         FormatterNameRosettaJson,
+			StakedBalances: make(map[string]*big.Int),
+		})
+		require.Nil(t, e)
+		require.NotNil(t, err)
+		require.True(t, strings.Contains(err.Error(), "can only be exported"))
+	})
+	t.Run("csv without staked column should add it", func(t *testing.T) {
+		t.Parallel()
+
+		columns := []string{csvColumnAddress, csvColumnBalance}
+		e, err := NewExporter(ArgsNewExporter{
+			Format:         FormatterNameCsv,
+			Columns:        columns,
+			StakedBalances: make(map[string]*big.Int),
+		})
+		require.Nil(t, err)
+		require.Equal(t, []string{csvColumnAddress, csvColumnBalance, csvColumnStaked}, e.columns)
+		require.Equal(t, []string{csvColumnAddress, csvColumnBalance}, columns)
+	})
+}
+
+func TestExporter_ShouldExportAccountWithStakedBalances(t *testing.T) {
+	t.Parallel()
+
+	account := createTestUserAccount(0)
+	e, err := NewExporter(ArgsNewExporter{
+		Format:     FormatterNamePlainJson,
+		MinBalance: big.NewInt(1000),
+		StakedBalances: map[string]*big.Int{
+			string(account.Address): big.NewInt(999),
+		},
+	})
+	require.Nil(t, err)
+
+	require.False(t, e.shouldExportAccount(account))
+
+	account.Balance = big.NewInt(1)
+	require.True(t, e.shouldExportAccount(account))
+	require.Equal(t, big.NewInt(1), account.Balance)
+}
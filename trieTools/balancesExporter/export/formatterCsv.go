@@ -0,0 +1,114 @@
+package export
+
+import (
+	"encoding/csv"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/multiversx/mx-chain-go/state"
+)
+
+const (
+	csvColumnAddress = "address"
+	csvColumnBalance = "balance"
+	// csvColumnRawBalance always holds the balance in the smallest denomination, even if the balances are human readable
+	csvColumnRawBalance = "rawBalance"
+	csvColumnNonce      = "nonce"
+	csvColumnRootHash   = "rootHash"
+	csvColumnShard      = "shard"
+	csvColumnStaked     = "staked"
+)
+
+var (
+	allCsvColumns      = []string{csvColumnAddress, csvColumnBalance, csvColumnRawBalance, csvColumnNonce, csvColumnRootHash, csvColumnShard, csvColumnStaked}
+	AllCsvColumnsNames = strings.Join(allCsvColumns, ", ")
+)
+
+type formatterCsv struct {
+	columns []string
+}
+
+func checkCsvColumns(columns []string) error {
+	if len(columns) == 0 {
+		return fmt.Errorf("no csv columns provided, valid columns are: %s", AllCsvColumnsNames)
+	}
+
+	for _, column := range columns {
+		if !isKnownCsvColumn(column) {
+			return fmt.Errorf("unknown csv column: %s, valid columns are: %s", column, AllCsvColumnsNames)
+		}
+	}
+
+	return nil
+}
+
+func hasCsvColumn(columns []string, column string) bool {
+	for _, c := range columns {
+		if c == column {
+			return true
+		}
+	}
+
+	return false
+}
+
+func isKnownCsvColumn(column string) bool {
+	return hasCsvColumn(allCsvColumns, column)
+}
+
+func (f *formatterCsv) toText(accounts []*state.UserAccountData, args formatterArgs) (string, error) {
+	var builder strings.Builder
+	writer := csv.NewWriter(&builder)
+
+	err := writer.Write(f.columns)
+	if err != nil {
+		return "", err
+	}
+
+	for _, account := range accounts {
+		record := make([]string, 0, len(f.columns))
+		for _, column := range f.columns {
+			record = append(record, getCsvValue(account, column, args))
+		}
+
+		err = writer.Write(record)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	writer.Flush()
+	err = writer.Error()
+	if err != nil {
+		return "", err
+	}
+
+	return builder.String(), nil
+}
+
+func getCsvValue(account *state.UserAccountData, column string, args formatterArgs) string {
+	switch column {
+	case csvColumnAddress:
+		return args.addressConverter.Encode(account.Address)
+	case csvColumnBalance:
+		return formatBalance(account.Balance, args)
+	case csvColumnRawBalance:
+		return account.Balance.String()
+	case csvColumnNonce:
+		return strconv.FormatUint(account.Nonce, 10)
+	case csvColumnRootHash:
+		return hex.EncodeToString(account.RootHash)
+	case csvColumnShard:
+		return strconv.FormatUint(uint64(args.shardID), 10)
+	case csvColumnStaked:
+		return getStakedBalance(account, args)
+	}
+
+	return ""
+}
+
+func (f *formatterCsv) getFileExtension() string {
+	return "csv"
+}
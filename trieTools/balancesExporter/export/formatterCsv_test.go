@@ -0,0 +1,147 @@
+package export
+
+import (
+	"bytes"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/multiversx/mx-chain-go/state"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckCsvColumns(t *testing.T) {
+	t.Parallel()
+
+	t.Run("no columns should error", func(t *testing.T) {
+		t.Parallel()
+
+		err := checkCsvColumns(nil)
+		require.NotNil(t, err)
+		require.True(t, strings.Contains(err.Error(), AllCsvColumnsNames))
+	})
+	t.Run("unknown column should error", func(t *testing.T) {
+		t.Parallel()
+
+		err := checkCsvColumns([]string{csvColumnAddress, "unknown"})
+		require.NotNil(t, err)
+		require.True(t, strings.Contains(err.Error(), "unknown csv column: unknown"))
+		require.True(t, strings.Contains(err.Error(), AllCsvColumnsNames))
+	})
+	t.Run("should work", func(t *testing.T) {
+		t.Parallel()
+
+		err := checkCsvColumns(allCsvColumns)
+		require.Nil(t, err)
+	})
+}
+
+func TestNewExporter_CsvWithUnknownColumnShouldError(t *testing.T) {
+	t.Parallel()
+
+	e, err := NewExporter(ArgsNewExporter{
+		Format:  FormatterNameCsv,
+		Columns: []string{"unknown"},
+	})
+	require.Nil(t, e)
+	require.NotNil(t, err)
+}
+
+func TestFormatterCsv_ToText(t *testing.T) {
+	t.Parallel()
+
+	address := bytes.Repeat([]byte{1}, addressLength)
+	accounts := []*state.UserAccountData{
+		{
+			Address:  address,
+			Balance:  big.NewInt(1000),
+			Nonce:    7,
+			RootHash: []byte{0xaa, 0xbb},
+		},
+	}
+
+	f := &formatterCsv{columns: []string{csvColumnShard, csvColumnNonce, csvColumnAddress, csvColumnBalance, csvColumnRootHash}}
+	text, err := f.toText(accounts, formatterArgs{addressConverter: defaultAddressConverter, shardID: 2})
+	require.Nil(t, err)
+
+	expectedText := "shard,nonce,address,balance,rootHash\n" +
+		"2,7," + defaultAddressConverter.Encode(address) + ",1000,aabb\n"
+	require.Equal(t, expectedText, text)
+	require.Equal(t, "csv", f.getFileExtension())
+}
+
+func TestFormatterCsv_ToTextWithStakedBalances(t *testing.T) {
+	t.Parallel()
+
+	stakedAddress := bytes.Repeat([]byte{1}, addressLength)
+	otherAddress := bytes.Repeat([]byte{2}, addressLength)
+	accounts := []*state.UserAccountData{
+		{
+			Address: stakedAddress,
+			Balance: big.NewInt(1000),
+		},
+		{
+			Address: otherAddress,
+			Balance: big.NewInt(10),
+		},
+	}
+
+	f := &formatterCsv{columns: []string{csvColumnAddress, csvColumnBalance, csvColumnStaked}}
+	text, err := f.toText(accounts, formatterArgs{
+		addressConverter: defaultAddressConverter,
+		stakedBalances: map[string]*big.Int{
+			string(stakedAddress): big.NewInt(2500),
+		},
+	})
+	require.Nil(t, err)
+
+	expectedText := "address,balance,staked\n" +
+		defaultAddressConverter.Encode(stakedAddress) + ",1000,2500\n" +
+		defaultAddressConverter.Encode(otherAddress) + ",10,0\n"
+	require.Equal(t, expectedText, text)
+}
+
+func TestFormatterCsv_ToTextHumanReadable(t *testing.T) {
+	t.Parallel()
+
+	address := bytes.Repeat([]byte{1}, addressLength)
+	accounts := []*state.UserAccountData{
+		{
+			Address: address,
+			Balance: big.NewInt(1050),
+		},
+	}
+
+	f := &formatterCsv{columns: []string{csvColumnAddress, csvColumnBalance, csvColumnRawBalance, csvColumnStaked}}
+	text, err := f.toText(accounts, formatterArgs{
+		addressConverter: defaultAddressConverter,
+		stakedBalances:   map[string]*big.Int{},
+		humanReadable:    true,
+		denomination:     3,
+	})
+	require.Nil(t, err)
+
+	expectedText := "address,balance,rawBalance,staked\n" +
+		defaultAddressConverter.Encode(address) + ",1.050,1050,0.000\n"
+	require.Equal(t, expectedText, text)
+}
+
+func TestNewExporter_HumanReadableRosettaJsonShouldError(t *testing.T) {
+	t.Parallel()
+
+	e, err := NewExporter(ArgsNewExporter{
+		Format:        FormatterNameRosettaJson,
+		HumanReadable: true,
+		Denomination:  18,
+	})
+	require.Nil(t, e)
+	require.NotNil(t, err)
+
+	e, err = NewExporter(ArgsNewExporter{
+		Format:        FormatterNamePlainJson,
+		HumanReadable: true,
+		Denomination:  18,
+	})
+	require.Nil(t, err)
+	require.True(t, e.humanReadable)
+}
@@ -13,12 +13,13 @@ func (f *formatterPlainJson) toText(accounts []*state.UserAccountData, args form
 	records := make([]plainBalance, 0, len(accounts))
 
 	for _, account := range accounts {
-		address := addressConverter.Encode(account.Address)
-		balance := account.Balance.String()
+		address := args.addressConverter.Encode(account.Address)
+		balance := formatBalance(account.Balance, args)
 
 		records = append(records, plainBalance{
 			Address: address,
 			Balance: balance,
+			Staked:  getStakedBalance(account, args),
 		})
 	}
 
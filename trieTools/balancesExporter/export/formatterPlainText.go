@@ -10,6 +10,7 @@ import (
 type plainBalance struct {
 	Address string `json:"address"`
 	Balance string `json:"balance"`
+	Staked  string `json:"staked,omitempty"`
 }
 
 type formatterPlainText struct {
@@ -19,8 +20,8 @@ func (f *formatterPlainText) toText(accounts []*state.UserAccountData, args form
 	var builder strings.Builder
 
 	for _, account := range accounts {
-		address := addressConverter.Encode(account.Address)
-		balance := account.Balance.String()
+		address := args.addressConverter.Encode(account.Address)
+		balance := formatBalance(account.Balance, args)
 		line := fmt.Sprintf("%s %s %s\n", address, balance, args.currency)
 		_, err := builder.WriteString(line)
 		if err != nil {
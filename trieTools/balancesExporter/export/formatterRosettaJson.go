@@ -33,7 +33,7 @@ func (f *formatterRosettaJson) toText(accounts []*state.UserAccountData, args fo
 	}
 
 	for _, account := range accounts {
-		address := addressConverter.Encode(account.Address)
+		address := args.addressConverter.Encode(account.Address)
 		balance := account.Balance.String()
 
 		records = append(records, rosettaBalance{
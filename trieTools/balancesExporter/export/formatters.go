@@ -1,25 +1,49 @@
 package export
 
 import (
+	"math/big"
 	"strings"
 
-	"github.com/multiversx/mx-chain-core-go/core/pubkeyConverter"
+	"github.com/multiversx/mx-chain-core-go/core"
+	"github.com/multiversx/mx-chain-go/state"
+	"github.com/multiversx/mx-chain-tools-go/trieTools/trieToolsCommon"
 )
 
 const (
 	FormatterNamePlainText   = "plain-text"
 	FormatterNamePlainJson   = "plain-json"
 	FormatterNameRosettaJson = "rosetta-json"
+	FormatterNameCsv         = "csv"
 	fourSpaces               = "    "
 	addressLength            = 32
 )
 
 var (
-	AllFormattersNames  = strings.Join([]string{FormatterNamePlainText, FormatterNamePlainText, FormatterNameRosettaJson}, ", ")
-	addressConverter, _ = pubkeyConverter.NewBech32PubkeyConverter(addressLength, log)
+	AllFormattersNames         = strings.Join([]string{FormatterNamePlainText, FormatterNamePlainJson, FormatterNameRosettaJson, FormatterNameCsv}, ", ")
+	defaultAddressConverter, _ = trieToolsCommon.NewBech32AddressConverter(addressLength, trieToolsCommon.DefaultAddressHrp, log)
 )
 
 type formatterArgs struct {
+	addressConverter core.PubkeyConverter
 	currency         string
 	currencyDecimals uint
+	shardID          uint32
+	stakedBalances   map[string]*big.Int
+	humanReadable    bool
+	denomination     uint
+}
+
+// getStakedBalance returns the staked balance of the provided account, or an empty string if the staked balances
+// were not resolved
+func getStakedBalance(account *state.UserAccountData, args formatterArgs) string {
+	if args.stakedBalances == nil {
+		return ""
+	}
+
+	stakedBalance, found := args.stakedBalances[string(account.Address)]
+	if !found {
+		return formatBalance(big.NewInt(0), args)
+	}
+
+	return formatBalance(stakedBalance, args)
 }
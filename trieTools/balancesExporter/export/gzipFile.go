@@ -0,0 +1,61 @@
+package export
+
+import (
+	"compress/gzip"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/multiversx/mx-chain-core-go/core"
+)
+
+const gzipFileExtension = ".gz"
+
+// saveGzipFile writes the gzip compressed text in the provided file. The data is written in a temporary file, which is
+// renamed only after the gzip stream and the file were successfully closed, so a failure does not leave a truncated file
+func saveGzipFile(filename string, text string) error {
+	tempFile, err := ioutil.TempFile(filepath.Dir(filename), filepath.Base(filename)+".tmp*")
+	if err != nil {
+		return err
+	}
+	tempFilename := tempFile.Name()
+
+	err = writeGzip(tempFile, text)
+	if err != nil {
+		_ = os.Remove(tempFilename)
+		return err
+	}
+
+	err = os.Chmod(tempFilename, core.FileModeReadWrite)
+	if err != nil {
+		_ = os.Remove(tempFilename)
+		return err
+	}
+
+	err = os.Rename(tempFilename, filename)
+	if err != nil {
+		_ = os.Remove(tempFilename)
+		return err
+	}
+
+	return nil
+}
+
+func writeGzip(file *os.File, text string) error {
+	writer := gzip.NewWriter(file)
+	_, err := writer.Write([]byte(text))
+	if err != nil {
+		_ = writer.Close()
+		_ = file.Close()
+		return err
+	}
+
+	// closing the gzip writer flushes the remaining data and writes the gzip footer
+	err = writer.Close()
+	if err != nil {
+		_ = file.Close()
+		return err
+	}
+
+	return file.Close()
+}
@@ -0,0 +1,50 @@
+package export
+
+import (
+	"compress/gzip"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSaveGzipFile(t *testing.T) {
+	t.Parallel()
+
+	t.Run("missing directory should error", func(t *testing.T) {
+		t.Parallel()
+
+		filename := filepath.Join(t.TempDir(), "missing", "balances.txt.gz")
+		err := saveGzipFile(filename, "text")
+		require.NotNil(t, err)
+	})
+	t.Run("round trip should be byte identical", func(t *testing.T) {
+		t.Parallel()
+
+		dir := t.TempDir()
+		filename := filepath.Join(dir, "balances.txt"+gzipFileExtension)
+		text := strings.Repeat("erd1qyu5wthldzr8wx5c9ucg8kjagg0jfs53s8nr3zpz3hypefsdd8ssycr6th 1000000000000000000 EGLD\n", 1000)
+
+		err := saveGzipFile(filename, text)
+		require.Nil(t, err)
+
+		file, err := os.Open(filename)
+		require.Nil(t, err)
+		defer func() {
+			_ = file.Close()
+		}()
+
+		reader, err := gzip.NewReader(file)
+		require.Nil(t, err)
+		decompressed, err := ioutil.ReadAll(reader)
+		require.Nil(t, err)
+		require.Equal(t, text, string(decompressed))
+
+		entries, err := ioutil.ReadDir(dir)
+		require.Nil(t, err)
+		require.Len(t, entries, 1)
+	})
+}
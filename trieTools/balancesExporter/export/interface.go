@@ -5,7 +5,7 @@ import (
 )
 
 type trieWrapper interface {
-	GetUserAccounts(rootHash []byte, predicate func(*state.UserAccountData) bool) ([]*state.UserAccountData, error)
+	GetUserAccounts(rootHash []byte, predicate func(*state.UserAccountData) bool, limit uint64) ([]*state.UserAccountData, error)
 }
 
 type formatter interface {
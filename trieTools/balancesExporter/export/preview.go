@@ -0,0 +1,57 @@
+package export
+
+import (
+	"github.com/multiversx/mx-chain-go/state"
+)
+
+const previewFileSuffix = "_preview"
+
+// Preview holds the arguments of a preview export, which only exports a subset of the accounts (for testing the
+// flags and the formats without exporting the whole trie). A preview export is never complete
+type Preview struct {
+	// Limit is the maximum number of exported accounts. If 0, there is no limit
+	Limit uint64
+	// SampleRate, if greater than 1, makes only one of every SampleRate accounts to be exported
+	SampleRate uint64
+}
+
+// IsEnabled returns true if the export is restricted to a subset of the accounts
+func (p Preview) IsEnabled() bool {
+	return p.Limit > 0 || p.SampleRate > 1
+}
+
+// numAccountsToRead returns the number of matching accounts to be read from the trie, so that the trie walk can stop
+// early, or 0 if all the accounts have to be read. With a sample rate, Limit sampled accounts need Limit * SampleRate
+// matching accounts
+func (p Preview) numAccountsToRead() uint64 {
+	if p.Limit == 0 {
+		return 0
+	}
+	if p.SampleRate > 1 {
+		return p.Limit * p.SampleRate
+	}
+
+	return p.Limit
+}
+
+// selectAccounts returns one of every SampleRate accounts, up to Limit accounts. The accounts are kept in the
+// provided order, so the selection is the same from one run to the other
+func (p Preview) selectAccounts(accounts []*state.UserAccountData) []*state.UserAccountData {
+	if !p.IsEnabled() {
+		return accounts
+	}
+
+	selected := make([]*state.UserAccountData, 0)
+	for i, account := range accounts {
+		if p.Limit > 0 && uint64(len(selected)) >= p.Limit {
+			break
+		}
+		if p.SampleRate > 1 && uint64(i)%p.SampleRate != 0 {
+			continue
+		}
+
+		selected = append(selected, account)
+	}
+
+	return selected
+}
@@ -0,0 +1,98 @@
+package export
+
+import (
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/multiversx/mx-chain-core-go/data/block"
+	"github.com/multiversx/mx-chain-go/state"
+	"github.com/stretchr/testify/require"
+)
+
+func createTestPreviewAccounts(numAccounts int) []*state.UserAccountData {
+	accounts := make([]*state.UserAccountData, 0, numAccounts)
+	for i := 0; i < numAccounts; i++ {
+		accounts = append(accounts, createTestUserAccount(int64(i)))
+	}
+
+	return accounts
+}
+
+func requireBalances(t *testing.T, accounts []*state.UserAccountData, balances ...int64) {
+	require.Len(t, accounts, len(balances))
+	for i, balance := range balances {
+		require.Equal(t, big.NewInt(balance), accounts[i].Balance)
+	}
+}
+
+func TestPreview_IsEnabled(t *testing.T) {
+	t.Parallel()
+
+	require.False(t, Preview{}.IsEnabled())
+	require.False(t, Preview{SampleRate: 1}.IsEnabled())
+	require.True(t, Preview{Limit: 1}.IsEnabled())
+	require.True(t, Preview{SampleRate: 2}.IsEnabled())
+}
+
+func TestPreview_SelectAccounts(t *testing.T) {
+	t.Parallel()
+
+	accounts := createTestPreviewAccounts(100)
+
+	t.Run("disabled preview should select all accounts", func(t *testing.T) {
+		t.Parallel()
+
+		require.Equal(t, accounts, Preview{}.selectAccounts(accounts))
+	})
+	t.Run("limit should be honored exactly", func(t *testing.T) {
+		t.Parallel()
+
+		for _, limit := range []uint64{1, 7, 99, 100} {
+			selected := Preview{Limit: limit}.selectAccounts(accounts)
+			require.Len(t, selected, int(limit), fmt.Sprintf("limit %d", limit))
+			require.Equal(t, accounts[:limit], selected)
+		}
+
+		require.Equal(t, accounts, Preview{Limit: 1000}.selectAccounts(accounts))
+	})
+	t.Run("limit should stop reading the accounts early", func(t *testing.T) {
+		t.Parallel()
+
+		require.Equal(t, uint64(0), Preview{}.numAccountsToRead())
+		require.Equal(t, uint64(0), Preview{SampleRate: 10}.numAccountsToRead())
+		require.Equal(t, uint64(7), Preview{Limit: 7}.numAccountsToRead())
+		require.Equal(t, uint64(7), Preview{Limit: 7, SampleRate: 1}.numAccountsToRead())
+
+		// reading only the accounts needed by the limit gives the same selection as reading all of them
+		preview := Preview{Limit: 3, SampleRate: 10}
+		require.Equal(t, uint64(30), preview.numAccountsToRead())
+		require.Equal(t, preview.selectAccounts(accounts), preview.selectAccounts(accounts[:preview.numAccountsToRead()]))
+	})
+	t.Run("sample rate should select every Kth account", func(t *testing.T) {
+		t.Parallel()
+
+		selected := Preview{SampleRate: 25}.selectAccounts(accounts)
+		requireBalances(t, selected, 0, 25, 50, 75)
+	})
+	t.Run("limit applies on the sampled accounts", func(t *testing.T) {
+		t.Parallel()
+
+		selected := Preview{Limit: 3, SampleRate: 10}.selectAccounts(accounts)
+		requireBalances(t, selected, 0, 10, 20)
+	})
+}
+
+func TestExporter_GetOutputFileBasenameWithPreview(t *testing.T) {
+	t.Parallel()
+
+	header := &block.Header{ChainID: []byte("1"), ShardID: 0, Epoch: 5, Nonce: 10}
+
+	e, err := NewExporter(ArgsNewExporter{Currency: "EGLD"})
+	require.Nil(t, err)
+	require.Equal(t, "1_shard_0_epoch_5_nonce_10_EGLD", e.getOutputFileBasename(header))
+
+	e, err = NewExporter(ArgsNewExporter{Currency: "EGLD", Preview: Preview{Limit: 10}})
+	require.Nil(t, err)
+	require.Equal(t, "1_shard_0_epoch_5_nonce_10_EGLD_preview", e.getOutputFileBasename(header))
+}
@@ -0,0 +1,52 @@
+package export
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/multiversx/mx-chain-tools-go/trieTools/trieToolsCommon"
+)
+
+// SupplyCheck holds the arguments of the total supply reconciliation
+type SupplyCheck struct {
+	// ExpectedSupply is the expected total supply. If nil, the reconciliation is disabled
+	ExpectedSupply *big.Int
+	// Offset is added to the sum of the exported balances before comparing it with the expected supply
+	// (e.g. the burned or reserved amounts, which are not held by the exported accounts)
+	Offset *big.Int
+	// Tolerance is the maximum accepted absolute difference between the expected and the computed supply
+	Tolerance *big.Int
+}
+
+// check returns an error if the sum of the exported balances, plus the offset, differs from the expected supply
+// by more than the tolerance
+func (sc SupplyCheck) check(exportedBalancesSum *big.Int) error {
+	if sc.ExpectedSupply == nil {
+		return nil
+	}
+
+	computedSupply := big.NewInt(0).Set(exportedBalancesSum)
+	if sc.Offset != nil {
+		computedSupply.Add(computedSupply, sc.Offset)
+	}
+
+	tolerance := big.NewInt(0)
+	if sc.Tolerance != nil {
+		tolerance.Set(sc.Tolerance)
+	}
+
+	difference := big.NewInt(0).Sub(computedSupply, sc.ExpectedSupply)
+	log.Info("Supply reconciliation:",
+		"expectedSupply", sc.ExpectedSupply.String(),
+		"computedSupply", computedSupply.String(),
+		"difference", difference.String(),
+		"tolerance", tolerance.String(),
+	)
+
+	if difference.CmpAbs(tolerance) > 0 {
+		return trieToolsCommon.NewIntegrityError(fmt.Errorf("supply mismatch: expected %s, computed %s (exported balances %s plus offset), difference %s exceeds the tolerance %s",
+			sc.ExpectedSupply.String(), computedSupply.String(), exportedBalancesSum.String(), difference.String(), tolerance.String()))
+	}
+
+	return nil
+}
@@ -0,0 +1,67 @@
+package export
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/multiversx/mx-chain-go/state"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSupplyCheck_Check(t *testing.T) {
+	t.Parallel()
+
+	t.Run("no expected supply should not check", func(t *testing.T) {
+		t.Parallel()
+
+		sc := SupplyCheck{}
+		require.Nil(t, sc.check(big.NewInt(100)))
+	})
+	t.Run("difference above the tolerance should error", func(t *testing.T) {
+		t.Parallel()
+
+		sc := SupplyCheck{
+			ExpectedSupply: big.NewInt(1000),
+			Offset:         big.NewInt(100),
+			Tolerance:      big.NewInt(10),
+		}
+		err := sc.check(big.NewInt(889))
+		require.NotNil(t, err)
+		require.True(t, strings.Contains(err.Error(), "supply mismatch: expected 1000, computed 989"))
+
+		err = sc.check(big.NewInt(911))
+		require.NotNil(t, err)
+		require.True(t, strings.Contains(err.Error(), "difference 11 exceeds the tolerance 10"))
+	})
+	t.Run("difference within the tolerance should work", func(t *testing.T) {
+		t.Parallel()
+
+		sc := SupplyCheck{
+			ExpectedSupply: big.NewInt(1000),
+			Offset:         big.NewInt(100),
+			Tolerance:      big.NewInt(10),
+		}
+		require.Nil(t, sc.check(big.NewInt(890)))
+		require.Nil(t, sc.check(big.NewInt(900)))
+		require.Nil(t, sc.check(big.NewInt(910)))
+	})
+	t.Run("nil offset and tolerance should require an exact match", func(t *testing.T) {
+		t.Parallel()
+
+		sc := SupplyCheck{
+			ExpectedSupply: big.NewInt(1000),
+		}
+		require.Nil(t, sc.check(big.NewInt(1000)))
+		require.NotNil(t, sc.check(big.NewInt(999)))
+	})
+}
+
+func TestSumBalances(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, big.NewInt(0), sumBalances(nil))
+
+	accounts := []*state.UserAccountData{createTestUserAccount(1), createTestUserAccount(20), createTestUserAccount(300)}
+	require.Equal(t, big.NewInt(321), sumBalances(accounts))
+}
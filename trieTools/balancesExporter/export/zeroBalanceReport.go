@@ -0,0 +1,170 @@
+package export
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/multiversx/mx-chain-core-go/core"
+	"github.com/multiversx/mx-chain-go/state"
+)
+
+const zeroBalanceFileSuffix = ".zero-balance"
+
+var zeroBalanceCsvColumns = []string{"address", "nonce", "hasCode", "hasDataTrie"}
+
+// ZeroBalanceReport holds the arguments of the report of the accounts with zero balance, which still exist in the
+// trie (e.g. because of their nonce or code). The accounts are counted while reading the trie for the export, before
+// the export filters are applied
+type ZeroBalanceReport struct {
+	// Enabled counts the zero balance accounts and saves the figures in a separate file
+	Enabled bool
+	// ListAccounts also saves the zero balance accounts in a csv file, sorted by address. It implies Enabled
+	ListAccounts bool
+}
+
+// IsEnabled returns true if the zero balance accounts should be counted
+func (r ZeroBalanceReport) IsEnabled() bool {
+	return r.Enabled || r.ListAccounts
+}
+
+// zeroBalanceFigures holds the counters of the zero balance accounts. An account with both code and a nonzero nonce
+// is counted as an account with code
+type zeroBalanceFigures struct {
+	NumAccounts             int `json:"numZeroBalanceAccounts"`
+	NumWithCode             int `json:"numWithCode"`
+	NumWithNonceWithoutCode int `json:"numWithNonceWithoutCode"`
+	NumWithoutNonceAndCode  int `json:"numWithoutNonceAndCode"`
+	NumWithDataTrie         int `json:"numWithDataTrie"`
+}
+
+// zeroBalanceReporter gathers the zero balance accounts. It is concurrent safe, since the accounts are read by more
+// than one worker
+type zeroBalanceReporter struct {
+	report   ZeroBalanceReport
+	mut      sync.Mutex
+	figures  zeroBalanceFigures
+	accounts []*state.UserAccountData
+}
+
+func newZeroBalanceReporter(report ZeroBalanceReport) *zeroBalanceReporter {
+	return &zeroBalanceReporter{
+		report:   report,
+		accounts: make([]*state.UserAccountData, 0),
+	}
+}
+
+func (r *zeroBalanceReporter) addAccount(account *state.UserAccountData) {
+	if !r.report.IsEnabled() || account.Balance.Sign() != 0 {
+		return
+	}
+
+	r.mut.Lock()
+	defer r.mut.Unlock()
+
+	r.figures.NumAccounts++
+	switch {
+	case len(account.CodeHash) > 0:
+		r.figures.NumWithCode++
+	case account.Nonce > 0:
+		r.figures.NumWithNonceWithoutCode++
+	default:
+		r.figures.NumWithoutNonceAndCode++
+	}
+	if len(account.RootHash) > 0 {
+		r.figures.NumWithDataTrie++
+	}
+
+	if r.report.ListAccounts {
+		r.accounts = append(r.accounts, account)
+	}
+}
+
+func (r *zeroBalanceReporter) getFigures() zeroBalanceFigures {
+	r.mut.Lock()
+	defer r.mut.Unlock()
+
+	return r.figures
+}
+
+// accountsToCsv returns the listed accounts as csv, sorted by address, since the workers add them in any order
+func (r *zeroBalanceReporter) accountsToCsv(addressConverter core.PubkeyConverter) (string, error) {
+	r.mut.Lock()
+	accounts := make([]*state.UserAccountData, len(r.accounts))
+	copy(accounts, r.accounts)
+	r.mut.Unlock()
+
+	sort.Slice(accounts, func(i, j int) bool {
+		return bytes.Compare(accounts[i].Address, accounts[j].Address) < 0
+	})
+
+	var builder strings.Builder
+	writer := csv.NewWriter(&builder)
+
+	err := writer.Write(zeroBalanceCsvColumns)
+	if err != nil {
+		return "", err
+	}
+
+	for _, account := range accounts {
+		err = writer.Write([]string{
+			addressConverter.Encode(account.Address),
+			strconv.FormatUint(account.Nonce, 10),
+			strconv.FormatBool(len(account.CodeHash) > 0),
+			strconv.FormatBool(len(account.RootHash) > 0),
+		})
+		if err != nil {
+			return "", err
+		}
+	}
+
+	writer.Flush()
+	err = writer.Error()
+	if err != nil {
+		return "", err
+	}
+
+	return builder.String(), nil
+}
+
+// save logs the figures and saves them in <fileBasename>.zero-balance.json. The accounts, if listed, are saved in
+// <fileBasename>.zero-balance.csv
+func (r *zeroBalanceReporter) save(fileBasename string, addressConverter core.PubkeyConverter) error {
+	if !r.report.IsEnabled() {
+		return nil
+	}
+
+	figures := r.getFigures()
+	log.Info("Zero balance accounts:",
+		"numZeroBalanceAccounts", figures.NumAccounts,
+		"numWithCode", figures.NumWithCode,
+		"numWithNonceWithoutCode", figures.NumWithNonceWithoutCode,
+		"numWithoutNonceAndCode", figures.NumWithoutNonceAndCode,
+		"numWithDataTrie", figures.NumWithDataTrie,
+	)
+
+	figuresJson, err := json.MarshalIndent(figures, "", fourSpaces)
+	if err != nil {
+		return err
+	}
+
+	err = saveFile(fileBasename+zeroBalanceFileSuffix+".json", string(figuresJson))
+	if err != nil {
+		return err
+	}
+
+	if !r.report.ListAccounts {
+		return nil
+	}
+
+	text, err := r.accountsToCsv(addressConverter)
+	if err != nil {
+		return err
+	}
+
+	return saveFile(fileBasename+zeroBalanceFileSuffix+".csv", text)
+}
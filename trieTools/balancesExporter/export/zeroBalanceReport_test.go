@@ -0,0 +1,81 @@
+package export
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"math/big"
+	"path/filepath"
+	"testing"
+
+	"github.com/multiversx/mx-chain-go/state"
+	"github.com/stretchr/testify/require"
+)
+
+func createTestZeroBalanceAccount(addressByte byte, nonce uint64, codeHash []byte, rootHash []byte) *state.UserAccountData {
+	return &state.UserAccountData{
+		Address:  bytes.Repeat([]byte{addressByte}, addressLength),
+		Balance:  big.NewInt(0),
+		Nonce:    nonce,
+		CodeHash: codeHash,
+		RootHash: rootHash,
+	}
+}
+
+func TestZeroBalanceReporter_AddAccount(t *testing.T) {
+	t.Parallel()
+
+	t.Run("disabled report should not count", func(t *testing.T) {
+		t.Parallel()
+
+		reporter := newZeroBalanceReporter(ZeroBalanceReport{})
+		reporter.addAccount(createTestZeroBalanceAccount(1, 0, nil, nil))
+		require.Equal(t, zeroBalanceFigures{}, reporter.getFigures())
+		require.Nil(t, reporter.save("unused", defaultAddressConverter))
+	})
+	t.Run("should count by category", func(t *testing.T) {
+		t.Parallel()
+
+		reporter := newZeroBalanceReporter(ZeroBalanceReport{Enabled: true})
+		reporter.addAccount(createTestUserAccount(5))
+		reporter.addAccount(createTestZeroBalanceAccount(1, 3, []byte("code"), []byte("root")))
+		reporter.addAccount(createTestZeroBalanceAccount(2, 3, nil, nil))
+		reporter.addAccount(createTestZeroBalanceAccount(3, 0, nil, []byte("root")))
+		reporter.addAccount(createTestZeroBalanceAccount(4, 0, nil, nil))
+
+		require.Equal(t, zeroBalanceFigures{
+			NumAccounts:             4,
+			NumWithCode:             1,
+			NumWithNonceWithoutCode: 1,
+			NumWithoutNonceAndCode:  2,
+			NumWithDataTrie:         2,
+		}, reporter.getFigures())
+		require.Empty(t, reporter.accounts)
+	})
+}
+
+func TestZeroBalanceReporter_Save(t *testing.T) {
+	t.Parallel()
+
+	reporter := newZeroBalanceReporter(ZeroBalanceReport{ListAccounts: true})
+	require.True(t, reporter.report.IsEnabled())
+	reporter.addAccount(createTestZeroBalanceAccount(2, 7, nil, nil))
+	reporter.addAccount(createTestZeroBalanceAccount(1, 0, []byte("code"), []byte("root")))
+
+	fileBasename := filepath.Join(t.TempDir(), "export")
+	err := reporter.save(fileBasename, defaultAddressConverter)
+	require.Nil(t, err)
+
+	figuresJson, err := ioutil.ReadFile(fileBasename + ".zero-balance.json")
+	require.Nil(t, err)
+	figures := zeroBalanceFigures{}
+	require.Nil(t, json.Unmarshal(figuresJson, &figures))
+	require.Equal(t, 2, figures.NumAccounts)
+
+	text, err := ioutil.ReadFile(fileBasename + ".zero-balance.csv")
+	require.Nil(t, err)
+	expectedText := "address,nonce,hasCode,hasDataTrie\n" +
+		defaultAddressConverter.Encode(bytes.Repeat([]byte{1}, addressLength)) + ",0,true,true\n" +
+		defaultAddressConverter.Encode(bytes.Repeat([]byte{2}, addressLength)) + ",7,false,false\n"
+	require.Equal(t, expectedText, string(text))
+}
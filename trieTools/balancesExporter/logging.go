@@ -1,42 +1,30 @@
 package main
 
 import (
-	"fmt"
 	"io"
 	"os"
 
 	logger "github.com/multiversx/mx-chain-logger-go"
-	"github.com/multiversx/mx-chain-logger-go/file"
+	"github.com/multiversx/mx-chain-tools-go/trieTools/trieToolsCommon"
 )
 
 const (
-	defaultLogsPath = "logs"
-	logFilePrefix   = "accounts-exporter"
+	logFilePrefix = "accounts-exporter"
 )
 
 var log = logger.GetOrCreate("main")
 
-func initializeLogger(logLevel string) (io.Closer, error) {
+func initializeLogger(logLevel string, logJSON bool) (io.Closer, error) {
 	currentDirectory, err := os.Getwd()
 	if err != nil {
 		return nil, err
 	}
 
-	err = logger.SetLogLevel(logLevel)
-	if err != nil {
-		return nil, err
-	}
-
-	logger.ToggleLoggerName(true)
-
-	fileLogging, err := file.NewFileLogging(file.ArgsFileLogging{
-		WorkingDir:      currentDirectory,
-		DefaultLogsPath: defaultLogsPath,
-		LogFilePrefix:   logFilePrefix,
+	return trieToolsCommon.AttachFileLogger(log, logFilePrefix, trieToolsCommon.ContextFlagsConfig{
+		WorkingDir:    currentDirectory,
+		LogLevel:      logLevel,
+		SaveLogFile:   true,
+		LogJSON:       logJSON,
+		EnableLogName: true,
 	})
-	if err != nil {
-		return nil, fmt.Errorf("%w creating a log file", err)
-	}
-
-	return fileLogging, nil
 }
@@ -1,25 +1,31 @@
 package main
 
 import (
+	"fmt"
+	"math/big"
 	"os"
+	"strconv"
+	"strings"
 
+	"github.com/multiversx/mx-chain-core-go/core"
 	"github.com/multiversx/mx-chain-go/sharding"
 	"github.com/multiversx/mx-chain-tools-go/trieTools/balancesExporter/blocks"
 	"github.com/multiversx/mx-chain-tools-go/trieTools/balancesExporter/export"
+	"github.com/multiversx/mx-chain-tools-go/trieTools/balancesExporter/staking"
 	"github.com/multiversx/mx-chain-tools-go/trieTools/balancesExporter/trie"
+	"github.com/multiversx/mx-chain-tools-go/trieTools/trieToolsCommon"
 	"github.com/urfave/cli"
 )
 
-const (
-	appVersion = "1.0.0"
-)
+const addressLength = 32
 
 func main() {
 	app := cli.NewApp()
-	app.Version = appVersion
+	app.Version = trieToolsCommon.GetVersion()
 	app.Name = "Balances exporter CLI app"
 	app.Usage = "Tool for exporting balances of accounts (given a node db)"
 	app.Flags = getAllCliFlags()
+	app.OnUsageError = trieToolsCommon.OnUsageError
 	app.Authors = []cli.Author{
 		{
 			Name:  "The MultiversX Team",
@@ -32,14 +38,14 @@ func main() {
 	err := app.Run(os.Args)
 	if err != nil {
 		log.Error(err.Error())
-		os.Exit(1)
+		os.Exit(trieToolsCommon.ExitCode(err))
 	}
 }
 
 func startExport(ctx *cli.Context) error {
 	cliFlags := getParsedCliFlags(ctx)
 
-	fileLogging, err := initializeLogger(cliFlags.logLevel)
+	fileLogging, err := initializeLogger(cliFlags.logLevel, cliFlags.logJSON)
 	if err != nil {
 		return err
 	}
@@ -47,46 +53,115 @@ func startExport(ctx *cli.Context) error {
 		_ = fileLogging.Close()
 	}()
 
+	err = cliFlags.storageConfig.Check()
+	if err != nil {
+		return trieToolsCommon.NewUsageError(err)
+	}
+
+	log.Info("storage config",
+		"cache capacity", cliFlags.storageConfig.CacheCapacity,
+		"cache size", cliFlags.storageConfig.CacheSizeInBytes,
+		"max batch size", cliFlags.storageConfig.MaxBatchSize,
+		"max open files", cliFlags.storageConfig.MaxOpenFiles)
+
+	if cliFlags.numWorkers < 1 {
+		return trieToolsCommon.NewUsageError(fmt.Errorf("invalid num workers: expected a positive value, got %d", cliFlags.numWorkers))
+	}
+
+	err = checkDeltaFlags(cliFlags)
+	if err != nil {
+		return trieToolsCommon.NewUsageError(err)
+	}
+
+	addressConverter, err := trieToolsCommon.NewBech32AddressConverter(addressLength, cliFlags.addressHrp, log)
+	if err != nil {
+		return trieToolsCommon.NewUsageError(err)
+	}
+
 	actualShardCoordinator, err := sharding.NewMultiShardCoordinator(cliFlags.numShards, cliFlags.shard)
 	if err != nil {
-		return err
+		return trieToolsCommon.NewUsageError(err)
 	}
 
 	trieFactory := trie.NewTrieFactory(trie.ArgsNewTrieFactory{
 		ShardCoordinator: actualShardCoordinator,
 		DbPath:           cliFlags.dbPath,
 		Epoch:            cliFlags.epoch,
+		StorageConfig:    cliFlags.storageConfig,
+		NumWorkers:       cliFlags.numWorkers,
+		TotalAccounts:    cliFlags.totalAccounts,
 	})
 
 	trieWrapper, err := trieFactory.CreateTrie()
 	if err != nil {
-		return err
+		return trieToolsCommon.NewIOError(err)
 	}
 	defer trieWrapper.Close()
 
 	blocksRepository := blocks.NewBlocksRepository(blocks.ArgsNewBlocksRepository{
-		DbPath:      cliFlags.dbPath,
-		Epoch:       cliFlags.epoch,
-		Shard:       cliFlags.shard,
-		TrieWrapper: trieWrapper,
+		DbPath:        cliFlags.dbPath,
+		Epoch:         cliFlags.epoch,
+		Shard:         cliFlags.shard,
+		TrieWrapper:   trieWrapper,
+		StorageConfig: cliFlags.storageConfig,
 	})
 
+	if isDeltaMode(cliFlags) {
+		return exportBalancesDelta(cliFlags, trieWrapper, blocksRepository, addressConverter)
+	}
+
 	bestBlock, err := blocksRepository.FindBestBlock()
 	if err != nil {
-		return err
+		return trieToolsCommon.NewIOError(err)
+	}
+
+	minBalance, ok := big.NewInt(0).SetString(cliFlags.minBalance, 10)
+	if !ok {
+		return trieToolsCommon.NewUsageError(fmt.Errorf("invalid min balance: %s", cliFlags.minBalance))
+	}
+
+	supplyCheck, err := createSupplyCheck(cliFlags)
+	if err != nil {
+		return trieToolsCommon.NewUsageError(err)
+	}
+
+	preview, err := createPreview(cliFlags)
+	if err != nil {
+		return trieToolsCommon.NewUsageError(err)
+	}
+
+	var stakedBalances map[string]*big.Int
+	if cliFlags.includeStaked {
+		stakedBalances, err = resolveStakedBalances(cliFlags)
+		if err != nil {
+			return err
+		}
 	}
 
 	exporter, err := export.NewExporter(export.ArgsNewExporter{
 		TrieWrapper:      trieWrapper,
 		Format:           cliFlags.exportFormat,
+		Columns:          cliFlags.columns,
 		Currency:         cliFlags.currency,
 		CurrencyDecimals: cliFlags.currencyDecimals,
 		WithContracts:    cliFlags.withContracts,
 		WithZero:         cliFlags.withZero,
+		MinBalance:       minBalance,
 		ByProjectedShard: cliFlags.byProjectedShard,
+		StakedBalances:   stakedBalances,
+		SupplyCheck:      supplyCheck,
+		Preview:          preview,
+		ZeroBalanceReport: export.ZeroBalanceReport{
+			Enabled:      cliFlags.reportZero,
+			ListAccounts: cliFlags.reportZeroList,
+		},
+		Gzip:             cliFlags.gzip,
+		HumanReadable:    cliFlags.humanReadable,
+		Denomination:     cliFlags.denomination,
+		AddressConverter: addressConverter,
 	})
 	if err != nil {
-		return err
+		return trieToolsCommon.NewUsageError(err)
 	}
 
 	err = exporter.ExportBalancesAtBlock(bestBlock)
@@ -96,3 +171,118 @@ func startExport(ctx *cli.Context) error {
 
 	return nil
 }
+
+func createPreview(cliFlags parsedCliFlags) (export.Preview, error) {
+	sampleRate, err := parseSampleRate(cliFlags.sample)
+	if err != nil {
+		return export.Preview{}, err
+	}
+
+	preview := export.Preview{
+		Limit:      cliFlags.limit,
+		SampleRate: sampleRate,
+	}
+	if preview.IsEnabled() && len(cliFlags.expectedSupply) > 0 {
+		return export.Preview{}, fmt.Errorf("--%s can not be used with --%s or --%s, since the export is not complete",
+			cliFlagExpectedSupply.Name, cliFlagLimit.Name, cliFlagSample.Name)
+	}
+
+	return preview, nil
+}
+
+// parseSampleRate parses a "1/K" sample and returns K. An empty sample means that all the accounts are exported
+func parseSampleRate(sample string) (uint64, error) {
+	if len(sample) == 0 {
+		return 0, nil
+	}
+
+	parts := strings.Split(sample, "/")
+	if len(parts) != 2 || parts[0] != "1" {
+		return 0, fmt.Errorf("invalid sample: expected 1/K, got %s", sample)
+	}
+
+	sampleRate, err := strconv.ParseUint(parts[1], 10, 64)
+	if err != nil || sampleRate == 0 {
+		return 0, fmt.Errorf("invalid sample: expected 1/K, with K a positive integer, got %s", sample)
+	}
+
+	return sampleRate, nil
+}
+
+func createSupplyCheck(cliFlags parsedCliFlags) (export.SupplyCheck, error) {
+	if len(cliFlags.expectedSupply) == 0 {
+		return export.SupplyCheck{}, nil
+	}
+
+	expectedSupply, ok := big.NewInt(0).SetString(cliFlags.expectedSupply, 10)
+	if !ok {
+		return export.SupplyCheck{}, fmt.Errorf("invalid expected supply: %s", cliFlags.expectedSupply)
+	}
+
+	offset, ok := big.NewInt(0).SetString(cliFlags.supplyOffset, 10)
+	if !ok {
+		return export.SupplyCheck{}, fmt.Errorf("invalid supply offset: %s", cliFlags.supplyOffset)
+	}
+
+	tolerance, ok := big.NewInt(0).SetString(cliFlags.supplyTolerance, 10)
+	if !ok || tolerance.Sign() < 0 {
+		return export.SupplyCheck{}, fmt.Errorf("invalid supply tolerance: %s", cliFlags.supplyTolerance)
+	}
+
+	return export.SupplyCheck{
+		ExpectedSupply: expectedSupply,
+		Offset:         offset,
+		Tolerance:      tolerance,
+	}, nil
+}
+
+func resolveStakedBalances(cliFlags parsedCliFlags) (map[string]*big.Int, error) {
+	metachainShardCoordinator, err := sharding.NewMultiShardCoordinator(cliFlags.numShards, core.MetachainShardId)
+	if err != nil {
+		return nil, err
+	}
+
+	trieFactory := trie.NewTrieFactory(trie.ArgsNewTrieFactory{
+		ShardCoordinator: metachainShardCoordinator,
+		DbPath:           cliFlags.metachainDbPath,
+		Epoch:            cliFlags.epoch,
+		StorageConfig:    cliFlags.storageConfig,
+		NumWorkers:       cliFlags.numWorkers,
+		TotalAccounts:    cliFlags.totalAccounts,
+	})
+
+	trieWrapper, err := trieFactory.CreateTrie()
+	if err != nil {
+		return nil, trieToolsCommon.NewIOError(err)
+	}
+	defer trieWrapper.Close()
+
+	blocksRepository := blocks.NewBlocksRepository(blocks.ArgsNewBlocksRepository{
+		DbPath:        cliFlags.metachainDbPath,
+		Epoch:         cliFlags.epoch,
+		Shard:         core.MetachainShardId,
+		TrieWrapper:   trieWrapper,
+		StorageConfig: cliFlags.storageConfig,
+	})
+
+	bestBlock, err := blocksRepository.FindBestBlock()
+	if err != nil {
+		return nil, trieToolsCommon.NewIOError(err)
+	}
+
+	log.Info("Resolving staked balances:",
+		"metachainBlockNonce", bestBlock.GetNonce(),
+		"metachainBlockRootHash", bestBlock.GetRootHash(),
+	)
+
+	resolver := staking.NewStakedBalancesResolver(staking.ArgsNewStakedBalancesResolver{
+		TrieWrapper: trieWrapper,
+	})
+
+	stakedBalances, err := resolver.ResolveStakedBalances(bestBlock.GetRootHash())
+	if err != nil {
+		return nil, trieToolsCommon.NewIntegrityError(err)
+	}
+
+	return stakedBalances, nil
+}
@@ -0,0 +1,11 @@
+package staking
+
+import (
+	"github.com/multiversx/mx-chain-go/state"
+)
+
+type trieWrapper interface {
+	GetAccount(rootHash []byte, address []byte) (*state.UserAccountData, error)
+	GetDataTrieValue(account *state.UserAccountData, key []byte) ([]byte, error)
+	GetDataTrieLeaves(account *state.UserAccountData, handler func(key []byte, value []byte)) error
+}
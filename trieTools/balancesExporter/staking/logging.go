@@ -0,0 +1,7 @@
+package staking
+
+import (
+	logger "github.com/multiversx/mx-chain-logger-go"
+)
+
+var log = logger.GetOrCreate("staking")
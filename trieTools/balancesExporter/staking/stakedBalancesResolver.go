@@ -0,0 +1,207 @@
+package staking
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/multiversx/mx-chain-core-go/marshal"
+	"github.com/multiversx/mx-chain-go/state"
+	"github.com/multiversx/mx-chain-go/vm"
+	"github.com/multiversx/mx-chain-go/vm/systemSmartContracts"
+)
+
+const (
+	addressLength          = 32
+	delegationContractsKey = "delegationContracts"
+)
+
+var (
+	marshaller = &marshal.GogoProtoMarshalizer{}
+)
+
+// ArgsNewStakedBalancesResolver holds arguments for creating a stakedBalancesResolver
+type ArgsNewStakedBalancesResolver struct {
+	TrieWrapper trieWrapper
+}
+
+type stakedBalancesResolver struct {
+	trie trieWrapper
+}
+
+// NewStakedBalancesResolver creates a new stakedBalancesResolver
+func NewStakedBalancesResolver(args ArgsNewStakedBalancesResolver) *stakedBalancesResolver {
+	return &stakedBalancesResolver{
+		trie: args.TrieWrapper,
+	}
+}
+
+// ResolveStakedBalances returns, for each address (as raw bytes), the sum of the active and unstaked amounts found in
+// the staking (validator) and delegation system smart contracts, under the provided metachain root hash
+func (resolver *stakedBalancesResolver) ResolveStakedBalances(rootHash []byte) (map[string]*big.Int, error) {
+	stakedBalances := make(map[string]*big.Int)
+
+	delegationContracts, err := resolver.getDelegationContracts(rootHash)
+	if err != nil {
+		return nil, fmt.Errorf("%w while reading the delegation contracts list", err)
+	}
+
+	for _, contractAddress := range delegationContracts {
+		err = resolver.addDelegatedBalances(rootHash, contractAddress, stakedBalances)
+		if err != nil {
+			return nil, fmt.Errorf("%w while reading the delegators of contract %x", err, contractAddress)
+		}
+	}
+
+	err = resolver.addStakedBalances(rootHash, delegationContracts, stakedBalances)
+	if err != nil {
+		return nil, fmt.Errorf("%w while reading the validators owners", err)
+	}
+
+	log.Info("ResolveStakedBalances()",
+		"numDelegationContracts", len(delegationContracts),
+		"numAddresses", len(stakedBalances),
+	)
+
+	return stakedBalances, nil
+}
+
+func (resolver *stakedBalancesResolver) getDelegationContracts(rootHash []byte) ([][]byte, error) {
+	delegationManager, err := resolver.trie.GetAccount(rootHash, vm.DelegationManagerSCAddress)
+	if err != nil {
+		return nil, err
+	}
+	if delegationManager == nil {
+		return make([][]byte, 0), nil
+	}
+
+	value, err := resolver.trie.GetDataTrieValue(delegationManager, []byte(delegationContractsKey))
+	if err != nil {
+		return nil, err
+	}
+
+	contractsList := &systemSmartContracts.DelegationContractList{}
+	err = marshaller.Unmarshal(contractsList, value)
+	if err != nil {
+		return nil, err
+	}
+
+	return contractsList.Addresses, nil
+}
+
+func (resolver *stakedBalancesResolver) addDelegatedBalances(rootHash []byte, contractAddress []byte, stakedBalances map[string]*big.Int) error {
+	contract, err := resolver.trie.GetAccount(rootHash, contractAddress)
+	if err != nil {
+		return err
+	}
+	if contract == nil {
+		return nil
+	}
+
+	delegators := make(map[string]*systemSmartContracts.DelegatorData)
+	err = resolver.trie.GetDataTrieLeaves(contract, func(key []byte, value []byte) {
+		// the delegators data is saved under the delegators addresses, the other keys hold the contract's own data
+		if len(key) != addressLength {
+			return
+		}
+
+		delegator := &systemSmartContracts.DelegatorData{}
+		errUnmarshal := marshaller.Unmarshal(delegator, value)
+		if errUnmarshal != nil {
+			return
+		}
+		if len(delegator.ActiveFund) == 0 && len(delegator.UnStakedFunds) == 0 {
+			return
+		}
+
+		delegators[string(key)] = delegator
+	})
+	if err != nil {
+		return err
+	}
+
+	for address, delegator := range delegators {
+		fundKeys := append([][]byte{delegator.ActiveFund}, delegator.UnStakedFunds...)
+		for _, fundKey := range fundKeys {
+			value, errFund := resolver.getFundValue(contract, fundKey)
+			if errFund != nil {
+				return errFund
+			}
+
+			addToBalance(stakedBalances, address, value)
+		}
+	}
+
+	return nil
+}
+
+func (resolver *stakedBalancesResolver) getFundValue(contract *state.UserAccountData, fundKey []byte) (*big.Int, error) {
+	if len(fundKey) == 0 {
+		return nil, nil
+	}
+
+	value, err := resolver.trie.GetDataTrieValue(contract, fundKey)
+	if err != nil {
+		return nil, err
+	}
+	if len(value) == 0 {
+		return nil, fmt.Errorf("missing fund %x", fundKey)
+	}
+
+	fund := &systemSmartContracts.Fund{}
+	err = marshaller.Unmarshal(fund, value)
+	if err != nil {
+		return nil, err
+	}
+
+	return fund.Value, nil
+}
+
+func (resolver *stakedBalancesResolver) addStakedBalances(rootHash []byte, delegationContracts [][]byte, stakedBalances map[string]*big.Int) error {
+	validatorSC, err := resolver.trie.GetAccount(rootHash, vm.ValidatorSCAddress)
+	if err != nil {
+		return err
+	}
+	if validatorSC == nil {
+		return nil
+	}
+
+	// the stake of the delegation contracts belongs to their delegators, which were already handled
+	isDelegationContract := make(map[string]struct{}, len(delegationContracts))
+	for _, contractAddress := range delegationContracts {
+		isDelegationContract[string(contractAddress)] = struct{}{}
+	}
+
+	return resolver.trie.GetDataTrieLeaves(validatorSC, func(key []byte, value []byte) {
+		// the validator data is saved under the owners addresses, the other keys hold the contract's own data
+		if len(key) != addressLength {
+			return
+		}
+		_, found := isDelegationContract[string(key)]
+		if found {
+			return
+		}
+
+		validatorData := &systemSmartContracts.ValidatorDataV2{}
+		errUnmarshal := marshaller.Unmarshal(validatorData, value)
+		if errUnmarshal != nil {
+			return
+		}
+
+		addToBalance(stakedBalances, string(key), validatorData.TotalStakeValue)
+		addToBalance(stakedBalances, string(key), validatorData.TotalUnstaked)
+	})
+}
+
+func addToBalance(balances map[string]*big.Int, address string, value *big.Int) {
+	if value == nil || value.Sign() == 0 {
+		return
+	}
+
+	balance, found := balances[address]
+	if !found {
+		balance = big.NewInt(0)
+		balances[address] = balance
+	}
+
+	balance.Add(balance, value)
+}
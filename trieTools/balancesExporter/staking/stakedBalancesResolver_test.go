@@ -0,0 +1,159 @@
+package staking
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/multiversx/mx-chain-go/state"
+	"github.com/multiversx/mx-chain-go/vm"
+	"github.com/multiversx/mx-chain-go/vm/systemSmartContracts"
+	"github.com/stretchr/testify/require"
+)
+
+// trieWrapperMock holds the accounts and their data tries in memory
+type trieWrapperMock struct {
+	accounts  map[string]*state.UserAccountData
+	dataTries map[string]map[string][]byte
+}
+
+func newTrieWrapperMock() *trieWrapperMock {
+	return &trieWrapperMock{
+		accounts:  make(map[string]*state.UserAccountData),
+		dataTries: make(map[string]map[string][]byte),
+	}
+}
+
+func (mock *trieWrapperMock) addDataTrieValue(t *testing.T, address []byte, key []byte, value interface{}) {
+	_, found := mock.accounts[string(address)]
+	if !found {
+		mock.accounts[string(address)] = &state.UserAccountData{
+			Address:  address,
+			RootHash: append([]byte("root"), address...),
+		}
+		mock.dataTries[string(address)] = make(map[string][]byte)
+	}
+
+	marshalledValue, err := marshaller.Marshal(value)
+	require.Nil(t, err)
+	mock.dataTries[string(address)][string(key)] = marshalledValue
+}
+
+func (mock *trieWrapperMock) GetAccount(_ []byte, address []byte) (*state.UserAccountData, error) {
+	return mock.accounts[string(address)], nil
+}
+
+func (mock *trieWrapperMock) GetDataTrieValue(account *state.UserAccountData, key []byte) ([]byte, error) {
+	return mock.dataTries[string(account.Address)][string(key)], nil
+}
+
+func (mock *trieWrapperMock) GetDataTrieLeaves(account *state.UserAccountData, handler func(key []byte, value []byte)) error {
+	for key, value := range mock.dataTries[string(account.Address)] {
+		handler([]byte(key), value)
+	}
+
+	return nil
+}
+
+func createAddress(b byte) []byte {
+	return bytes.Repeat([]byte{b}, addressLength)
+}
+
+func TestStakedBalancesResolver_ResolveStakedBalances(t *testing.T) {
+	t.Parallel()
+
+	t.Run("no system smart contracts should return empty", func(t *testing.T) {
+		t.Parallel()
+
+		resolver := NewStakedBalancesResolver(ArgsNewStakedBalancesResolver{
+			TrieWrapper: newTrieWrapperMock(),
+		})
+
+		stakedBalances, err := resolver.ResolveStakedBalances([]byte("rootHash"))
+		require.Nil(t, err)
+		require.Empty(t, stakedBalances)
+	})
+	t.Run("missing fund should error", func(t *testing.T) {
+		t.Parallel()
+
+		delegationContract := createAddress(0xdd)
+		mock := newTrieWrapperMock()
+		mock.addDataTrieValue(t, vm.DelegationManagerSCAddress, []byte(delegationContractsKey), &systemSmartContracts.DelegationContractList{
+			Addresses: [][]byte{delegationContract},
+		})
+		mock.addDataTrieValue(t, delegationContract, createAddress(1), &systemSmartContracts.DelegatorData{
+			ActiveFund: []byte("fund1"),
+		})
+
+		resolver := NewStakedBalancesResolver(ArgsNewStakedBalancesResolver{
+			TrieWrapper: mock,
+		})
+
+		stakedBalances, err := resolver.ResolveStakedBalances([]byte("rootHash"))
+		require.Nil(t, stakedBalances)
+		require.NotNil(t, err)
+		require.Contains(t, err.Error(), "missing fund")
+	})
+	t.Run("should sum the delegated and staked amounts", func(t *testing.T) {
+		t.Parallel()
+
+		delegationContract := createAddress(0xdd)
+		delegator := createAddress(1)
+		validatorOwner := createAddress(2)
+		delegatorAndOwner := createAddress(3)
+
+		mock := newTrieWrapperMock()
+		mock.addDataTrieValue(t, vm.DelegationManagerSCAddress, []byte(delegationContractsKey), &systemSmartContracts.DelegationContractList{
+			Addresses: [][]byte{delegationContract},
+		})
+
+		mock.addDataTrieValue(t, delegationContract, delegator, &systemSmartContracts.DelegatorData{
+			ActiveFund:    []byte("fund1"),
+			UnStakedFunds: [][]byte{[]byte("fund2"), []byte("fund3")},
+		})
+		mock.addDataTrieValue(t, delegationContract, delegatorAndOwner, &systemSmartContracts.DelegatorData{
+			ActiveFund: []byte("fund4"),
+		})
+		mock.addDataTrieValue(t, delegationContract, []byte("fund1"), &systemSmartContracts.Fund{Value: big.NewInt(100)})
+		mock.addDataTrieValue(t, delegationContract, []byte("fund2"), &systemSmartContracts.Fund{Value: big.NewInt(20)})
+		mock.addDataTrieValue(t, delegationContract, []byte("fund3"), &systemSmartContracts.Fund{Value: big.NewInt(3)})
+		mock.addDataTrieValue(t, delegationContract, []byte("fund4"), &systemSmartContracts.Fund{Value: big.NewInt(5)})
+
+		mock.addDataTrieValue(t, vm.ValidatorSCAddress, validatorOwner, &systemSmartContracts.ValidatorDataV2{
+			TotalStakeValue: big.NewInt(2500),
+			TotalUnstaked:   big.NewInt(500),
+		})
+		mock.addDataTrieValue(t, vm.ValidatorSCAddress, delegatorAndOwner, &systemSmartContracts.ValidatorDataV2{
+			TotalStakeValue: big.NewInt(2500),
+		})
+		// the stake of the delegation contract belongs to its delegators
+		mock.addDataTrieValue(t, vm.ValidatorSCAddress, delegationContract, &systemSmartContracts.ValidatorDataV2{
+			TotalStakeValue: big.NewInt(10000),
+		})
+
+		resolver := NewStakedBalancesResolver(ArgsNewStakedBalancesResolver{
+			TrieWrapper: mock,
+		})
+
+		stakedBalances, err := resolver.ResolveStakedBalances([]byte("rootHash"))
+		require.Nil(t, err)
+		require.Equal(t, map[string]*big.Int{
+			string(delegator):         big.NewInt(123),
+			string(validatorOwner):    big.NewInt(3000),
+			string(delegatorAndOwner): big.NewInt(2505),
+		}, stakedBalances)
+	})
+}
+
+func TestAddToBalance(t *testing.T) {
+	t.Parallel()
+
+	balances := make(map[string]*big.Int)
+	addToBalance(balances, "a", nil)
+	addToBalance(balances, "a", big.NewInt(0))
+	require.Empty(t, balances)
+
+	addToBalance(balances, "a", big.NewInt(1))
+	addToBalance(balances, "a", big.NewInt(2))
+	require.Equal(t, big.NewInt(3), balances["a"])
+}
@@ -3,22 +3,23 @@ package trie
 import (
 	nodeConfig "github.com/multiversx/mx-chain-go/config"
 	"github.com/multiversx/mx-chain-storage-go/storageUnit"
+	"github.com/multiversx/mx-chain-tools-go/trieTools/balancesExporter/common"
 )
 
-func getCacheConfig() storageUnit.CacheConfig {
+func getCacheConfig(storageConfig common.StorageConfig) storageUnit.CacheConfig {
 	return storageUnit.CacheConfig{
 		Type:        "SizeLRU",
-		Capacity:    500000,
-		SizeInBytes: 314572800, // 300MB
+		Capacity:    storageConfig.CacheCapacity,
+		SizeInBytes: storageConfig.CacheSizeInBytes,
 	}
 }
 
-func getDbConfig(filePath string) nodeConfig.DBConfig {
+func getDbConfig(filePath string, storageConfig common.StorageConfig) nodeConfig.DBConfig {
 	return nodeConfig.DBConfig{
 		FilePath:          filePath,
 		Type:              "LvlDBSerial",
 		BatchDelaySeconds: 2,
-		MaxBatchSize:      45000,
-		MaxOpenFiles:      10,
+		MaxBatchSize:      storageConfig.MaxBatchSize,
+		MaxOpenFiles:      storageConfig.MaxOpenFiles,
 	}
 }
@@ -0,0 +1,102 @@
+package trie
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const defaultProgressLogInterval = 30 * time.Second
+
+// progressLogger periodically logs the number of trie leaves read so far. When the total number of leaves is known
+// (e.g. from a prior trieChecker run), the completion percentage and the estimated remaining time are logged as well
+type progressLogger struct {
+	numLeaves   uint64
+	totalLeaves uint64
+	start       time.Time
+
+	closeChan chan struct{}
+	wg        sync.WaitGroup
+}
+
+func newProgressLogger(totalLeaves uint64, interval time.Duration) *progressLogger {
+	logger := &progressLogger{
+		totalLeaves: totalLeaves,
+		start:       time.Now(),
+		closeChan:   make(chan struct{}),
+	}
+
+	logger.wg.Add(1)
+	go logger.logContinuously(interval)
+
+	return logger
+}
+
+func (pl *progressLogger) addLeaf() {
+	atomic.AddUint64(&pl.numLeaves, 1)
+}
+
+func (pl *progressLogger) logContinuously(interval time.Duration) {
+	defer pl.wg.Done()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			pl.logProgress()
+		case <-pl.closeChan:
+			return
+		}
+	}
+}
+
+func (pl *progressLogger) logProgress() {
+	numLeaves := atomic.LoadUint64(&pl.numLeaves)
+	elapsed := time.Since(pl.start)
+
+	percentage, eta, ok := computeProgress(numLeaves, pl.totalLeaves, elapsed)
+	if !ok {
+		log.Info("Reading accounts:", "numLeaves", numLeaves, "elapsed", elapsed.Round(time.Second))
+		return
+	}
+
+	log.Info("Reading accounts:",
+		"numLeaves", numLeaves,
+		"totalLeaves", pl.totalLeaves,
+		"progress", fmt.Sprintf("%.2f%%", percentage),
+		"elapsed", elapsed.Round(time.Second),
+		"eta", eta.Round(time.Second),
+	)
+}
+
+// close stops the periodic logging and logs the final number of leaves read
+func (pl *progressLogger) close() {
+	close(pl.closeChan)
+	pl.wg.Wait()
+
+	log.Info("Finished reading accounts:",
+		"numLeaves", atomic.LoadUint64(&pl.numLeaves),
+		"elapsed", time.Since(pl.start).Round(time.Second),
+	)
+}
+
+// computeProgress returns the completion percentage and the estimated remaining time, assuming a constant reading
+// rate. It returns false if the total is unknown (0), or already exceeded, in which case the total was a wrong hint
+func computeProgress(numLeaves uint64, totalLeaves uint64, elapsed time.Duration) (float64, time.Duration, bool) {
+	if totalLeaves == 0 || numLeaves > totalLeaves {
+		return 0, 0, false
+	}
+
+	percentage := float64(numLeaves) * 100 / float64(totalLeaves)
+	if numLeaves == 0 {
+		return percentage, 0, true
+	}
+
+	remaining := float64(totalLeaves-numLeaves) / float64(numLeaves)
+	eta := time.Duration(float64(elapsed) * remaining)
+
+	return percentage, eta, true
+}
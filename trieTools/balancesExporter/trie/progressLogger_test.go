@@ -0,0 +1,68 @@
+package trie
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestComputeProgress(t *testing.T) {
+	t.Parallel()
+
+	t.Run("unknown total should not compute", func(t *testing.T) {
+		t.Parallel()
+
+		_, _, ok := computeProgress(10, 0, time.Minute)
+		require.False(t, ok)
+	})
+	t.Run("exceeded total should not compute", func(t *testing.T) {
+		t.Parallel()
+
+		_, _, ok := computeProgress(11, 10, time.Minute)
+		require.False(t, ok)
+	})
+	t.Run("nothing read yet", func(t *testing.T) {
+		t.Parallel()
+
+		percentage, eta, ok := computeProgress(0, 10, time.Minute)
+		require.True(t, ok)
+		require.Equal(t, float64(0), percentage)
+		require.Equal(t, time.Duration(0), eta)
+	})
+	t.Run("should estimate using the reading rate", func(t *testing.T) {
+		t.Parallel()
+
+		percentage, eta, ok := computeProgress(250, 1000, time.Minute)
+		require.True(t, ok)
+		require.Equal(t, float64(25), percentage)
+		require.Equal(t, 3*time.Minute, eta)
+
+		percentage, eta, ok = computeProgress(1000, 1000, time.Minute)
+		require.True(t, ok)
+		require.Equal(t, float64(100), percentage)
+		require.Equal(t, time.Duration(0), eta)
+	})
+}
+
+func TestExtractUserAccounts_ShouldCountTheLeaves(t *testing.T) {
+	t.Parallel()
+
+	tr, rootHash := createTestAccountsTrie(t, 100)
+	wrapper := newTrieWrapper(tr, 1, 0)
+
+	for _, numWorkers := range []int{1, 4} {
+		progress := newProgressLogger(101, time.Millisecond)
+		leavesChan := createTestLeavesChannel(t, wrapper, rootHash)
+		if numWorkers == 1 {
+			_ = extractUserAccounts(leavesChan, acceptAllAccounts, progress)
+		} else {
+			_ = extractUserAccountsConcurrently(leavesChan, acceptAllAccounts, progress, numWorkers)
+		}
+		progress.close()
+
+		// the code node is a leaf as well
+		require.Equal(t, uint64(101), atomic.LoadUint64(&progress.numLeaves))
+	}
+}
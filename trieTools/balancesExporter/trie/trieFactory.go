@@ -1,11 +1,14 @@
 package trie
 
 import (
+	"fmt"
+	"os"
+
+	"github.com/multiversx/mx-chain-core-go/core"
 	"github.com/multiversx/mx-chain-core-go/hashing/blake2b"
 	"github.com/multiversx/mx-chain-core-go/marshal"
 	"github.com/multiversx/mx-chain-go/epochStart/notifier"
 	"github.com/multiversx/mx-chain-go/sharding"
-	storageFactory "github.com/multiversx/mx-chain-go/storage/factory"
 	"github.com/multiversx/mx-chain-go/storage/pruning"
 	"github.com/multiversx/mx-chain-go/testscommon"
 	"github.com/multiversx/mx-chain-go/trie"
@@ -15,7 +18,6 @@ import (
 
 const (
 	maxTrieLevelInMemory  = 5
-	maxBatchSize          = 45000
 	storageUnitIdentifier = "AccountsTrie"
 )
 
@@ -29,12 +31,21 @@ type ArgsNewTrieFactory struct {
 	ShardCoordinator sharding.Coordinator
 	DbPath           string
 	Epoch            uint32
+	StorageConfig    common.StorageConfig
+	// NumWorkers is the number of goroutines unmarshalling the accounts. Values lower than 1 mean a single one
+	NumWorkers int
+	// TotalAccounts is the expected number of accounts trie leaves, used for logging the progress percentage and the
+	// estimated remaining time. If 0, only the number of leaves read so far is logged
+	TotalAccounts uint64
 }
 
 type trieFactory struct {
 	shardCoordinator sharding.Coordinator
 	dbPath           string
 	epoch            uint32
+	storageConfig    common.StorageConfig
+	numWorkers       int
+	totalAccounts    uint64
 }
 
 // NewTrieFactory creates a new trieFactory
@@ -43,26 +54,41 @@ func NewTrieFactory(args ArgsNewTrieFactory) *trieFactory {
 		shardCoordinator: args.ShardCoordinator,
 		dbPath:           args.DbPath,
 		epoch:            args.Epoch,
+		storageConfig:    args.StorageConfig,
+		numWorkers:       args.NumWorkers,
+		totalAccounts:    args.TotalAccounts,
 	}
 }
 
 // CreateTrie creates a trie (actually, a wrapper over the actual trie)
 func (factory *trieFactory) CreateTrie() (*trieWrapper, error) {
-	cacheConfig := getCacheConfig()
-	dbConfig := getDbConfig(factory.dbPath)
+	cacheConfig := getCacheConfig(factory.storageConfig)
+	dbConfig := getDbConfig(factory.dbPath, factory.storageConfig)
 	pathManager := common.NewSimplePathManager(factory.dbPath)
 
+	shardID := core.GetShardIDString(factory.shardCoordinator.SelfId())
+	trieStoragePath := pathManager.PathForEpoch(shardID, factory.epoch, storageUnitIdentifier)
+	_, err := os.Stat(trieStoragePath)
+	if err != nil {
+		return nil, fmt.Errorf("%w: accounts trie of epoch %d, shard %s is not present in the database", err, factory.epoch, shardID)
+	}
+
+	persisterFactory, err := trieToolsCommon.NewPersisterFactory(dbConfig, factory.storageConfig.ReadOnly, factory.storageConfig.DbOpenRetry)
+	if err != nil {
+		return nil, err
+	}
+
 	args := pruning.StorerArgs{
 		Identifier:             storageUnitIdentifier,
 		ShardCoordinator:       factory.shardCoordinator,
 		CacheConf:              cacheConfig,
 		PathManager:            pathManager,
 		DbPath:                 "",
-		PersisterFactory:       storageFactory.NewPersisterFactory(dbConfig),
+		PersisterFactory:       persisterFactory,
 		Notifier:               notifier.NewManualEpochStartNotifier(),
 		OldDataCleanerProvider: &testscommon.OldDataCleanerProviderStub{},
 		CustomDatabaseRemover:  &testscommon.CustomDatabaseRemoverStub{},
-		MaxBatchSize:           maxBatchSize,
+		MaxBatchSize:           factory.storageConfig.MaxBatchSize,
 		EpochsData: pruning.EpochArgs{
 			NumOfEpochsToKeep:     factory.epoch + 1,
 			NumOfActivePersisters: factory.epoch + 1,
@@ -87,5 +113,5 @@ func (factory *trieFactory) CreateTrie() (*trieWrapper, error) {
 		return nil, err
 	}
 
-	return newTrieWrapper(t), nil
+	return newTrieWrapper(t, factory.numWorkers, factory.totalAccounts), nil
 }
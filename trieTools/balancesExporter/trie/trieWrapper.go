@@ -2,6 +2,11 @@ package trie
 
 import (
 	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/multiversx/mx-chain-core-go/core"
 	"github.com/multiversx/mx-chain-go/common"
@@ -10,11 +15,33 @@ import (
 )
 
 type trieWrapper struct {
-	trie common.Trie
+	trie             common.Trie
+	numWorkers       int
+	totalAccounts    uint64
+	progressInterval time.Duration
 }
 
-func newTrieWrapper(t common.Trie) *trieWrapper {
-	return &trieWrapper{trie: t}
+type indexedLeaf struct {
+	index    int
+	keyValue core.KeyValueHolder
+}
+
+type indexedAccount struct {
+	index   int
+	account *state.UserAccountData
+}
+
+func newTrieWrapper(t common.Trie, numWorkers int, totalAccounts uint64) *trieWrapper {
+	if numWorkers < 1 {
+		numWorkers = 1
+	}
+
+	return &trieWrapper{
+		trie:             t,
+		numWorkers:       numWorkers,
+		totalAccounts:    totalAccounts,
+		progressInterval: defaultProgressLogInterval,
+	}
 }
 
 // IsRootHashAvailable checks whether a rootHash is available in the trie database (e.g. for trie reconstruction)
@@ -28,39 +55,221 @@ func (tw *trieWrapper) IsRootHashAvailable(rootHash []byte) bool {
 	return true
 }
 
-func (tw *trieWrapper) GetUserAccounts(rootHash []byte, predicate func(*state.UserAccountData) bool) ([]*state.UserAccountData, error) {
+// GetUserAccounts returns, in trie order, the user accounts accepted by the predicate. When using more than one worker,
+// the leaves are unmarshalled concurrently, so the predicate must be safe for concurrent calls. If the limit is not 0,
+// the trie walk is stopped once limit accounts were accepted and only the first limit accounts are returned
+func (tw *trieWrapper) GetUserAccounts(rootHash []byte, predicate func(*state.UserAccountData) bool, limit uint64) ([]*state.UserAccountData, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
 	iteratorChannels := &common.TrieIteratorChannels{
 		LeavesChan: make(chan core.KeyValueHolder, common.TrieLeavesChannelDefaultCapacity),
 		ErrChan:    make(chan error, 1),
 	}
-	err := tw.trie.GetAllLeavesOnChannel(iteratorChannels, context.Background(), rootHash, keyBuilder.NewDisabledKeyBuilder())
+	err := tw.trie.GetAllLeavesOnChannel(iteratorChannels, ctx, rootHash, keyBuilder.NewDisabledKeyBuilder())
 	if err != nil {
 		return nil, err
 	}
 
-	users := make([]*state.UserAccountData, 0)
+	limitedPredicate := predicate
+	if limit > 0 {
+		numAccepted := uint64(0)
+		limitedPredicate = func(account *state.UserAccountData) bool {
+			if !predicate(account) {
+				return false
+			}
+			if atomic.AddUint64(&numAccepted, 1) >= limit {
+				// the leaves already sent on the channel are still read, so more accounts might be accepted
+				cancel()
+			}
 
-	for keyValue := range iteratorChannels.LeavesChan {
-		user := &state.UserAccountData{}
-		errUnmarshal := marshaller.Unmarshal(user, keyValue.Value())
-		if errUnmarshal != nil {
-			// Probably a code node
-			continue
+			return true
 		}
+	}
 
-		if predicate(user) {
-			users = append(users, user)
-		}
+	progress := newProgressLogger(tw.totalAccounts, tw.progressInterval)
+	var users []*state.UserAccountData
+	if tw.numWorkers == 1 {
+		users = extractUserAccounts(iteratorChannels.LeavesChan, limitedPredicate, progress)
+	} else {
+		users = extractUserAccountsConcurrently(iteratorChannels.LeavesChan, limitedPredicate, progress, tw.numWorkers)
 	}
+	progress.close()
 
 	err = common.GetErrorFromChanNonBlocking(iteratorChannels.ErrChan)
 	if err != nil {
 		return nil, err
 	}
 
+	if limit > 0 && uint64(len(users)) > limit {
+		users = users[:limit]
+	}
+
 	return users, nil
 }
 
+func extractUserAccounts(leavesChan chan core.KeyValueHolder, predicate func(*state.UserAccountData) bool, progress *progressLogger) []*state.UserAccountData {
+	users := make([]*state.UserAccountData, 0)
+
+	for keyValue := range leavesChan {
+		progress.addLeaf()
+		user, ok := unmarshalUserAccount(keyValue)
+		if ok && predicate(user) {
+			users = append(users, user)
+		}
+	}
+
+	return users
+}
+
+// extractUserAccountsConcurrently numbers the leaves in trie order and hands them to a pool of workers. The accepted
+// accounts are then sorted by their leaf number, so the result is the same as the one of a single worker
+func extractUserAccountsConcurrently(
+	leavesChan chan core.KeyValueHolder,
+	predicate func(*state.UserAccountData) bool,
+	progress *progressLogger,
+	numWorkers int,
+) []*state.UserAccountData {
+	leaves := make(chan indexedLeaf, common.TrieLeavesChannelDefaultCapacity)
+	go func() {
+		index := 0
+		for keyValue := range leavesChan {
+			progress.addLeaf()
+			leaves <- indexedLeaf{index: index, keyValue: keyValue}
+			index++
+		}
+		close(leaves)
+	}()
+
+	workersAccounts := make([][]indexedAccount, numWorkers)
+	wg := sync.WaitGroup{}
+	wg.Add(numWorkers)
+	for i := 0; i < numWorkers; i++ {
+		go func(workerIndex int) {
+			defer wg.Done()
+
+			for leaf := range leaves {
+				user, ok := unmarshalUserAccount(leaf.keyValue)
+				if ok && predicate(user) {
+					workersAccounts[workerIndex] = append(workersAccounts[workerIndex], indexedAccount{index: leaf.index, account: user})
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	accounts := make([]indexedAccount, 0)
+	for _, workerAccounts := range workersAccounts {
+		accounts = append(accounts, workerAccounts...)
+	}
+	sort.Slice(accounts, func(i, j int) bool {
+		return accounts[i].index < accounts[j].index
+	})
+
+	users := make([]*state.UserAccountData, 0, len(accounts))
+	for _, account := range accounts {
+		users = append(users, account.account)
+	}
+
+	return users
+}
+
+func unmarshalUserAccount(keyValue core.KeyValueHolder) (*state.UserAccountData, bool) {
+	user := &state.UserAccountData{}
+	errUnmarshal := marshaller.Unmarshal(user, keyValue.Value())
+	if errUnmarshal != nil {
+		// Probably a code node
+		return nil, false
+	}
+
+	return user, true
+}
+
+// GetAccount returns the user account stored under the provided address, in the trie with the provided root hash.
+// It returns nil if the account does not exist
+func (tw *trieWrapper) GetAccount(rootHash []byte, address []byte) (*state.UserAccountData, error) {
+	t, err := tw.trie.Recreate(rootHash)
+	if err != nil {
+		return nil, err
+	}
+
+	value, _, err := t.Get(address)
+	if err != nil {
+		return nil, err
+	}
+	if len(value) == 0 {
+		return nil, nil
+	}
+
+	account := &state.UserAccountData{}
+	err = marshaller.Unmarshal(account, value)
+	if err != nil {
+		return nil, err
+	}
+
+	return account, nil
+}
+
+// GetDataTrieValue returns the value stored under the provided key, in the data trie of the provided account.
+// It returns nil if the key does not exist
+func (tw *trieWrapper) GetDataTrieValue(account *state.UserAccountData, key []byte) ([]byte, error) {
+	if len(account.RootHash) == 0 {
+		return nil, nil
+	}
+
+	t, err := tw.trie.Recreate(account.RootHash)
+	if err != nil {
+		return nil, err
+	}
+
+	value, _, err := t.Get(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(value) == 0 {
+		return nil, nil
+	}
+
+	return trimDataTrieValue(value, key, account.Address)
+}
+
+// GetDataTrieLeaves calls the provided handler for each key-value pair in the data trie of the provided account
+func (tw *trieWrapper) GetDataTrieLeaves(account *state.UserAccountData, handler func(key []byte, value []byte)) error {
+	if len(account.RootHash) == 0 {
+		return nil
+	}
+
+	iteratorChannels := &common.TrieIteratorChannels{
+		LeavesChan: make(chan core.KeyValueHolder, common.TrieLeavesChannelDefaultCapacity),
+		ErrChan:    make(chan error, 1),
+	}
+	err := tw.trie.GetAllLeavesOnChannel(iteratorChannels, context.Background(), account.RootHash, keyBuilder.NewKeyBuilder())
+	if err != nil {
+		return err
+	}
+
+	for keyValue := range iteratorChannels.LeavesChan {
+		value, errTrim := trimDataTrieValue(keyValue.Value(), keyValue.Key(), account.Address)
+		if errTrim != nil {
+			continue
+		}
+
+		handler(keyValue.Key(), value)
+	}
+
+	return common.GetErrorFromChanNonBlocking(iteratorChannels.ErrChan)
+}
+
+// trimDataTrieValue removes the key and the account address appended to each value saved in a data trie
+func trimDataTrieValue(value []byte, key []byte, address []byte) ([]byte, error) {
+	dataLength := len(value) - len(key) - len(address)
+	if dataLength < 0 {
+		return nil, fmt.Errorf("invalid data trie value length %d for key %x", len(value), key)
+	}
+
+	return value[:dataLength], nil
+}
+
 func (tw *trieWrapper) Close() {
 	err := tw.trie.Close()
 	if err != nil {
@@ -0,0 +1,125 @@
+package trie
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sync/atomic"
+	"testing"
+
+	"github.com/multiversx/mx-chain-core-go/core"
+	"github.com/multiversx/mx-chain-go/common"
+	"github.com/multiversx/mx-chain-go/state"
+	"github.com/multiversx/mx-chain-go/testscommon/genericMocks"
+	"github.com/multiversx/mx-chain-go/trie/keyBuilder"
+	"github.com/multiversx/mx-chain-tools-go/trieTools/trieToolsCommon"
+	"github.com/stretchr/testify/require"
+)
+
+func createTestAccountsTrie(tb testing.TB, numAccounts int) (common.Trie, []byte) {
+	tr, err := trieToolsCommon.CreateTrie(genericMocks.NewStorerMock())
+	require.Nil(tb, err)
+
+	for i := 0; i < numAccounts; i++ {
+		address := []byte(fmt.Sprintf("%032d", i))
+		account := &state.UserAccountData{
+			Address: address,
+			Nonce:   uint64(i),
+			Balance: big.NewInt(int64(i)),
+		}
+		value, errMarshal := marshaller.Marshal(account)
+		require.Nil(tb, errMarshal)
+		require.Nil(tb, tr.Update(address, value))
+	}
+	// a code node, which is skipped
+	require.Nil(tb, tr.Update([]byte("code hash"), []byte{0xff, 0xff, 0xff}))
+	require.Nil(tb, tr.Commit())
+
+	rootHash, err := tr.RootHash()
+	require.Nil(tb, err)
+
+	return tr, rootHash
+}
+
+func acceptAllAccounts(_ *state.UserAccountData) bool {
+	return true
+}
+
+func createTestLeavesChannel(tb testing.TB, wrapper *trieWrapper, rootHash []byte) chan core.KeyValueHolder {
+	iteratorChannels := &common.TrieIteratorChannels{
+		LeavesChan: make(chan core.KeyValueHolder, common.TrieLeavesChannelDefaultCapacity),
+		ErrChan:    make(chan error, 1),
+	}
+	err := wrapper.trie.GetAllLeavesOnChannel(iteratorChannels, context.Background(), rootHash, keyBuilder.NewDisabledKeyBuilder())
+	require.Nil(tb, err)
+
+	return iteratorChannels.LeavesChan
+}
+
+func TestTrieWrapper_GetUserAccounts(t *testing.T) {
+	t.Parallel()
+
+	tr, rootHash := createTestAccountsTrie(t, 1000)
+	isEven := func(account *state.UserAccountData) bool {
+		return account.Balance.Int64()%2 == 0
+	}
+
+	expected, err := newTrieWrapper(tr, 1, 0).GetUserAccounts(rootHash, isEven, 0)
+	require.Nil(t, err)
+	require.Len(t, expected, 500)
+
+	for _, numWorkers := range []int{0, 2, 8} {
+		numWorkers := numWorkers
+		t.Run(fmt.Sprintf("%d workers", numWorkers), func(t *testing.T) {
+			t.Parallel()
+
+			numCalls := uint32(0)
+			accounts, err := newTrieWrapper(tr, numWorkers, 0).GetUserAccounts(rootHash, func(account *state.UserAccountData) bool {
+				atomic.AddUint32(&numCalls, 1)
+				return isEven(account)
+			}, 0)
+			require.Nil(t, err)
+			require.Equal(t, uint32(1000), atomic.LoadUint32(&numCalls))
+			require.Equal(t, expected, accounts)
+		})
+		t.Run(fmt.Sprintf("%d workers, limit should stop the trie walk early", numWorkers), func(t *testing.T) {
+			t.Parallel()
+
+			numCalls := uint32(0)
+			accounts, err := newTrieWrapper(tr, numWorkers, 0).GetUserAccounts(rootHash, func(account *state.UserAccountData) bool {
+				atomic.AddUint32(&numCalls, 1)
+				return isEven(account)
+			}, 10)
+			require.Nil(t, err)
+			require.Equal(t, expected[:10], accounts)
+			// the walk stops once the limit is reached, only the leaves already buffered in the channels are read
+			require.Less(t, atomic.LoadUint32(&numCalls), uint32(500))
+		})
+	}
+}
+
+func benchmarkGetUserAccounts(b *testing.B, numAccounts int, numWorkers int) {
+	tr, rootHash := createTestAccountsTrie(b, numAccounts)
+	wrapper := newTrieWrapper(tr, numWorkers, 0)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = wrapper.GetUserAccounts(rootHash, acceptAllAccounts, 0)
+	}
+}
+
+func BenchmarkGetUserAccounts_Sequential_1000(b *testing.B) {
+	benchmarkGetUserAccounts(b, 1000, 1)
+}
+
+func BenchmarkGetUserAccounts_Parallel_1000(b *testing.B) {
+	benchmarkGetUserAccounts(b, 1000, 4)
+}
+
+func BenchmarkGetUserAccounts_Sequential_50000(b *testing.B) {
+	benchmarkGetUserAccounts(b, 50000, 1)
+}
+
+func BenchmarkGetUserAccounts_Parallel_50000(b *testing.B) {
+	benchmarkGetUserAccounts(b, 50000, 4)
+}
@@ -3,6 +3,7 @@ module github.com/multiversx/mx-chain-tools-go/trieTools
 go 1.17
 
 require (
+	github.com/btcsuite/btcd/btcutil v1.1.3
 	github.com/multiversx/mx-chain-core-go v1.1.30
 	github.com/multiversx/mx-chain-go v1.4.4
 	github.com/multiversx/mx-chain-logger-go v1.0.11
@@ -11,12 +12,12 @@ require (
 	github.com/multiversx/mx-chain-vm-common-go v1.3.36
 	github.com/pelletier/go-toml v1.9.3
 	github.com/stretchr/testify v1.8.1
+	github.com/syndtr/goleveldb v1.0.1-0.20210819022825-2ae1ddf74ef7
 	github.com/tidwall/gjson v1.14.0
 	github.com/urfave/cli v1.22.10
 )
 
 require (
-	github.com/btcsuite/btcd/btcutil v1.1.3 // indirect
 	github.com/cpuguy83/go-md2man/v2 v2.0.0 // indirect
 	github.com/davecgh/go-spew v1.1.1 // indirect
 	github.com/denisbrodbeck/machineid v1.0.1 // indirect
@@ -46,7 +47,6 @@ require (
 	github.com/pmezard/go-difflib v1.0.0 // indirect
 	github.com/russross/blackfriday/v2 v2.0.1 // indirect
 	github.com/shurcooL/sanitized_anchor_name v1.0.0 // indirect
-	github.com/syndtr/goleveldb v1.0.1-0.20210819022825-2ae1ddf74ef7 // indirect
 	github.com/tidwall/match v1.1.1 // indirect
 	github.com/tidwall/pretty v1.2.0 // indirect
 	github.com/ugorji/go/codec v1.2.7 // indirect
@@ -21,6 +21,7 @@ func getFlags() []cli.Flag {
 		trieToolsCommon.LogLevel,
 		trieToolsCommon.DisableAnsiColor,
 		trieToolsCommon.LogSaveFile,
+		trieToolsCommon.LogJSON,
 		trieToolsCommon.LogWithLoggerName,
 		trieToolsCommon.ProfileMode,
 		trieToolsCommon.HexRootHash,
@@ -35,6 +36,7 @@ func getFlagsConfig(ctx *cli.Context) config.ContextFlagsTokensExporter {
 	flagsConfig.DbDir = ctx.GlobalString(trieToolsCommon.DbDirectory.Name)
 	flagsConfig.LogLevel = ctx.GlobalString(trieToolsCommon.LogLevel.Name)
 	flagsConfig.SaveLogFile = ctx.GlobalBool(trieToolsCommon.LogSaveFile.Name)
+	flagsConfig.LogJSON = ctx.GlobalBool(trieToolsCommon.LogJSON.Name)
 	flagsConfig.EnableLogName = ctx.GlobalBool(trieToolsCommon.LogWithLoggerName.Name)
 	flagsConfig.EnablePprof = ctx.GlobalBool(trieToolsCommon.ProfileMode.Name)
 	flagsConfig.HexRootHash = ctx.GlobalString(trieToolsCommon.HexRootHash.Name)
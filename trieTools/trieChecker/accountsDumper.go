@@ -0,0 +1,96 @@
+package main
+
+import (
+	"bufio"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"sync"
+
+	"github.com/multiversx/mx-chain-go/state"
+)
+
+const dumpFilePerms = 0644
+
+type dumpedAccount struct {
+	Address         string `json:"address"`
+	Balance         string `json:"balance"`
+	Nonce           uint64 `json:"nonce"`
+	RootHash        string `json:"rootHash"`
+	CodeHash        string `json:"codeHash"`
+	DeveloperReward string `json:"developerReward"`
+}
+
+// jsonlAccountsDumper writes each account as a JSON line, using a buffered writer. It is safe for concurrent use
+type jsonlAccountsDumper struct {
+	mut    sync.Mutex
+	file   *os.File
+	writer *bufio.Writer
+}
+
+func newJsonlAccountsDumper(filePath string) (*jsonlAccountsDumper, error) {
+	file, err := os.OpenFile(filePath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, dumpFilePerms)
+	if err != nil {
+		return nil, err
+	}
+
+	return &jsonlAccountsDumper{
+		file:   file,
+		writer: bufio.NewWriter(file),
+	}, nil
+}
+
+// dumpAccount writes the provided account
+func (dumper *jsonlAccountsDumper) dumpAccount(address string, account *state.UserAccountData) error {
+	record := &dumpedAccount{
+		Address:         address,
+		Balance:         "0",
+		Nonce:           account.Nonce,
+		RootHash:        hex.EncodeToString(account.RootHash),
+		CodeHash:        hex.EncodeToString(account.CodeHash),
+		DeveloperReward: "0",
+	}
+	if account.Balance != nil {
+		record.Balance = account.Balance.String()
+	}
+	if account.DeveloperReward != nil {
+		record.DeveloperReward = account.DeveloperReward.String()
+	}
+
+	recordBytes, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	dumper.mut.Lock()
+	_, err = dumper.writer.Write(append(recordBytes, '\n'))
+	dumper.mut.Unlock()
+
+	return err
+}
+
+// close flushes the buffered accounts and closes the file
+func (dumper *jsonlAccountsDumper) close() error {
+	dumper.mut.Lock()
+	defer dumper.mut.Unlock()
+
+	errFlush := dumper.writer.Flush()
+	errClose := dumper.file.Close()
+	if errFlush != nil {
+		return errFlush
+	}
+
+	return errClose
+}
+
+type disabledAccountsDumper struct{}
+
+// dumpAccount does nothing
+func (dumper *disabledAccountsDumper) dumpAccount(_ string, _ *state.UserAccountData) error {
+	return nil
+}
+
+// close does nothing
+func (dumper *disabledAccountsDumper) close() error {
+	return nil
+}
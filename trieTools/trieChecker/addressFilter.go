@@ -0,0 +1,61 @@
+package main
+
+import "sync"
+
+// addressFilter decides which accounts of the main trie are processed. If a list of included addresses is provided,
+// only those are processed, while the excluded addresses are never processed. It is safe for concurrent use
+type addressFilter struct {
+	included map[string]struct{}
+	excluded map[string]struct{}
+	mutFound sync.Mutex
+	found    map[string]struct{}
+}
+
+func newAddressFilter(included map[string]struct{}, excluded map[string]struct{}) *addressFilter {
+	return &addressFilter{
+		included: included,
+		excluded: excluded,
+		found:    make(map[string]struct{}),
+	}
+}
+
+// shouldProcess returns true if the account with the provided address should be processed
+func (af *addressFilter) shouldProcess(address []byte) bool {
+	_, isExcluded := af.excluded[string(address)]
+	if isExcluded {
+		return false
+	}
+	if af.included == nil {
+		return true
+	}
+
+	_, isIncluded := af.included[string(address)]
+	if isIncluded {
+		af.mutFound.Lock()
+		af.found[string(address)] = struct{}{}
+		af.mutFound.Unlock()
+	}
+
+	return isIncluded
+}
+
+// hasIncludedAddresses returns true if the processing is limited to a list of addresses
+func (af *addressFilter) hasIncludedAddresses() bool {
+	return af.included != nil
+}
+
+// missingAddresses returns the included addresses that were not found in the trie
+func (af *addressFilter) missingAddresses() [][]byte {
+	af.mutFound.Lock()
+	defer af.mutFound.Unlock()
+
+	missing := make([][]byte, 0)
+	for address := range af.included {
+		_, isFound := af.found[address]
+		if !isFound {
+			missing = append(missing, []byte(address))
+		}
+	}
+
+	return missing
+}
@@ -0,0 +1,24 @@
+package main
+
+import (
+	"bytes"
+
+	"github.com/multiversx/mx-chain-go/state"
+	"github.com/multiversx/mx-chain-tools-go/trieTools/trieToolsCommon"
+)
+
+const maxUnknownNodesSamples = 10
+
+// isCodeNode returns true if the provided main trie leaf holds a smart contract code entry, that is, the value
+// is a code entry whose code hash equals the leaf key
+func isCodeNode(key []byte, value []byte) bool {
+	codeEntry := &state.CodeEntry{}
+	err := trieToolsCommon.Marshaller.Unmarshal(codeEntry, value)
+	if err != nil {
+		return false
+	}
+
+	codeHash := trieToolsCommon.Hasher.Compute(string(codeEntry.Code))
+
+	return bytes.Equal(codeHash, key)
+}
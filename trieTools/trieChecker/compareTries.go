@@ -0,0 +1,175 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/multiversx/mx-chain-core-go/core"
+	"github.com/multiversx/mx-chain-go/common"
+	"github.com/multiversx/mx-chain-go/state"
+	"github.com/multiversx/mx-chain-go/trie/keyBuilder"
+	"github.com/multiversx/mx-chain-tools-go/trieTools/trieToolsCommon"
+)
+
+var errUnsortedLeaves = errors.New("the trie leaves are not provided in ascending key order")
+
+type trieDiffStats struct {
+	numAdded     int
+	numRemoved   int
+	numChanged   int
+	numUnchanged int
+}
+
+type argsCompareTries struct {
+	tr               trieLeavesRetriever
+	addressConverter core.PubkeyConverter
+	firstRootHash    []byte
+	secondRootHash   []byte
+	diffOutput       io.Writer
+}
+
+// accountsStream yields, in ascending key order, the accounts of a main trie. Code nodes and other leaves that are
+// not accounts are skipped
+type accountsStream struct {
+	channels *common.TrieIteratorChannels
+	lastKey  []byte
+	current  core.KeyValueHolder
+	finished bool
+}
+
+func newAccountsStream(tr trieLeavesRetriever, rootHash []byte) (*accountsStream, error) {
+	channels := &common.TrieIteratorChannels{
+		LeavesChan: make(chan core.KeyValueHolder, common.TrieLeavesChannelDefaultCapacity),
+		ErrChan:    make(chan error, 1),
+	}
+	err := tr.GetAllLeavesOnChannel(channels, context.Background(), rootHash, keyBuilder.NewKeyBuilder())
+	if err != nil {
+		return nil, err
+	}
+
+	return &accountsStream{
+		channels: channels,
+	}, nil
+}
+
+// next moves the stream to the next account. The accounts are compared by key while merging the two tries, so the
+// stream errors if the keys are not strictly ascending
+func (stream *accountsStream) next() error {
+	for kv := range stream.channels.LeavesChan {
+		userAccount := &state.UserAccountData{}
+		errUnmarshal := trieToolsCommon.Marshaller.Unmarshal(userAccount, kv.Value())
+		if errUnmarshal != nil {
+			continue
+		}
+
+		if stream.lastKey != nil && bytes.Compare(kv.Key(), stream.lastKey) <= 0 {
+			return errUnsortedLeaves
+		}
+
+		stream.lastKey = kv.Key()
+		stream.current = kv
+		return nil
+	}
+
+	stream.finished = true
+	stream.current = nil
+
+	return common.GetErrorFromChanNonBlocking(stream.channels.ErrChan)
+}
+
+// drain consumes the remaining leaves, so the trie iteration is not blocked
+func (stream *accountsStream) drain() {
+	for range stream.channels.LeavesChan {
+	}
+}
+
+// compareTries walks the two main tries at once and reports the accounts added, removed or changed (any field of the
+// account, including the data trie root hash) from the first root hash to the second one. Since both tries are
+// iterated in ascending key order, the diff is computed without holding the accounts in memory
+func compareTries(args argsCompareTries) (*trieDiffStats, error) {
+	first, err := newAccountsStream(args.tr, args.firstRootHash)
+	if err != nil {
+		return nil, trieToolsCommon.NewIntegrityError(fmt.Errorf("%w while starting the iteration of the first trie", err))
+	}
+	defer first.drain()
+
+	second, err := newAccountsStream(args.tr, args.secondRootHash)
+	if err != nil {
+		return nil, trieToolsCommon.NewIntegrityError(fmt.Errorf("%w while starting the iteration of the second trie", err))
+	}
+	defer second.drain()
+
+	diffWriter := bufio.NewWriter(io.Discard)
+	if args.diffOutput != nil {
+		diffWriter = bufio.NewWriter(args.diffOutput)
+	}
+	writeDiff := func(kind string, key []byte) error {
+		_, errWrite := fmt.Fprintf(diffWriter, "%s %s\n", kind, args.addressConverter.Encode(key))
+		return errWrite
+	}
+
+	err = first.next()
+	if err != nil {
+		return nil, trieToolsCommon.NewIntegrityError(fmt.Errorf("%w while iterating the first trie", err))
+	}
+	err = second.next()
+	if err != nil {
+		return nil, trieToolsCommon.NewIntegrityError(fmt.Errorf("%w while iterating the second trie", err))
+	}
+
+	stats := &trieDiffStats{}
+	for !first.finished || !second.finished {
+		advanceFirst, advanceSecond := false, false
+		switch {
+		case second.finished || (!first.finished && bytes.Compare(first.current.Key(), second.current.Key()) < 0):
+			stats.numRemoved++
+			err = writeDiff("removed", first.current.Key())
+			advanceFirst = true
+		case first.finished || bytes.Compare(first.current.Key(), second.current.Key()) > 0:
+			stats.numAdded++
+			err = writeDiff("added", second.current.Key())
+			advanceSecond = true
+		default:
+			if bytes.Equal(first.current.Value(), second.current.Value()) {
+				stats.numUnchanged++
+			} else {
+				stats.numChanged++
+				err = writeDiff("changed", first.current.Key())
+			}
+			advanceFirst, advanceSecond = true, true
+		}
+		if err != nil {
+			return nil, trieToolsCommon.NewIOError(fmt.Errorf("%w while writing the diff", err))
+		}
+
+		if advanceFirst {
+			err = first.next()
+			if err != nil {
+				return nil, trieToolsCommon.NewIntegrityError(fmt.Errorf("%w while iterating the first trie", err))
+			}
+		}
+		if advanceSecond {
+			err = second.next()
+			if err != nil {
+				return nil, trieToolsCommon.NewIntegrityError(fmt.Errorf("%w while iterating the second trie", err))
+			}
+		}
+	}
+
+	err = diffWriter.Flush()
+	if err != nil {
+		return nil, trieToolsCommon.NewIOError(fmt.Errorf("%w while writing the diff", err))
+	}
+
+	log.Info("compared tries",
+		"num added accounts", stats.numAdded,
+		"num removed accounts", stats.numRemoved,
+		"num changed accounts", stats.numChanged,
+		"num unchanged accounts", stats.numUnchanged)
+
+	return stats, nil
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/multiversx/mx-chain-core-go/core"
+	"github.com/multiversx/mx-chain-core-go/core/keyValStorage"
+	"github.com/multiversx/mx-chain-go/common"
+	"github.com/multiversx/mx-chain-go/state"
+	"github.com/multiversx/mx-chain-tools-go/trieTools/trieChecker/mocks"
+	"github.com/multiversx/mx-chain-tools-go/trieTools/trieToolsCommon"
+	"github.com/stretchr/testify/require"
+)
+
+var testSecondRootHash = bytes.Repeat([]byte("s"), rootHashLength)
+
+func createTestAccountLeafWithBalance(t *testing.T, address []byte, balance int64) core.KeyValueHolder {
+	account := &state.UserAccountData{
+		Address: address,
+		Balance: big.NewInt(balance),
+	}
+	accountBytes, err := trieToolsCommon.Marshaller.Marshal(account)
+	require.Nil(t, err)
+
+	return keyValStorage.NewKeyValStorage(address, accountBytes)
+}
+
+func createTestTriesForComparison(firstLeaves []core.KeyValueHolder, secondLeaves []core.KeyValueHolder) *mocks.TrieLeavesRetrieverStub {
+	return &mocks.TrieLeavesRetrieverStub{
+		GetAllLeavesOnChannelCalled: func(leavesChannels *common.TrieIteratorChannels, ctx context.Context, rootHash []byte, keyBuilder common.KeyBuilder) error {
+			if bytes.Equal(rootHash, testMainRootHash) {
+				sendLeaves(leavesChannels, nil, firstLeaves...)
+				return nil
+			}
+
+			sendLeaves(leavesChannels, nil, secondLeaves...)
+			return nil
+		},
+	}
+}
+
+func TestCompareTries(t *testing.T) {
+	t.Parallel()
+
+	addressA := bytes.Repeat([]byte("a"), addressLength)
+	addressB := bytes.Repeat([]byte("b"), addressLength)
+	addressC := bytes.Repeat([]byte("c"), addressLength)
+	addressD := bytes.Repeat([]byte("d"), addressLength)
+
+	t.Run("unsorted leaves, should error", func(t *testing.T) {
+		t.Parallel()
+
+		tr := createTestTriesForComparison(
+			[]core.KeyValueHolder{createTestAccountLeafWithBalance(t, addressB, 1), createTestAccountLeafWithBalance(t, addressA, 1)},
+			[]core.KeyValueHolder{createTestAccountLeafWithBalance(t, addressA, 1)},
+		)
+
+		stats, err := compareTries(argsCompareTries{
+			tr:               tr,
+			addressConverter: createTestAddressConverter(t),
+			firstRootHash:    testMainRootHash,
+			secondRootHash:   testSecondRootHash,
+		})
+		require.Nil(t, stats)
+		require.ErrorIs(t, err, errUnsortedLeaves)
+	})
+	t.Run("should work", func(t *testing.T) {
+		t.Parallel()
+
+		tr := createTestTriesForComparison(
+			[]core.KeyValueHolder{
+				createTestAccountLeafWithBalance(t, addressA, 1),
+				createTestAccountLeafWithBalance(t, addressB, 1),
+				createTestCodeLeaf(t, []byte("code")),
+				createTestAccountLeafWithBalance(t, addressC, 1),
+			},
+			[]core.KeyValueHolder{
+				createTestAccountLeafWithBalance(t, addressA, 1),
+				createTestAccountLeafWithBalance(t, addressC, 2),
+				createTestAccountLeafWithBalance(t, addressD, 1),
+			},
+		)
+
+		diffOutput := &bytes.Buffer{}
+		converter := createTestAddressConverter(t)
+		stats, err := compareTries(argsCompareTries{
+			tr:               tr,
+			addressConverter: converter,
+			firstRootHash:    testMainRootHash,
+			secondRootHash:   testSecondRootHash,
+			diffOutput:       diffOutput,
+		})
+		require.Nil(t, err)
+		require.Equal(t, &trieDiffStats{
+			numAdded:     1,
+			numRemoved:   1,
+			numChanged:   1,
+			numUnchanged: 1,
+		}, stats)
+
+		expectedDiff := fmt.Sprintf("removed %s\nchanged %s\nadded %s\n",
+			converter.Encode(addressB), converter.Encode(addressC), converter.Encode(addressD))
+		require.Equal(t, expectedDiff, diffOutput.String())
+	})
+}
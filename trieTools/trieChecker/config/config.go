@@ -1,13 +1,21 @@
 package config
 
-// ContextFlagsConfig the configuration for flags
-type ContextFlagsConfig struct {
-	WorkingDir       string
-	DbDir            string
-	LogLevel         string
-	DisableAnsiColor bool
-	SaveLogFile      bool
-	EnableLogName    bool
-	EnablePprof      bool
-	HexRootHash      string
+import "github.com/multiversx/mx-chain-tools-go/trieTools/trieToolsCommon"
+
+// ContextFlagsTrieChecker is the flags config for trie checker
+type ContextFlagsTrieChecker struct {
+	trieToolsCommon.ContextFlagsConfig
+	StatsOutput        string
+	AddressesFile      string
+	ExcludeFile        string
+	DumpAccounts       string
+	CompareHexRootHash string
+	CompareOutput      string
+	SkipDataTries      bool
+	EstimateFraction   float64
+	NodeHistogram      bool
+	TokenReport        bool
+	TokenReportTop     int
+	NumWorkers         int
+	DataRootsOut       string
 }
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"bufio"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"sync"
+)
+
+// fileDataRootsWriter writes each data trie root hash as an "<address> <hex root hash>" line, as soon as the account
+// is found in the main trie, so the whole map is not held for writing. It is safe for concurrent use
+type fileDataRootsWriter struct {
+	mut    sync.Mutex
+	file   *os.File
+	writer *bufio.Writer
+}
+
+func newFileDataRootsWriter(filePath string) (*fileDataRootsWriter, error) {
+	file, err := os.OpenFile(filePath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, dumpFilePerms)
+	if err != nil {
+		return nil, err
+	}
+
+	return &fileDataRootsWriter{
+		file:   file,
+		writer: bufio.NewWriter(file),
+	}, nil
+}
+
+// writeDataRoot writes the data trie root hash of the provided address
+func (drw *fileDataRootsWriter) writeDataRoot(address string, rootHash []byte) error {
+	drw.mut.Lock()
+	defer drw.mut.Unlock()
+
+	_, err := fmt.Fprintf(drw.writer, "%s %s\n", address, hex.EncodeToString(rootHash))
+
+	return err
+}
+
+// flush writes the buffered lines in the file
+func (drw *fileDataRootsWriter) flush() error {
+	drw.mut.Lock()
+	defer drw.mut.Unlock()
+
+	return drw.writer.Flush()
+}
+
+// close flushes the buffered lines and closes the file
+func (drw *fileDataRootsWriter) close() error {
+	drw.mut.Lock()
+	defer drw.mut.Unlock()
+
+	errFlush := drw.writer.Flush()
+	errClose := drw.file.Close()
+	if errFlush != nil {
+		return errFlush
+	}
+
+	return errClose
+}
+
+type disabledDataRootsWriter struct{}
+
+// writeDataRoot does nothing
+func (drw *disabledDataRootsWriter) writeDataRoot(_ string, _ []byte) error {
+	return nil
+}
+
+// flush does nothing
+func (drw *disabledDataRootsWriter) flush() error {
+	return nil
+}
+
+// close does nothing
+func (drw *disabledDataRootsWriter) close() error {
+	return nil
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"runtime"
+
+	"github.com/multiversx/mx-chain-tools-go/trieTools/trieChecker/config"
+	"github.com/multiversx/mx-chain-tools-go/trieTools/trieToolsCommon"
+	"github.com/urfave/cli"
+)
+
+var (
+	statsOutput = cli.StringFlag{
+		Name:  "stats-output",
+		Usage: "This flag specifies the file where the trie statistics will be written as JSON. If not set, the statistics will only be logged",
+		Value: "",
+	}
+	addressesFile = cli.StringFlag{
+		Name:  "addresses-file",
+		Usage: "This flag specifies a file with one bech32 address per line. If set, only the listed accounts (and their data tries) are checked",
+		Value: "",
+	}
+	dumpAccounts = cli.StringFlag{
+		Name:  "dump-accounts",
+		Usage: "This flag specifies the file where the checked accounts (address, balance, nonce, root hash, code hash and developer reward) will be written, one JSON per line",
+		Value: "",
+	}
+	compareRootHash = cli.StringFlag{
+		Name:  "compare-root-hash",
+		Usage: "This flag specifies a second hex root hash. If set, the tool compares the two main tries and reports the accounts added, removed or changed from the hex-roothash to the compare-root-hash, instead of checking the trie",
+		Value: "",
+	}
+	compareOutput = cli.StringFlag{
+		Name:  "compare-output",
+		Usage: "This flag specifies the file where the differing addresses will be written, one per line, when the compare-root-hash flag is set",
+		Value: "",
+	}
+	skipDataTries = cli.BoolFlag{
+		Name:  "skip-data-tries",
+		Usage: "If set, the data tries are not iterated, so only the main trie statistics are reported. The num of data tries leaves is not computed",
+	}
+	estimate = cli.Float64Flag{
+		Name:  "estimate",
+		Usage: "This flag specifies the fraction, in the (0, 1) interval, of data tries to be iterated. The num of data tries leaves is extrapolated from the sampled data tries and reported as an estimation. 0 or 1 iterate all the data tries",
+		Value: 0,
+	}
+	nodeHistogram = cli.BoolFlag{
+		Name:  "node-histogram",
+		Usage: "If set, the raw nodes of the main trie and of the data tries are also walked, after the leaves iteration, in order to report the number and size of the branch, extension and leaf nodes. This reads each trie node once more, so it significantly increases the check duration",
+	}
+	tokenReport = cli.BoolFlag{
+		Name:  "token-report",
+		Usage: "If set, the ESDT token entries found in each iterated data trie are counted and a report with the accounts holding the most token entries and the distribution of the accounts by num of token entries is emitted",
+	}
+	tokenReportTop = cli.IntFlag{
+		Name:  "token-report-top",
+		Usage: "This flag specifies the number of accounts with the most token entries to be reported, when the token-report flag is set",
+		Value: 10,
+	}
+	numWorkers = cli.IntFlag{
+		Name:  "num-workers",
+		Usage: "This flag specifies the number of goroutines that unmarshal the main trie leaves. It defaults to the number of CPUs",
+		Value: runtime.NumCPU(),
+	}
+	dataRootsOut = cli.StringFlag{
+		Name:  "data-roots-out",
+		Usage: "This flag specifies the file where the data tries root hashes of the checked accounts will be written, one \"<bech32 address> <hex root hash>\" per line, while iterating the main trie",
+		Value: "",
+	}
+	excludeFile = cli.StringFlag{
+		Name:  "exclude-file",
+		Usage: "This flag specifies a file with one bech32 address per line. The listed accounts (and their data tries) are not checked",
+		Value: "",
+	}
+)
+
+func getFlags() []cli.Flag {
+	return append(trieToolsCommon.GetFlags(),
+		statsOutput,
+		addressesFile,
+		excludeFile,
+		dumpAccounts,
+		compareRootHash,
+		compareOutput,
+		skipDataTries,
+		estimate,
+		nodeHistogram,
+		tokenReport,
+		tokenReportTop,
+		numWorkers,
+		dataRootsOut,
+	)
+}
+
+func getFlagsConfig(ctx *cli.Context) config.ContextFlagsTrieChecker {
+	flagsConfig := config.ContextFlagsTrieChecker{}
+
+	flagsConfig.ContextFlagsConfig = trieToolsCommon.GetFlagsConfig(ctx)
+	flagsConfig.StatsOutput = ctx.GlobalString(statsOutput.Name)
+	flagsConfig.AddressesFile = ctx.GlobalString(addressesFile.Name)
+	flagsConfig.ExcludeFile = ctx.GlobalString(excludeFile.Name)
+	flagsConfig.DumpAccounts = ctx.GlobalString(dumpAccounts.Name)
+	flagsConfig.CompareHexRootHash = ctx.GlobalString(compareRootHash.Name)
+	flagsConfig.CompareOutput = ctx.GlobalString(compareOutput.Name)
+	flagsConfig.SkipDataTries = ctx.GlobalBool(skipDataTries.Name)
+	flagsConfig.EstimateFraction = ctx.GlobalFloat64(estimate.Name)
+	flagsConfig.NodeHistogram = ctx.GlobalBool(nodeHistogram.Name)
+	flagsConfig.TokenReport = ctx.GlobalBool(tokenReport.Name)
+	flagsConfig.TokenReportTop = ctx.GlobalInt(tokenReportTop.Name)
+	flagsConfig.NumWorkers = ctx.GlobalInt(numWorkers.Name)
+	flagsConfig.DataRootsOut = ctx.GlobalString(dataRootsOut.Name)
+
+	return flagsConfig
+}
@@ -0,0 +1,216 @@
+package main
+
+import (
+	"bufio"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/multiversx/mx-chain-go/storage"
+	"github.com/multiversx/mx-chain-tools-go/trieTools/trieToolsCommon"
+)
+
+const (
+	memStatsSamplingInterval = time.Second
+	procStatusFile           = "/proc/self/status"
+	peakRSSField             = "VmHWM:"
+	bytesInKB                = 1024
+)
+
+// countingStorer counts the Get calls, that is, the trie nodes read from the storage
+type countingStorer struct {
+	storage.Storer
+	numReads uint64
+}
+
+func newCountingStorer(storer storage.Storer) *countingStorer {
+	return &countingStorer{
+		Storer: storer,
+	}
+}
+
+// Get counts the read and returns the value from the wrapped storer
+func (cs *countingStorer) Get(key []byte) ([]byte, error) {
+	atomic.AddUint64(&cs.numReads, 1)
+
+	return cs.Storer.Get(key)
+}
+
+func (cs *countingStorer) numNodeReads() uint64 {
+	return atomic.LoadUint64(&cs.numReads)
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (cs *countingStorer) IsInterfaceNil() bool {
+	return cs == nil
+}
+
+// phasesTracker measures the wall-clock time and the trie node reads of consecutive processing phases
+type phasesTracker struct {
+	counter        nodeReadsCounter
+	phases         []trieToolsCommon.PhaseStatistics
+	currentName    string
+	currentStart   time.Time
+	currentNumRead uint64
+}
+
+func newPhasesTracker(counter nodeReadsCounter) *phasesTracker {
+	return &phasesTracker{
+		counter: counter,
+		phases:  make([]trieToolsCommon.PhaseStatistics, 0),
+	}
+}
+
+func (pt *phasesTracker) numNodeReads() uint64 {
+	if pt.counter == nil {
+		return 0
+	}
+
+	return pt.counter.numNodeReads()
+}
+
+func (pt *phasesTracker) startPhase(name string) {
+	pt.currentName = name
+	pt.currentStart = time.Now()
+	pt.currentNumRead = pt.numNodeReads()
+}
+
+func (pt *phasesTracker) endPhase() {
+	phase := trieToolsCommon.PhaseStatistics{
+		Name:         pt.currentName,
+		DurationMs:   time.Since(pt.currentStart).Milliseconds(),
+		NumNodeReads: pt.numNodeReads() - pt.currentNumRead,
+	}
+	pt.phases = append(pt.phases, phase)
+
+	log.Info("finished phase",
+		"phase", phase.Name,
+		"duration", time.Duration(phase.DurationMs)*time.Millisecond,
+		"num node reads", phase.NumNodeReads)
+}
+
+func (pt *phasesTracker) statistics() []trieToolsCommon.PhaseStatistics {
+	return pt.phases
+}
+
+// memStatsSampler periodically samples the runtime memory statistics, keeping the peak values
+type memStatsSampler struct {
+	mut                sync.Mutex
+	peakSysBytes       uint64
+	peakHeapInUseBytes uint64
+	lastMemStats       runtime.MemStats
+
+	closeChan chan struct{}
+	wg        sync.WaitGroup
+}
+
+func newMemStatsSampler(interval time.Duration) *memStatsSampler {
+	sampler := &memStatsSampler{
+		closeChan: make(chan struct{}),
+	}
+	sampler.sample()
+
+	sampler.wg.Add(1)
+	go sampler.sampleContinuously(interval)
+
+	return sampler
+}
+
+func (sampler *memStatsSampler) sampleContinuously(interval time.Duration) {
+	defer sampler.wg.Done()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			sampler.sample()
+		case <-sampler.closeChan:
+			return
+		}
+	}
+}
+
+func (sampler *memStatsSampler) sample() {
+	memStats := runtime.MemStats{}
+	runtime.ReadMemStats(&memStats)
+
+	sampler.mut.Lock()
+	defer sampler.mut.Unlock()
+
+	if memStats.Sys > sampler.peakSysBytes {
+		sampler.peakSysBytes = memStats.Sys
+	}
+	if memStats.HeapInuse > sampler.peakHeapInUseBytes {
+		sampler.peakHeapInUseBytes = memStats.HeapInuse
+	}
+	sampler.lastMemStats = memStats
+
+	log.Debug("memory usage",
+		"sys", memStats.Sys,
+		"heap in use", memStats.HeapInuse,
+		"num GC", memStats.NumGC)
+}
+
+// close stops the sampling and returns the gathered statistics
+func (sampler *memStatsSampler) close() *trieToolsCommon.MemoryStatistics {
+	close(sampler.closeChan)
+	sampler.wg.Wait()
+	sampler.sample()
+
+	sampler.mut.Lock()
+	defer sampler.mut.Unlock()
+
+	stats := &trieToolsCommon.MemoryStatistics{
+		PeakRSSBytes:       readPeakRSSBytes(procStatusFile),
+		PeakSysBytes:       sampler.peakSysBytes,
+		PeakHeapInUseBytes: sampler.peakHeapInUseBytes,
+		NumGC:              sampler.lastMemStats.NumGC,
+		GCPauseTotalMs:     time.Duration(sampler.lastMemStats.PauseTotalNs).Milliseconds(),
+		GCCPUFraction:      sampler.lastMemStats.GCCPUFraction,
+	}
+
+	log.Info("memory statistics",
+		"peak RSS", stats.PeakRSSBytes,
+		"peak sys", stats.PeakSysBytes,
+		"peak heap in use", stats.PeakHeapInUseBytes,
+		"num GC", stats.NumGC,
+		"GC pause total", time.Duration(sampler.lastMemStats.PauseTotalNs),
+		"GC CPU fraction", stats.GCCPUFraction)
+
+	return stats
+}
+
+// readPeakRSSBytes reads the peak resident set size from the provided proc status file. It returns 0 if the value
+// is not available (e.g. on a non-linux OS)
+func readPeakRSSBytes(statusFile string) uint64 {
+	file, err := os.Open(statusFile)
+	if err != nil {
+		return 0
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 || fields[0] != peakRSSField {
+			continue
+		}
+
+		valueInKB, errParse := strconv.ParseUint(fields[1], 10, 64)
+		if errParse != nil {
+			return 0
+		}
+
+		return valueInKB * bytesInKB
+	}
+
+	return 0
+}
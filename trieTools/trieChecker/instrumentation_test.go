@@ -0,0 +1,96 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/multiversx/mx-chain-go/testscommon/genericMocks"
+	"github.com/stretchr/testify/require"
+)
+
+type nodeReadsCounterStub struct {
+	numReads uint64
+}
+
+func (stub *nodeReadsCounterStub) numNodeReads() uint64 {
+	return stub.numReads
+}
+
+func TestCountingStorer_Get(t *testing.T) {
+	t.Parallel()
+
+	storer := genericMocks.NewStorerMock()
+	require.Nil(t, storer.Put([]byte("key"), []byte("value")))
+
+	cs := newCountingStorer(storer)
+	val, err := cs.Get([]byte("key"))
+	require.Nil(t, err)
+	require.Equal(t, []byte("value"), val)
+
+	_, _ = cs.Get([]byte("missing key"))
+	require.Equal(t, uint64(2), cs.numNodeReads())
+}
+
+func TestPhasesTracker(t *testing.T) {
+	t.Parallel()
+
+	t.Run("nil counter should work", func(t *testing.T) {
+		t.Parallel()
+
+		pt := newPhasesTracker(nil)
+		pt.startPhase("phase")
+		pt.endPhase()
+
+		phases := pt.statistics()
+		require.Len(t, phases, 1)
+		require.Equal(t, "phase", phases[0].Name)
+		require.Equal(t, uint64(0), phases[0].NumNodeReads)
+	})
+	t.Run("should count the reads of each phase", func(t *testing.T) {
+		t.Parallel()
+
+		counter := &nodeReadsCounterStub{numReads: 5}
+		pt := newPhasesTracker(counter)
+		pt.startPhase("first")
+		counter.numReads = 12
+		pt.endPhase()
+		pt.startPhase("second")
+		counter.numReads = 15
+		pt.endPhase()
+
+		phases := pt.statistics()
+		require.Len(t, phases, 2)
+		require.Equal(t, uint64(7), phases[0].NumNodeReads)
+		require.Equal(t, uint64(3), phases[1].NumNodeReads)
+	})
+}
+
+func TestMemStatsSampler(t *testing.T) {
+	t.Parallel()
+
+	sampler := newMemStatsSampler(time.Millisecond)
+	time.Sleep(time.Millisecond * 10)
+	stats := sampler.close()
+	require.NotZero(t, stats.PeakSysBytes)
+	require.NotZero(t, stats.PeakHeapInUseBytes)
+}
+
+func TestReadPeakRSSBytes(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	statusFile := filepath.Join(dir, "status")
+	err := os.WriteFile(statusFile, []byte("Name:\ttrieChecker\nVmPeak:\t  2000 kB\nVmHWM:\t  1500 kB\nVmRSS:\t  1000 kB\n"), 0644)
+	require.Nil(t, err)
+	require.Equal(t, uint64(1500*1024), readPeakRSSBytes(statusFile))
+
+	invalidFile := filepath.Join(dir, "invalid")
+	err = os.WriteFile(invalidFile, []byte("VmHWM:\tabc kB\n"), 0644)
+	require.Nil(t, err)
+	require.Equal(t, uint64(0), readPeakRSSBytes(invalidFile))
+
+	require.Equal(t, uint64(0), readPeakRSSBytes(filepath.Join(dir, "missing")))
+}
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"context"
+
+	"github.com/multiversx/mx-chain-go/common"
+	"github.com/multiversx/mx-chain-go/state"
+)
+
+type trieLeavesRetriever interface {
+	GetAllLeavesOnChannel(leavesChannels *common.TrieIteratorChannels, ctx context.Context, rootHash []byte, keyBuilder common.KeyBuilder) error
+}
+
+type accountsDumper interface {
+	dumpAccount(address string, account *state.UserAccountData) error
+	close() error
+}
+
+type dataRootsWriter interface {
+	writeDataRoot(address string, rootHash []byte) error
+	flush() error
+	close() error
+}
+
+type nodesGetter interface {
+	Get(key []byte) ([]byte, error)
+}
+
+type nodeReadsCounter interface {
+	numNodeReads() uint64
+}
@@ -3,32 +3,45 @@ package main
 import (
 	"context"
 	"encoding/hex"
+	"errors"
 	"fmt"
+	"math"
 	"os"
+	"os/signal"
 	"path/filepath"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
 
 	"github.com/multiversx/mx-chain-core-go/core"
 	"github.com/multiversx/mx-chain-core-go/core/pubkeyConverter"
 	"github.com/multiversx/mx-chain-go/common"
-	"github.com/multiversx/mx-chain-go/state"
 	"github.com/multiversx/mx-chain-go/storage"
 	"github.com/multiversx/mx-chain-go/trie/keyBuilder"
 	logger "github.com/multiversx/mx-chain-logger-go"
+	"github.com/multiversx/mx-chain-tools-go/trieTools/trieChecker/config"
 	"github.com/multiversx/mx-chain-tools-go/trieTools/trieToolsCommon"
 	"github.com/urfave/cli"
 )
 
+var errTrieCheckInterrupted = errors.New("the trie check was interrupted, the reported statistics are partial")
+
 const (
 	logFilePrefix  = "trie-checker"
 	rootHashLength = 32
 	addressLength  = 32
+	mainTriePhase  = "main trie"
+	dataTriesPhase = "data tries"
 )
 
 func main() {
 	app := cli.NewApp()
 	app.Name = "Trie checker CLI app"
 	app.Usage = "This is the entry point for the tool that checks the trie DB"
-	app.Flags = trieToolsCommon.GetFlags()
+	app.Version = trieToolsCommon.GetVersion()
+	app.Flags = getFlags()
+	app.OnUsageError = trieToolsCommon.OnUsageError
 	app.Authors = []cli.Author{
 		{
 			Name:  "The MultiversX Team",
@@ -43,7 +56,7 @@ func main() {
 	err := app.Run(os.Args)
 	if err != nil {
 		log.Error(err.Error())
-		os.Exit(1)
+		os.Exit(trieToolsCommon.ExitCode(err))
 		return
 	}
 
@@ -51,9 +64,9 @@ func main() {
 }
 
 func startProcess(c *cli.Context) error {
-	flagsConfig := trieToolsCommon.GetFlagsConfig(c)
+	flagsConfig := getFlagsConfig(c)
 
-	_, errLogger := trieToolsCommon.AttachFileLogger(log, logFilePrefix, flagsConfig)
+	_, errLogger := trieToolsCommon.AttachFileLogger(log, logFilePrefix, flagsConfig.ContextFlagsConfig)
 	if errLogger != nil {
 		return errLogger
 	}
@@ -62,15 +75,42 @@ func startProcess(c *cli.Context) error {
 
 	err := logger.SetLogLevel(flagsConfig.LogLevel)
 	if err != nil {
-		return err
+		return trieToolsCommon.NewUsageError(err)
 	}
 
-	rootHash, err := hex.DecodeString(flagsConfig.HexRootHash)
-	if err != nil {
-		return fmt.Errorf("%w when decoding the provided hex root hash", err)
+	if flagsConfig.EstimateFraction < 0 || flagsConfig.EstimateFraction > 1 {
+		return trieToolsCommon.NewUsageError(fmt.Errorf("invalid estimate fraction: expected a value in the [0, 1] interval, got %v", flagsConfig.EstimateFraction))
 	}
-	if len(rootHash) != rootHashLength {
-		return fmt.Errorf("wrong root hash length: expected %d, got %d", rootHashLength, len(rootHash))
+	if flagsConfig.SkipDataTries && flagsConfig.EstimateFraction > 0 {
+		return trieToolsCommon.NewUsageError(fmt.Errorf("the skip-data-tries and estimate flags can not be used together"))
+	}
+	if flagsConfig.NumWorkers < 1 {
+		return trieToolsCommon.NewUsageError(fmt.Errorf("invalid num workers: expected a positive value, got %d", flagsConfig.NumWorkers))
+	}
+	if flagsConfig.SkipDataTries && flagsConfig.TokenReport {
+		return trieToolsCommon.NewUsageError(fmt.Errorf("the skip-data-tries and token-report flags can not be used together"))
+	}
+	if flagsConfig.TokenReport && flagsConfig.TokenReportTop < 1 {
+		return trieToolsCommon.NewUsageError(fmt.Errorf("invalid token report top: expected a positive value, got %d", flagsConfig.TokenReportTop))
+	}
+
+	var rootHash []byte
+	if !trieToolsCommon.ShouldUseLatestRootHash(flagsConfig.ContextFlagsConfig) {
+		rootHash, err = decodeRootHash(flagsConfig.HexRootHash)
+		if err != nil {
+			return trieToolsCommon.NewUsageError(err)
+		}
+	}
+
+	if len(flagsConfig.CompareHexRootHash) > 0 {
+		compareRootHash, errDecode := decodeRootHash(flagsConfig.CompareHexRootHash)
+		if errDecode != nil {
+			return trieToolsCommon.NewUsageError(fmt.Errorf("%w for the compare root hash", errDecode))
+		}
+
+		log.Info("starting comparing tries", "pid", os.Getpid())
+
+		return compareTriesFromDB(flagsConfig, rootHash, compareRootHash)
 	}
 
 	log.Info("starting processing trie", "pid", os.Getpid())
@@ -78,13 +118,25 @@ func startProcess(c *cli.Context) error {
 	return checkTrie(flagsConfig, rootHash)
 }
 
-func checkTrie(flags trieToolsCommon.ContextFlagsConfig, mainRootHash []byte) error {
+func decodeRootHash(hexRootHash string) ([]byte, error) {
+	rootHash, err := hex.DecodeString(hexRootHash)
+	if err != nil {
+		return nil, fmt.Errorf("%w when decoding the provided hex root hash", err)
+	}
+	if len(rootHash) != rootHashLength {
+		return nil, fmt.Errorf("wrong root hash length: expected %d, got %d", rootHashLength, len(rootHash))
+	}
+
+	return rootHash, nil
+}
+
+func compareTriesFromDB(flags config.ContextFlagsTrieChecker, firstRootHash []byte, secondRootHash []byte) error {
 	addressConverter, err := pubkeyConverter.NewBech32PubkeyConverter(addressLength, log)
 	if err != nil {
 		return err
 	}
 
-	storer, err := createStorer(flags, log)
+	storer, err := createStorer(flags.ContextFlagsConfig, log)
 	if err != nil {
 		return err
 	}
@@ -99,80 +151,471 @@ func checkTrie(flags trieToolsCommon.ContextFlagsConfig, mainRootHash []byte) er
 		log.LogIfError(errNotCritical)
 	}()
 
-	iteratorChannels := &common.TrieIteratorChannels{
-		LeavesChan: make(chan core.KeyValueHolder, common.TrieLeavesChannelDefaultCapacity),
-		ErrChan:    make(chan error, 1),
+	firstRootHash, err = trieToolsCommon.ResolveRootHash(firstRootHash, flags.ContextFlagsConfig, storer)
+	if err != nil {
+		return err
+	}
+
+	args := argsCompareTries{
+		tr:               tr,
+		addressConverter: addressConverter,
+		firstRootHash:    firstRootHash,
+		secondRootHash:   secondRootHash,
+	}
+	if len(flags.CompareOutput) > 0 {
+		file, errCreate := os.Create(flags.CompareOutput)
+		if errCreate != nil {
+			return trieToolsCommon.NewIOError(fmt.Errorf("%w while creating the compare output file", errCreate))
+		}
+		defer func() {
+			log.LogIfError(file.Close())
+		}()
+
+		args.diffOutput = file
 	}
-	err = tr.GetAllLeavesOnChannel(iteratorChannels, context.Background(), mainRootHash, keyBuilder.NewKeyBuilder())
+
+	_, err = compareTries(args)
+
+	return err
+}
+
+func checkTrie(flags config.ContextFlagsTrieChecker, mainRootHash []byte) error {
+	addressConverter, err := pubkeyConverter.NewBech32PubkeyConverter(addressLength, log)
 	if err != nil {
 		return err
 	}
 
-	numAccountsOnMainTrie := 0
-	numCodeNodes := 0
-	dataTriesRootHashes := make(map[string][]byte)
-	numDataTriesLeaves := 0
-	for kv := range iteratorChannels.LeavesChan {
-		numAccountsOnMainTrie++
-
-		userAccount := &state.UserAccountData{}
-		errUnmarshal := trieToolsCommon.Marshaller.Unmarshal(userAccount, kv.Value())
-		if errUnmarshal != nil {
-			// probably a code node
-			numCodeNodes++
-			continue
+	filter, err := createAddressFilter(flags, addressConverter)
+	if err != nil {
+		return err
+	}
+
+	storer, err := createStorer(flags.ContextFlagsConfig, log)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stopSignalHandler := cancelOnSignal(cancel)
+	defer stopSignalHandler()
+
+	countingStorerInstance := newCountingStorer(storer)
+	tr, err := trieToolsCommon.CreateTrie(countingStorerInstance)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		errNotCritical := tr.Close()
+		log.LogIfError(errNotCritical)
+	}()
+
+	// the latest root hash is read from the underlying storer, so that the read is not counted in the statistics
+	mainRootHash, err = trieToolsCommon.ResolveRootHash(mainRootHash, flags.ContextFlagsConfig, storer)
+	if err != nil {
+		return err
+	}
+
+	dumper, err := createAccountsDumper(flags.DumpAccounts)
+	if err != nil {
+		return err
+	}
+
+	rootsWriter, err := createDataRootsWriter(flags.DataRootsOut)
+	if err != nil {
+		_ = dumper.close()
+		return err
+	}
+
+	var tokenReportBuilderInstance *tokenReportBuilder
+	if flags.TokenReport {
+		tokenReportBuilderInstance = newTokenReportBuilder(flags.TokenReportTop)
+	}
+
+	sampler := newMemStatsSampler(memStatsSamplingInterval)
+	stats, err := iterateTries(argsIterateTries{
+		ctx:              ctx,
+		tr:               tr,
+		addressConverter: addressConverter,
+		mainRootHash:     mainRootHash,
+		filter:           filter,
+		dumper:           dumper,
+		dataRootsWriter:  rootsWriter,
+		readsCounter:     countingStorerInstance,
+		tokenReport:      tokenReportBuilderInstance,
+		numWorkers:       flags.NumWorkers,
+
+		skipDataTries:           flags.SkipDataTries,
+		dataTriesSampleFraction: flags.EstimateFraction,
+	})
+	if err == nil && flags.NodeHistogram && !stats.Partial {
+		err = addNodeHistogram(ctx, countingStorerInstance, mainRootHash, !flags.SkipDataTries, stats)
+	}
+	memoryStats := sampler.close()
+	errClose := dumper.close()
+	errCloseRootsWriter := rootsWriter.close()
+	if err != nil {
+		return err
+	}
+	if errClose != nil {
+		return trieToolsCommon.NewIOError(fmt.Errorf("%w while writing the accounts dump", errClose))
+	}
+	if errCloseRootsWriter != nil {
+		return trieToolsCommon.NewIOError(fmt.Errorf("%w while writing the data tries root hashes", errCloseRootsWriter))
+	}
+
+	if len(flags.StatsOutput) > 0 {
+		stats.Memory = memoryStats
+		stats.Timestamp = time.Now().Unix()
+
+		err = trieToolsCommon.SaveTrieStatistics(stats, flags.StatsOutput)
+		if err != nil {
+			return err
 		}
-		if len(userAccount.RootHash) == 0 {
-			continue
+	}
+
+	if stats.Partial {
+		return errTrieCheckInterrupted
+	}
+
+	return nil
+}
+
+// addNodeHistogram walks the raw nodes of the provided main trie (and of its data tries, if walkDataTries is set), as an
+// extra phase, and adds the node histogram to the provided statistics. An interrupted walk marks the statistics as partial
+func addNodeHistogram(ctx context.Context, storer *countingStorer, mainRootHash []byte, walkDataTries bool, stats *trieToolsCommon.TrieStatistics) error {
+	phases := newPhasesTracker(storer)
+	phases.startPhase(nodeHistogramPhase)
+	histogram, err := collectNodeHistogram(ctx, storer, mainRootHash, walkDataTries)
+	phases.endPhase()
+
+	interrupted := ctx.Err() != nil && errors.Is(err, ctx.Err())
+	if err != nil && !interrupted {
+		return trieToolsCommon.NewIntegrityError(fmt.Errorf("%w while walking the trie nodes", err))
+	}
+
+	stats.NodeHistogram = histogram
+	stats.Phases = append(stats.Phases, phases.statistics()...)
+	logNodeHistogram("main", histogram.MainTrie)
+	if walkDataTries {
+		logNodeHistogram(fmt.Sprintf("data tries (%d)", histogram.NumDataTries), histogram.DataTries)
+	}
+	if interrupted {
+		stats.Partial = true
+		log.Warn("the trie nodes walk was interrupted, the node histogram is PARTIAL")
+	}
+
+	return nil
+}
+
+// cancelOnSignal calls the provided cancel function on the first SIGINT or SIGTERM. The returned function stops the
+// signal handling. A second signal is no longer caught, so it kills the process as usual
+func cancelOnSignal(cancel func()) func() {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+
+	done := make(chan struct{})
+	go func() {
+		select {
+		case sig := <-signals:
+			signal.Stop(signals)
+			log.Warn("received signal, stopping the trie iteration and reporting the partial statistics", "signal", sig)
+			cancel()
+		case <-done:
 		}
+	}()
 
-		address := addressConverter.Encode(kv.Key())
-		dataTriesRootHashes[address] = userAccount.RootHash
+	return func() {
+		signal.Stop(signals)
+		close(done)
 	}
+}
+
+func createAddressFilter(flags config.ContextFlagsTrieChecker, addressConverter core.PubkeyConverter) (*addressFilter, error) {
+	var included map[string]struct{}
+	var excluded map[string]struct{}
+	var err error
+	if len(flags.AddressesFile) > 0 {
+		included, err = trieToolsCommon.LoadAddressSet(flags.AddressesFile, addressConverter)
+		if err != nil {
+			return nil, fmt.Errorf("%w while reading the addresses file", err)
+		}
+	}
+	if len(flags.ExcludeFile) > 0 {
+		excluded, err = trieToolsCommon.LoadAddressSet(flags.ExcludeFile, addressConverter)
+		if err != nil {
+			return nil, fmt.Errorf("%w while reading the exclude file", err)
+		}
+	}
+
+	return newAddressFilter(included, excluded), nil
+}
+
+func createAccountsDumper(filePath string) (accountsDumper, error) {
+	if len(filePath) == 0 {
+		return &disabledAccountsDumper{}, nil
+	}
+
+	dumper, err := newJsonlAccountsDumper(filePath)
+	if err != nil {
+		return nil, trieToolsCommon.NewIOError(fmt.Errorf("%w while creating the accounts dump file", err))
+	}
+
+	return dumper, nil
+}
+
+func createDataRootsWriter(filePath string) (dataRootsWriter, error) {
+	if len(filePath) == 0 {
+		return &disabledDataRootsWriter{}, nil
+	}
+
+	writer, err := newFileDataRootsWriter(filePath)
+	if err != nil {
+		return nil, trieToolsCommon.NewIOError(fmt.Errorf("%w while creating the data roots output file", err))
+	}
+
+	return writer, nil
+}
+
+type argsIterateTries struct {
+	ctx              context.Context
+	tr               trieLeavesRetriever
+	addressConverter core.PubkeyConverter
+	mainRootHash     []byte
+	filter           *addressFilter
+	dumper           accountsDumper
+	dataRootsWriter  dataRootsWriter
+	readsCounter     nodeReadsCounter
+	// numWorkers is the number of goroutines unmarshalling the main trie leaves. Values lower than 1 mean a single one
+	numWorkers int
+	// tokenReport gathers the token entries of the iterated data tries. It is nil if the token report is disabled
+	tokenReport *tokenReportBuilder
+
+	skipDataTries           bool
+	dataTriesSampleFraction float64
+}
+
+func iterateTries(args argsIterateTries) (*trieToolsCommon.TrieStatistics, error) {
+	tr := args.tr
+	addressConverter := args.addressConverter
+	mainRootHash := args.mainRootHash
+	filter := args.filter
+	phases := newPhasesTracker(args.readsCounter)
+
+	phases.startPhase(mainTriePhase)
+	iteratorChannels := &common.TrieIteratorChannels{
+		LeavesChan: make(chan core.KeyValueHolder, common.TrieLeavesChannelDefaultCapacity),
+		ErrChan:    make(chan error, 1),
+	}
+	err := tr.GetAllLeavesOnChannel(iteratorChannels, args.ctx, mainRootHash, keyBuilder.NewKeyBuilder())
+	if err != nil {
+		return nil, trieToolsCommon.NewIntegrityError(fmt.Errorf("%w while starting the main trie iteration", err))
+	}
+
+	counters, dataTriesRootHashes := consumeMainTrieLeaves(iteratorChannels.LeavesChan, args.numWorkers, args)
+	numAccountsOnMainTrie := counters.numAccounts
+	numCodeNodes := counters.numCodeNodes
+	numUnknownNodes := counters.numUnknownNodes
+	numSkippedAccounts := counters.numSkippedAccounts
+	numDataTriesLeaves := 0
 
 	err = common.GetErrorFromChanNonBlocking(iteratorChannels.ErrChan)
 	if err != nil {
-		return err
+		return nil, trieToolsCommon.NewIntegrityError(fmt.Errorf("%w while iterating the main trie, after %d accounts", err, numAccountsOnMainTrie))
+	}
+	if counters.errDump != nil {
+		return nil, trieToolsCommon.NewIOError(fmt.Errorf("%w while dumping the accounts", counters.errDump))
+	}
+	if counters.errWriteDataRoots == nil {
+		// the data tries root hashes are complete before the (longer) data tries iteration starts
+		counters.errWriteDataRoots = args.dataRootsWriter.flush()
+	}
+	if counters.errWriteDataRoots != nil {
+		return nil, trieToolsCommon.NewIOError(fmt.Errorf("%w while writing the data tries root hashes", counters.errWriteDataRoots))
+	}
+	phases.endPhase()
+
+	// on cancel, the trie iteration stops and closes the leaves channel, so the loop above ends after draining it
+	if args.ctx.Err() != nil {
+		log.Warn("the main trie iteration was interrupted, the statistics are PARTIAL",
+			"num accounts", numAccountsOnMainTrie,
+			"num code nodes", numCodeNodes,
+			"num unknown nodes", numUnknownNodes,
+			"num skipped accounts", numSkippedAccounts,
+			"num data tries", len(dataTriesRootHashes))
+
+		return &trieToolsCommon.TrieStatistics{
+			RootHash:               hex.EncodeToString(mainRootHash),
+			NumAccountsOnMainTrie:  numAccountsOnMainTrie,
+			NumCodeNodes:           numCodeNodes,
+			NumUnknownNodes:        numUnknownNodes,
+			NumDataTries:           len(dataTriesRootHashes),
+			NumDataTriesLeavesMode: trieToolsCommon.LeavesCountSkipped,
+			Partial:                true,
+			Phases:                 phases.statistics(),
+		}, nil
 	}
 
 	log.Info("parsed main trie",
 		"num accounts", numAccountsOnMainTrie,
 		"num code nodes", numCodeNodes,
+		"num unknown nodes", numUnknownNodes,
+		"num skipped accounts", numSkippedAccounts,
 		"num data tries", len(dataTriesRootHashes))
+	logRequestedAddresses(filter, addressConverter)
+	if numUnknownNodes > 0 {
+		log.Warn("found main trie leaves that are neither accounts, nor code nodes",
+			"num unknown nodes", numUnknownNodes,
+			"sample keys", strings.Join(counters.unknownNodesSamples, ", "))
+	}
 
+	stats := &trieToolsCommon.TrieStatistics{
+		RootHash:               hex.EncodeToString(mainRootHash),
+		NumAccountsOnMainTrie:  numAccountsOnMainTrie,
+		NumCodeNodes:           numCodeNodes,
+		NumUnknownNodes:        numUnknownNodes,
+		NumDataTries:           len(dataTriesRootHashes),
+		NumDataTriesLeavesMode: trieToolsCommon.LeavesCountExact,
+		Phases:                 phases.statistics(),
+	}
 	if len(dataTriesRootHashes) == 0 {
-		return nil
+		return stats, nil
 	}
+	if args.skipDataTries {
+		stats.NumDataTriesLeavesMode = trieToolsCommon.LeavesCountSkipped
+		log.Info("skipped the data tries iteration, the num of data tries leaves was not computed")
+		return stats, nil
+	}
+
+	phases.startPhase(dataTriesPhase)
+	selectedAddresses := selectDataTries(dataTriesRootHashes, args.dataTriesSampleFraction)
+	numIteratedDataTries := 0
+	for _, address := range selectedAddresses {
+		if args.ctx.Err() != nil {
+			break
+		}
 
-	for address, dataRootHash := range dataTriesRootHashes {
+		dataRootHash := dataTriesRootHashes[address]
 		log.Debug("iterating data trie", "address", address, "data trie root hash", dataRootHash)
 
 		dataTrieIteratorChannels := &common.TrieIteratorChannels{
 			LeavesChan: make(chan core.KeyValueHolder, common.TrieLeavesChannelDefaultCapacity),
 			ErrChan:    make(chan error, 1),
 		}
-		errGetAllLeaves := tr.GetAllLeavesOnChannel(dataTrieIteratorChannels, context.Background(), dataRootHash, keyBuilder.NewDisabledKeyBuilder())
+		// the leaves keys are only built when needed, for recognizing the token entries
+		var dataTrieKeyBuilder common.KeyBuilder = keyBuilder.NewDisabledKeyBuilder()
+		if args.tokenReport != nil {
+			dataTrieKeyBuilder = keyBuilder.NewKeyBuilder()
+		}
+		errGetAllLeaves := tr.GetAllLeavesOnChannel(dataTrieIteratorChannels, args.ctx, dataRootHash, dataTrieKeyBuilder)
 		if errGetAllLeaves != nil {
-			return errGetAllLeaves
+			return nil, trieToolsCommon.NewIntegrityError(fmt.Errorf("%w while starting the data trie iteration for address %s", errGetAllLeaves, address))
 		}
 
-		for range dataTrieIteratorChannels.LeavesChan {
+		numTokens := 0
+		for kv := range dataTrieIteratorChannels.LeavesChan {
 			numDataTriesLeaves++
+			if args.tokenReport != nil && isESDTKey(kv.Key()) {
+				numTokens++
+			}
 		}
 
 		err = common.GetErrorFromChanNonBlocking(dataTrieIteratorChannels.ErrChan)
 		if err != nil {
-			return err
+			return nil, trieToolsCommon.NewIntegrityError(fmt.Errorf("%w while iterating the data trie for address %s", err, address))
+		}
+		if args.ctx.Err() != nil {
+			break
+		}
+
+		numIteratedDataTries++
+		if args.tokenReport != nil {
+			args.tokenReport.addAccount(address, numTokens)
 		}
 	}
 
-	log.Info("parsed all tries",
-		"num accounts", numAccountsOnMainTrie,
-		"num code nodes", numCodeNodes,
-		"num data tries", len(dataTriesRootHashes),
-		"num data tries leaves", numDataTriesLeaves)
+	phases.endPhase()
+	stats.Phases = phases.statistics()
+	if args.tokenReport != nil {
+		stats.TokenReport = args.tokenReport.report()
+		logTokenReport(stats.TokenReport)
+	}
 
-	return nil
+	if numIteratedDataTries < len(selectedAddresses) {
+		stats.NumDataTriesLeaves = numDataTriesLeaves
+		stats.NumDataTriesLeavesMode = trieToolsCommon.LeavesCountPartial
+		stats.Partial = true
+		log.Warn("the data tries iteration was interrupted, the statistics are PARTIAL",
+			"num accounts", numAccountsOnMainTrie,
+			"num code nodes", numCodeNodes,
+			"num unknown nodes", numUnknownNodes,
+			"num data tries", len(dataTriesRootHashes),
+			"num fully iterated data tries", numIteratedDataTries,
+			"num data tries leaves (so far)", numDataTriesLeaves)
+
+		return stats, nil
+	}
+
+	if len(selectedAddresses) < len(dataTriesRootHashes) {
+		numDataTriesLeaves = numDataTriesLeaves * len(dataTriesRootHashes) / len(selectedAddresses)
+		stats.NumDataTriesLeavesMode = trieToolsCommon.LeavesCountEstimated
+		log.Info("parsed main trie and sampled data tries",
+			"num accounts", numAccountsOnMainTrie,
+			"num code nodes", numCodeNodes,
+			"num unknown nodes", numUnknownNodes,
+			"num data tries", len(dataTriesRootHashes),
+			"num sampled data tries", len(selectedAddresses),
+			"num data tries leaves (estimation)", numDataTriesLeaves)
+	} else {
+		log.Info("parsed all tries",
+			"num accounts", numAccountsOnMainTrie,
+			"num code nodes", numCodeNodes,
+			"num unknown nodes", numUnknownNodes,
+			"num data tries", len(dataTriesRootHashes),
+			"num data tries leaves", numDataTriesLeaves)
+	}
+
+	stats.NumDataTriesLeaves = numDataTriesLeaves
+
+	return stats, nil
+}
+
+// selectDataTries returns the addresses of the data tries to be iterated. If the provided sample fraction is in the
+// (0, 1) interval, only that fraction of the data tries is selected, evenly spaced in the sorted list of addresses
+func selectDataTries(dataTriesRootHashes map[string][]byte, sampleFraction float64) []string {
+	addresses := make([]string, 0, len(dataTriesRootHashes))
+	for address := range dataTriesRootHashes {
+		addresses = append(addresses, address)
+	}
+	if sampleFraction <= 0 || sampleFraction >= 1 {
+		return addresses
+	}
+
+	sort.Strings(addresses)
+	numAddresses := len(addresses)
+	numSampled := int(math.Ceil(float64(numAddresses) * sampleFraction))
+	sampled := make([]string, 0, numSampled)
+	for i := 0; i < numSampled; i++ {
+		sampled = append(sampled, addresses[i*numAddresses/numSampled])
+	}
+
+	return sampled
+}
+
+func logRequestedAddresses(filter *addressFilter, addressConverter core.PubkeyConverter) {
+	if !filter.hasIncludedAddresses() {
+		return
+	}
+
+	missingAddresses := filter.missingAddresses()
+	for _, address := range missingAddresses {
+		log.Debug("requested address not found in the main trie", "address", addressConverter.Encode(address))
+	}
+
+	log.Info("requested addresses",
+		"num requested", len(filter.included),
+		"num found", len(filter.included)-len(missingAddresses))
 }
 
 func createStorer(flags trieToolsCommon.ContextFlagsConfig, log logger.Logger) (storage.Storer, error) {
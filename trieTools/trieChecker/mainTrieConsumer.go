@@ -0,0 +1,140 @@
+package main
+
+import (
+	"encoding/hex"
+	"sync"
+
+	"github.com/multiversx/mx-chain-core-go/core"
+	"github.com/multiversx/mx-chain-go/state"
+	"github.com/multiversx/mx-chain-tools-go/trieTools/trieToolsCommon"
+)
+
+// mainTrieCounters holds the figures gathered while consuming the main trie leaves. Each worker fills its own
+// counters, merged once all the workers are done
+type mainTrieCounters struct {
+	numAccounts         int
+	numCodeNodes        int
+	numUnknownNodes     int
+	numSkippedAccounts  int
+	unknownNodesSamples []string
+	errDump             error
+	errWriteDataRoots   error
+}
+
+func (counters *mainTrieCounters) merge(other *mainTrieCounters) {
+	counters.numAccounts += other.numAccounts
+	counters.numCodeNodes += other.numCodeNodes
+	counters.numUnknownNodes += other.numUnknownNodes
+	counters.numSkippedAccounts += other.numSkippedAccounts
+	for _, sample := range other.unknownNodesSamples {
+		if len(counters.unknownNodesSamples) >= maxUnknownNodesSamples {
+			break
+		}
+		counters.unknownNodesSamples = append(counters.unknownNodesSamples, sample)
+	}
+	if counters.errDump == nil {
+		counters.errDump = other.errDump
+	}
+	if counters.errWriteDataRoots == nil {
+		counters.errWriteDataRoots = other.errWriteDataRoots
+	}
+}
+
+// dataTriesRootHashesHolder is a concurrent safe map of the data tries root hashes, by bech32 address
+type dataTriesRootHashesHolder struct {
+	mut        sync.Mutex
+	rootHashes map[string][]byte
+}
+
+func newDataTriesRootHashesHolder() *dataTriesRootHashesHolder {
+	return &dataTriesRootHashesHolder{
+		rootHashes: make(map[string][]byte),
+	}
+}
+
+func (holder *dataTriesRootHashesHolder) add(address string, rootHash []byte) {
+	holder.mut.Lock()
+	holder.rootHashes[address] = rootHash
+	holder.mut.Unlock()
+}
+
+// getAll returns the gathered map. It should only be called once all the workers are done
+func (holder *dataTriesRootHashesHolder) getAll() map[string][]byte {
+	holder.mut.Lock()
+	defer holder.mut.Unlock()
+
+	return holder.rootHashes
+}
+
+// consumeMainTrieLeaves drains the provided leaves channel using numWorkers goroutines, which unmarshal the leaves
+// concurrently. It returns once the channel is closed and all the workers are done
+func consumeMainTrieLeaves(leavesChan chan core.KeyValueHolder, numWorkers int, args argsIterateTries) (*mainTrieCounters, map[string][]byte) {
+	if numWorkers < 1 {
+		numWorkers = 1
+	}
+
+	rootHashesHolder := newDataTriesRootHashesHolder()
+	workersCounters := make([]*mainTrieCounters, numWorkers)
+	wg := sync.WaitGroup{}
+	wg.Add(numWorkers)
+	for i := 0; i < numWorkers; i++ {
+		workersCounters[i] = &mainTrieCounters{
+			unknownNodesSamples: make([]string, 0),
+		}
+
+		go func(counters *mainTrieCounters) {
+			defer wg.Done()
+
+			for kv := range leavesChan {
+				processMainTrieLeaf(kv, counters, rootHashesHolder, args)
+			}
+		}(workersCounters[i])
+	}
+	wg.Wait()
+
+	counters := &mainTrieCounters{
+		unknownNodesSamples: make([]string, 0),
+	}
+	for _, workerCounters := range workersCounters {
+		counters.merge(workerCounters)
+	}
+
+	return counters, rootHashesHolder.getAll()
+}
+
+func processMainTrieLeaf(kv core.KeyValueHolder, counters *mainTrieCounters, rootHashesHolder *dataTriesRootHashesHolder, args argsIterateTries) {
+	counters.numAccounts++
+
+	userAccount := &state.UserAccountData{}
+	errUnmarshal := trieToolsCommon.Marshaller.Unmarshal(userAccount, kv.Value())
+	if errUnmarshal != nil {
+		if isCodeNode(kv.Key(), kv.Value()) {
+			counters.numCodeNodes++
+			return
+		}
+
+		counters.numUnknownNodes++
+		if len(counters.unknownNodesSamples) < maxUnknownNodesSamples {
+			counters.unknownNodesSamples = append(counters.unknownNodesSamples, hex.EncodeToString(kv.Key()))
+		}
+		return
+	}
+	if !args.filter.shouldProcess(kv.Key()) {
+		counters.numSkippedAccounts++
+		return
+	}
+
+	address := args.addressConverter.Encode(kv.Key())
+	if counters.errDump == nil {
+		// the leaves channel is still drained after a failed write, so the trie iteration is not blocked
+		counters.errDump = args.dumper.dumpAccount(address, userAccount)
+	}
+	if len(userAccount.RootHash) == 0 {
+		return
+	}
+
+	rootHashesHolder.add(address, userAccount.RootHash)
+	if counters.errWriteDataRoots == nil {
+		counters.errWriteDataRoots = args.dataRootsWriter.writeDataRoot(address, userAccount.RootHash)
+	}
+}
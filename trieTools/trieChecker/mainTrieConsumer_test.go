@@ -0,0 +1,130 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/multiversx/mx-chain-core-go/core"
+	"github.com/multiversx/mx-chain-core-go/core/keyValStorage"
+	"github.com/stretchr/testify/require"
+)
+
+func createTestMainTrieLeaves(tb testing.TB, numAccounts int) []core.KeyValueHolder {
+	leaves := make([]core.KeyValueHolder, 0, numAccounts)
+	for i := 0; i < numAccounts; i++ {
+		address := []byte(fmt.Sprintf("%032d", i))
+		var dataRootHash []byte
+		if i%3 == 0 {
+			dataRootHash = testDataRootHash
+		}
+
+		leaves = append(leaves, createTestAccountLeaf(tb, address, dataRootHash))
+	}
+
+	return leaves
+}
+
+func createTestLeavesChannel(leaves []core.KeyValueHolder) chan core.KeyValueHolder {
+	leavesChan := make(chan core.KeyValueHolder, len(leaves))
+	for _, leaf := range leaves {
+		leavesChan <- leaf
+	}
+	close(leavesChan)
+
+	return leavesChan
+}
+
+func TestConsumeMainTrieLeaves(t *testing.T) {
+	t.Parallel()
+
+	leaves := createTestMainTrieLeaves(t, 300)
+	leaves = append(leaves,
+		createTestCodeLeaf(t, []byte("code 1")),
+		createTestCodeLeaf(t, []byte("code 2")),
+	)
+	for i := 0; i < 2*maxUnknownNodesSamples; i++ {
+		leaves = append(leaves, keyValStorage.NewKeyValStorage([]byte(fmt.Sprintf("unknown %d", i)), []byte{0xff, 0xff, 0xff}))
+	}
+	excluded := map[string]struct{}{
+		fmt.Sprintf("%032d", 0): {},
+		fmt.Sprintf("%032d", 1): {},
+	}
+
+	for _, numWorkers := range []int{0, 1, 2, 8} {
+		numWorkers := numWorkers
+		t.Run(fmt.Sprintf("%d workers", numWorkers), func(t *testing.T) {
+			t.Parallel()
+
+			args := createTestArgsIterateTriesWithFilter(t, nil, newAddressFilter(nil, excluded))
+			counters, dataTriesRootHashes := consumeMainTrieLeaves(createTestLeavesChannel(leaves), numWorkers, args)
+			require.Equal(t, 322, counters.numAccounts)
+			require.Equal(t, 2, counters.numCodeNodes)
+			require.Equal(t, 2*maxUnknownNodesSamples, counters.numUnknownNodes)
+			require.Len(t, counters.unknownNodesSamples, maxUnknownNodesSamples)
+			require.Equal(t, 2, counters.numSkippedAccounts)
+			require.Len(t, dataTriesRootHashes, 99)
+			for _, rootHash := range dataTriesRootHashes {
+				require.True(t, bytes.Equal(testDataRootHash, rootHash))
+			}
+		})
+	}
+}
+
+func TestMainTrieCounters_Merge(t *testing.T) {
+	t.Parallel()
+
+	firstErr := fmt.Errorf("first error")
+	counters := &mainTrieCounters{
+		numAccounts:         1,
+		unknownNodesSamples: []string{"a"},
+	}
+	counters.merge(&mainTrieCounters{
+		numAccounts:         2,
+		numCodeNodes:        3,
+		numUnknownNodes:     4,
+		numSkippedAccounts:  5,
+		unknownNodesSamples: []string{"b", "c", "d", "e", "f", "g", "h", "i", "j", "k", "l"},
+		errDump:             firstErr,
+	})
+	counters.merge(&mainTrieCounters{
+		errDump: fmt.Errorf("second error"),
+	})
+
+	require.Equal(t, 3, counters.numAccounts)
+	require.Equal(t, 3, counters.numCodeNodes)
+	require.Equal(t, 4, counters.numUnknownNodes)
+	require.Equal(t, 5, counters.numSkippedAccounts)
+	require.Equal(t, []string{"a", "b", "c", "d", "e", "f", "g", "h", "i", "j"}, counters.unknownNodesSamples)
+	require.Equal(t, firstErr, counters.errDump)
+}
+
+func benchmarkConsumeMainTrieLeaves(b *testing.B, numWorkers int, numAccounts int) {
+	leaves := createTestMainTrieLeaves(b, numAccounts)
+	args := createTestArgsIterateTries(b, nil)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		leavesChan := createTestLeavesChannel(leaves)
+		b.StartTimer()
+
+		_, _ = consumeMainTrieLeaves(leavesChan, numWorkers, args)
+	}
+}
+
+func BenchmarkConsumeMainTrieLeaves_SmallInputSequential(b *testing.B) {
+	benchmarkConsumeMainTrieLeaves(b, 1, 100)
+}
+
+func BenchmarkConsumeMainTrieLeaves_SmallInputParallel(b *testing.B) {
+	benchmarkConsumeMainTrieLeaves(b, 4, 100)
+}
+
+func BenchmarkConsumeMainTrieLeaves_LargeInputSequential(b *testing.B) {
+	benchmarkConsumeMainTrieLeaves(b, 1, 100000)
+}
+
+func BenchmarkConsumeMainTrieLeaves_LargeInputParallel(b *testing.B) {
+	benchmarkConsumeMainTrieLeaves(b, 4, 100000)
+}
@@ -0,0 +1,519 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/multiversx/mx-chain-core-go/core"
+	"github.com/multiversx/mx-chain-core-go/core/keyValStorage"
+	"github.com/multiversx/mx-chain-core-go/core/pubkeyConverter"
+	"github.com/multiversx/mx-chain-go/common"
+	"github.com/multiversx/mx-chain-go/state"
+	"github.com/multiversx/mx-chain-tools-go/trieTools/trieChecker/mocks"
+	"github.com/multiversx/mx-chain-tools-go/trieTools/trieToolsCommon"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	testMainRootHash = bytes.Repeat([]byte("m"), rootHashLength)
+	testDataRootHash = bytes.Repeat([]byte("d"), rootHashLength)
+)
+
+func createTestAddressConverter(t testing.TB) core.PubkeyConverter {
+	converter, err := pubkeyConverter.NewBech32PubkeyConverter(addressLength, log)
+	require.Nil(t, err)
+
+	return converter
+}
+
+func createTestAccountLeaf(t testing.TB, address []byte, dataRootHash []byte) core.KeyValueHolder {
+	account := &state.UserAccountData{
+		Address:  address,
+		Balance:  big.NewInt(1),
+		RootHash: dataRootHash,
+	}
+	accountBytes, err := trieToolsCommon.Marshaller.Marshal(account)
+	require.Nil(t, err)
+
+	return keyValStorage.NewKeyValStorage(address, accountBytes)
+}
+
+func createTestArgsIterateTries(t testing.TB, tr trieLeavesRetriever) argsIterateTries {
+	return createTestArgsIterateTriesWithFilter(t, tr, newAddressFilter(nil, nil))
+}
+
+func createTestArgsIterateTriesWithFilter(t testing.TB, tr trieLeavesRetriever, filter *addressFilter) argsIterateTries {
+	return argsIterateTries{
+		ctx:              context.Background(),
+		tr:               tr,
+		addressConverter: createTestAddressConverter(t),
+		mainRootHash:     testMainRootHash,
+		filter:           filter,
+		dumper:           &disabledAccountsDumper{},
+		dataRootsWriter:  &disabledDataRootsWriter{},
+	}
+}
+
+func createTestCodeLeaf(t *testing.T, code []byte) core.KeyValueHolder {
+	codeEntryBytes, err := trieToolsCommon.Marshaller.Marshal(&state.CodeEntry{Code: code, NumReferences: 1})
+	require.Nil(t, err)
+
+	return keyValStorage.NewKeyValStorage(trieToolsCommon.Hasher.Compute(string(code)), codeEntryBytes)
+}
+
+func sendLeaves(leavesChannels *common.TrieIteratorChannels, errToSignal error, leaves ...core.KeyValueHolder) {
+	go func() {
+		for _, leaf := range leaves {
+			leavesChannels.LeavesChan <- leaf
+		}
+		if errToSignal != nil {
+			leavesChannels.ErrChan <- errToSignal
+		}
+
+		close(leavesChannels.LeavesChan)
+		close(leavesChannels.ErrChan)
+	}()
+}
+
+func TestIterateTries(t *testing.T) {
+	t.Parallel()
+
+	t.Run("main trie iteration can not start, should error", func(t *testing.T) {
+		t.Parallel()
+
+		expectedErr := errors.New("expected error")
+		tr := &mocks.TrieLeavesRetrieverStub{
+			GetAllLeavesOnChannelCalled: func(leavesChannels *common.TrieIteratorChannels, ctx context.Context, rootHash []byte, keyBuilder common.KeyBuilder) error {
+				return expectedErr
+			},
+		}
+
+		stats, err := iterateTries(createTestArgsIterateTries(t, tr))
+		require.Nil(t, stats)
+		require.ErrorIs(t, err, expectedErr)
+		require.True(t, strings.Contains(err.Error(), "main trie"))
+	})
+	t.Run("main trie iteration fails midway, should error", func(t *testing.T) {
+		t.Parallel()
+
+		expectedErr := errors.New("expected error")
+		tr := &mocks.TrieLeavesRetrieverStub{
+			GetAllLeavesOnChannelCalled: func(leavesChannels *common.TrieIteratorChannels, ctx context.Context, rootHash []byte, keyBuilder common.KeyBuilder) error {
+				sendLeaves(leavesChannels, expectedErr, createTestAccountLeaf(t, bytes.Repeat([]byte("a"), addressLength), nil))
+				return nil
+			},
+		}
+
+		stats, err := iterateTries(createTestArgsIterateTries(t, tr))
+		require.Nil(t, stats)
+		require.ErrorIs(t, err, expectedErr)
+		require.True(t, strings.Contains(err.Error(), "while iterating the main trie, after 1 accounts"))
+	})
+	t.Run("data trie iteration fails midway, should error", func(t *testing.T) {
+		t.Parallel()
+
+		expectedErr := errors.New("expected error")
+		tr := &mocks.TrieLeavesRetrieverStub{
+			GetAllLeavesOnChannelCalled: func(leavesChannels *common.TrieIteratorChannels, ctx context.Context, rootHash []byte, keyBuilder common.KeyBuilder) error {
+				if bytes.Equal(rootHash, testMainRootHash) {
+					sendLeaves(leavesChannels, nil, createTestAccountLeaf(t, bytes.Repeat([]byte("a"), addressLength), testDataRootHash))
+					return nil
+				}
+
+				sendLeaves(leavesChannels, expectedErr, keyValStorage.NewKeyValStorage([]byte("key"), []byte("value")))
+				return nil
+			},
+		}
+
+		stats, err := iterateTries(createTestArgsIterateTries(t, tr))
+		require.Nil(t, stats)
+		require.ErrorIs(t, err, expectedErr)
+		require.True(t, strings.Contains(err.Error(), "while iterating the data trie for address"))
+	})
+	t.Run("should work", func(t *testing.T) {
+		t.Parallel()
+
+		numCalls := 0
+		tr := &mocks.TrieLeavesRetrieverStub{
+			GetAllLeavesOnChannelCalled: func(leavesChannels *common.TrieIteratorChannels, ctx context.Context, rootHash []byte, keyBuilder common.KeyBuilder) error {
+				numCalls++
+				if bytes.Equal(rootHash, testMainRootHash) {
+					sendLeaves(leavesChannels, nil,
+						createTestAccountLeaf(t, bytes.Repeat([]byte("a"), addressLength), testDataRootHash),
+						createTestAccountLeaf(t, bytes.Repeat([]byte("b"), addressLength), nil),
+					)
+					return nil
+				}
+
+				sendLeaves(leavesChannels, nil, keyValStorage.NewKeyValStorage([]byte("key"), []byte("value")))
+				return nil
+			},
+		}
+
+		stats, err := iterateTries(createTestArgsIterateTries(t, tr))
+		require.Nil(t, err)
+		require.Equal(t, 2, numCalls)
+
+		expectedStats := &trieToolsCommon.TrieStatistics{
+			RootHash:               hex.EncodeToString(testMainRootHash),
+			NumAccountsOnMainTrie:  2,
+			NumCodeNodes:           0,
+			NumDataTries:           1,
+			NumDataTriesLeaves:     1,
+			NumDataTriesLeavesMode: trieToolsCommon.LeavesCountExact,
+		}
+		require.Len(t, stats.Phases, 2)
+		require.Equal(t, mainTriePhase, stats.Phases[0].Name)
+		require.Equal(t, dataTriesPhase, stats.Phases[1].Name)
+		stats.Phases = nil
+		require.Equal(t, expectedStats, stats)
+	})
+}
+
+func TestIterateTries_SkipAndEstimateDataTries(t *testing.T) {
+	t.Parallel()
+
+	createTrie := func(numCalls *int32) trieLeavesRetriever {
+		return &mocks.TrieLeavesRetrieverStub{
+			GetAllLeavesOnChannelCalled: func(leavesChannels *common.TrieIteratorChannels, ctx context.Context, rootHash []byte, keyBuilder common.KeyBuilder) error {
+				atomic.AddInt32(numCalls, 1)
+				if bytes.Equal(rootHash, testMainRootHash) {
+					sendLeaves(leavesChannels, nil,
+						createTestAccountLeaf(t, bytes.Repeat([]byte("a"), addressLength), testDataRootHash),
+						createTestAccountLeaf(t, bytes.Repeat([]byte("b"), addressLength), testDataRootHash),
+						createTestAccountLeaf(t, bytes.Repeat([]byte("c"), addressLength), testDataRootHash),
+						createTestAccountLeaf(t, bytes.Repeat([]byte("d"), addressLength), testDataRootHash),
+					)
+					return nil
+				}
+
+				sendLeaves(leavesChannels, nil,
+					keyValStorage.NewKeyValStorage([]byte("key1"), []byte("value")),
+					keyValStorage.NewKeyValStorage([]byte("key2"), []byte("value")),
+				)
+				return nil
+			},
+		}
+	}
+
+	t.Run("skip data tries", func(t *testing.T) {
+		t.Parallel()
+
+		numCalls := int32(0)
+		args := createTestArgsIterateTries(t, createTrie(&numCalls))
+		args.skipDataTries = true
+		stats, err := iterateTries(args)
+		require.Nil(t, err)
+		require.Equal(t, int32(1), numCalls)
+		require.Equal(t, 4, stats.NumDataTries)
+		require.Equal(t, 0, stats.NumDataTriesLeaves)
+		require.Equal(t, trieToolsCommon.LeavesCountSkipped, stats.NumDataTriesLeavesMode)
+	})
+	t.Run("estimate data tries leaves", func(t *testing.T) {
+		t.Parallel()
+
+		numCalls := int32(0)
+		args := createTestArgsIterateTries(t, createTrie(&numCalls))
+		args.dataTriesSampleFraction = 0.5
+		stats, err := iterateTries(args)
+		require.Nil(t, err)
+		require.Equal(t, int32(3), numCalls)
+		require.Equal(t, 4, stats.NumDataTries)
+		require.Equal(t, 8, stats.NumDataTriesLeaves)
+		require.Equal(t, trieToolsCommon.LeavesCountEstimated, stats.NumDataTriesLeavesMode)
+	})
+}
+
+func TestIterateTries_Interrupted(t *testing.T) {
+	t.Parallel()
+
+	t.Run("interrupted while iterating the main trie, should return partial stats", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		numCalls := 0
+		tr := &mocks.TrieLeavesRetrieverStub{
+			GetAllLeavesOnChannelCalled: func(leavesChannels *common.TrieIteratorChannels, ctx context.Context, rootHash []byte, keyBuilder common.KeyBuilder) error {
+				numCalls++
+				sendLeaves(leavesChannels, nil, createTestAccountLeaf(t, bytes.Repeat([]byte("a"), addressLength), testDataRootHash))
+				return nil
+			},
+		}
+
+		args := createTestArgsIterateTries(t, tr)
+		args.ctx = ctx
+		stats, err := iterateTries(args)
+		require.Nil(t, err)
+		require.Equal(t, 1, numCalls)
+		require.True(t, stats.Partial)
+		require.Equal(t, 1, stats.NumAccountsOnMainTrie)
+		require.Equal(t, 1, stats.NumDataTries)
+		require.Equal(t, trieToolsCommon.LeavesCountSkipped, stats.NumDataTriesLeavesMode)
+	})
+	t.Run("interrupted while iterating the data tries, should return partial stats", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		numCalls := 0
+		tr := &mocks.TrieLeavesRetrieverStub{
+			GetAllLeavesOnChannelCalled: func(leavesChannels *common.TrieIteratorChannels, ctx context.Context, rootHash []byte, keyBuilder common.KeyBuilder) error {
+				numCalls++
+				if bytes.Equal(rootHash, testMainRootHash) {
+					sendLeaves(leavesChannels, nil,
+						createTestAccountLeaf(t, bytes.Repeat([]byte("a"), addressLength), testDataRootHash),
+						createTestAccountLeaf(t, bytes.Repeat([]byte("b"), addressLength), testDataRootHash),
+					)
+					return nil
+				}
+
+				cancel()
+				sendLeaves(leavesChannels, nil, keyValStorage.NewKeyValStorage([]byte("key"), []byte("value")))
+				return nil
+			},
+		}
+
+		args := createTestArgsIterateTries(t, tr)
+		args.ctx = ctx
+		stats, err := iterateTries(args)
+		require.Nil(t, err)
+		require.Equal(t, 2, numCalls)
+		require.True(t, stats.Partial)
+		require.Equal(t, 2, stats.NumDataTries)
+		require.Equal(t, 1, stats.NumDataTriesLeaves)
+		require.Equal(t, trieToolsCommon.LeavesCountPartial, stats.NumDataTriesLeavesMode)
+		require.Len(t, stats.Phases, 2)
+	})
+}
+
+func TestSelectDataTries(t *testing.T) {
+	t.Parallel()
+
+	dataTries := map[string][]byte{"a": nil, "b": nil, "c": nil, "d": nil, "e": nil}
+	require.Len(t, selectDataTries(dataTries, 0), 5)
+	require.Len(t, selectDataTries(dataTries, 1), 5)
+	require.Equal(t, []string{"a", "c"}, selectDataTries(dataTries, 0.4))
+	require.Equal(t, []string{"a", "b", "c", "d"}, selectDataTries(dataTries, 0.7))
+	require.Equal(t, []string{"a"}, selectDataTries(dataTries, 0.01))
+}
+
+func TestIterateTries_WithAddressFilter(t *testing.T) {
+	t.Parallel()
+
+	addressA := bytes.Repeat([]byte("a"), addressLength)
+	addressB := bytes.Repeat([]byte("b"), addressLength)
+	addressC := bytes.Repeat([]byte("c"), addressLength)
+	createTrie := func(numCalls *int) *mocks.TrieLeavesRetrieverStub {
+		return &mocks.TrieLeavesRetrieverStub{
+			GetAllLeavesOnChannelCalled: func(leavesChannels *common.TrieIteratorChannels, ctx context.Context, rootHash []byte, keyBuilder common.KeyBuilder) error {
+				*numCalls++
+				if bytes.Equal(rootHash, testMainRootHash) {
+					sendLeaves(leavesChannels, nil,
+						createTestAccountLeaf(t, addressA, testDataRootHash),
+						createTestAccountLeaf(t, addressB, testDataRootHash),
+					)
+					return nil
+				}
+
+				sendLeaves(leavesChannels, nil, keyValStorage.NewKeyValStorage([]byte("key"), []byte("value")))
+				return nil
+			},
+		}
+	}
+
+	t.Run("included addresses, should only check those", func(t *testing.T) {
+		t.Parallel()
+
+		numCalls := 0
+		filter := newAddressFilter(map[string]struct{}{string(addressA): {}, string(addressC): {}}, nil)
+		stats, err := iterateTries(createTestArgsIterateTriesWithFilter(t, createTrie(&numCalls), filter))
+		require.Nil(t, err)
+		require.Equal(t, 2, numCalls)
+		require.Equal(t, 1, stats.NumDataTries)
+		require.Equal(t, [][]byte{addressC}, filter.missingAddresses())
+	})
+	t.Run("excluded addresses, should skip those", func(t *testing.T) {
+		t.Parallel()
+
+		numCalls := 0
+		filter := newAddressFilter(nil, map[string]struct{}{string(addressB): {}})
+		stats, err := iterateTries(createTestArgsIterateTriesWithFilter(t, createTrie(&numCalls), filter))
+		require.Nil(t, err)
+		require.Equal(t, 2, numCalls)
+		require.Equal(t, 1, stats.NumDataTries)
+		require.Equal(t, 2, stats.NumAccountsOnMainTrie)
+	})
+}
+
+func TestIterateTries_DumpAccounts(t *testing.T) {
+	t.Parallel()
+
+	addressA := bytes.Repeat([]byte("a"), addressLength)
+	tr := &mocks.TrieLeavesRetrieverStub{
+		GetAllLeavesOnChannelCalled: func(leavesChannels *common.TrieIteratorChannels, ctx context.Context, rootHash []byte, keyBuilder common.KeyBuilder) error {
+			sendLeaves(leavesChannels, nil,
+				createTestAccountLeaf(t, addressA, nil),
+				createTestCodeLeaf(t, []byte("code")),
+			)
+			return nil
+		},
+	}
+
+	filePath := filepath.Join(t.TempDir(), "accounts.jsonl")
+	dumper, err := newJsonlAccountsDumper(filePath)
+	require.Nil(t, err)
+
+	args := createTestArgsIterateTries(t, tr)
+	args.dumper = dumper
+	stats, err := iterateTries(args)
+	require.Nil(t, err)
+	require.Equal(t, 1, stats.NumCodeNodes)
+	require.Nil(t, dumper.close())
+
+	dumpBytes, err := os.ReadFile(filePath)
+	require.Nil(t, err)
+	expectedLine := fmt.Sprintf(`{"address":"%s","balance":"1","nonce":0,"rootHash":"","codeHash":"","developerReward":"0"}`,
+		createTestAddressConverter(t).Encode(addressA))
+	require.Equal(t, expectedLine+"\n", string(dumpBytes))
+}
+
+func TestIterateTries_DataRootsOut(t *testing.T) {
+	t.Parallel()
+
+	converter := createTestAddressConverter(t)
+	addressA := bytes.Repeat([]byte("a"), addressLength)
+	addressB := bytes.Repeat([]byte("b"), addressLength)
+	filePath := filepath.Join(t.TempDir(), "dataRoots.txt")
+	expectedContent := fmt.Sprintf("%s %s\n", converter.Encode(addressA), hex.EncodeToString(testDataRootHash))
+
+	contentOnDataTriesIteration := ""
+	tr := &mocks.TrieLeavesRetrieverStub{
+		GetAllLeavesOnChannelCalled: func(leavesChannels *common.TrieIteratorChannels, ctx context.Context, rootHash []byte, keyBuilder common.KeyBuilder) error {
+			if bytes.Equal(rootHash, testMainRootHash) {
+				sendLeaves(leavesChannels, nil,
+					createTestAccountLeaf(t, addressA, testDataRootHash),
+					createTestAccountLeaf(t, addressB, nil),
+				)
+				return nil
+			}
+
+			contentBytes, err := os.ReadFile(filePath)
+			require.Nil(t, err)
+			contentOnDataTriesIteration = string(contentBytes)
+
+			sendLeaves(leavesChannels, nil, keyValStorage.NewKeyValStorage([]byte("key"), []byte("value")))
+			return nil
+		},
+	}
+
+	writer, err := newFileDataRootsWriter(filePath)
+	require.Nil(t, err)
+
+	args := createTestArgsIterateTries(t, tr)
+	args.dataRootsWriter = writer
+	stats, err := iterateTries(args)
+	require.Nil(t, err)
+	require.Equal(t, 1, stats.NumDataTriesLeaves)
+	require.Equal(t, expectedContent, contentOnDataTriesIteration)
+	require.Nil(t, writer.close())
+
+	contentBytes, err := os.ReadFile(filePath)
+	require.Nil(t, err)
+	require.Equal(t, expectedContent, string(contentBytes))
+}
+
+func TestIterateTries_CodeAndUnknownNodes(t *testing.T) {
+	t.Parallel()
+
+	unknownKey := []byte("unknown key")
+	tr := &mocks.TrieLeavesRetrieverStub{
+		GetAllLeavesOnChannelCalled: func(leavesChannels *common.TrieIteratorChannels, ctx context.Context, rootHash []byte, keyBuilder common.KeyBuilder) error {
+			sendLeaves(leavesChannels, nil,
+				createTestAccountLeaf(t, bytes.Repeat([]byte("a"), addressLength), nil),
+				createTestCodeLeaf(t, []byte("code 1")),
+				createTestCodeLeaf(t, []byte("code 2")),
+				keyValStorage.NewKeyValStorage(unknownKey, []byte{0xff, 0xff, 0xff}),
+			)
+			return nil
+		},
+	}
+
+	stats, err := iterateTries(createTestArgsIterateTries(t, tr))
+	require.Nil(t, err)
+	require.Equal(t, 4, stats.NumAccountsOnMainTrie)
+	require.Equal(t, 2, stats.NumCodeNodes)
+	require.Equal(t, 1, stats.NumUnknownNodes)
+}
+
+func TestIterateTries_TokenReport(t *testing.T) {
+	t.Parallel()
+
+	firstAddress := bytes.Repeat([]byte("a"), addressLength)
+	secondAddress := bytes.Repeat([]byte("b"), addressLength)
+	secondDataRootHash := bytes.Repeat([]byte("e"), rootHashLength)
+	esdtKey := func(token string) core.KeyValueHolder {
+		return keyValStorage.NewKeyValStorage([]byte(core.ProtectedKeyPrefix+core.ESDTKeyIdentifier+token), []byte("value"))
+	}
+	createTrie := func() trieLeavesRetriever {
+		return &mocks.TrieLeavesRetrieverStub{
+			GetAllLeavesOnChannelCalled: func(leavesChannels *common.TrieIteratorChannels, ctx context.Context, rootHash []byte, keyBuilder common.KeyBuilder) error {
+				switch {
+				case bytes.Equal(rootHash, testMainRootHash):
+					sendLeaves(leavesChannels, nil,
+						createTestAccountLeaf(t, firstAddress, testDataRootHash),
+						createTestAccountLeaf(t, secondAddress, secondDataRootHash),
+					)
+				case bytes.Equal(rootHash, testDataRootHash):
+					sendLeaves(leavesChannels, nil,
+						esdtKey("TKN-abcdef"),
+						keyValStorage.NewKeyValStorage([]byte("key"), []byte("value")),
+					)
+				default:
+					sendLeaves(leavesChannels, nil,
+						esdtKey("TKN-abcdef"),
+						esdtKey("NFT-abcdef\x01"),
+						esdtKey("NFT-abcdef\x02"),
+					)
+				}
+				return nil
+			},
+		}
+	}
+
+	t.Run("disabled, should not report", func(t *testing.T) {
+		t.Parallel()
+
+		stats, err := iterateTries(createTestArgsIterateTries(t, createTrie()))
+		require.Nil(t, err)
+		require.Equal(t, 5, stats.NumDataTriesLeaves)
+		require.Nil(t, stats.TokenReport)
+	})
+	t.Run("enabled, should count the token entries without changing the leaves count", func(t *testing.T) {
+		t.Parallel()
+
+		converter := createTestAddressConverter(t)
+		args := createTestArgsIterateTries(t, createTrie())
+		args.tokenReport = newTokenReportBuilder(1)
+		stats, err := iterateTries(args)
+		require.Nil(t, err)
+		require.Equal(t, 5, stats.NumDataTriesLeaves)
+		require.Equal(t, trieToolsCommon.LeavesCountExact, stats.NumDataTriesLeavesMode)
+		require.Equal(t, 2, stats.TokenReport.NumAccountsWithTokens)
+		require.Equal(t, 4, stats.TokenReport.NumTokenEntries)
+		require.Equal(t, []trieToolsCommon.AccountTokens{{Address: converter.Encode(secondAddress), NumTokens: 3}}, stats.TokenReport.TopAccounts)
+		require.Equal(t, 1, stats.TokenReport.Distribution[0].NumAccounts)
+		require.Equal(t, 1, stats.TokenReport.Distribution[1].NumAccounts)
+	})
+}
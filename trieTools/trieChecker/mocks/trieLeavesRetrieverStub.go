@@ -0,0 +1,24 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/multiversx/mx-chain-go/common"
+)
+
+// TrieLeavesRetrieverStub -
+type TrieLeavesRetrieverStub struct {
+	GetAllLeavesOnChannelCalled func(leavesChannels *common.TrieIteratorChannels, ctx context.Context, rootHash []byte, keyBuilder common.KeyBuilder) error
+}
+
+// GetAllLeavesOnChannel -
+func (stub *TrieLeavesRetrieverStub) GetAllLeavesOnChannel(leavesChannels *common.TrieIteratorChannels, ctx context.Context, rootHash []byte, keyBuilder common.KeyBuilder) error {
+	if stub.GetAllLeavesOnChannelCalled != nil {
+		return stub.GetAllLeavesOnChannelCalled(leavesChannels, ctx, rootHash, keyBuilder)
+	}
+
+	close(leavesChannels.LeavesChan)
+	close(leavesChannels.ErrChan)
+
+	return nil
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"github.com/multiversx/mx-chain-core-go/core"
+	"github.com/multiversx/mx-chain-go/state"
+	"github.com/multiversx/mx-chain-go/trie"
+	"github.com/multiversx/mx-chain-tools-go/trieTools/trieToolsCommon"
+)
+
+// the node types, as appended by the trie to each encoded node
+const (
+	extensionNodeType = byte(iota)
+	leafNodeType
+	branchNodeType
+)
+
+const nodeHistogramPhase = "node histogram"
+
+var errInvalidTrieNode = errors.New("invalid trie node")
+
+// nodeHistogramCollector walks the raw trie nodes, as read from the storage, and counts them by node type. Unlike the
+// leaves iteration, the walk is depth first and only holds the nodes on the current path in memory
+type nodeHistogramCollector struct {
+	ctx           context.Context
+	getter        nodesGetter
+	walkDataTries bool
+	stats         *trieToolsCommon.NodeHistogramStatistics
+}
+
+// collectNodeHistogram walks the main trie with the provided root hash and, if walkDataTries is set, the data tries of
+// all its accounts. On cancel, the walk stops and the histogram gathered so far is returned along with the context error
+func collectNodeHistogram(ctx context.Context, getter nodesGetter, mainRootHash []byte, walkDataTries bool) (*trieToolsCommon.NodeHistogramStatistics, error) {
+	collector := &nodeHistogramCollector{
+		ctx:           ctx,
+		getter:        getter,
+		walkDataTries: walkDataTries,
+		stats:         &trieToolsCommon.NodeHistogramStatistics{},
+	}
+
+	err := collector.walk(mainRootHash, 0, &collector.stats.MainTrie, true)
+
+	return collector.stats, err
+}
+
+func (collector *nodeHistogramCollector) walk(hash []byte, depth uint32, histogram *trieToolsCommon.NodeHistogram, isMainTrie bool) error {
+	err := collector.ctx.Err()
+	if err != nil {
+		return err
+	}
+
+	encodedNode, err := collector.getter.Get(hash)
+	if err != nil {
+		return fmt.Errorf("%w while reading the trie node %s", err, hex.EncodeToString(hash))
+	}
+	if len(encodedNode) == 0 {
+		return fmt.Errorf("%w: empty encoding for the trie node %s", errInvalidTrieNode, hex.EncodeToString(hash))
+	}
+
+	if depth > histogram.MaxDepth {
+		histogram.MaxDepth = depth
+	}
+	size := uint64(len(encodedNode))
+	nodeType := encodedNode[len(encodedNode)-1]
+	encodedNode = encodedNode[:len(encodedNode)-1]
+
+	switch nodeType {
+	case branchNodeType:
+		branchNode := &trie.CollapsedBn{}
+		err = trieToolsCommon.Marshaller.Unmarshal(branchNode, encodedNode)
+		if err != nil {
+			return fmt.Errorf("%w while decoding the branch node %s", err, hex.EncodeToString(hash))
+		}
+
+		histogram.NumBranchNodes++
+		histogram.BranchNodesSize += size
+		for _, childHash := range branchNode.EncodedChildren {
+			if len(childHash) == 0 {
+				continue
+			}
+
+			err = collector.walk(childHash, depth+1, histogram, isMainTrie)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	case extensionNodeType:
+		extensionNode := &trie.CollapsedEn{}
+		err = trieToolsCommon.Marshaller.Unmarshal(extensionNode, encodedNode)
+		if err != nil {
+			return fmt.Errorf("%w while decoding the extension node %s", err, hex.EncodeToString(hash))
+		}
+
+		histogram.NumExtensionNodes++
+		histogram.ExtensionNodesSize += size
+
+		return collector.walk(extensionNode.EncodedChild, depth+1, histogram, isMainTrie)
+	case leafNodeType:
+		leafNode := &trie.CollapsedLn{}
+		err = trieToolsCommon.Marshaller.Unmarshal(leafNode, encodedNode)
+		if err != nil {
+			return fmt.Errorf("%w while decoding the leaf node %s", err, hex.EncodeToString(hash))
+		}
+
+		histogram.NumLeafNodes++
+		histogram.LeafNodesSize += size
+		if !isMainTrie || !collector.walkDataTries {
+			return nil
+		}
+
+		return collector.walkDataTrie(leafNode.Value)
+	default:
+		return fmt.Errorf("%w: unknown node type %d for the trie node %s", errInvalidTrieNode, nodeType, hex.EncodeToString(hash))
+	}
+}
+
+// walkDataTrie walks the data trie of the account held by a main trie leaf. The leaves that are not accounts (e.g.
+// code nodes) and the accounts without a data trie are skipped
+func (collector *nodeHistogramCollector) walkDataTrie(leafValue []byte) error {
+	userAccount := &state.UserAccountData{}
+	err := trieToolsCommon.Marshaller.Unmarshal(userAccount, leafValue)
+	if err != nil || len(userAccount.RootHash) == 0 {
+		return nil
+	}
+
+	collector.stats.NumDataTries++
+
+	return collector.walk(userAccount.RootHash, 0, &collector.stats.DataTries, false)
+}
+
+func logNodeHistogram(trieName string, histogram trieToolsCommon.NodeHistogram) {
+	log.Info("trie nodes histogram",
+		"trie", trieName,
+		"num branch nodes", histogram.NumBranchNodes,
+		"branch nodes size", core.ConvertBytes(histogram.BranchNodesSize),
+		"num extension nodes", histogram.NumExtensionNodes,
+		"extension nodes size", core.ConvertBytes(histogram.ExtensionNodesSize),
+		"num leaf nodes", histogram.NumLeafNodes,
+		"leaf nodes size", core.ConvertBytes(histogram.LeafNodesSize),
+		"max depth", histogram.MaxDepth)
+}
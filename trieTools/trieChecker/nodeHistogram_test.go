@@ -0,0 +1,163 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/multiversx/mx-chain-go/common"
+	"github.com/multiversx/mx-chain-go/state"
+	"github.com/multiversx/mx-chain-go/testscommon/genericMocks"
+	"github.com/multiversx/mx-chain-tools-go/trieTools/trieToolsCommon"
+	"github.com/stretchr/testify/require"
+)
+
+func createTestCommittedTrie(t *testing.T, storer *genericMocks.StorerMock, leaves map[string][]byte) (common.Trie, []byte) {
+	tr, err := trieToolsCommon.CreateTrie(storer)
+	require.Nil(t, err)
+
+	for key, value := range leaves {
+		require.Nil(t, tr.Update([]byte(key), value))
+	}
+	require.Nil(t, tr.Commit())
+
+	rootHash, err := tr.RootHash()
+	require.Nil(t, err)
+
+	return tr, rootHash
+}
+
+func requireSameHistogram(t *testing.T, tr common.Trie, rootHash []byte, histogram trieToolsCommon.NodeHistogram) {
+	expected, err := tr.(common.TrieStats).GetTrieStats("", rootHash)
+	require.Nil(t, err)
+
+	require.Equal(t, expected.NumBranchNodes, histogram.NumBranchNodes)
+	require.Equal(t, expected.BranchNodesSize, histogram.BranchNodesSize)
+	require.Equal(t, expected.NumExtensionNodes, histogram.NumExtensionNodes)
+	require.Equal(t, expected.ExtensionNodesSize, histogram.ExtensionNodesSize)
+	require.Equal(t, expected.NumLeafNodes, histogram.NumLeafNodes)
+	require.Equal(t, expected.LeafNodesSize, histogram.LeafNodesSize)
+	require.Equal(t, expected.MaxTrieDepth, histogram.MaxDepth)
+}
+
+func TestCollectNodeHistogram(t *testing.T) {
+	t.Parallel()
+
+	storer := genericMocks.NewStorerMock()
+	dataLeaves := make(map[string][]byte)
+	for i := 0; i < 50; i++ {
+		dataLeaves[fmt.Sprintf("key%d", i)] = []byte(fmt.Sprintf("value%d", i))
+	}
+	dataTrie, dataRootHash := createTestCommittedTrie(t, storer, dataLeaves)
+
+	mainLeaves := make(map[string][]byte)
+	for i := 0; i < 100; i++ {
+		address := []byte(fmt.Sprintf("%032d", i))
+		account := &state.UserAccountData{
+			Address: address,
+			Balance: big.NewInt(int64(i)),
+		}
+		if i%10 == 0 {
+			account.RootHash = dataRootHash
+		}
+
+		accountBytes, err := trieToolsCommon.Marshaller.Marshal(account)
+		require.Nil(t, err)
+		mainLeaves[string(address)] = accountBytes
+	}
+	mainTrie, mainRootHash := createTestCommittedTrie(t, storer, mainLeaves)
+
+	t.Run("main trie and data tries", func(t *testing.T) {
+		t.Parallel()
+
+		histogram, err := collectNodeHistogram(context.Background(), storer, mainRootHash, true)
+		require.Nil(t, err)
+		requireSameHistogram(t, mainTrie, mainRootHash, histogram.MainTrie)
+		require.Equal(t, 10, histogram.NumDataTries)
+
+		dataTrieStats, err := dataTrie.(common.TrieStats).GetTrieStats("", dataRootHash)
+		require.Nil(t, err)
+		require.Equal(t, 10*dataTrieStats.NumLeafNodes, histogram.DataTries.NumLeafNodes)
+		require.Equal(t, 10*dataTrieStats.TotalNodesSize,
+			histogram.DataTries.BranchNodesSize+histogram.DataTries.ExtensionNodesSize+histogram.DataTries.LeafNodesSize)
+		require.Equal(t, dataTrieStats.MaxTrieDepth, histogram.DataTries.MaxDepth)
+	})
+	t.Run("main trie only", func(t *testing.T) {
+		t.Parallel()
+
+		histogram, err := collectNodeHistogram(context.Background(), storer, mainRootHash, false)
+		require.Nil(t, err)
+		requireSameHistogram(t, mainTrie, mainRootHash, histogram.MainTrie)
+		require.Equal(t, 0, histogram.NumDataTries)
+		require.Equal(t, trieToolsCommon.NodeHistogram{}, histogram.DataTries)
+	})
+	t.Run("missing node, should error", func(t *testing.T) {
+		t.Parallel()
+
+		histogram, err := collectNodeHistogram(context.Background(), genericMocks.NewStorerMock(), mainRootHash, true)
+		require.NotNil(t, err)
+		require.NotNil(t, histogram)
+		require.Contains(t, err.Error(), "while reading the trie node")
+	})
+	t.Run("unknown node type, should error", func(t *testing.T) {
+		t.Parallel()
+
+		invalidStorer := genericMocks.NewStorerMock()
+		require.Nil(t, invalidStorer.Put([]byte("root"), []byte{1, 2, 3, 7}))
+
+		_, err := collectNodeHistogram(context.Background(), invalidStorer, []byte("root"), true)
+		require.ErrorIs(t, err, errInvalidTrieNode)
+	})
+	t.Run("interrupted, should return the context error", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		histogram, err := collectNodeHistogram(ctx, storer, mainRootHash, true)
+		require.ErrorIs(t, err, context.Canceled)
+		require.Equal(t, uint64(0), histogram.MainTrie.NumLeafNodes)
+	})
+}
+
+func TestAddNodeHistogram(t *testing.T) {
+	t.Parallel()
+
+	storer := genericMocks.NewStorerMock()
+	_, rootHash := createTestCommittedTrie(t, storer, map[string][]byte{"key1": []byte("value1"), "key2": []byte("value2")})
+
+	t.Run("should add the histogram and the phase", func(t *testing.T) {
+		t.Parallel()
+
+		stats := &trieToolsCommon.TrieStatistics{}
+		err := addNodeHistogram(context.Background(), newCountingStorer(storer), rootHash, true, stats)
+		require.Nil(t, err)
+		require.False(t, stats.Partial)
+		require.Equal(t, uint64(2), stats.NodeHistogram.MainTrie.NumLeafNodes)
+		require.Equal(t, 1, len(stats.Phases))
+		require.Equal(t, nodeHistogramPhase, stats.Phases[0].Name)
+		require.Equal(t, uint64(3), stats.Phases[0].NumNodeReads)
+	})
+	t.Run("interrupted, should mark the statistics as partial", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		stats := &trieToolsCommon.TrieStatistics{}
+		err := addNodeHistogram(ctx, newCountingStorer(storer), rootHash, true, stats)
+		require.Nil(t, err)
+		require.True(t, stats.Partial)
+		require.NotNil(t, stats.NodeHistogram)
+	})
+	t.Run("missing node, should error", func(t *testing.T) {
+		t.Parallel()
+
+		stats := &trieToolsCommon.TrieStatistics{}
+		err := addNodeHistogram(context.Background(), newCountingStorer(genericMocks.NewStorerMock()), rootHash, true, stats)
+		require.NotNil(t, err)
+		require.Equal(t, trieToolsCommon.ExitCodeIntegrity, trieToolsCommon.ExitCode(err))
+		require.Nil(t, stats.NodeHistogram)
+	})
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+
+	"github.com/multiversx/mx-chain-core-go/core"
+	"github.com/multiversx/mx-chain-tools-go/trieTools/trieToolsCommon"
+)
+
+var esdtKeyPrefix = []byte(core.ProtectedKeyPrefix + core.ESDTKeyIdentifier)
+
+// tokensCountBucketsMaxTokens holds the upper bounds of the distribution buckets. The last bucket is not bounded
+var tokensCountBucketsMaxTokens = []int{1, 10, 100, 1000, 10000}
+
+// tokenReportBuilder gathers the num of ESDT token entries (fungible tokens or NFTs/SFTs/meta ESDTs nonces) of the
+// accounts whose data tries are iterated
+type tokenReportBuilder struct {
+	topN            int
+	accounts        []trieToolsCommon.AccountTokens
+	numTokenEntries int
+	distribution    []trieToolsCommon.TokensCountBucket
+}
+
+func newTokenReportBuilder(topN int) *tokenReportBuilder {
+	distribution := make([]trieToolsCommon.TokensCountBucket, 0, len(tokensCountBucketsMaxTokens)+1)
+	minTokens := 1
+	for _, maxTokens := range tokensCountBucketsMaxTokens {
+		distribution = append(distribution, trieToolsCommon.TokensCountBucket{
+			MinTokens: minTokens,
+			MaxTokens: maxTokens,
+		})
+		minTokens = maxTokens + 1
+	}
+	distribution = append(distribution, trieToolsCommon.TokensCountBucket{
+		MinTokens: minTokens,
+	})
+
+	return &tokenReportBuilder{
+		topN:         topN,
+		accounts:     make([]trieToolsCommon.AccountTokens, 0),
+		distribution: distribution,
+	}
+}
+
+// isESDTKey returns true if the provided data trie key holds an ESDT token entry
+func isESDTKey(key []byte) bool {
+	return bytes.HasPrefix(key, esdtKeyPrefix)
+}
+
+// addAccount adds the num of token entries found in the data trie of the provided account. The accounts without
+// token entries are not reported
+func (builder *tokenReportBuilder) addAccount(address string, numTokens int) {
+	if numTokens == 0 {
+		return
+	}
+
+	builder.accounts = append(builder.accounts, trieToolsCommon.AccountTokens{
+		Address:   address,
+		NumTokens: numTokens,
+	})
+	builder.numTokenEntries += numTokens
+
+	for index := range builder.distribution {
+		bucket := &builder.distribution[index]
+		if bucket.MaxTokens == 0 || numTokens <= bucket.MaxTokens {
+			bucket.NumAccounts++
+			return
+		}
+	}
+}
+
+// report returns the gathered report. The top accounts are sorted descending by the num of token entries and, for
+// the same num of token entries, ascending by address
+func (builder *tokenReportBuilder) report() *trieToolsCommon.TokenReport {
+	sort.Slice(builder.accounts, func(i, j int) bool {
+		if builder.accounts[i].NumTokens != builder.accounts[j].NumTokens {
+			return builder.accounts[i].NumTokens > builder.accounts[j].NumTokens
+		}
+
+		return builder.accounts[i].Address < builder.accounts[j].Address
+	})
+
+	numTopAccounts := builder.topN
+	if numTopAccounts > len(builder.accounts) {
+		numTopAccounts = len(builder.accounts)
+	}
+	topAccounts := make([]trieToolsCommon.AccountTokens, numTopAccounts)
+	copy(topAccounts, builder.accounts)
+
+	distribution := make([]trieToolsCommon.TokensCountBucket, len(builder.distribution))
+	copy(distribution, builder.distribution)
+
+	return &trieToolsCommon.TokenReport{
+		NumAccountsWithTokens: len(builder.accounts),
+		NumTokenEntries:       builder.numTokenEntries,
+		TopAccounts:           topAccounts,
+		Distribution:          distribution,
+	}
+}
+
+func logTokenReport(report *trieToolsCommon.TokenReport) {
+	log.Info("token report",
+		"num accounts with tokens", report.NumAccountsWithTokens,
+		"num token entries", report.NumTokenEntries)
+
+	for index, account := range report.TopAccounts {
+		log.Info("top account by num token entries",
+			"rank", index+1,
+			"address", account.Address,
+			"num token entries", account.NumTokens)
+	}
+
+	for _, bucket := range report.Distribution {
+		log.Info("accounts by num token entries",
+			"num token entries", tokensCountBucketLabel(bucket),
+			"num accounts", bucket.NumAccounts)
+	}
+}
+
+func tokensCountBucketLabel(bucket trieToolsCommon.TokensCountBucket) string {
+	switch {
+	case bucket.MaxTokens == 0:
+		return fmt.Sprintf(">=%d", bucket.MinTokens)
+	case bucket.MinTokens == bucket.MaxTokens:
+		return fmt.Sprintf("%d", bucket.MinTokens)
+	default:
+		return fmt.Sprintf("%d-%d", bucket.MinTokens, bucket.MaxTokens)
+	}
+}
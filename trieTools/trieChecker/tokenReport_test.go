@@ -0,0 +1,72 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/multiversx/mx-chain-core-go/core"
+	"github.com/multiversx/mx-chain-tools-go/trieTools/trieToolsCommon"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsESDTKey(t *testing.T) {
+	t.Parallel()
+
+	require.True(t, isESDTKey([]byte(core.ProtectedKeyPrefix+core.ESDTKeyIdentifier+"TKN-abcdef")))
+	require.False(t, isESDTKey([]byte(core.ProtectedKeyPrefix+core.ESDTRoleIdentifier+core.ESDTKeyIdentifier+"TKN-abcdef")))
+	require.False(t, isESDTKey([]byte("key")))
+	require.False(t, isESDTKey(nil))
+}
+
+func TestTokenReportBuilder(t *testing.T) {
+	t.Parallel()
+
+	t.Run("empty report", func(t *testing.T) {
+		t.Parallel()
+
+		report := newTokenReportBuilder(3).report()
+		require.Equal(t, 0, report.NumAccountsWithTokens)
+		require.Equal(t, 0, report.NumTokenEntries)
+		require.Empty(t, report.TopAccounts)
+		require.Equal(t, len(tokensCountBucketsMaxTokens)+1, len(report.Distribution))
+	})
+	t.Run("should sort the top accounts and fill the distribution", func(t *testing.T) {
+		t.Parallel()
+
+		builder := newTokenReportBuilder(3)
+		builder.addAccount("erd1a", 0)
+		builder.addAccount("erd1b", 1)
+		builder.addAccount("erd1c", 50)
+		builder.addAccount("erd1d", 10)
+		builder.addAccount("erd1e", 50)
+		builder.addAccount("erd1f", 20000)
+
+		report := builder.report()
+		require.Equal(t, 5, report.NumAccountsWithTokens)
+		require.Equal(t, 20111, report.NumTokenEntries)
+		require.Equal(t, []trieToolsCommon.AccountTokens{
+			{Address: "erd1f", NumTokens: 20000},
+			{Address: "erd1c", NumTokens: 50},
+			{Address: "erd1e", NumTokens: 50},
+		}, report.TopAccounts)
+		require.Equal(t, []trieToolsCommon.TokensCountBucket{
+			{MinTokens: 1, MaxTokens: 1, NumAccounts: 1},
+			{MinTokens: 2, MaxTokens: 10, NumAccounts: 1},
+			{MinTokens: 11, MaxTokens: 100, NumAccounts: 2},
+			{MinTokens: 101, MaxTokens: 1000, NumAccounts: 0},
+			{MinTokens: 1001, MaxTokens: 10000, NumAccounts: 0},
+			{MinTokens: 10001, MaxTokens: 0, NumAccounts: 1},
+		}, report.Distribution)
+	})
+}
+
+func TestTokensCountBucketLabel(t *testing.T) {
+	t.Parallel()
+
+	labels := make([]string, 0)
+	for _, bucket := range newTokenReportBuilder(1).distribution {
+		labels = append(labels, tokensCountBucketLabel(bucket))
+	}
+
+	require.Equal(t, "[1 2-10 11-100 101-1000 1001-10000 >=10001]", fmt.Sprintf("%v", labels))
+}
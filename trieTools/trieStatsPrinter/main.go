@@ -28,7 +28,9 @@ func main() {
 	app := cli.NewApp()
 	app.Name = "Trie stats CLI app"
 	app.Usage = "This is the entry point for the tool that prints stats about the state"
+	app.Version = trieToolsCommon.GetVersion()
 	app.Flags = trieToolsCommon.GetFlags()
+	app.OnUsageError = trieToolsCommon.OnUsageError
 	app.Authors = []cli.Author{
 		{
 			Name:  "The MultiversX Team",
@@ -43,7 +45,7 @@ func main() {
 	err := app.Run(os.Args)
 	if err != nil {
 		log.Error(err.Error())
-		os.Exit(1)
+		os.Exit(trieToolsCommon.ExitCode(err))
 		return
 	}
 
@@ -65,12 +67,15 @@ func startProcess(c *cli.Context) error {
 		return err
 	}
 
-	rootHash, err := hex.DecodeString(flagsConfig.HexRootHash)
-	if err != nil {
-		return fmt.Errorf("%w when decoding the provided hex root hash", err)
-	}
-	if len(rootHash) != rootHashLength {
-		return fmt.Errorf("wrong root hash length: expected %d, got %d", rootHashLength, len(rootHash))
+	var rootHash []byte
+	if !trieToolsCommon.ShouldUseLatestRootHash(flagsConfig) {
+		rootHash, err = hex.DecodeString(flagsConfig.HexRootHash)
+		if err != nil {
+			return fmt.Errorf("%w when decoding the provided hex root hash", err)
+		}
+		if len(rootHash) != rootHashLength {
+			return fmt.Errorf("wrong root hash length: expected %d, got %d", rootHashLength, len(rootHash))
+		}
 	}
 
 	log.Info("starting processing trie", "pid", os.Getpid())
@@ -94,6 +99,11 @@ func printTrieStats(flags trieToolsCommon.ContextFlagsConfig, mainRootHash []byt
 		log.LogIfError(errNotCritical)
 	}()
 
+	mainRootHash, err = trieToolsCommon.ResolveRootHash(mainRootHash, flags, storer)
+	if err != nil {
+		return err
+	}
+
 	accDb, err := trieToolsCommon.NewAccountsAdapter(tr)
 	if err != nil {
 		return err
@@ -0,0 +1,135 @@
+package trieToolsCommon
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcutil/bech32"
+	"github.com/multiversx/mx-chain-core-go/core"
+	"github.com/multiversx/mx-chain-core-go/core/check"
+)
+
+const (
+	// DefaultAddressHrp is the bech32 human-readable part of the MultiversX addresses
+	DefaultAddressHrp = "erd"
+
+	bech32MaxLength      = 90
+	bech32ChecksumLength = 6
+	bech32FromBits       = byte(8)
+	bech32ToBits         = byte(5)
+)
+
+var (
+	errInvalidAddressHrp    = errors.New("invalid address hrp")
+	errInvalidAddressLength = errors.New("invalid address length")
+	errUnexpectedAddressHrp = errors.New("unexpected address hrp")
+)
+
+// bech32AddressConverter encodes or decodes the addresses as/from bech32, using the configured human-readable part.
+// Unlike the pubkeyConverter from mx-chain-core-go, the human-readable part is not fixed to erd
+type bech32AddressConverter struct {
+	hrp string
+	len int
+	log core.Logger
+}
+
+// NewBech32AddressConverter creates a bech32 address converter that uses the provided human-readable part
+func NewBech32AddressConverter(addressLen int, hrp string, log core.Logger) (*bech32AddressConverter, error) {
+	if addressLen < 1 {
+		return nil, fmt.Errorf("%w: %d", errInvalidAddressLength, addressLen)
+	}
+	if check.IfNil(log) {
+		return nil, core.ErrNilLogger
+	}
+
+	err := CheckAddressHrp(hrp, addressLen)
+	if err != nil {
+		return nil, err
+	}
+
+	return &bech32AddressConverter{
+		hrp: hrp,
+		len: addressLen,
+		log: log,
+	}, nil
+}
+
+// CheckAddressHrp checks that the provided human-readable part is a legal bech32 prefix for addresses of the provided
+// length: it is lowercase, made of printable US-ASCII characters, and the encoded address fits the bech32 max length
+func CheckAddressHrp(hrp string, addressLen int) error {
+	if len(hrp) == 0 {
+		return fmt.Errorf("%w: empty hrp", errInvalidAddressHrp)
+	}
+
+	for _, c := range hrp {
+		if c < 33 || c > 126 {
+			return fmt.Errorf("%w %q: character %q is not a printable US-ASCII character", errInvalidAddressHrp, hrp, c)
+		}
+		if c >= 'A' && c <= 'Z' {
+			return fmt.Errorf("%w %q: uppercase characters are not allowed", errInvalidAddressHrp, hrp)
+		}
+	}
+
+	numDataChars := (addressLen*int(bech32FromBits) + int(bech32ToBits) - 1) / int(bech32ToBits)
+	maxHrpLength := bech32MaxLength - numDataChars - bech32ChecksumLength - 1
+	if len(hrp) > maxHrpLength {
+		return fmt.Errorf("%w %q: the hrp can have at most %d characters for addresses of %d bytes",
+			errInvalidAddressHrp, hrp, maxHrpLength, addressLen)
+	}
+
+	return nil
+}
+
+// Len returns the decoded address length
+func (converter *bech32AddressConverter) Len() int {
+	return converter.len
+}
+
+// Decode converts the provided bech32 address in bytes
+func (converter *bech32AddressConverter) Decode(humanReadable string) ([]byte, error) {
+	decodedHrp, buff, err := bech32.Decode(humanReadable)
+	if err != nil {
+		return nil, err
+	}
+	if decodedHrp != converter.hrp {
+		return nil, fmt.Errorf("%w: expected %s, got %s", errUnexpectedAddressHrp, converter.hrp, decodedHrp)
+	}
+
+	decodedBytes, err := bech32.ConvertBits(buff, bech32ToBits, bech32FromBits, false)
+	if err != nil {
+		return nil, err
+	}
+	if len(decodedBytes) != converter.len {
+		return nil, fmt.Errorf("%w: expected %d, got %d", errInvalidAddressLength, converter.len, len(decodedBytes))
+	}
+
+	return decodedBytes, nil
+}
+
+// Encode converts the provided address bytes in bech32. It returns an empty string if the address can not be encoded
+func (converter *bech32AddressConverter) Encode(pkBytes []byte) string {
+	if len(pkBytes) != converter.len {
+		converter.log.Debug("bech32AddressConverter.Encode", "hex buff", hex.EncodeToString(pkBytes), "error", errInvalidAddressLength)
+		return ""
+	}
+
+	conv, err := bech32.ConvertBits(pkBytes, bech32FromBits, bech32ToBits, true)
+	if err != nil {
+		converter.log.Warn("bech32AddressConverter.Encode ConvertBits", "hex buff", hex.EncodeToString(pkBytes), "error", err)
+		return ""
+	}
+
+	encoded, err := bech32.Encode(converter.hrp, conv)
+	if err != nil {
+		converter.log.Warn("bech32AddressConverter.Encode Encode", "hex buff", hex.EncodeToString(pkBytes), "error", err)
+		return ""
+	}
+
+	return encoded
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (converter *bech32AddressConverter) IsInterfaceNil() bool {
+	return converter == nil
+}
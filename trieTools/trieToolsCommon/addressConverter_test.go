@@ -0,0 +1,101 @@
+package trieToolsCommon
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/multiversx/mx-chain-core-go/core"
+	"github.com/multiversx/mx-chain-core-go/core/pubkeyConverter"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewBech32AddressConverter(t *testing.T) {
+	t.Parallel()
+
+	t.Run("invalid address length should error", func(t *testing.T) {
+		t.Parallel()
+
+		converter, err := NewBech32AddressConverter(0, DefaultAddressHrp, log)
+		require.Nil(t, converter)
+		require.True(t, errors.Is(err, errInvalidAddressLength))
+	})
+	t.Run("nil logger should error", func(t *testing.T) {
+		t.Parallel()
+
+		converter, err := NewBech32AddressConverter(addressLength, DefaultAddressHrp, nil)
+		require.Nil(t, converter)
+		require.Equal(t, core.ErrNilLogger, err)
+	})
+	t.Run("invalid hrp should error", func(t *testing.T) {
+		t.Parallel()
+
+		converter, err := NewBech32AddressConverter(addressLength, "ERD", log)
+		require.Nil(t, converter)
+		require.True(t, errors.Is(err, errInvalidAddressHrp))
+	})
+	t.Run("should work", func(t *testing.T) {
+		t.Parallel()
+
+		converter, err := NewBech32AddressConverter(addressLength, DefaultAddressHrp, log)
+		require.Nil(t, err)
+		require.False(t, converter.IsInterfaceNil())
+		require.Equal(t, addressLength, converter.Len())
+	})
+}
+
+func TestCheckAddressHrp(t *testing.T) {
+	t.Parallel()
+
+	require.Nil(t, CheckAddressHrp(DefaultAddressHrp, addressLength))
+	require.Nil(t, CheckAddressHrp("test1", addressLength))
+	require.Nil(t, CheckAddressHrp(strings.Repeat("a", 31), addressLength))
+
+	require.True(t, errors.Is(CheckAddressHrp("", addressLength), errInvalidAddressHrp))
+	require.True(t, errors.Is(CheckAddressHrp("Erd", addressLength), errInvalidAddressHrp))
+	require.True(t, errors.Is(CheckAddressHrp("e rd", addressLength), errInvalidAddressHrp))
+	require.True(t, errors.Is(CheckAddressHrp("erdé", addressLength), errInvalidAddressHrp))
+	require.True(t, errors.Is(CheckAddressHrp(strings.Repeat("a", 32), addressLength), errInvalidAddressHrp))
+}
+
+func TestBech32AddressConverter_EncodeDecode(t *testing.T) {
+	t.Parallel()
+
+	address := bytes.Repeat([]byte{1}, addressLength)
+
+	t.Run("default hrp should match the core converter", func(t *testing.T) {
+		t.Parallel()
+
+		converter, _ := NewBech32AddressConverter(addressLength, DefaultAddressHrp, log)
+		coreConverter, _ := pubkeyConverter.NewBech32PubkeyConverter(addressLength, log)
+
+		encoded := converter.Encode(address)
+		require.Equal(t, coreConverter.Encode(address), encoded)
+
+		decoded, err := converter.Decode(encoded)
+		require.Nil(t, err)
+		require.Equal(t, address, decoded)
+	})
+	t.Run("custom hrp", func(t *testing.T) {
+		t.Parallel()
+
+		converter, _ := NewBech32AddressConverter(addressLength, "test", log)
+		encoded := converter.Encode(address)
+		require.True(t, strings.HasPrefix(encoded, "test1"))
+
+		decoded, err := converter.Decode(encoded)
+		require.Nil(t, err)
+		require.Equal(t, address, decoded)
+
+		defaultConverter, _ := NewBech32AddressConverter(addressLength, DefaultAddressHrp, log)
+		_, err = defaultConverter.Decode(encoded)
+		require.True(t, errors.Is(err, errUnexpectedAddressHrp))
+	})
+	t.Run("wrong length should not encode", func(t *testing.T) {
+		t.Parallel()
+
+		converter, _ := NewBech32AddressConverter(addressLength, DefaultAddressHrp, log)
+		require.Equal(t, "", converter.Encode([]byte{1, 2}))
+	})
+}
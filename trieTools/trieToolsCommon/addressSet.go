@@ -0,0 +1,55 @@
+package trieToolsCommon
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/multiversx/mx-chain-core-go/core"
+)
+
+// LoadAddressSet reads a file containing one address per line and returns the set of decoded addresses, as 32 bytes
+// keys. Empty lines and lines starting with # are ignored. The first invalid address is reported along with its line number
+func LoadAddressSet(path string, converter core.PubkeyConverter) (map[string]struct{}, error) {
+	if converter == nil || converter.IsInterfaceNil() {
+		return nil, fmt.Errorf("nil pubkey converter provided")
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, NewIOError(err)
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	addresses := make(map[string]struct{})
+	scanner := bufio.NewScanner(file)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		if len(line) == 0 || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		address, errDecode := converter.Decode(line)
+		if errDecode != nil {
+			return nil, NewUsageError(fmt.Errorf("%w for address %s on line %d of file %s", errDecode, line, lineNumber, path))
+		}
+		if len(address) != addressLength {
+			return nil, NewUsageError(fmt.Errorf("invalid address length %d for address %s on line %d of file %s, expected %d",
+				len(address), line, lineNumber, path, addressLength))
+		}
+
+		addresses[string(address)] = struct{}{}
+	}
+
+	err = scanner.Err()
+	if err != nil {
+		return nil, NewIOError(fmt.Errorf("%w while reading file %s", err, path))
+	}
+
+	return addresses, nil
+}
@@ -0,0 +1,75 @@
+package trieToolsCommon
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/multiversx/mx-chain-core-go/core/pubkeyConverter"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadAddressSet(t *testing.T) {
+	t.Parallel()
+
+	converter, err := pubkeyConverter.NewBech32PubkeyConverter(addressLength, log)
+	require.Nil(t, err)
+	addressA := bytes.Repeat([]byte("a"), addressLength)
+	addressB := bytes.Repeat([]byte("b"), addressLength)
+
+	t.Run("nil converter, should error", func(t *testing.T) {
+		t.Parallel()
+
+		addresses, err := LoadAddressSet("addresses.txt", nil)
+		require.Nil(t, addresses)
+		require.ErrorContains(t, err, "nil pubkey converter")
+	})
+	t.Run("missing file, should error", func(t *testing.T) {
+		t.Parallel()
+
+		addresses, err := LoadAddressSet(filepath.Join(t.TempDir(), "missing.txt"), converter)
+		require.Nil(t, addresses)
+		require.True(t, errors.Is(err, os.ErrNotExist))
+		require.Equal(t, ExitCodeIO, ExitCode(err))
+	})
+	t.Run("invalid address, should error", func(t *testing.T) {
+		t.Parallel()
+
+		filePath := filepath.Join(t.TempDir(), "addresses.txt")
+		require.Nil(t, os.WriteFile(filePath, []byte(converter.Encode(addressA)+"\n# comment\ninvalid\n"), 0644))
+
+		addresses, err := LoadAddressSet(filePath, converter)
+		require.Nil(t, addresses)
+		require.Error(t, err)
+		require.True(t, strings.Contains(err.Error(), "line 3"))
+		require.Equal(t, ExitCodeUsage, ExitCode(err))
+	})
+	t.Run("invalid address length, should error", func(t *testing.T) {
+		t.Parallel()
+
+		shortConverter, err := pubkeyConverter.NewHexPubkeyConverter(4)
+		require.Nil(t, err)
+
+		filePath := filepath.Join(t.TempDir(), "addresses.txt")
+		require.Nil(t, os.WriteFile(filePath, []byte("\n0a0b0c0d\n"), 0644))
+
+		addresses, err := LoadAddressSet(filePath, shortConverter)
+		require.Nil(t, addresses)
+		require.ErrorContains(t, err, "invalid address length 4")
+		require.ErrorContains(t, err, "line 2")
+	})
+	t.Run("should work", func(t *testing.T) {
+		t.Parallel()
+
+		filePath := filepath.Join(t.TempDir(), "addresses.txt")
+		content := "# comment\n\n  " + converter.Encode(addressA) + "  \n" + converter.Encode(addressB) + "\n" + converter.Encode(addressA) + "\n"
+		require.Nil(t, os.WriteFile(filePath, []byte(content), 0644))
+
+		addresses, err := LoadAddressSet(filePath, converter)
+		require.Nil(t, err)
+		require.Equal(t, map[string]struct{}{string(addressA): {}, string(addressB): {}}, addresses)
+	})
+}
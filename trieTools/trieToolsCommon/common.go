@@ -2,6 +2,7 @@ package trieToolsCommon
 
 import (
 	"fmt"
+	"io"
 	"io/ioutil"
 	"math/big"
 	"os"
@@ -19,7 +20,6 @@ import (
 	disabled2 "github.com/multiversx/mx-chain-go/state/storagePruningManager/disabled"
 	"github.com/multiversx/mx-chain-go/storage"
 	"github.com/multiversx/mx-chain-go/storage/databaseremover/disabled"
-	"github.com/multiversx/mx-chain-go/storage/factory"
 	"github.com/multiversx/mx-chain-go/storage/pruning"
 	"github.com/multiversx/mx-chain-go/testscommon"
 	"github.com/multiversx/mx-chain-go/trie"
@@ -47,7 +47,13 @@ const (
 func AttachFileLogger(log logger.Logger, logFilePrefix string, flagsConfig ContextFlagsConfig) (nodeFactory.FileLoggingHandler, error) {
 	var fileLogging nodeFactory.FileLoggingHandler
 	var err error
-	if flagsConfig.SaveLogFile {
+	switch {
+	case flagsConfig.SaveLogFile && flagsConfig.LogJSON:
+		fileLogging, err = newJSONFileLogging(flagsConfig.WorkingDir, defaultLogsPath, logFilePrefix)
+		if err != nil {
+			return nil, fmt.Errorf("%w creating a log file", err)
+		}
+	case flagsConfig.SaveLogFile:
 		fileLogging, err = file.NewFileLogging(file.ArgsFileLogging{
 			WorkingDir:      flagsConfig.WorkingDir,
 			DefaultLogsPath: defaultLogsPath,
@@ -67,27 +73,39 @@ func AttachFileLogger(log logger.Logger, logFilePrefix string, flagsConfig Conte
 		return nil, err
 	}
 
-	if flagsConfig.DisableAnsiColor {
-		err = logger.RemoveLogObserver(os.Stdout)
-		if err != nil {
-			return nil, err
-		}
-
-		err = logger.AddLogObserver(os.Stdout, &logger.PlainFormatter{})
-		if err != nil {
-			return nil, err
-		}
+	err = setConsoleFormatter(flagsConfig)
+	if err != nil {
+		return nil, err
 	}
-	log.Trace("logger updated", "level", logLevelFlagValue, "disable ANSI color", flagsConfig.DisableAnsiColor)
+	log.Trace("logger updated", "level", logLevelFlagValue, "disable ANSI color", flagsConfig.DisableAnsiColor, "JSON", flagsConfig.LogJSON)
 
 	return fileLogging, nil
 }
 
+func setConsoleFormatter(flagsConfig ContextFlagsConfig) error {
+	var formatter logger.Formatter
+	switch {
+	case flagsConfig.LogJSON:
+		formatter = &JSONFormatter{}
+	case flagsConfig.DisableAnsiColor:
+		formatter = &logger.PlainFormatter{}
+	default:
+		return nil
+	}
+
+	err := logger.RemoveLogObserver(os.Stdout)
+	if err != nil {
+		return err
+	}
+
+	return logger.AddLogObserver(os.Stdout, formatter)
+}
+
 // GetMaxDBValue will search in parentDir for all dbs directories and return max db value
 func GetMaxDBValue(parentDir string, log logger.Logger) (int, error) {
 	contents, err := ioutil.ReadDir(parentDir)
 	if err != nil {
-		return 0, err
+		return 0, NewIOError(err)
 	}
 
 	directories := make([]string, 0)
@@ -116,10 +134,10 @@ func GetMaxDBValue(parentDir string, log logger.Logger) (int, error) {
 	}
 
 	if numDirs == 0 {
-		return 0, fmt.Errorf("missing ordered directories in %s, like 0, 1 and so on", parentDir)
+		return 0, NewIOError(fmt.Errorf("missing ordered directories in %s, like 0, 1 and so on", parentDir))
 	}
 	if numDirs != len(directories) {
-		return 0, fmt.Errorf("unordered directories in %s, like 0, 1 and so on", parentDir)
+		return 0, NewIOError(fmt.Errorf("unordered directories in %s, like 0, 1 and so on", parentDir))
 	}
 
 	return numDirs - 1, nil
@@ -146,6 +164,11 @@ func CreatePruningStorer(flags ContextFlagsConfig, maxDBValue int) (storage.Stor
 		StartingEpoch:         uint32(maxDBValue),
 	}
 
+	persisterFactory, err := NewPersisterFactory(localDbConfig, flags.ReadOnly, flags.DbOpenRetry)
+	if err != nil {
+		return nil, err
+	}
+
 	dbPath := path.Join(flags.WorkingDir, flags.DbDir)
 	args := pruning.StorerArgs{
 		Identifier:                "",
@@ -153,7 +176,7 @@ func CreatePruningStorer(flags ContextFlagsConfig, maxDBValue int) (storage.Stor
 		CacheConf:                 cacheConfig,
 		PathManager:               components.NewSimplePathManager(dbPath),
 		DbPath:                    "",
-		PersisterFactory:          factory.NewPersisterFactory(localDbConfig),
+		PersisterFactory:          persisterFactory,
 		Notifier:                  notifier.NewManualEpochStartNotifier(),
 		OldDataCleanerProvider:    &testscommon.OldDataCleanerProviderStub{},
 		CustomDatabaseRemover:     disabled.NewDisabledCustomDatabaseRemover(),
@@ -164,7 +187,12 @@ func CreatePruningStorer(flags ContextFlagsConfig, maxDBValue int) (storage.Stor
 		PersistersTracker:         pruning.NewPersistersTracker(epochsData),
 	}
 
-	return pruning.NewTriePruningStorer(args)
+	storer, err := pruning.NewTriePruningStorer(args)
+	if err != nil {
+		return nil, NewIOError(err)
+	}
+
+	return storer, nil
 }
 
 // CreateStorer will create and return a storer using the provided flags
@@ -181,7 +209,17 @@ func CreateStorer(flags ContextFlagsConfig) (storage.Storer, error) {
 		MaxOpenFiles:      dbConfig.MaxOpenFiles,
 	}
 
-	return storageUnit.NewStorageUnitFromConf(cacheConfig, dbConf)
+	var storer storage.Storer
+	err := RetryOpen(flags.DbOpenRetry, dbPath, func() (io.Closer, error) {
+		var errCreate error
+		storer, errCreate = NewStorageUnit(cacheConfig, dbConf, flags.ReadOnly)
+		return storer, errCreate
+	})
+	if err != nil {
+		return nil, NewIOError(err)
+	}
+
+	return storer, nil
 }
 
 // CreateTrie will create and return a trie using the provided flags
@@ -266,9 +304,17 @@ func GetFlags() []cli.Flag {
 		LogLevel,
 		DisableAnsiColor,
 		LogSaveFile,
+		LogJSON,
 		LogWithLoggerName,
 		ProfileMode,
 		HexRootHash,
+		Latest,
+		BlocksDbDirectory,
+		Shard,
+		DbOpenAttempts,
+		DbOpenRetryDelay,
+		DbOpenTimeout,
+		ReadOnly,
 	}
 }
 
@@ -281,9 +327,19 @@ func GetFlagsConfig(ctx *cli.Context) ContextFlagsConfig {
 	flagsConfig.LogLevel = ctx.GlobalString(LogLevel.Name)
 	flagsConfig.DisableAnsiColor = ctx.GlobalBool(DisableAnsiColor.Name)
 	flagsConfig.SaveLogFile = ctx.GlobalBool(LogSaveFile.Name)
+	flagsConfig.LogJSON = ctx.GlobalBool(LogJSON.Name)
 	flagsConfig.EnableLogName = ctx.GlobalBool(LogWithLoggerName.Name)
 	flagsConfig.EnablePprof = ctx.GlobalBool(ProfileMode.Name)
 	flagsConfig.HexRootHash = ctx.GlobalString(HexRootHash.Name)
+	flagsConfig.Latest = ctx.GlobalBool(Latest.Name)
+	flagsConfig.BlocksDbDir = ctx.GlobalString(BlocksDbDirectory.Name)
+	flagsConfig.Shard = uint32(ctx.GlobalUint64(Shard.Name))
+	flagsConfig.DbOpenRetry = DbOpenRetryConfig{
+		Attempts: ctx.GlobalUint64(DbOpenAttempts.Name),
+		Delay:    ctx.GlobalDuration(DbOpenRetryDelay.Name),
+		Timeout:  ctx.GlobalDuration(DbOpenTimeout.Name),
+	}
+	flagsConfig.ReadOnly = ctx.GlobalBoolT(ReadOnly.Name)
 
 	return flagsConfig
 }
@@ -7,8 +7,14 @@ type ContextFlagsConfig struct {
 	LogLevel         string
 	DisableAnsiColor bool
 	SaveLogFile      bool
+	LogJSON          bool
 	EnableLogName    bool
 	EnablePprof      bool
 	HexRootHash      string
+	Latest           bool
+	BlocksDbDir      string
+	Shard            uint32
 	Address          string
+	DbOpenRetry      DbOpenRetryConfig
+	ReadOnly         bool
 }
@@ -0,0 +1,161 @@
+package trieToolsCommon
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/multiversx/mx-chain-go/storage"
+	"github.com/multiversx/mx-chain-go/storage/pruning"
+)
+
+const (
+	// DefaultDbOpenAttempts is the default number of attempts made to open a database
+	DefaultDbOpenAttempts = 1
+	// DefaultDbOpenRetryDelay is the default delay between two attempts of opening a database
+	DefaultDbOpenRetryDelay = time.Second
+)
+
+var errDatabaseInUse = errors.New("database appears to be in use")
+
+// DbOpenRetryConfig holds the parameters used when opening the databases, which might still be locked by a node
+// that has just been stopped. A non-zero Timeout bounds the whole opening, retries included
+type DbOpenRetryConfig struct {
+	Attempts uint64
+	Delay    time.Duration
+	Timeout  time.Duration
+}
+
+// Check returns an error if any of the parameters is invalid
+func (config DbOpenRetryConfig) Check() error {
+	if config.Attempts == 0 {
+		return fmt.Errorf("invalid db open attempts: %d, expected at least one attempt", config.Attempts)
+	}
+	if config.Delay < 0 {
+		return fmt.Errorf("invalid db open retry delay: %v", config.Delay)
+	}
+	if config.Timeout < 0 {
+		return fmt.Errorf("invalid db open timeout: %v", config.Timeout)
+	}
+
+	return nil
+}
+
+// RetryOpen calls the open function until it succeeds or the configured number of attempts is reached, waiting the
+// configured delay between the attempts. The error of the last attempt is returned. If the configured timeout is
+// reached before, either while an attempt is blocked or before the next attempt, a database in use error is returned.
+// The open function returns what it has opened, so that a blocked attempt which succeeds too late can close it
+func RetryOpen(config DbOpenRetryConfig, path string, open func() (io.Closer, error)) error {
+	err := config.Check()
+	if err != nil {
+		return err
+	}
+
+	deadline := time.Now().Add(config.Timeout)
+	for attempt := uint64(1); ; attempt++ {
+		err = openWithTimeout(config, deadline, open)
+		if errors.Is(err, errDatabaseInUse) {
+			return fmt.Errorf("%w: could not open %s within the %v open timeout, the database might be locked by a running node", err, path, config.Timeout)
+		}
+		if err == nil || attempt >= config.Attempts {
+			break
+		}
+		if config.Timeout > 0 && time.Now().Add(config.Delay).After(deadline) {
+			return fmt.Errorf("%w: could not open %s within the %v open timeout, the database might be locked by a running node, last error: %s",
+				errDatabaseInUse, path, config.Timeout, err.Error())
+		}
+
+		log.Warn("could not open the database, retrying", "path", path, "attempt", attempt,
+			"max attempts", config.Attempts, "retry delay", config.Delay, "error", err)
+		time.Sleep(config.Delay)
+	}
+	if err != nil && config.Attempts > 1 {
+		return fmt.Errorf("%w after %d attempts, path %s", err, config.Attempts, path)
+	}
+
+	return err
+}
+
+// openWithTimeout calls the open function and, if a timeout is configured, stops waiting for it at the provided
+// deadline. An open call which is still blocked is left running, as it can not be interrupted, and whatever it
+// opens after the deadline is closed, as no one will use it
+func openWithTimeout(config DbOpenRetryConfig, deadline time.Time, open func() (io.Closer, error)) error {
+	if config.Timeout == 0 {
+		_, err := open()
+		return err
+	}
+
+	chResult := make(chan error)
+	chDone := make(chan struct{})
+	go func() {
+		opened, err := open()
+		select {
+		case chResult <- err:
+		case <-chDone:
+			if err == nil && opened != nil {
+				log.Warn("closing the database opened after the open timeout")
+				log.LogIfError(opened.Close())
+			}
+		}
+	}()
+
+	timer := time.NewTimer(time.Until(deadline))
+	defer timer.Stop()
+
+	select {
+	case err := <-chResult:
+		return err
+	case <-timer.C:
+		close(chDone)
+		return errDatabaseInUse
+	}
+}
+
+type retryPersisterFactory struct {
+	persisterFactory pruning.DbFactoryHandler
+	config           DbOpenRetryConfig
+}
+
+// NewRetryPersisterFactory wraps the provided persister factory so that the persisters creation is retried using
+// the provided config
+func NewRetryPersisterFactory(persisterFactory pruning.DbFactoryHandler, config DbOpenRetryConfig) (*retryPersisterFactory, error) {
+	if persisterFactory == nil || persisterFactory.IsInterfaceNil() {
+		return nil, fmt.Errorf("nil persister factory provided")
+	}
+
+	err := config.Check()
+	if err != nil {
+		return nil, err
+	}
+
+	return &retryPersisterFactory{
+		persisterFactory: persisterFactory,
+		config:           config,
+	}, nil
+}
+
+// Create creates a persister for the provided path, retrying on errors
+func (factory *retryPersisterFactory) Create(path string) (storage.Persister, error) {
+	var persister storage.Persister
+	err := RetryOpen(factory.config, path, func() (io.Closer, error) {
+		var errCreate error
+		persister, errCreate = factory.persisterFactory.Create(path)
+		return persister, errCreate
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return persister, nil
+}
+
+// CreateDisabled creates a disabled persister
+func (factory *retryPersisterFactory) CreateDisabled() storage.Persister {
+	return factory.persisterFactory.CreateDisabled()
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (factory *retryPersisterFactory) IsInterfaceNil() bool {
+	return factory == nil
+}
@@ -0,0 +1,179 @@
+package trieToolsCommon
+
+import (
+	"errors"
+	"io"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	nodeConfig "github.com/multiversx/mx-chain-go/config"
+	"github.com/multiversx/mx-chain-go/storage/factory"
+	"github.com/stretchr/testify/require"
+)
+
+type closerStub struct {
+	numCloseCalls uint32
+}
+
+// Close -
+func (stub *closerStub) Close() error {
+	atomic.AddUint32(&stub.numCloseCalls, 1)
+	return nil
+}
+
+func TestRetryOpen(t *testing.T) {
+	t.Parallel()
+
+	expectedErr := errors.New("resource temporarily unavailable")
+
+	t.Run("invalid config, should error", func(t *testing.T) {
+		t.Parallel()
+
+		err := RetryOpen(DbOpenRetryConfig{Attempts: 0}, "path", func() (io.Closer, error) {
+			require.Fail(t, "should have not been called")
+			return nil, nil
+		})
+		require.ErrorContains(t, err, "invalid db open attempts")
+
+		err = RetryOpen(DbOpenRetryConfig{Attempts: 1, Delay: -time.Second}, "path", func() (io.Closer, error) {
+			require.Fail(t, "should have not been called")
+			return nil, nil
+		})
+		require.ErrorContains(t, err, "invalid db open retry delay")
+
+		err = RetryOpen(DbOpenRetryConfig{Attempts: 1, Timeout: -time.Second}, "path", func() (io.Closer, error) {
+			require.Fail(t, "should have not been called")
+			return nil, nil
+		})
+		require.ErrorContains(t, err, "invalid db open timeout")
+	})
+	t.Run("single attempt, should return the error", func(t *testing.T) {
+		t.Parallel()
+
+		numCalls := 0
+		err := RetryOpen(DbOpenRetryConfig{Attempts: 1, Delay: time.Hour}, "path", func() (io.Closer, error) {
+			numCalls++
+			return nil, expectedErr
+		})
+		require.Equal(t, expectedErr, err)
+		require.Equal(t, 1, numCalls)
+	})
+	t.Run("should retry until success", func(t *testing.T) {
+		t.Parallel()
+
+		numCalls := 0
+		err := RetryOpen(DbOpenRetryConfig{Attempts: 5, Delay: time.Millisecond}, "path", func() (io.Closer, error) {
+			numCalls++
+			if numCalls < 3 {
+				return nil, expectedErr
+			}
+			return nil, nil
+		})
+		require.Nil(t, err)
+		require.Equal(t, 3, numCalls)
+	})
+	t.Run("all attempts failed, should return the last error", func(t *testing.T) {
+		t.Parallel()
+
+		numCalls := 0
+		start := time.Now()
+		err := RetryOpen(DbOpenRetryConfig{Attempts: 3, Delay: 10 * time.Millisecond}, "path", func() (io.Closer, error) {
+			numCalls++
+			return nil, expectedErr
+		})
+		require.ErrorIs(t, err, expectedErr)
+		require.Contains(t, err.Error(), "after 3 attempts, path path")
+		require.Equal(t, 3, numCalls)
+		require.GreaterOrEqual(t, time.Since(start), 20*time.Millisecond)
+	})
+	t.Run("blocked open, should return database in use after the timeout", func(t *testing.T) {
+		t.Parallel()
+
+		chUnblock := make(chan struct{})
+		defer close(chUnblock)
+
+		start := time.Now()
+		err := RetryOpen(DbOpenRetryConfig{Attempts: 1, Timeout: 20 * time.Millisecond}, "path", func() (io.Closer, error) {
+			<-chUnblock
+			return nil, nil
+		})
+		require.ErrorIs(t, err, errDatabaseInUse)
+		require.Contains(t, err.Error(), "could not open path within the 20ms open timeout")
+		require.GreaterOrEqual(t, time.Since(start), 20*time.Millisecond)
+	})
+	t.Run("blocked open succeeding after the timeout, should close what it opened", func(t *testing.T) {
+		t.Parallel()
+
+		chUnblock := make(chan struct{})
+		closer := &closerStub{}
+		err := RetryOpen(DbOpenRetryConfig{Attempts: 1, Timeout: 20 * time.Millisecond}, "path", func() (io.Closer, error) {
+			<-chUnblock
+			return closer, nil
+		})
+		require.ErrorIs(t, err, errDatabaseInUse)
+
+		close(chUnblock)
+		require.Eventually(t, func() bool {
+			return atomic.LoadUint32(&closer.numCloseCalls) == 1
+		}, time.Second, time.Millisecond)
+	})
+	t.Run("retries exceeding the timeout, should return database in use", func(t *testing.T) {
+		t.Parallel()
+
+		numCalls := 0
+		err := RetryOpen(DbOpenRetryConfig{Attempts: 100, Delay: 10 * time.Millisecond, Timeout: 35 * time.Millisecond}, "path", func() (io.Closer, error) {
+			numCalls++
+			return nil, expectedErr
+		})
+		require.ErrorIs(t, err, errDatabaseInUse)
+		require.Contains(t, err.Error(), "last error: "+expectedErr.Error())
+		require.Less(t, numCalls, 100)
+	})
+	t.Run("open within the timeout, should work", func(t *testing.T) {
+		t.Parallel()
+
+		numCalls := 0
+		err := RetryOpen(DbOpenRetryConfig{Attempts: 5, Delay: time.Millisecond, Timeout: time.Minute}, "path", func() (io.Closer, error) {
+			numCalls++
+			if numCalls < 3 {
+				return nil, expectedErr
+			}
+			return nil, nil
+		})
+		require.Nil(t, err)
+		require.Equal(t, 3, numCalls)
+	})
+}
+
+func TestRetryPersisterFactory(t *testing.T) {
+	t.Parallel()
+
+	retryConfig := DbOpenRetryConfig{Attempts: 2, Delay: time.Millisecond}
+
+	t.Run("nil persister factory, should error", func(t *testing.T) {
+		t.Parallel()
+
+		persisterFactory, err := NewRetryPersisterFactory(nil, retryConfig)
+		require.Nil(t, persisterFactory)
+		require.ErrorContains(t, err, "nil persister factory")
+	})
+	t.Run("invalid config, should error", func(t *testing.T) {
+		t.Parallel()
+
+		persisterFactory, err := NewRetryPersisterFactory(factory.NewPersisterFactory(nodeConfig.DBConfig{}), DbOpenRetryConfig{})
+		require.Nil(t, persisterFactory)
+		require.ErrorContains(t, err, "invalid db open attempts")
+	})
+	t.Run("should work", func(t *testing.T) {
+		t.Parallel()
+
+		persisterFactory, err := NewRetryPersisterFactory(factory.NewPersisterFactory(nodeConfig.DBConfig{Type: "MemoryDB"}), retryConfig)
+		require.Nil(t, err)
+
+		persister, err := persisterFactory.Create("path")
+		require.Nil(t, err)
+		require.NotNil(t, persister)
+		require.NotNil(t, persisterFactory.CreateDisabled())
+	})
+}
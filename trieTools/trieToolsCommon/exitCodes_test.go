@@ -0,0 +1,34 @@
+package trieToolsCommon
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestExitCode(t *testing.T) {
+	t.Parallel()
+
+	expectedErr := errors.New("expected error")
+
+	require.Equal(t, 0, ExitCode(nil))
+	require.Equal(t, ExitCodeGeneric, ExitCode(expectedErr))
+	require.Equal(t, ExitCodeUsage, ExitCode(NewUsageError(expectedErr)))
+	require.Equal(t, ExitCodeIO, ExitCode(NewIOError(expectedErr)))
+	require.Equal(t, ExitCodeIntegrity, ExitCode(NewIntegrityError(expectedErr)))
+	require.Equal(t, ExitCodeUsage, ExitCode(OnUsageError(nil, expectedErr, false)))
+
+	wrappedErr := fmt.Errorf("%w while processing", NewIOError(expectedErr))
+	require.Equal(t, ExitCodeIO, ExitCode(wrappedErr))
+	require.True(t, errors.Is(wrappedErr, expectedErr))
+	require.Equal(t, "expected error while processing", wrappedErr.Error())
+
+	require.Equal(t, ExitCodeIO, ExitCode(NewIntegrityError(NewIOError(expectedErr))))
+	require.Equal(t, ExitCodeUsage, ExitCode(NewIOError(fmt.Errorf("%w in file", NewUsageError(expectedErr)))))
+
+	require.Nil(t, NewUsageError(nil))
+	require.Nil(t, NewIOError(nil))
+	require.Nil(t, NewIntegrityError(nil))
+}
@@ -26,6 +26,11 @@ var (
 		Name:  "log-save",
 		Usage: "Boolean option for enabling log saving. If set, it will automatically save all the logs into a file.",
 	}
+	// LogJSON is used when the log lines need to be written as JSON objects, one per line
+	LogJSON = cli.BoolFlag{
+		Name:  "log-json",
+		Usage: "Boolean option for writing the logs as JSON lines, both in the console and in the log file (if log-save is set).",
+	}
 	// LogWithLoggerName is used to enable log correlation elements
 	LogWithLoggerName = cli.BoolFlag{
 		Name:  "log-logger-name",
@@ -59,10 +64,52 @@ var (
 		Usage: "This flag specifies the `directory` where the application will find the trie storage.",
 		Value: "db",
 	}
+	// DbOpenAttempts defines a flag for the number of attempts made to open a database
+	DbOpenAttempts = cli.Uint64Flag{
+		Name:  "db-open-attempts",
+		Usage: "This flag specifies the number of attempts made to open a database (e.g. while a node still holds its lock). The default, 1, means no retry",
+		Value: DefaultDbOpenAttempts,
+	}
+	// DbOpenRetryDelay defines a flag for the delay between two attempts of opening a database
+	DbOpenRetryDelay = cli.DurationFlag{
+		Name:  "db-open-retry-delay",
+		Usage: "This flag specifies the delay between two attempts of opening a database, when db-open-attempts is greater than 1",
+		Value: DefaultDbOpenRetryDelay,
+	}
+	// DbOpenTimeout defines a flag for the max duration of opening a database, retries included
+	DbOpenTimeout = cli.DurationFlag{
+		Name:  "open-timeout",
+		Usage: "This flag specifies the max duration of opening a database, retries included. If reached (e.g. while a running node holds the lock), the tool stops with a database in use error. The default, 0, means no timeout",
+		Value: 0,
+	}
+	// ReadOnly defines a flag for opening the databases in read-only mode
+	ReadOnly = cli.BoolTFlag{
+		Name: "read-only",
+		Usage: "Boolean option for opening the databases in read-only mode, so nothing is written in them and they are opened " +
+			"with a shared lock. Only the LvlDB and LvlDBSerial persister types support it, the other types are opened in " +
+			"read-write mode. Enabled by default, use --read-only=false to open the databases in read-write mode.",
+	}
 	// HexRootHash defines a flag for the trie root hash expressed in hex format
 	HexRootHash = cli.StringFlag{
 		Name:  "hex-roothash",
 		Usage: "This flag specifies the roothash to start the checking from",
 		Value: "",
 	}
+	// Latest defines a flag for reading the root hash from the latest block header, instead of providing it
+	Latest = cli.BoolFlag{
+		Name:  "latest",
+		Usage: "Boolean option for using the root hash of the latest block header found in the blocks-db-directory, instead of the hex-roothash. If both are provided, the hex-roothash wins",
+	}
+	// BlocksDbDirectory defines a flag for the node database path, inside the working directory, holding the block headers
+	BlocksDbDirectory = cli.StringFlag{
+		Name:  "blocks-db-directory",
+		Usage: "This flag specifies the node database `directory` (holding the Epoch_<N>/Shard_<S>/BlockHeaders storages) where the latest block header is searched for, when the latest flag is set",
+		Value: "",
+	}
+	// Shard defines a flag for the shard of the block headers read when the latest flag is set
+	Shard = cli.Uint64Flag{
+		Name:  "shard",
+		Usage: "This flag specifies the shard of the block headers read when the latest flag is set (4294967295 for the metachain)",
+		Value: 0,
+	}
 )
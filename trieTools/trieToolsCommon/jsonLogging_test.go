@@ -0,0 +1,67 @@
+package trieToolsCommon
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	logger "github.com/multiversx/mx-chain-logger-go"
+	"github.com/multiversx/mx-chain-logger-go/proto"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSONFormatter_Output(t *testing.T) {
+	t.Parallel()
+
+	t.Run("nil line", func(t *testing.T) {
+		t.Parallel()
+
+		formatter := &JSONFormatter{}
+		require.Nil(t, formatter.Output(nil))
+	})
+	t.Run("should output a JSON line", func(t *testing.T) {
+		t.Parallel()
+
+		timestamp := time.Date(2023, 1, 2, 3, 4, 5, 6000000, time.UTC)
+		line := &logger.LogLineWrapper{
+			LogLineMessage: proto.LogLineMessage{
+				Message:   "checking trie",
+				LogLevel:  int32(logger.LogInfo),
+				Args:      []string{"num accounts", "10", "root hash", "aabb", "odd"},
+				Timestamp: timestamp.UnixNano(),
+			},
+		}
+
+		formatter := &JSONFormatter{}
+		output := formatter.Output(line)
+		require.True(t, strings.HasSuffix(string(output), "\n"))
+		require.Equal(t, 1, strings.Count(string(output), "\n"))
+
+		result := make(map[string]interface{})
+		err := json.Unmarshal(output, &result)
+		require.Nil(t, err)
+		require.Equal(t, time.Unix(0, timestamp.UnixNano()).Format(jsonLogTimeLayout), result["timestamp"])
+		require.Equal(t, "INFO", result["level"])
+		require.Equal(t, "checking trie", result["message"])
+		require.Equal(t, map[string]interface{}{"num accounts": "10", "root hash": "aabb"}, result["fields"])
+	})
+	t.Run("no args should omit the fields", func(t *testing.T) {
+		t.Parallel()
+
+		line := &logger.LogLineWrapper{
+			LogLineMessage: proto.LogLineMessage{
+				Message:  "done",
+				LogLevel: int32(logger.LogWarning),
+			},
+		}
+
+		formatter := &JSONFormatter{}
+		result := make(map[string]interface{})
+		err := json.Unmarshal(formatter.Output(line), &result)
+		require.Nil(t, err)
+		require.Equal(t, "WARN", result["level"])
+		_, found := result["fields"]
+		require.False(t, found)
+	})
+}
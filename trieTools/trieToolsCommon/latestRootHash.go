@@ -0,0 +1,213 @@
+package trieToolsCommon
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/multiversx/mx-chain-core-go/core"
+	"github.com/multiversx/mx-chain-core-go/core/check"
+	"github.com/multiversx/mx-chain-core-go/data"
+	dataBlock "github.com/multiversx/mx-chain-core-go/data/block"
+	"github.com/multiversx/mx-chain-go/storage"
+	"github.com/multiversx/mx-chain-storage-go/storageUnit"
+)
+
+const (
+	blockHeadersUnitIdentifier = "BlockHeaders"
+	epochDirectoryPrefix       = "Epoch_"
+	shardDirectoryPrefix       = "Shard_"
+	rootHashLength             = 32
+)
+
+var errLatestRootHashNotFound = errors.New("the latest root hash can not be resolved from the block headers")
+
+// GetLatestRootHash returns the root hash of the latest block header saved by the node in the blocks database, that
+// is, the header with the highest nonce of the latest epoch, whose accounts trie is found in the provided trie storer.
+// The block headers are read from the Epoch_<N>/Shard_<S>/BlockHeaders storage of the blocks database directory. If
+// none of the headers of the latest epoch has its trie in the storer, the previous epochs are tried as well
+func GetLatestRootHash(flags ContextFlagsConfig, storer storage.Storer) ([]byte, error) {
+	if check.IfNil(storer) {
+		return nil, fmt.Errorf("nil storer provided")
+	}
+	if len(flags.BlocksDbDir) == 0 {
+		return nil, NewUsageError(fmt.Errorf("%w: the --%s flag is required, or use the --%s flag instead",
+			errLatestRootHashNotFound, BlocksDbDirectory.Name, HexRootHash.Name))
+	}
+
+	blocksDbPath := filepath.Join(flags.WorkingDir, flags.BlocksDbDir)
+	epochs, err := getEpochsDescending(blocksDbPath)
+	if err != nil {
+		return nil, NewIOError(fmt.Errorf("%w while reading the blocks database directory %s", err, blocksDbPath))
+	}
+
+	shardDirectory := shardDirectoryPrefix + core.GetShardIDString(flags.Shard)
+	numHeaders := 0
+	for _, epoch := range epochs {
+		unitPath := filepath.Join(blocksDbPath, fmt.Sprintf("%s%d", epochDirectoryPrefix, epoch), shardDirectory, blockHeadersUnitIdentifier)
+		headers, errLoad := loadBlockHeaders(flags, unitPath)
+		if errLoad != nil {
+			return nil, errLoad
+		}
+
+		numHeaders += len(headers)
+		for _, header := range headers {
+			rootHash := header.GetRootHash()
+			if len(rootHash) != rootHashLength || storer.Has(rootHash) != nil {
+				continue
+			}
+
+			log.Info("found the latest block header with the accounts trie in the trie database",
+				"epoch", epoch, "nonce", header.GetNonce(), "root hash", hex.EncodeToString(rootHash))
+
+			return rootHash, nil
+		}
+	}
+
+	return nil, NewUsageError(fmt.Errorf("%w: none of the %d block headers found in %s for shard %s has its root hash in the trie database, use the --%s flag instead",
+		errLatestRootHashNotFound, numHeaders, blocksDbPath, core.GetShardIDString(flags.Shard), HexRootHash.Name))
+}
+
+// getEpochsDescending returns the epochs of the Epoch_<N> directories found in the provided directory, latest first
+func getEpochsDescending(blocksDbPath string) ([]uint32, error) {
+	contents, err := ioutil.ReadDir(blocksDbPath)
+	if err != nil {
+		return nil, err
+	}
+
+	epochs := make([]uint32, 0)
+	for _, c := range contents {
+		if !c.IsDir() || !strings.HasPrefix(c.Name(), epochDirectoryPrefix) {
+			continue
+		}
+
+		epoch, errParse := strconv.ParseUint(strings.TrimPrefix(c.Name(), epochDirectoryPrefix), 10, 32)
+		if errParse != nil {
+			log.Debug("blocks DB directory found that will not be taken into account", "name", c.Name())
+			continue
+		}
+
+		epochs = append(epochs, uint32(epoch))
+	}
+
+	sort.Slice(epochs, func(i, j int) bool {
+		return epochs[i] > epochs[j]
+	})
+
+	return epochs, nil
+}
+
+// loadBlockHeaders returns the block headers found in the provided storage unit, the highest nonce first. A missing
+// storage unit (e.g. the epoch holds no block of the shard) has no headers. The headers that can not be decoded are
+// skipped, the loading failing only if none of the headers of the unit can be decoded
+func loadBlockHeaders(flags ContextFlagsConfig, unitPath string) ([]data.HeaderHandler, error) {
+	_, err := os.Stat(unitPath)
+	if err != nil {
+		log.Debug("block headers storage not found", "path", unitPath)
+		return nil, nil
+	}
+
+	dbConf := storageUnit.DBConfig{
+		FilePath:          unitPath,
+		Type:              storageUnit.DBType(dbConfig.Type),
+		BatchDelaySeconds: dbConfig.BatchDelaySeconds,
+		MaxBatchSize:      dbConfig.MaxBatchSize,
+		MaxOpenFiles:      dbConfig.MaxOpenFiles,
+	}
+
+	var unit *storageUnit.Unit
+	err = RetryOpen(flags.DbOpenRetry, unitPath, func() (io.Closer, error) {
+		var errCreate error
+		unit, errCreate = NewStorageUnit(cacheConfig, dbConf, flags.ReadOnly)
+		return unit, errCreate
+	})
+	if err != nil {
+		return nil, NewIOError(err)
+	}
+	defer func() {
+		log.LogIfError(unit.Close())
+	}()
+
+	headers := make([]data.HeaderHandler, 0)
+	numUndecodable := 0
+	var lastErrUnmarshal error
+	unit.RangeKeys(func(key, value []byte) bool {
+		header, errUnmarshal := unmarshalHeader(flags.Shard, value)
+		if errUnmarshal != nil {
+			log.Warn("cannot decode the block header, skipping it", "path", unitPath, "hash", hex.EncodeToString(key), "error", errUnmarshal)
+			lastErrUnmarshal = errUnmarshal
+			numUndecodable++
+			return true
+		}
+
+		headers = append(headers, header)
+		return true
+	})
+	if len(headers) == 0 && numUndecodable > 0 {
+		return nil, NewIntegrityError(fmt.Errorf("%w while decoding the %d block headers of %s", lastErrUnmarshal, numUndecodable, unitPath))
+	}
+
+	sort.Slice(headers, func(i, j int) bool {
+		return headers[i].GetNonce() > headers[j].GetNonce()
+	})
+
+	return headers, nil
+}
+
+// unmarshalHeader decodes the provided block header of the provided shard. The shard headers are tried as HeaderV2
+// first, and as the former Header if that fails, so the databases written before HeaderV2 can be read as well
+func unmarshalHeader(shard uint32, headerBytes []byte) (data.HeaderHandler, error) {
+	if shard == core.MetachainShardId {
+		metaBlock := &dataBlock.MetaBlock{}
+		err := Marshaller.Unmarshal(metaBlock, headerBytes)
+		if err != nil {
+			return nil, err
+		}
+
+		return metaBlock, nil
+	}
+
+	headerV2 := &dataBlock.HeaderV2{}
+	errV2 := Marshaller.Unmarshal(headerV2, headerBytes)
+	if errV2 == nil && headerV2.Header != nil {
+		return headerV2, nil
+	}
+
+	headerV1 := &dataBlock.Header{}
+	errV1 := Marshaller.Unmarshal(headerV1, headerBytes)
+	if errV1 != nil {
+		return nil, fmt.Errorf("%v as HeaderV2, %w as Header", errV2, errV1)
+	}
+
+	return headerV1, nil
+}
+
+// ResolveRootHash returns the provided root hash, if not empty (the hex-roothash flag wins over the latest flag).
+// Otherwise, the root hash of the latest block header is read from the blocks database
+func ResolveRootHash(rootHash []byte, flags ContextFlagsConfig, storer storage.Storer) ([]byte, error) {
+	if len(rootHash) > 0 {
+		return rootHash, nil
+	}
+
+	latestRootHash, err := GetLatestRootHash(flags, storer)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Info("using the root hash of the latest block header", "root hash", hex.EncodeToString(latestRootHash))
+
+	return latestRootHash, nil
+}
+
+// ShouldUseLatestRootHash returns true if the root hash should be read from the latest block header, that is, if the
+// latest flag is set and no hex root hash is provided
+func ShouldUseLatestRootHash(flags ContextFlagsConfig) bool {
+	return flags.Latest && len(flags.HexRootHash) == 0
+}
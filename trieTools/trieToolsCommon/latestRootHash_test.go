@@ -0,0 +1,218 @@
+package trieToolsCommon
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/multiversx/mx-chain-core-go/core"
+	"github.com/multiversx/mx-chain-core-go/data"
+	dataBlock "github.com/multiversx/mx-chain-core-go/data/block"
+	"github.com/multiversx/mx-chain-go/testscommon/genericMocks"
+	"github.com/multiversx/mx-chain-storage-go/leveldb"
+	"github.com/stretchr/testify/require"
+)
+
+func createTestRootHash(value byte) []byte {
+	return bytes.Repeat([]byte{value}, rootHashLength)
+}
+
+func createShardHeader(nonce uint64, rootHash []byte) data.HeaderHandler {
+	return &dataBlock.HeaderV2{Header: &dataBlock.Header{Nonce: nonce, RootHash: rootHash}}
+}
+
+// createBlocksDb writes the provided headers (or raw bytes), by epoch, in the block headers storages of the provided
+// shard and returns the flags pointing to the created blocks database
+func createBlocksDb(t *testing.T, shard uint32, headersByEpoch map[uint32][]interface{}) ContextFlagsConfig {
+	workingDir := t.TempDir()
+	blocksDbDir := "blocks"
+	for epoch, headers := range headersByEpoch {
+		unitPath := filepath.Join(workingDir, blocksDbDir, fmt.Sprintf("Epoch_%d", epoch), "Shard_"+core.GetShardIDString(shard), blockHeadersUnitIdentifier)
+		db, err := leveldb.NewSerialDB(unitPath, 2, 100, 10)
+		require.Nil(t, err)
+
+		for i, header := range headers {
+			headerBytes, isRaw := header.([]byte)
+			if !isRaw {
+				var errMarshal error
+				headerBytes, errMarshal = Marshaller.Marshal(header)
+				require.Nil(t, errMarshal)
+			}
+			require.Nil(t, db.Put([]byte(fmt.Sprintf("hash%d", i)), headerBytes))
+		}
+		require.Nil(t, db.Close())
+	}
+
+	return ContextFlagsConfig{
+		WorkingDir:  workingDir,
+		BlocksDbDir: blocksDbDir,
+		Shard:       shard,
+		DbOpenRetry: DbOpenRetryConfig{Attempts: 1},
+		ReadOnly:    true,
+	}
+}
+
+func TestGetLatestRootHash(t *testing.T) {
+	t.Parallel()
+
+	t.Run("nil storer, should error", func(t *testing.T) {
+		t.Parallel()
+
+		rootHash, err := GetLatestRootHash(ContextFlagsConfig{BlocksDbDir: "blocks"}, nil)
+		require.ErrorContains(t, err, "nil storer provided")
+		require.Nil(t, rootHash)
+	})
+	t.Run("missing blocks DB directory flag, should error", func(t *testing.T) {
+		t.Parallel()
+
+		rootHash, err := GetLatestRootHash(ContextFlagsConfig{}, genericMocks.NewStorerMock())
+		require.ErrorIs(t, err, errLatestRootHashNotFound)
+		require.ErrorContains(t, err, "--"+BlocksDbDirectory.Name)
+		require.Equal(t, ExitCodeUsage, ExitCode(err))
+		require.Nil(t, rootHash)
+	})
+	t.Run("missing blocks DB directory, should error", func(t *testing.T) {
+		t.Parallel()
+
+		rootHash, err := GetLatestRootHash(ContextFlagsConfig{WorkingDir: t.TempDir(), BlocksDbDir: "missing"}, genericMocks.NewStorerMock())
+		require.Equal(t, ExitCodeIO, ExitCode(err))
+		require.Nil(t, rootHash)
+	})
+	t.Run("no root hash in the trie database, should error", func(t *testing.T) {
+		t.Parallel()
+
+		flags := createBlocksDb(t, 0, map[uint32][]interface{}{
+			3: {createShardHeader(10, createTestRootHash(1))},
+		})
+
+		rootHash, err := GetLatestRootHash(flags, genericMocks.NewStorerMock())
+		require.ErrorIs(t, err, errLatestRootHashNotFound)
+		require.ErrorContains(t, err, "--hex-roothash")
+		require.Equal(t, ExitCodeUsage, ExitCode(err))
+		require.Nil(t, rootHash)
+	})
+	t.Run("should return the root hash of the highest nonce of the latest epoch", func(t *testing.T) {
+		t.Parallel()
+
+		flags := createBlocksDb(t, 1, map[uint32][]interface{}{
+			2: {createShardHeader(5, createTestRootHash(1))},
+			3: {
+				createShardHeader(11, createTestRootHash(3)),
+				createShardHeader(12, createTestRootHash(4)),
+				createShardHeader(10, createTestRootHash(2)),
+			},
+		})
+		storer := genericMocks.NewStorerMock()
+		for value := byte(1); value <= 4; value++ {
+			_ = storer.Put(createTestRootHash(value), []byte("root node"))
+		}
+
+		rootHash, err := GetLatestRootHash(flags, storer)
+		require.Nil(t, err)
+		require.Equal(t, createTestRootHash(4), rootHash)
+	})
+	t.Run("should skip the headers whose trie is not in the trie database", func(t *testing.T) {
+		t.Parallel()
+
+		flags := createBlocksDb(t, 0, map[uint32][]interface{}{
+			2: {createShardHeader(5, createTestRootHash(1))},
+			3: {createShardHeader(10, createTestRootHash(2))},
+		})
+		storer := genericMocks.NewStorerMock()
+		_ = storer.Put(createTestRootHash(1), []byte("root node"))
+
+		rootHash, err := GetLatestRootHash(flags, storer)
+		require.Nil(t, err)
+		require.Equal(t, createTestRootHash(1), rootHash)
+	})
+	t.Run("v1 headers should work", func(t *testing.T) {
+		t.Parallel()
+
+		flags := createBlocksDb(t, 0, map[uint32][]interface{}{
+			1: {
+				&dataBlock.Header{Nonce: 8, RootHash: createTestRootHash(6)},
+				createShardHeader(7, createTestRootHash(5)),
+			},
+		})
+		storer := genericMocks.NewStorerMock()
+		_ = storer.Put(createTestRootHash(5), []byte("root node"))
+		_ = storer.Put(createTestRootHash(6), []byte("root node"))
+
+		rootHash, err := GetLatestRootHash(flags, storer)
+		require.Nil(t, err)
+		require.Equal(t, createTestRootHash(6), rootHash)
+	})
+	t.Run("undecodable headers should be skipped", func(t *testing.T) {
+		t.Parallel()
+
+		flags := createBlocksDb(t, 0, map[uint32][]interface{}{
+			1: {
+				[]byte("not a header"),
+				createShardHeader(7, createTestRootHash(5)),
+			},
+		})
+		storer := genericMocks.NewStorerMock()
+		_ = storer.Put(createTestRootHash(5), []byte("root node"))
+
+		rootHash, err := GetLatestRootHash(flags, storer)
+		require.Nil(t, err)
+		require.Equal(t, createTestRootHash(5), rootHash)
+	})
+	t.Run("no decodable header should error", func(t *testing.T) {
+		t.Parallel()
+
+		flags := createBlocksDb(t, 0, map[uint32][]interface{}{
+			1: {[]byte("not a header")},
+		})
+
+		rootHash, err := GetLatestRootHash(flags, genericMocks.NewStorerMock())
+		require.Equal(t, ExitCodeIntegrity, ExitCode(err))
+		require.Nil(t, rootHash)
+	})
+	t.Run("metachain headers should work", func(t *testing.T) {
+		t.Parallel()
+
+		flags := createBlocksDb(t, core.MetachainShardId, map[uint32][]interface{}{
+			1: {&dataBlock.MetaBlock{Nonce: 7, RootHash: createTestRootHash(5)}},
+		})
+		storer := genericMocks.NewStorerMock()
+		_ = storer.Put(createTestRootHash(5), []byte("root node"))
+
+		rootHash, err := GetLatestRootHash(flags, storer)
+		require.Nil(t, err)
+		require.Equal(t, createTestRootHash(5), rootHash)
+	})
+}
+
+func TestResolveRootHash(t *testing.T) {
+	t.Parallel()
+
+	latestRootHash := createTestRootHash(1)
+	providedRootHash := createTestRootHash(2)
+	flags := createBlocksDb(t, 0, map[uint32][]interface{}{
+		1: {createShardHeader(1, latestRootHash)},
+	})
+	storer := genericMocks.NewStorerMock()
+	_ = storer.Put(latestRootHash, []byte("root node"))
+
+	rootHash, err := ResolveRootHash(providedRootHash, flags, storer)
+	require.Nil(t, err)
+	require.Equal(t, providedRootHash, rootHash)
+
+	rootHash, err = ResolveRootHash(nil, flags, storer)
+	require.Nil(t, err)
+	require.Equal(t, latestRootHash, rootHash)
+
+	_, err = ResolveRootHash(nil, flags, genericMocks.NewStorerMock())
+	require.ErrorIs(t, err, errLatestRootHashNotFound)
+}
+
+func TestShouldUseLatestRootHash(t *testing.T) {
+	t.Parallel()
+
+	require.False(t, ShouldUseLatestRootHash(ContextFlagsConfig{}))
+	require.True(t, ShouldUseLatestRootHash(ContextFlagsConfig{Latest: true}))
+	require.False(t, ShouldUseLatestRootHash(ContextFlagsConfig{Latest: true, HexRootHash: "aa"}))
+	require.False(t, ShouldUseLatestRootHash(ContextFlagsConfig{HexRootHash: "aa"}))
+}
@@ -0,0 +1,246 @@
+package trieToolsCommon
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+
+	nodeConfig "github.com/multiversx/mx-chain-go/config"
+	"github.com/multiversx/mx-chain-go/storage"
+	"github.com/multiversx/mx-chain-go/storage/factory"
+	"github.com/multiversx/mx-chain-go/storage/pruning"
+	"github.com/multiversx/mx-chain-storage-go/memorydb"
+	"github.com/multiversx/mx-chain-storage-go/storageUnit"
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/opt"
+)
+
+var errReadOnlyPersister = errors.New("the persister is opened in read-only mode")
+
+// readOnlyPersisterTypes holds the persister types which can be opened in read-only mode. Both LvlDB and LvlDBSerial
+// are level DBs on disk, so they are opened by the same read-only persister. MemoryDB has nothing to be protected
+var readOnlyPersisterTypes = []storageUnit.DBType{storageUnit.LvlDB, storageUnit.LvlDBSerial}
+
+// IsReadOnlySupported returns true if the provided persister type can be opened in read-only mode
+func IsReadOnlySupported(dbType storageUnit.DBType) bool {
+	for _, readOnlyType := range readOnlyPersisterTypes {
+		if dbType == readOnlyType {
+			return true
+		}
+	}
+
+	return false
+}
+
+// readOnlyLevelDB is a level DB persister which never writes on disk: the writes are rejected and the DB is opened
+// with a shared lock instead of an exclusive one. The shared lock still conflicts with the exclusive lock of a
+// running node, but several read-only tools can open the same DB at the same time
+type readOnlyLevelDB struct {
+	mut  sync.RWMutex
+	path string
+	db   *leveldb.DB
+}
+
+// NewReadOnlyLevelDB opens the level DB found in the provided path in read-only mode. The DB should exist
+func NewReadOnlyLevelDB(path string, maxOpenFiles int) (*readOnlyLevelDB, error) {
+	db, err := leveldb.OpenFile(path, &opt.Options{
+		// disable internal cache
+		BlockCacheCapacity:     -1,
+		OpenFilesCacheCapacity: maxOpenFiles,
+		ErrorIfMissing:         true,
+		ReadOnly:               true,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w while opening in read-only mode, path %s", err, path)
+	}
+
+	return &readOnlyLevelDB{
+		path: path,
+		db:   db,
+	}, nil
+}
+
+func (persister *readOnlyLevelDB) getDb() *leveldb.DB {
+	persister.mut.RLock()
+	defer persister.mut.RUnlock()
+
+	return persister.db
+}
+
+// Put returns an error, as the persister is read-only
+func (persister *readOnlyLevelDB) Put(_, _ []byte) error {
+	return fmt.Errorf("%w: can not put, path %s", errReadOnlyPersister, persister.path)
+}
+
+// Get returns the value associated to the key
+func (persister *readOnlyLevelDB) Get(key []byte) ([]byte, error) {
+	db := persister.getDb()
+	if db == nil {
+		return nil, storage.ErrDBIsClosed
+	}
+
+	data, err := db.Get(key, nil)
+	if err == leveldb.ErrNotFound {
+		return nil, storage.ErrKeyNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// Has returns nil if the given key is present in the persistence medium
+func (persister *readOnlyLevelDB) Has(key []byte) error {
+	db := persister.getDb()
+	if db == nil {
+		return storage.ErrDBIsClosed
+	}
+
+	has, err := db.Has(key, nil)
+	if err != nil {
+		return err
+	}
+	if !has {
+		return storage.ErrKeyNotFound
+	}
+
+	return nil
+}
+
+// Close closes the DB, releasing its lock
+func (persister *readOnlyLevelDB) Close() error {
+	persister.mut.Lock()
+	defer persister.mut.Unlock()
+
+	if persister.db == nil {
+		return nil
+	}
+
+	err := persister.db.Close()
+	persister.db = nil
+
+	return err
+}
+
+// Remove returns an error, as the persister is read-only
+func (persister *readOnlyLevelDB) Remove(_ []byte) error {
+	return fmt.Errorf("%w: can not remove, path %s", errReadOnlyPersister, persister.path)
+}
+
+// Destroy returns an error, as the persister is read-only
+func (persister *readOnlyLevelDB) Destroy() error {
+	return fmt.Errorf("%w: can not destroy, path %s", errReadOnlyPersister, persister.path)
+}
+
+// DestroyClosed returns an error, as the persister is read-only
+func (persister *readOnlyLevelDB) DestroyClosed() error {
+	return persister.Destroy()
+}
+
+// RangeKeys calls the handler for each (key, value) pair, until it returns false
+func (persister *readOnlyLevelDB) RangeKeys(handler func(key []byte, val []byte) bool) {
+	if handler == nil {
+		return
+	}
+
+	db := persister.getDb()
+	if db == nil {
+		return
+	}
+
+	iterator := db.NewIterator(nil, nil)
+	defer iterator.Release()
+
+	for iterator.Next() {
+		key := make([]byte, len(iterator.Key()))
+		copy(key, iterator.Key())
+		val := make([]byte, len(iterator.Value()))
+		copy(val, iterator.Value())
+
+		if !handler(key, val) {
+			return
+		}
+	}
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (persister *readOnlyLevelDB) IsInterfaceNil() bool {
+	return persister == nil
+}
+
+type readOnlyPersisterFactory struct {
+	persisterFactory pruning.DbFactoryHandler
+	maxOpenFiles     int
+}
+
+// Create opens the provided path in read-only mode. A missing path (e.g. an epoch not present in the database) is
+// opened as an empty in-memory persister, instead of being created on disk
+func (factory *readOnlyPersisterFactory) Create(path string) (storage.Persister, error) {
+	_, err := os.Stat(path)
+	if os.IsNotExist(err) {
+		log.Debug("missing DB opened as an empty persister, in read-only mode", "path", path)
+		return memorydb.New(), nil
+	}
+
+	return NewReadOnlyLevelDB(path, factory.maxOpenFiles)
+}
+
+// CreateDisabled creates a disabled persister
+func (factory *readOnlyPersisterFactory) CreateDisabled() storage.Persister {
+	return factory.persisterFactory.CreateDisabled()
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (factory *readOnlyPersisterFactory) IsInterfaceNil() bool {
+	return factory == nil
+}
+
+// NewPersisterFactory creates the persister factory for the provided DB config, read-only if requested and
+// supported by the persister type, otherwise read-write (with a warning), wrapped so that the persisters creation is
+// retried using the provided config
+func NewPersisterFactory(dbConf nodeConfig.DBConfig, readOnly bool, retryConfig DbOpenRetryConfig) (pruning.DbFactoryHandler, error) {
+	var persisterFactory pruning.DbFactoryHandler = factory.NewPersisterFactory(dbConf)
+	if shouldOpenReadOnly(storageUnit.DBType(dbConf.Type), readOnly) {
+		persisterFactory = &readOnlyPersisterFactory{
+			persisterFactory: persisterFactory,
+			maxOpenFiles:     dbConf.MaxOpenFiles,
+		}
+	}
+
+	return NewRetryPersisterFactory(persisterFactory, retryConfig)
+}
+
+// NewStorageUnit creates a storage unit from the provided configs, opening the DB in read-only mode if requested and
+// supported by the persister type, otherwise read-write (with a warning)
+func NewStorageUnit(cacheConf storageUnit.CacheConfig, dbConf storageUnit.DBConfig, readOnly bool) (*storageUnit.Unit, error) {
+	if !shouldOpenReadOnly(dbConf.Type, readOnly) {
+		return storageUnit.NewStorageUnitFromConf(cacheConf, dbConf)
+	}
+
+	cache, err := storageUnit.NewCache(cacheConf)
+	if err != nil {
+		return nil, err
+	}
+
+	persister, err := NewReadOnlyLevelDB(dbConf.FilePath, dbConf.MaxOpenFiles)
+	if err != nil {
+		return nil, err
+	}
+
+	return storageUnit.NewStorageUnit(cache, persister)
+}
+
+func shouldOpenReadOnly(dbType storageUnit.DBType, readOnly bool) bool {
+	if !readOnly {
+		return false
+	}
+	if !IsReadOnlySupported(dbType) {
+		log.Warn("the persister type does not support the read-only mode, the DB is opened in read-write mode",
+			"type", dbType, "read-only types", readOnlyPersisterTypes)
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,162 @@
+package trieToolsCommon
+
+import (
+	"path/filepath"
+	"testing"
+
+	nodeConfig "github.com/multiversx/mx-chain-go/config"
+	"github.com/multiversx/mx-chain-go/storage"
+	"github.com/multiversx/mx-chain-storage-go/leveldb"
+	"github.com/multiversx/mx-chain-storage-go/storageUnit"
+	"github.com/stretchr/testify/require"
+)
+
+func createLevelDB(t *testing.T, values map[string]string) string {
+	dbPath := filepath.Join(t.TempDir(), "db")
+	db, err := leveldb.NewSerialDB(dbPath, 2, 100, 10)
+	require.Nil(t, err)
+
+	for key, value := range values {
+		require.Nil(t, db.Put([]byte(key), []byte(value)))
+	}
+	require.Nil(t, db.Close())
+
+	return dbPath
+}
+
+func TestIsReadOnlySupported(t *testing.T) {
+	t.Parallel()
+
+	require.True(t, IsReadOnlySupported(storageUnit.LvlDB))
+	require.True(t, IsReadOnlySupported(storageUnit.LvlDBSerial))
+	require.False(t, IsReadOnlySupported(storageUnit.MemoryDB))
+}
+
+func TestReadOnlyLevelDB(t *testing.T) {
+	t.Parallel()
+
+	t.Run("missing DB, should error", func(t *testing.T) {
+		t.Parallel()
+
+		persister, err := NewReadOnlyLevelDB(filepath.Join(t.TempDir(), "missing"), 10)
+		require.NotNil(t, err)
+		require.Nil(t, persister)
+	})
+	t.Run("should read and reject the writes", func(t *testing.T) {
+		t.Parallel()
+
+		dbPath := createLevelDB(t, map[string]string{"key1": "value1", "key2": "value2"})
+		persister, err := NewReadOnlyLevelDB(dbPath, 10)
+		require.Nil(t, err)
+
+		value, err := persister.Get([]byte("key1"))
+		require.Nil(t, err)
+		require.Equal(t, []byte("value1"), value)
+		require.Nil(t, persister.Has([]byte("key2")))
+
+		_, err = persister.Get([]byte("missing"))
+		require.Equal(t, storage.ErrKeyNotFound, err)
+		require.Equal(t, storage.ErrKeyNotFound, persister.Has([]byte("missing")))
+
+		require.ErrorIs(t, persister.Put([]byte("key3"), []byte("value3")), errReadOnlyPersister)
+		require.ErrorIs(t, persister.Remove([]byte("key1")), errReadOnlyPersister)
+		require.ErrorIs(t, persister.Destroy(), errReadOnlyPersister)
+
+		numKeys := 0
+		persister.RangeKeys(func(key []byte, val []byte) bool {
+			numKeys++
+			return true
+		})
+		require.Equal(t, 2, numKeys)
+
+		require.Nil(t, persister.Close())
+		_, err = persister.Get([]byte("key1"))
+		require.Equal(t, storage.ErrDBIsClosed, err)
+	})
+	t.Run("more read-only persisters can open the same DB", func(t *testing.T) {
+		t.Parallel()
+
+		dbPath := createLevelDB(t, map[string]string{"key": "value"})
+		first, err := NewReadOnlyLevelDB(dbPath, 10)
+		require.Nil(t, err)
+		second, err := NewReadOnlyLevelDB(dbPath, 10)
+		require.Nil(t, err)
+
+		require.Nil(t, first.Has([]byte("key")))
+		require.Nil(t, second.Has([]byte("key")))
+		require.Nil(t, first.Close())
+		require.Nil(t, second.Close())
+	})
+}
+
+func TestNewPersisterFactory(t *testing.T) {
+	t.Parallel()
+
+	retryConfig := DbOpenRetryConfig{Attempts: 1}
+
+	t.Run("read-only, missing path should create an empty persister", func(t *testing.T) {
+		t.Parallel()
+
+		persisterFactory, err := NewPersisterFactory(nodeConfig.DBConfig{Type: "LvlDBSerial", MaxOpenFiles: 10}, true, retryConfig)
+		require.Nil(t, err)
+
+		missingPath := filepath.Join(t.TempDir(), "missing")
+		persister, err := persisterFactory.Create(missingPath)
+		require.Nil(t, err)
+		require.Equal(t, storage.ErrKeyNotFound, persister.Has([]byte("key")))
+		require.NoDirExists(t, missingPath)
+	})
+	t.Run("read-only, existing path should open it in read-only mode", func(t *testing.T) {
+		t.Parallel()
+
+		dbPath := createLevelDB(t, map[string]string{"key": "value"})
+		persisterFactory, err := NewPersisterFactory(nodeConfig.DBConfig{Type: "LvlDB", MaxOpenFiles: 10}, true, retryConfig)
+		require.Nil(t, err)
+
+		persister, err := persisterFactory.Create(dbPath)
+		require.Nil(t, err)
+		require.Nil(t, persister.Has([]byte("key")))
+		require.ErrorIs(t, persister.Put([]byte("key"), []byte("new value")), errReadOnlyPersister)
+		require.Nil(t, persister.Close())
+	})
+	t.Run("read-only not supported, should fall back to read-write", func(t *testing.T) {
+		t.Parallel()
+
+		persisterFactory, err := NewPersisterFactory(nodeConfig.DBConfig{Type: "MemoryDB"}, true, retryConfig)
+		require.Nil(t, err)
+
+		persister, err := persisterFactory.Create("path")
+		require.Nil(t, err)
+		require.Nil(t, persister.Put([]byte("key"), []byte("value")))
+	})
+}
+
+func TestNewStorageUnit(t *testing.T) {
+	t.Parallel()
+
+	cacheConf := storageUnit.CacheConfig{Type: "SizeLRU", Capacity: 100, SizeInBytes: 10000}
+
+	t.Run("read-only should reject the writes", func(t *testing.T) {
+		t.Parallel()
+
+		dbPath := createLevelDB(t, map[string]string{"key": "value"})
+		unit, err := NewStorageUnit(cacheConf, storageUnit.DBConfig{FilePath: dbPath, Type: storageUnit.LvlDBSerial, MaxBatchSize: 10, MaxOpenFiles: 10}, true)
+		require.Nil(t, err)
+
+		value, err := unit.Get([]byte("key"))
+		require.Nil(t, err)
+		require.Equal(t, []byte("value"), value)
+		require.ErrorIs(t, unit.Put([]byte("key2"), []byte("value2")), errReadOnlyPersister)
+		require.Nil(t, unit.Close())
+	})
+	t.Run("read-write should work", func(t *testing.T) {
+		t.Parallel()
+
+		dbPath := createLevelDB(t, map[string]string{"key": "value"})
+		unit, err := NewStorageUnit(cacheConf, storageUnit.DBConfig{FilePath: dbPath, Type: storageUnit.LvlDBSerial, MaxBatchSize: 10, MaxOpenFiles: 10}, false)
+		require.Nil(t, err)
+
+		require.Nil(t, unit.Put([]byte("key2"), []byte("value2")))
+		require.Nil(t, unit.Close())
+	})
+}
@@ -0,0 +1,112 @@
+package trieToolsCommon
+
+import (
+	"encoding/json"
+	"io/fs"
+	"io/ioutil"
+)
+
+const statisticsFilePerms = 0644
+
+const (
+	// LeavesCountExact marks that all the data tries leaves were counted
+	LeavesCountExact = "exact"
+	// LeavesCountEstimated marks that the num of data tries leaves was extrapolated from a sample of data tries
+	LeavesCountEstimated = "estimated"
+	// LeavesCountSkipped marks that the data tries were not iterated, so the num of data tries leaves is not computed
+	LeavesCountSkipped = "skipped"
+	// LeavesCountPartial marks that the data tries iteration was interrupted, so only the leaves of the data tries
+	// iterated so far were counted
+	LeavesCountPartial = "partial"
+)
+
+// TrieStatistics holds the statistics gathered while iterating a state trie and its data tries
+type TrieStatistics struct {
+	RootHash              string `json:"rootHash"`
+	NumAccountsOnMainTrie int    `json:"numAccountsOnMainTrie"`
+	NumCodeNodes          int    `json:"numCodeNodes"`
+	NumUnknownNodes       int    `json:"numUnknownNodes"`
+	NumDataTries          int    `json:"numDataTries"`
+	NumDataTriesLeaves    int    `json:"numDataTriesLeaves"`
+	// NumDataTriesLeavesMode is one of LeavesCountExact, LeavesCountEstimated, LeavesCountSkipped or LeavesCountPartial
+	NumDataTriesLeavesMode string `json:"numDataTriesLeavesMode"`
+	// Partial marks that the processing was interrupted, so the statistics only cover the leaves iterated so far
+	Partial   bool  `json:"partial"`
+	Timestamp int64 `json:"timestamp"`
+
+	Phases        []PhaseStatistics        `json:"phases,omitempty"`
+	Memory        *MemoryStatistics        `json:"memory,omitempty"`
+	NodeHistogram *NodeHistogramStatistics `json:"nodeHistogram,omitempty"`
+	TokenReport   *TokenReport             `json:"tokenReport,omitempty"`
+}
+
+// TokenReport holds the number of ESDT token entries found in the iterated data tries, by account
+type TokenReport struct {
+	NumAccountsWithTokens int `json:"numAccountsWithTokens"`
+	NumTokenEntries       int `json:"numTokenEntries"`
+	// TopAccounts holds the accounts with the most token entries, sorted descending by the num of token entries
+	TopAccounts  []AccountTokens     `json:"topAccounts"`
+	Distribution []TokensCountBucket `json:"distribution"`
+}
+
+// AccountTokens holds the number of ESDT token entries of an account
+type AccountTokens struct {
+	Address   string `json:"address"`
+	NumTokens int    `json:"numTokens"`
+}
+
+// TokensCountBucket holds the number of accounts having between MinTokens and MaxTokens (inclusive) token entries.
+// A MaxTokens of 0 means that the bucket is not bounded
+type TokensCountBucket struct {
+	MinTokens   int `json:"minTokens"`
+	MaxTokens   int `json:"maxTokens"`
+	NumAccounts int `json:"numAccounts"`
+}
+
+// NodeHistogramStatistics holds the node histograms of the main trie and of its data tries
+type NodeHistogramStatistics struct {
+	MainTrie     NodeHistogram `json:"mainTrie"`
+	DataTries    NodeHistogram `json:"dataTries"`
+	NumDataTries int           `json:"numDataTries"`
+}
+
+// NodeHistogram holds the number and the total encoded size (in bytes) of the trie nodes, by node type, along with
+// the max depth of the walked tries
+type NodeHistogram struct {
+	NumBranchNodes     uint64 `json:"numBranchNodes"`
+	BranchNodesSize    uint64 `json:"branchNodesSize"`
+	NumExtensionNodes  uint64 `json:"numExtensionNodes"`
+	ExtensionNodesSize uint64 `json:"extensionNodesSize"`
+	NumLeafNodes       uint64 `json:"numLeafNodes"`
+	LeafNodesSize      uint64 `json:"leafNodesSize"`
+	MaxDepth           uint32 `json:"maxDepth"`
+}
+
+// PhaseStatistics holds the wall-clock duration and the number of trie node reads of a processing phase
+type PhaseStatistics struct {
+	Name         string `json:"name"`
+	DurationMs   int64  `json:"durationMs"`
+	NumNodeReads uint64 `json:"numNodeReads"`
+}
+
+// MemoryStatistics holds the memory and GC figures sampled while processing
+type MemoryStatistics struct {
+	// PeakRSSBytes is the peak resident set size, as reported by the OS. It is 0 if it could not be read
+	PeakRSSBytes       uint64  `json:"peakRSSBytes"`
+	PeakSysBytes       uint64  `json:"peakSysBytes"`
+	PeakHeapInUseBytes uint64  `json:"peakHeapInUseBytes"`
+	NumGC              uint32  `json:"numGC"`
+	GCPauseTotalMs     int64   `json:"gcPauseTotalMs"`
+	GCCPUFraction      float64 `json:"gcCPUFraction"`
+}
+
+// SaveTrieStatistics will write the provided statistics in the provided file, as indented JSON
+func SaveTrieStatistics(stats *TrieStatistics, outfile string) error {
+	jsonBytes, err := json.MarshalIndent(stats, "", " ")
+	if err != nil {
+		return err
+	}
+
+	log.Info("writing trie statistics in", "file", outfile)
+	return NewIOError(ioutil.WriteFile(outfile, jsonBytes, fs.FileMode(statisticsFilePerms)))
+}
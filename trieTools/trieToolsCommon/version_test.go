@@ -0,0 +1,19 @@
+package trieToolsCommon
+
+import (
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetVersion(t *testing.T) {
+	t.Parallel()
+
+	version := GetVersion()
+	require.True(t, strings.HasPrefix(version, AppVersion+" "))
+	require.True(t, strings.Contains(version, "commit "+Commit))
+	require.True(t, strings.Contains(version, "built "+BuildDate))
+	require.True(t, strings.Contains(version, runtime.Version()))
+}
@@ -30,6 +30,7 @@ func getFlags() []cli.Flag {
 		trieToolsCommon.LogLevel,
 		trieToolsCommon.DisableAnsiColor,
 		trieToolsCommon.LogSaveFile,
+		trieToolsCommon.LogJSON,
 		trieToolsCommon.LogWithLoggerName,
 		trieToolsCommon.ProfileMode,
 		tokensDirectory,
@@ -43,6 +44,7 @@ func getFlagsConfig(ctx *cli.Context) config.ContextFlagsZeroBalanceSysAccChecke
 
 	flagsConfig.LogLevel = ctx.GlobalString(trieToolsCommon.LogLevel.Name)
 	flagsConfig.SaveLogFile = ctx.GlobalBool(trieToolsCommon.LogSaveFile.Name)
+	flagsConfig.LogJSON = ctx.GlobalBool(trieToolsCommon.LogJSON.Name)
 	flagsConfig.EnableLogName = ctx.GlobalBool(trieToolsCommon.LogWithLoggerName.Name)
 	flagsConfig.EnablePprof = ctx.GlobalBool(trieToolsCommon.ProfileMode.Name)
 	flagsConfig.TokensDirectory = ctx.GlobalString(tokensDirectory.Name)
@@ -29,7 +29,9 @@ func main() {
 	app := cli.NewApp()
 	app.Name = "Tokens exporter CLI app"
 	app.Usage = "This is the entry point for the tool that checks which tokens are not used anymore(only stored in system account)"
+	app.Version = trieToolsCommon.GetVersion()
 	app.Flags = getFlags()
+	app.OnUsageError = trieToolsCommon.OnUsageError
 	app.Authors = []cli.Author{
 		{
 			Name:  "The MultiversX Team",
@@ -44,7 +46,7 @@ func main() {
 	err := app.Run(os.Args)
 	if err != nil {
 		log.Error(err.Error())
-		os.Exit(1)
+		os.Exit(trieToolsCommon.ExitCode(err))
 		return
 	}
 }